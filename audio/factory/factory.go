@@ -14,6 +14,7 @@ import (
 	"github.com/AshBuk/dabri/audio/recorders"
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
 )
 
 // AudioRecorderFactory creates audio recorder instances based on configuration
@@ -171,6 +172,7 @@ func (f *AudioRecorderFactory) CreateRecorderWithFallback() (interfaces.AudioRec
 		}
 		if f.TestRecorderMethod(method) == nil {
 			f.logger.Info("[AUDIO] Automatically switching to %s (fallback)", method)
+			metrics.FallbackSwitches.Inc("recorder", originalMethod, method)
 			f.config.Audio.RecordingMethod = method
 			return f.CreateRecorder()
 		}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/ipc"
 	"github.com/AshBuk/dabri/internal/utils"
 )
@@ -20,14 +21,39 @@ import (
 // Each command sends a request to the running daemon via Unix socket.
 func addCLICommands(root *cobra.Command) {
 	root.AddCommand(
-		newIPCCommand("start", "Start recording", "start-recording"),
-		newIPCCommand("stop", "Stop recording and return transcript", "stop-recording"),
-		newIPCCommand("toggle", "Toggle recording (start if stopped, stop if recording)", "toggle-recording"),
+		newRecordingCommand("start", "Start recording", "start-recording"),
+		newRecordingCommand("stop", "Stop recording and return transcript", "stop-recording"),
+		newRecordingCommand("toggle", "Toggle recording (start if stopped, stop if recording)", "toggle-recording"),
+		newIPCCommand("cancel", "Discard the recording or pending transcription without output", "cancel"),
 		newIPCCommand("status", "Show current state and configuration", "status"),
 		newIPCCommand("transcript", "Show the last transcript", "last-transcript"),
+		newIPCCommand("undo", "Erase the last typed dictation or restore the clipboard", "undo"),
+		newSetModeCommand(),
+		newWatchCommand(),
+		newDoctorCommand(),
 	)
 }
 
+// newSetModeCommand creates "set-mode <default|code>" which switches transcript post-processing
+func newSetModeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "set-mode <default|code>",
+		Short:     "Set dictation mode (default prose or code identifiers/symbols)",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{config.PostProcessingDefault, config.PostProcessingCode},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode := args[0]
+			if mode != config.PostProcessingDefault && mode != config.PostProcessingCode {
+				return fmt.Errorf("unknown mode: %s (must be '%s' or '%s')", mode, config.PostProcessingDefault, config.PostProcessingCode)
+			}
+			return runIPCCommand(cmd, "set-mode", map[string]string{"mode": mode})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	return cmd
+}
+
 // newIPCCommand creates a cobra.Command that sends an IPC request to the daemon.
 // Factory Pattern — shared flags and execution logic for all IPC commands.
 func newIPCCommand(use, short, ipcCommand string) *cobra.Command {
@@ -36,11 +62,54 @@ func newIPCCommand(use, short, ipcCommand string) *cobra.Command {
 		Short: short,
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runIPCCommand(cmd, ipcCommand)
+			return runIPCCommand(cmd, ipcCommand, nil)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	return cmd
+}
+
+// sessionFlags maps the per-dictation flags to their IPC params
+var sessionFlags = map[string]string{
+	"lang":            "language",
+	"output":          "output",
+	"post-processing": "post_processing",
+	"task":            "task",
+}
+
+// newRecordingCommand creates a recording command accepting per-dictation overrides,
+// e.g. "dabri start --lang de --output none"; on stop they replace the start options
+func newRecordingCommand(use, short, ipcCommand string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := map[string]string{}
+			for flag, param := range sessionFlags {
+				if value, _ := cmd.Flags().GetString(flag); value != "" {
+					params[param] = value
+				}
+			}
+			opts := config.SessionOptions{
+				Language:       params["language"],
+				Output:         params["output"],
+				PostProcessing: params["post_processing"],
+				Task:           params["task"],
+			}
+			if err := config.ValidateSessionOptions(opts); err != nil {
+				return err
+			}
+			return runIPCCommand(cmd, ipcCommand, params)
 		},
 		SilenceUsage: true,
 	}
 	addCLIFlags(cmd)
+	cmd.Flags().String("lang", "", "Recognition language for this dictation (e.g., de)")
+	cmd.Flags().String("output", "", "Output for this dictation: none, clipboard, type or stdout-only")
+	cmd.Flags().String("post-processing", "", "Post-processing for this dictation: default or code")
+	cmd.Flags().String("task", "", "Whisper task for this dictation: transcribe or translate (to English)")
 	return cmd
 }
 
@@ -52,7 +121,7 @@ func addCLIFlags(cmd *cobra.Command) {
 
 // runIPCCommand executes a single IPC request to the daemon and formats the response.
 // Execution flow: read flags → send IPC → format output (text or JSON)
-func runIPCCommand(cmd *cobra.Command, ipcCommand string) error {
+func runIPCCommand(cmd *cobra.Command, ipcCommand string, params map[string]string) error {
 	socketPath, _ := cmd.Flags().GetString("socket")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	timeoutSec, _ := cmd.Flags().GetInt("timeout")
@@ -61,10 +130,10 @@ func runIPCCommand(cmd *cobra.Command, ipcCommand string) error {
 		socketPath = utils.GetDefaultSocketPath()
 	}
 
-	name := cmd.Name()
+	name := commandKey(cmd)
 	timeout := deriveTimeout(name, timeoutSec)
 
-	req := ipc.Request{Command: ipcCommand}
+	req := ipc.Request{Command: ipcCommand, Params: params}
 	resp, err := ipc.SendRequest(socketPath, req, timeout)
 	if err != nil {
 		return err
@@ -77,6 +146,15 @@ func runIPCCommand(cmd *cobra.Command, ipcCommand string) error {
 	return nil
 }
 
+// commandKey names a command for timeout and output selection; subcommands
+// include their parent ("history list", "config list") so names do not collide
+func commandKey(cmd *cobra.Command) string {
+	if parent := cmd.Parent(); parent != nil && parent.HasParent() {
+		return parent.Name() + " " + cmd.Name()
+	}
+	return cmd.Name()
+}
+
 const (
 	defaultStatusTimeout = 5 * time.Second
 	defaultStopTimeout   = 60 * time.Second
@@ -89,9 +167,14 @@ func deriveTimeout(command string, override int) time.Duration {
 	}
 
 	switch command {
-	case "stop", "toggle":
+	case "stop", "toggle", "history type", "hotkey bind":
+		// hotkey bind may wait for the user to press the new keys
 		return defaultStopTimeout
-	case "set", "delete":
+	case "daemon stop":
+		// Shutdown waits for an in-flight transcription to finish
+		return defaultStopTimeout
+	case "model set", "model delete", "config set", "config unset", "daemon restart", "daemon reload":
+		// Model loads and service reloads (e.g., the whisper model via config set) can be slow
 		return defaultModelTimeout
 	default:
 		return defaultStatusTimeout
@@ -108,6 +191,7 @@ func printResponse(command string, resp ipc.Response) {
 		if recording {
 			fmt.Println("Recording started.")
 		} else {
+			printOutputResults(data)
 			if transcript, ok := getString(data, "transcript"); ok && transcript != "" {
 				fmt.Println(transcript)
 			} else {
@@ -118,11 +202,19 @@ func printResponse(command string, resp ipc.Response) {
 		if warning, ok := getString(data, "warning"); ok && warning != "" {
 			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 		}
+		printOutputResults(data)
 		if transcript, ok := getString(data, "transcript"); ok && transcript != "" {
 			fmt.Println(transcript)
 		} else {
 			fmt.Println("Recording stopped (no transcript available).")
 		}
+	case "cancel":
+		if getBoolOr(data, "cancelled", false) {
+			stage, _ := getString(data, "stage")
+			fmt.Printf("Cancelled %s; nothing was output.\n", stage)
+		} else {
+			fmt.Println("Nothing to cancel.")
+		}
 	case "status":
 		printStatusResponse(data)
 	case "transcript":
@@ -131,14 +223,77 @@ func printResponse(command string, resp ipc.Response) {
 		} else {
 			fmt.Println("No transcript available.")
 		}
-	case "set":
+	case "model set":
 		if model, ok := getString(data, "model"); ok && model != "" {
 			fmt.Printf("Model switched to: %s\n", model)
 		}
-	case "delete":
+	case "model delete":
 		if model, ok := getString(data, "model"); ok && model != "" {
 			fmt.Printf("Model deleted: %s\n", model)
 		}
+	case "set-mode":
+		if mode, ok := getString(data, "mode"); ok && mode != "" {
+			fmt.Printf("Dictation mode set to: %s\n", mode)
+		}
+	case "history list", "history search":
+		printHistoryEntries(data)
+	case "history show":
+		printHistoryEntry(data)
+	case "history copy":
+		fmt.Printf("Transcript %d copied to clipboard.\n", getIntOr(data, "id", 0))
+	case "history type":
+		fmt.Printf("Transcript %d typed.\n", getIntOr(data, "id", 0))
+	case "history clear":
+		fmt.Println("Transcript history cleared.")
+	case "undo":
+		if erased := getIntOr(data, "erased", 0); erased > 0 {
+			fmt.Printf("Erased %d characters.\n", erased)
+		}
+		if getBoolOr(data, "clipboard_restored", false) {
+			fmt.Println("Previous clipboard content restored.")
+		}
+	case "config get":
+		fmt.Println(formatConfigValue(data["value"]))
+	case "config set", "config unset":
+		printConfigUpdate(data)
+	case "config list", "hotkey list":
+		printConfigList(data)
+	case "language set":
+		language, _ := getString(data, "language")
+		fmt.Printf("Language set to: %s\n", language)
+	case "output set":
+		mode, _ := getString(data, "mode")
+		fmt.Printf("Output mode set to: %s\n", mode)
+	case "recorder set":
+		method, _ := getString(data, "method")
+		fmt.Printf("Recorder set to: %s\n", method)
+	case "notifications":
+		if getBoolOr(data, "enabled", false) {
+			fmt.Println("Workflow notifications enabled.")
+		} else {
+			fmt.Println("Workflow notifications disabled.")
+		}
+	case "hotkey bind":
+		action, _ := getString(data, "action")
+		combo, _ := getString(data, "combo")
+		fmt.Printf("Hotkey %s bound to: %s\n", action, combo)
+	case "reset":
+		fmt.Println("Configuration reset to defaults.")
+	case "daemon stop":
+		fmt.Println("Daemon stopped.")
+	case "daemon restart":
+		fmt.Printf("Daemon restarted (PID: %d).\n", getIntOr(data, "pid", 0))
+	case "daemon reload":
+		printReloadResult(data)
+	case "daemon pid":
+		fmt.Println(getIntOr(data, "pid", 0))
+	case "log-level":
+		level, _ := getString(data, "level")
+		if previous, ok := getString(data, "previous"); ok {
+			fmt.Printf("Log level changed from %s to %s\n", previous, level)
+		} else {
+			fmt.Println(level)
+		}
 	default:
 		if resp.Message != "" {
 			fmt.Println(resp.Message)
@@ -146,6 +301,54 @@ func printResponse(command string, resp ipc.Response) {
 	}
 }
 
+// printReloadResult lists the settings a config reload changed
+func printReloadResult(data map[string]any) {
+	changed, _ := data["changed"].([]any)
+	if len(changed) == 0 {
+		fmt.Println("Configuration reloaded; nothing changed.")
+	} else {
+		fmt.Printf("Configuration reloaded; %d settings changed:\n", len(changed))
+		for _, key := range changed {
+			fmt.Printf("  %v\n", key)
+		}
+	}
+	warnings, _ := data["warnings"].([]any)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+	if restart, _ := data["restart_required"].([]any); len(restart) > 0 {
+		keys := make([]string, 0, len(restart))
+		for _, key := range restart {
+			keys = append(keys, fmt.Sprint(key))
+		}
+		fmt.Printf("Restart required to apply: %s (dabri daemon restart)\n", strings.Join(keys, ", "))
+	}
+}
+
+// printOutputResults reports output targets that did not deliver cleanly
+func printOutputResults(data map[string]any) {
+	outputs, _ := data["outputs"].([]any)
+	for _, item := range outputs {
+		result, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		status, _ := getString(result, "status")
+		if status == "ok" {
+			continue
+		}
+		target, _ := getString(result, "target")
+		msg := fmt.Sprintf("Output %s: %s", target, status)
+		if fallback, ok := getString(result, "fallback"); ok && fallback != "" {
+			msg += " via " + fallback
+		}
+		if errMsg, ok := getString(result, "error"); ok && errMsg != "" {
+			msg += " (" + errMsg + ")"
+		}
+		fmt.Fprintln(os.Stderr, msg)
+	}
+}
+
 func printStatusResponse(data map[string]any) {
 	recording := getBoolOr(data, "recording", false)
 	fmt.Printf("Recording: %t\n", recording)
@@ -165,6 +368,9 @@ func printStatusResponse(data map[string]any) {
 		if method, ok := getString(cfg, "audio_method"); ok && method != "" {
 			fmt.Printf("  Audio method: %s\n", method)
 		}
+		if mode, ok := getString(cfg, "mode"); ok && mode != "" {
+			fmt.Printf("  Dictation mode: %s\n", mode)
+		}
 	}
 	// Hotkeys section
 	if hotkeys := getMap(data, "hotkeys"); hotkeys != nil {
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// addConfigCommand registers the "config" command tree (IPC → daemon).
+// Settings are addressed by dotted path, e.g. audio.device or output.type_tool:
+//
+//	config get <key>          — print one setting
+//	config set <key> <value>  — change a setting and apply it live
+//	config unset <key>        — restore a setting's default
+//	config list [prefix]      — print all settings or one section
+func addConfigCommand(root *cobra.Command) {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change settings by dotted path (get/set/unset/list)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	getCmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a setting (e.g., audio.device)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "config-get", map[string]string{"key": args[0]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(getCmd)
+
+	setCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting and apply it without restarting",
+		Long: "Change a setting and apply it without restarting.\n\n" +
+			"Numbers, booleans and lists are written as YAML, for example:\n" +
+			"  dabri config set web_server.port 9090\n" +
+			"  dabri config set security.allowed_commands '[xsel, wl-copy]'",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "config-set", map[string]string{"key": args[0], "value": args[1]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(setCmd)
+
+	unsetCmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Restore a setting to its default",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "config-unset", map[string]string{"key": args[0]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(unsetCmd)
+
+	listCmd := &cobra.Command{
+		Use:   "list [prefix]",
+		Short: "List settings, optionally one section (e.g., output)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params map[string]string
+			if len(args) == 1 {
+				params = map[string]string{"prefix": args[0]}
+			}
+			return runIPCCommand(cmd, "config-list", params)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(listCmd)
+
+	configCmd.AddCommand(getCmd, setCmd, unsetCmd, listCmd)
+	root.AddCommand(configCmd)
+}
+
+// printConfigUpdate reports the new value and whether a restart is needed
+func printConfigUpdate(data map[string]any) {
+	key, _ := getString(data, "key")
+	fmt.Printf("%s = %s\n", key, formatConfigValue(data["value"]))
+	if getBoolOr(data, "restart_required", false) {
+		fmt.Fprintln(os.Stderr, "Saved; restart dabri to apply this setting.")
+	}
+}
+
+// printConfigList prints one "key = value" line per setting
+func printConfigList(data map[string]any) {
+	settings, _ := data["settings"].([]any)
+	for _, item := range settings {
+		setting, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		key, _ := getString(setting, "key")
+		fmt.Printf("%s = %s\n", key, formatConfigValue(setting["value"]))
+	}
+}
+
+// formatConfigValue renders a setting for display; lists are printed as
+// JSON, which is valid YAML, so they can be pasted back into "config set"
+func formatConfigValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "[]"
+	case []any, map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
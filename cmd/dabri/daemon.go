@@ -4,13 +4,17 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/app"
+	"github.com/AshBuk/dabri/internal/ipc"
 	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/utils"
 )
@@ -29,9 +33,10 @@ func addDaemonFlags(cmd *cobra.Command) {
 // Daemon orchestrator - initializes and runs the main application
 // Execution flow:
 //  1. Read flags (--config, --debug) from cobra.Command
-//  2. Bootstrap logger (Info/Debug level)
+//  2. Bootstrap structured logger (Info/Debug level, text on stderr)
 //  3. Single-instance lock (prevent multiple daemon processes)
 //  4. App lifecycle: NewApp() → Initialize() → RunAndWait()
+//  5. "dabri daemon restart": release the lock and re-execute in place
 func runDaemonCobra(cmd *cobra.Command) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	debug, _ := cmd.Flags().GetBool("debug")
@@ -41,7 +46,9 @@ func runDaemonCobra(cmd *cobra.Command) error {
 	if debug {
 		logLevel = logger.DebugLevel
 	}
-	appLogger := logger.NewDefaultLogger(logLevel)
+	// Format and log file follow the config once it is loaded (see app.configureLogging)
+	appLogger := logger.NewStructuredLogger(logger.Options{Level: logLevel})
+	defer func() { _ = appLogger.Close() }()
 
 	// Log AppImage environment if detected
 	if appDir := os.Getenv("APPDIR"); appDir != "" {
@@ -81,5 +88,122 @@ func runDaemonCobra(cmd *cobra.Command) error {
 		appLogger.Error("Application error: %v", err)
 		return err
 	}
+	if application.RestartRequested() {
+		// The new process takes the lock itself; the deferred Unlock becomes a no-op
+		if err := lockFile.Unlock(); err != nil {
+			appLogger.Warning("Failed to release lock: %v", err)
+		}
+		env, err := application.RestartEnv()
+		if err != nil {
+			appLogger.Error("Failed to prepare restart: %v", err)
+			return err
+		}
+		return reexecDaemon(appLogger, env)
+	}
+	return nil
+}
+
+// reexecDaemon replaces the process with a fresh daemon using the same arguments
+// and env; the PID stays the same, so supervisors keep tracking it
+func reexecDaemon(appLogger logger.Logger, env []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable for restart: %w", err)
+	}
+	appLogger.Info("Restarting daemon: %s", executable)
+	// #nosec G204 - re-executes our own binary with its original arguments
+	if err := syscall.Exec(executable, os.Args, env); err != nil {
+		return fmt.Errorf("failed to restart daemon: %w", err)
+	}
+	return nil
+}
+
+// addDaemonCommand registers the "daemon" command tree for controlling the running daemon:
+//
+//	daemon stop    — shut down and wait until the process exits
+//	daemon restart — re-execute the daemon and wait until it is back
+//	daemon reload  — re-read the config file (same as SIGHUP)
+//	daemon pid     — print the daemon PID
+func addDaemonCommand(root *cobra.Command) {
+	daemonCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Control the running daemon (stop/restart/reload/pid)",
+	}
+
+	stopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonLifecycle(cmd, "daemon-stop")
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(stopCmd)
+
+	restartCmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the running daemon with its original arguments",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDaemonLifecycle(cmd, "daemon-restart")
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(restartCmd)
+
+	daemonCmd.AddCommand(
+		stopCmd,
+		restartCmd,
+		newIPCCommand("reload", "Re-read the config file without unloading the model", "daemon-reload"),
+		newIPCCommand("pid", "Print the daemon process ID", "daemon-pid"),
+	)
+	root.AddCommand(daemonCmd)
+}
+
+const daemonPollInterval = 100 * time.Millisecond
+
+// runDaemonLifecycle sends daemon-stop or daemon-restart and waits for the result:
+// stop until the instance lock is released, restart until a new instance answers
+func runDaemonLifecycle(cmd *cobra.Command, ipcCommand string) error {
+	socketPath, _ := cmd.Flags().GetString("socket")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	timeoutSec, _ := cmd.Flags().GetInt("timeout")
+	if socketPath == "" {
+		socketPath = utils.GetDefaultSocketPath()
+	}
+	name := commandKey(cmd)
+	timeout := deriveTimeout(name, timeoutSec)
+
+	resp, err := ipc.SendRequest(socketPath, ipc.Request{Command: ipcCommand}, timeout)
+	if err != nil {
+		return err
+	}
+	previous, _ := getString(mapFromResponse(resp.Data), "started_at")
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if ipcCommand == "daemon-stop" {
+			running, _, err := utils.NewLockFile(utils.GetDefaultLockPath()).CheckExistingInstance()
+			if err == nil && !running {
+				break
+			}
+		} else if current, err := ipc.SendRequest(socketPath, ipc.Request{Command: "daemon-pid"}, defaultStatusTimeout); err == nil {
+			// Until the old instance closes its socket it still answers with its own start time
+			if startedAt, _ := getString(mapFromResponse(current.Data), "started_at"); startedAt != previous {
+				resp = current
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for daemon to %s", cmd.Name())
+		}
+		time.Sleep(daemonPollInterval)
+	}
+
+	if jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(resp)
+	}
+	printResponse(name, resp)
 	return nil
 }
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/doctor"
+)
+
+// newDoctorCommand creates "doctor" which checks the environment without the daemon.
+// Exits non-zero when a check fails so it can gate scripts and bug reports
+func newDoctorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check hotkey, audio, typing and model prerequisites",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFile, _ := cmd.Flags().GetString("config")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			bundlePath, _ := cmd.Flags().GetString("bundle")
+
+			cfg, err := config.LoadConfig(configFile)
+			if err != nil {
+				return fmt.Errorf("failed to load config %s: %w", configFile, err)
+			}
+			d := doctor.New(cfg)
+			results := d.Run()
+
+			if jsonOutput {
+				if err := json.NewEncoder(os.Stdout).Encode(results); err != nil {
+					return err
+				}
+			} else {
+				printDoctorResults(results)
+			}
+			if bundlePath != "" {
+				if err := d.WriteBundle(bundlePath, results); err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Support bundle written to %s (review it before sharing)\n", bundlePath)
+			}
+			if doctor.Failed(results) {
+				return errors.New("some checks failed")
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	defaultConfigPath, err := config.ConfigFilePath()
+	if err != nil {
+		defaultConfigPath = "config.yaml"
+	}
+	cmd.Flags().String("config", defaultConfigPath, "Path to configuration file")
+	cmd.Flags().Bool("json", false, "Print results as JSON")
+	cmd.Flags().String("bundle", "", "Write a redacted support tarball (config, environment, recent logs)")
+	cmd.Flags().Lookup("bundle").NoOptDefVal = fmt.Sprintf("dabri-doctor-%s.tar.gz", time.Now().Format("20060102-150405"))
+	return cmd
+}
+
+// printDoctorResults prints one line per check with the fix hint below it
+func printDoctorResults(results []doctor.Result) {
+	labels := map[doctor.Status]string{doctor.StatusPass: "PASS", doctor.StatusWarn: "WARN", doctor.StatusFail: "FAIL"}
+	for _, r := range results {
+		fmt.Printf("[%s] %-24s %s\n", labels[r.Status], r.Name, r.Detail)
+		if r.Fix != "" && r.Status != doctor.StatusPass {
+			fmt.Printf("       %-24s fix: %s\n", "", r.Fix)
+		}
+	}
+}
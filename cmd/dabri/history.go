@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AshBuk/dabri/internal/history"
+)
+
+// addHistoryCommand registers the "history" command tree (IPC → daemon):
+//
+//	history list        — list recent transcripts
+//	history search <q>  — list transcripts containing the text
+//	history show <id>   — show a transcript with its metadata
+//	history copy <id>   — copy a transcript to the clipboard
+//	history type <id>   — type a transcript into the focused window
+//	history clear       — delete all recorded transcripts
+func addHistoryCommand(root *cobra.Command) {
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Browse and re-output past transcripts (list/search/show/copy/type/clear)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent transcripts, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "history-list", historyLimitParams(cmd))
+		},
+		SilenceUsage: true,
+	}
+	addHistoryLimitFlag(listCmd)
+
+	searchCmd := &cobra.Command{
+		Use:   "search <text>",
+		Short: "List transcripts containing the text (case-insensitive)",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := historyLimitParams(cmd)
+			params["query"] = strings.Join(args, " ")
+			return runIPCCommand(cmd, "history-search", params)
+		},
+		SilenceUsage: true,
+	}
+	addHistoryLimitFlag(searchCmd)
+
+	showCmd := newHistoryEntryCommand("show <id>", "Show a transcript with its metadata", "history-show")
+	copyCmd := newHistoryEntryCommand("copy <id>", "Copy a transcript to the clipboard", "history-copy")
+	typeCmd := newHistoryEntryCommand("type <id>", "Type a transcript into the focused window", "history-type")
+	typeCmd.Flags().Int("delay", 0, "Seconds to wait before typing, to focus the target window")
+
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all recorded transcripts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "history-clear", nil)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(clearCmd)
+
+	historyCmd.AddCommand(listCmd, searchCmd, showCmd, copyCmd, typeCmd, clearCmd)
+	root.AddCommand(historyCmd)
+}
+
+// newHistoryEntryCommand creates a subcommand acting on one transcript by ID
+func newHistoryEntryCommand(use, short, ipcCommand string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := strconv.ParseInt(args[0], 10, 64); err != nil {
+				return fmt.Errorf("invalid transcript id: %s (use 'dabri history list' to see ids)", args[0])
+			}
+			if delay, _ := cmd.Flags().GetInt("delay"); delay > 0 {
+				time.Sleep(time.Duration(delay) * time.Second)
+			}
+			return runIPCCommand(cmd, ipcCommand, map[string]string{"id": args[0]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	return cmd
+}
+
+func addHistoryLimitFlag(cmd *cobra.Command) {
+	addCLIFlags(cmd)
+	cmd.Flags().Int("limit", 20, "Maximum number of transcripts to show (0 for all)")
+}
+
+func historyLimitParams(cmd *cobra.Command) map[string]string {
+	limit, _ := cmd.Flags().GetInt("limit")
+	return map[string]string{"limit": strconv.Itoa(limit)}
+}
+
+// printHistoryEntries prints one line per transcript: id, time and a preview
+func printHistoryEntries(data map[string]any) {
+	entries, _ := data["entries"].([]any)
+	if !getBoolOr(data, "enabled", true) {
+		fmt.Fprintln(os.Stderr, "Transcript history is disabled (history.enabled: false).")
+	}
+	if len(entries) == 0 {
+		fmt.Println("No transcripts found.")
+		return
+	}
+	for _, item := range entries {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		text, _ := getString(entry, "text")
+		fmt.Printf("%5d  %s  %s\n", getIntOr(entry, "id", 0), formatHistoryTime(entry), history.Preview(text, 60))
+	}
+}
+
+// printHistoryEntry prints a transcript's metadata followed by its full text
+func printHistoryEntry(data map[string]any) {
+	entry := getMap(data, "entry")
+	if entry == nil {
+		fmt.Println("Transcript not found.")
+		return
+	}
+	fmt.Printf("ID:       %d\n", getIntOr(entry, "id", 0))
+	fmt.Printf("Time:     %s\n", formatHistoryTime(entry))
+	if ms := getIntOr(entry, "duration_ms", 0); ms > 0 {
+		fmt.Printf("Duration: %s\n", (time.Duration(ms) * time.Millisecond).Round(100*time.Millisecond))
+	}
+	if model, ok := getString(entry, "model"); ok && model != "" {
+		fmt.Printf("Model:    %s\n", model)
+	}
+	if lang, ok := getString(entry, "language"); ok && lang != "" {
+		fmt.Printf("Language: %s\n", lang)
+	}
+	if targets, _ := entry["targets"].([]any); len(targets) > 0 {
+		names := make([]string, 0, len(targets))
+		for _, t := range targets {
+			if name, ok := t.(string); ok {
+				names = append(names, name)
+			}
+		}
+		fmt.Printf("Output:   %s\n", strings.Join(names, ", "))
+	}
+	text, _ := getString(entry, "text")
+	fmt.Printf("\n%s\n", text)
+}
+
+// formatHistoryTime renders an entry timestamp in local time
+func formatHistoryTime(entry map[string]any) string {
+	raw, _ := getString(entry, "timestamp")
+	ts, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return raw
+	}
+	return ts.Local().Format("2006-01-02 15:04")
+}
@@ -17,6 +17,8 @@ import (
 //
 //	→ Root (no subcommand): daemon — app.NewApp() → Initialize() → RunAndWait()
 //	→ Subcommands (client): start/stop/toggle/status/transcript/model → IPC
+//	→ watch (client): subscribe → event stream until Ctrl+C
+//	→ daemon (client): stop/restart/reload/pid of the running daemon → IPC
 var rootCmd = &cobra.Command{
 	Use:   "dabri",
 	Short: "Linux STT",
@@ -35,6 +37,11 @@ func init() {
 	addDaemonFlags(rootCmd)
 	addCLICommands(rootCmd)
 	addModelCommand(rootCmd)
+	addHistoryCommand(rootCmd)
+	addConfigCommand(rootCmd)
+	addSettingsCommands(rootCmd)
+	addDaemonCommand(rootCmd)
+	addSystemdCommand(rootCmd)
 }
 
 func main() {
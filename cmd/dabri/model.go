@@ -83,7 +83,7 @@ func runModelMutation(cmd *cobra.Command, ipcCommand, modelID string) error {
 	if socketPath == "" {
 		socketPath = utils.GetDefaultSocketPath()
 	}
-	timeout := deriveTimeout(cmd.Name(), timeoutSec)
+	timeout := deriveTimeout(commandKey(cmd), timeoutSec)
 
 	req := ipc.Request{
 		Command: ipcCommand,
@@ -97,7 +97,7 @@ func runModelMutation(cmd *cobra.Command, ipcCommand, modelID string) error {
 	if jsonOutput {
 		return json.NewEncoder(os.Stdout).Encode(resp)
 	}
-	printResponse(cmd.Name(), resp)
+	printResponse(commandKey(cmd), resp)
 	return nil
 }
 
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/constants"
+)
+
+// hotkeyActions are the bindable actions, as in the tray "Rebind Hotkeys" menu
+var hotkeyActions = []string{"start_recording", "code_recording", "undo", "cancel", "show_config", "reset_to_defaults"}
+
+// addSettingsCommands registers the tray menu actions as commands (IPC → daemon),
+// so tray-less and headless setups can change the same settings:
+//
+//	language set <code>            — change the recognition language
+//	language list                  — list supported language codes
+//	output set <mode>              — switch the output mode
+//	recorder set <method>          — switch the recording backend
+//	notifications <on|off|toggle>  — workflow notifications
+//	hotkey bind <action> [combo]   — rebind a hotkey, capturing it when no combo is given
+//	hotkey list                    — print the current bindings
+//	reset                          — restore the default configuration
+//	log-level [level]              — print or change the daemon log level (not saved)
+func addSettingsCommands(root *cobra.Command) {
+	root.AddCommand(
+		newLanguageCommand(),
+		newSettingCommand("output", "mode", "set-output-mode", "Switch the output mode",
+			[]string{config.OutputModeClipboard, config.OutputModeActiveWindow, config.OutputModePaste, config.OutputModeFile, config.OutputModeWebhook}),
+		newSettingCommand("recorder", "method", "set-recorder", "Switch the recording backend",
+			[]string{"arecord", "ffmpeg"}),
+		newNotificationsCommand(),
+		newHotkeyCommand(),
+		newResetCommand(),
+		newLogLevelCommand(),
+	)
+}
+
+// newSettingCommand creates "<name> set <value>" sending value as param to ipcCommand
+func newSettingCommand(name, param, ipcCommand, short string, values []string) *cobra.Command {
+	parent := &cobra.Command{
+		Use:   name,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	setCmd := &cobra.Command{
+		Use:       fmt.Sprintf("set <%s>", strings.Join(values, "|")),
+		Short:     short,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: values,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, ipcCommand, map[string]string{param: args[0]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(setCmd)
+	parent.AddCommand(setCmd)
+	return parent
+}
+
+func newLanguageCommand() *cobra.Command {
+	languageCmd := &cobra.Command{
+		Use:   "language",
+		Short: "Change the recognition language (set/list)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	setCmd := &cobra.Command{
+		Use:   "set <code>",
+		Short: "Set the recognition language (ISO 639-1 code, e.g., de)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if constants.LanguageByCode(args[0]) == nil {
+				return fmt.Errorf("unsupported language: %s (use 'dabri language list' to see supported codes)", args[0])
+			}
+			return runIPCCommand(cmd, "set-language", map[string]string{"language": args[0]})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(setCmd)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List supported language codes",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			for _, lang := range constants.WhisperLanguages {
+				fmt.Printf("%-4s %s\n", lang.Code, lang.Name)
+			}
+		},
+	}
+	languageCmd.AddCommand(setCmd, listCmd)
+	return languageCmd
+}
+
+func newNotificationsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "notifications <on|off|toggle>",
+		Short:     "Enable, disable or toggle workflow notifications",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"on", "off", "toggle"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params map[string]string
+			switch args[0] {
+			case "on", "off":
+				params = map[string]string{"enabled": args[0]}
+			case "toggle":
+			default:
+				return fmt.Errorf("invalid argument: %s (must be on, off or toggle)", args[0])
+			}
+			return runIPCCommand(cmd, "set-notifications", params)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	return cmd
+}
+
+func newLogLevelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "log-level [debug|info|warning|error]",
+		Short:     "Print or change the daemon log level until it restarts",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{"debug", "info", "warning", "error"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var params map[string]string
+			if len(args) == 1 {
+				params = map[string]string{"level": args[0]}
+			}
+			return runIPCCommand(cmd, "log-level", params)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	return cmd
+}
+
+func newHotkeyCommand() *cobra.Command {
+	hotkeyCmd := &cobra.Command{
+		Use:   "hotkey",
+		Short: "Rebind hotkeys (bind/list)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	bindCmd := &cobra.Command{
+		Use:   "bind <action> [combo]",
+		Short: "Rebind a hotkey; without a combo, press the new keys",
+		Long: "Rebind a hotkey action (" + strings.Join(hotkeyActions, ", ") + ").\n\n" +
+			"Pass the combination explicitly, or omit it and press the new keys\n" +
+			"(Esc cancels). Capturing needs the evdev hotkey provider, for example:\n" +
+			"  dabri hotkey bind start_recording ctrl+alt+r\n" +
+			"  dabri hotkey bind undo",
+		Args:      cobra.RangeArgs(1, 2),
+		ValidArgs: hotkeyActions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := map[string]string{"action": args[0]}
+			if len(args) == 2 {
+				params["combo"] = args[1]
+			} else {
+				fmt.Fprintln(os.Stderr, "Press the new hotkey… (Esc to cancel)")
+			}
+			return runIPCCommand(cmd, "bind-hotkey", params)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(bindCmd)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Print the current hotkey bindings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIPCCommand(cmd, "config-list", map[string]string{"prefix": "hotkeys"})
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(listCmd)
+	hotkeyCmd.AddCommand(bindCmd, listCmd)
+	return hotkeyCmd
+}
+
+// newResetCommand creates "reset"; asks for confirmation when run interactively
+func newResetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Restore the default configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			yes, _ := cmd.Flags().GetBool("yes")
+			if !yes && stdinIsTerminal() && !confirm("Reset all settings to defaults?") {
+				return fmt.Errorf("aborted")
+			}
+			return runIPCCommand(cmd, "reset-to-defaults", nil)
+		},
+		SilenceUsage: true,
+	}
+	addCLIFlags(cmd)
+	cmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation")
+	return cmd
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirm asks a yes/no question on stderr; anything but y/yes declines
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AshBuk/dabri/internal/systemd"
+)
+
+// addSystemdCommand registers "systemd generate", which writes user unit files:
+//
+//	dabri.service — Type=notify daemon with watchdog and SIGHUP reload
+//	dabri.socket  — IPC socket that starts the daemon on the first CLI command
+func addSystemdCommand(root *cobra.Command) {
+	systemdCmd := &cobra.Command{
+		Use:   "systemd",
+		Short: "Run dabri as a systemd user service",
+	}
+
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate systemd user unit files (stdout, or --dir)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			noSocket, _ := cmd.Flags().GetBool("no-socket")
+			watchdog, _ := cmd.Flags().GetDuration("watchdog")
+
+			executable, err := daemonExecutable()
+			if err != nil {
+				return err
+			}
+			opts := systemd.UnitOptions{Executable: executable, Socket: !noSocket, Watchdog: watchdog}
+			if cmd.Flags().Changed("config") {
+				configFile, _ := cmd.Flags().GetString("config")
+				if configFile, err = filepath.Abs(configFile); err != nil {
+					return err
+				}
+				opts.Args = []string{"--config", configFile}
+			}
+
+			units := []unitFile{{systemd.ServiceUnitName, systemd.ServiceUnit(opts)}}
+			if opts.Socket {
+				units = append(units, unitFile{systemd.SocketUnitName, systemd.SocketUnit(opts)})
+			}
+			if dir == "" {
+				for i, unit := range units {
+					if i > 0 {
+						fmt.Println()
+					}
+					fmt.Printf("# %s\n%s", unit.name, unit.content)
+				}
+				return nil
+			}
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dir, err)
+			}
+			for _, unit := range units {
+				path := filepath.Join(dir, unit.name)
+				if err := os.WriteFile(path, []byte(unit.content), 0o644); err != nil { // #nosec G306 - unit files are not secret
+					return fmt.Errorf("failed to write %s: %w", path, err)
+				}
+				fmt.Printf("Wrote %s\n", path)
+			}
+			enable := systemd.ServiceUnitName
+			if opts.Socket {
+				enable = systemd.SocketUnitName
+			}
+			fmt.Printf("Activate with: systemctl --user daemon-reload && systemctl --user enable --now %s\n", enable)
+			return nil
+		},
+		SilenceUsage: true,
+	}
+	generateCmd.Flags().String("dir", "", "Write the units into this directory (e.g. ~/.config/systemd/user)")
+	generateCmd.Flags().String("config", "", "Configuration file the service passes to the daemon")
+	generateCmd.Flags().Bool("no-socket", false, "Skip dabri.socket (the daemon binds the IPC socket itself)")
+	generateCmd.Flags().Duration("watchdog", systemd.DefaultWatchdog, "WatchdogSec of the service (0 disables)")
+
+	systemdCmd.AddCommand(generateCmd)
+	root.AddCommand(systemdCmd)
+}
+
+// unitFile is one generated unit and its file name
+type unitFile struct {
+	name    string
+	content string
+}
+
+// daemonExecutable is the ExecStart binary: the AppImage itself when running
+// from one (the mounted path changes every launch), otherwise this binary
+func daemonExecutable() (string, error) {
+	if appImage := os.Getenv("APPIMAGE"); appImage != "" {
+		return appImage, nil
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the dabri binary: %w", err)
+	}
+	return filepath.EvalSymlinks(executable)
+}
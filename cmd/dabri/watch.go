@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/ipc"
+	"github.com/AshBuk/dabri/internal/utils"
+)
+
+// newWatchCommand creates "watch" which streams daemon events until interrupted.
+// Text output skips audio levels unless requested with --events
+func newWatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream recording, transcript and config events (Ctrl+C to stop)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			socketPath, _ := cmd.Flags().GetString("socket")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			filter, _ := cmd.Flags().GetString("events")
+			if socketPath == "" {
+				socketPath = utils.GetDefaultSocketPath()
+			}
+			if filter == "" && !jsonOutput {
+				filter = strings.Join(slices.DeleteFunc(slices.Clone(events.Types), func(t string) bool {
+					return t == events.AudioLevel
+				}), ",")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			encoder := json.NewEncoder(os.Stdout)
+			return ipc.Subscribe(ctx, socketPath, map[string]string{"events": filter}, func(ev events.Event) error {
+				if jsonOutput {
+					return encoder.Encode(ev)
+				}
+				fmt.Printf("%s  %s\n", ev.Time.Local().Format("15:04:05"), formatEvent(ev))
+				return nil
+			})
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().String("socket", "", "Path to IPC socket (defaults to user runtime path)")
+	cmd.Flags().Bool("json", false, "Print events as JSON lines")
+	cmd.Flags().String("events", "", "Comma-separated event types to show (default: all; text output omits audio_level)")
+	return cmd
+}
+
+// formatEvent renders an event as a one-line human-readable message
+func formatEvent(ev events.Event) string {
+	data := ev.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	switch ev.Type {
+	case events.RecordingStarted:
+		if mode, ok := getString(data, "mode"); ok && mode != "" {
+			return fmt.Sprintf("Recording started (%s mode)", mode)
+		}
+		return "Recording started"
+	case events.RecordingStopped:
+		if ms := getIntOr(data, "duration_ms", 0); ms > 0 {
+			return fmt.Sprintf("Recording stopped (%s)", (time.Duration(ms) * time.Millisecond).Round(100*time.Millisecond))
+		}
+		return "Recording stopped"
+	case events.AudioLevel:
+		level, _ := data["level"].(float64)
+		return fmt.Sprintf("Audio level: %.2f", level)
+	case events.Transcribing:
+		return "Transcribing..."
+	case events.RecordingCancelled:
+		return "Cancelled (nothing was output)"
+	case events.Transcript:
+		text, _ := getString(data, "text")
+		return "Transcript: " + text
+	case events.Error:
+		stage, _ := getString(data, "stage")
+		message, _ := getString(data, "message")
+		return fmt.Sprintf("Error (%s): %s", stage, message)
+	case events.ModelSwitched:
+		model, _ := getString(data, "model")
+		return "Model switched to: " + model
+	case events.ConfigChanged:
+		return "Config changed"
+	default:
+		return ev.Type
+	}
+}
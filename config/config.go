@@ -26,10 +26,46 @@ import (
 // This provides a convenient way to reference the configuration type without importing the models package directly.
 type Config = models.Config
 
+// OutputTarget is a type alias for an entry of the ordered output target list.
+type OutputTarget = models.OutputTarget
+
+// Profile is a type alias for a per-application override defined in the models package.
+type Profile = models.Profile
+
+// SessionOptions is a type alias for per-dictation overrides defined in the models package.
+type SessionOptions = models.SessionOptions
+
 const (
 	// Output mode constants, aliased from the models package for convenience.
 	OutputModeClipboard    = models.OutputModeClipboard
 	OutputModeActiveWindow = models.OutputModeActiveWindow
+	OutputModePaste        = models.OutputModePaste
+	OutputModeFile         = models.OutputModeFile
+	OutputModeWebhook      = models.OutputModeWebhook
+
+	OutputFailureContinue = models.OutputFailureContinue
+	OutputFailureStop     = models.OutputFailureStop
+
+	ClipboardSelectionClipboard = models.ClipboardSelectionClipboard
+	ClipboardSelectionPrimary   = models.ClipboardSelectionPrimary
+	ClipboardSelectionBoth      = models.ClipboardSelectionBoth
+
+	JournalFormatMarkdown = models.JournalFormatMarkdown
+	JournalFormatJSONL    = models.JournalFormatJSONL
+	JournalRotationDaily  = models.JournalRotationDaily
+	JournalRotationNone   = models.JournalRotationNone
+
+	// Post-processing constants, aliased from the models package for convenience.
+	PostProcessingDefault = models.PostProcessingDefault
+	PostProcessingCode    = models.PostProcessingCode
+
+	// Session output and task constants, aliased from the models package for convenience.
+	SessionOutputNone       = models.SessionOutputNone
+	SessionOutputClipboard  = models.SessionOutputClipboard
+	SessionOutputType       = models.SessionOutputType
+	SessionOutputStdoutOnly = models.SessionOutputStdoutOnly
+	TaskTranscribe          = models.TaskTranscribe
+	TaskTranslate           = models.TaskTranslate
 
 	// AppName is the application identifier used in paths.
 	AppName = "dabri"
@@ -50,6 +86,19 @@ func ConfigDir() (string, error) {
 	return filepath.Join(home, ".config", AppName), nil
 }
 
+// DataDir returns the XDG data directory for the application.
+// Returns $XDG_DATA_HOME/dabri or ~/.local/share/dabri
+func DataDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, AppName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", AppName), nil
+}
+
 // ConfigFilePath returns the default configuration file path.
 // Returns $XDG_CONFIG_HOME/dabri/config.yaml or ~/.config/dabri/config.yaml
 func ConfigFilePath() (string, error) {
@@ -92,6 +141,11 @@ func ValidateConfig(config *Config) error {
 	return validators.ValidateConfig(config)
 }
 
+// Reject unsupported per-dictation overrides.
+func ValidateSessionOptions(opts SessionOptions) error {
+	return validators.ValidateSessionOptions(opts)
+}
+
 // Check if a command is permitted by the security policy.
 func IsCommandAllowed(config *Config, command string) bool {
 	return security.IsCommandAllowed(config, command)
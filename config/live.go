@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Live holds the running configuration as an immutable snapshot.
+// Readers call Get and must not modify the result; writers go through Update,
+// which publishes a new snapshot so a reader never sees a half-applied change.
+type Live struct {
+	mu      sync.Mutex // Serializes writers
+	current atomic.Pointer[Config]
+}
+
+// NewLive publishes cfg as the first snapshot; the caller must stop modifying it.
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.current.Store(cfg)
+	return l
+}
+
+// Get returns the current snapshot.
+func (l *Live) Get() *Config {
+	return l.current.Load()
+}
+
+// Update applies change to a copy of the current snapshot and publishes it
+// when change succeeds. Concurrent updates are serialized, so change always
+// starts from the latest snapshot. Returns the replaced and the new snapshot.
+func (l *Live) Update(change func(next *Config) error) (prev, next *Config, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	prev = l.current.Load()
+	next, err = Clone(prev)
+	if err != nil {
+		return prev, nil, fmt.Errorf("failed to copy config: %w", err)
+	}
+	if err := change(next); err != nil {
+		return prev, nil, err
+	}
+	l.current.Store(next)
+	return prev, next, nil
+}
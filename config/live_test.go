@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestLive_UpdatePublishesNewSnapshot(t *testing.T) {
+	initial := &Config{}
+	initial.General.Language = "en"
+	live := NewLive(initial)
+
+	prev, next, err := live.Update(func(next *Config) error {
+		next.General.Language = "de"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if prev != initial || live.Get() != next {
+		t.Fatal("Update must return the replaced snapshot and publish the new one")
+	}
+	if initial.General.Language != "en" {
+		t.Errorf("published snapshot was modified: language %q", initial.General.Language)
+	}
+	if got := live.Get().General.Language; got != "de" {
+		t.Errorf("language = %q, want de", got)
+	}
+}
+
+func TestLive_FailedUpdateKeepsSnapshot(t *testing.T) {
+	initial := &Config{}
+	live := NewLive(initial)
+	wantErr := errors.New("rejected")
+
+	_, _, err := live.Update(func(next *Config) error {
+		next.General.Language = "de"
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Update error = %v, want %v", err, wantErr)
+	}
+	if live.Get() != initial || initial.General.Language != "" {
+		t.Error("a failed update must leave the snapshot untouched")
+	}
+}
+
+func TestLive_ConcurrentUpdatesAreSerialized(t *testing.T) {
+	live := NewLive(&Config{})
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = live.Update(func(next *Config) error {
+				next.Audio.SampleRate++
+				return nil
+			})
+			_ = live.Get().Audio.SampleRate
+		}()
+	}
+	wg.Wait()
+	if got := live.Get().Audio.SampleRate; got != 20 {
+		t.Errorf("sample rate = %d, want 20 (lost update)", got)
+	}
+}
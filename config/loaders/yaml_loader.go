@@ -59,6 +59,7 @@ func SetDefaultConfig(config *models.Config) {
 	config.General.WhisperModel = "small-q5_1" // Fixed whisper model
 	config.General.TempAudioPath = "/tmp"
 	config.General.Language = "en" // Default to English
+	config.General.PostProcessing = models.PostProcessingDefault
 
 	// Hotkey settings
 	config.Hotkeys.Provider = "auto"
@@ -66,6 +67,9 @@ func SetDefaultConfig(config *models.Config) {
 	config.Hotkeys.StopRecording = "alt+r"   // Same combination for start/stop
 	config.Hotkeys.ShowConfig = "alt+c"      // Show config
 	config.Hotkeys.ResetToDefaults = "alt+d" // Reset to defaults
+	config.Hotkeys.CodeRecording = ""        // Code dictation hotkey disabled by default
+	config.Hotkeys.Undo = ""                 // Undo hotkey disabled by default
+	config.Hotkeys.Cancel = ""               // Cancel hotkey disabled by default
 
 	// Audio settings
 	config.Audio.Device = "default"
@@ -80,10 +84,32 @@ func SetDefaultConfig(config *models.Config) {
 	config.Output.DefaultMode = models.OutputModeActiveWindow
 	config.Output.ClipboardTool = "auto" // auto-detect
 	config.Output.TypeTool = "auto"      // auto-detect
+	config.Output.TypeDelayMs = 0        // Typing tool default
+	config.Output.TypeChunkSize = 100
+	config.Output.TypeToOriginWindow = false    // Type into whatever has focus when the transcript is ready
+	config.Output.UndoWithoutFocusCheck = false // Refuse to erase when focus cannot be verified
+	config.Output.ClipboardSelection = models.ClipboardSelectionClipboard
+	config.Output.PasteKeys = "ctrl+v"
+	config.Output.PasteRestoreDelayMs = 500
+	config.Output.ClipboardRestoreSeconds = 0 // Keep the transcript on the clipboard
+	config.Output.Journal.Enabled = false
+	config.Output.Journal.Directory = "" // $XDG_DATA_HOME/dabri/journal
+	config.Output.Journal.Format = models.JournalFormatMarkdown
+	config.Output.Journal.Rotation = models.JournalRotationDaily
+	config.Output.Webhook.URL = "" // Disabled until configured
+	config.Output.Webhook.TimeoutSeconds = 5
+	config.Output.Webhook.MaxRetries = 3
+	config.Output.Webhook.QueueSize = 100
 
 	// Notification settings
 	config.Notifications.EnableWorkflowNotifications = true // Enable workflow notifications by default
 
+	// Transcript history settings
+	config.History.Enabled = true
+	config.History.MaxEntries = 500
+	config.History.MaxAgeDays = 30
+	config.History.MaxSizeKB = 1024
+
 	// Web server settings (disabled by default for security)
 	config.WebServer.Enabled = false
 	config.WebServer.Port = 8080
@@ -94,8 +120,21 @@ func SetDefaultConfig(config *models.Config) {
 	config.WebServer.CORSOrigins = "*" // Allow all origins by default
 	config.WebServer.MaxClients = 10
 
+	// Metrics settings (not served by default)
+	config.Metrics.Enabled = false
+	config.Metrics.Socket = ""
+
+	// Logging settings (text to stderr only by default)
+	config.Logging.Format = models.LogFormatText
+	config.Logging.File = ""
+	config.Logging.MaxSizeMB = 10
+	config.Logging.MaxBackups = 3
+
+	// Per-application profiles (none by default)
+	config.Profiles = nil
+
 	// Security settings
-	config.Security.AllowedCommands = []string{"arecord", "ffmpeg", "whisper", "xdotool", "wtype", "ydotool", "wl-copy", "wl-paste", "xsel", "notify-send", "xdg-open"}
+	config.Security.AllowedCommands = []string{"arecord", "ffmpeg", "whisper", "xdotool", "wtype", "ydotool", "dotool", "wlrctl", "wl-copy", "wl-paste", "xsel", "notify-send", "xdg-open", "swaymsg", "hyprctl", "kdotool"}
 	config.Security.CheckIntegrity = false
 	config.Security.ConfigHash = ""
 	config.Security.MaxTempFileSize = 50 * 1024 * 1024 // 50MB by default
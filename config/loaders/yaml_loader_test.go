@@ -64,6 +64,9 @@ hotkeys:
 				if cfg.Output.DefaultMode != "clipboard" {
 					t.Errorf("expected default mode to be 'clipboard', got %s", cfg.Output.DefaultMode)
 				}
+				if cfg.Output.TypeToOriginWindow {
+					t.Errorf("expected type_to_origin_window to be off by default")
+				}
 			},
 		},
 		{
@@ -8,8 +8,129 @@ package models
 const (
 	OutputModeClipboard    = "clipboard"     // Copy text to the clipboard
 	OutputModeActiveWindow = "active_window" // Type text into the currently active window
+	OutputModePaste        = "paste"         // Paste text via the clipboard, then restore the previous clipboard
+	OutputModeFile         = "file"          // Append text to a journal file
+	OutputModeWebhook      = "webhook"       // POST text as JSON to a configured URL
 )
 
+// OutputFailure constants define what happens to the remaining output targets
+// when one target fails.
+const (
+	OutputFailureContinue = "continue" // Record the failure and deliver to the remaining targets
+	OutputFailureStop     = "stop"     // Skip the remaining targets and report the dictation as failed
+)
+
+// ClipboardSelection constants define which X11/Wayland selections clipboard output writes.
+// PRIMARY is the selection pasted with a middle click.
+const (
+	ClipboardSelectionClipboard = "clipboard" // CLIPBOARD selection (Ctrl+V)
+	ClipboardSelectionPrimary   = "primary"   // PRIMARY selection (middle click)
+	ClipboardSelectionBoth      = "both"      // Both selections
+)
+
+// LogFormat constants define how daemon log lines are rendered.
+const (
+	LogFormatText = "text" // key=value pairs
+	LogFormatJSON = "json" // One JSON object per line
+)
+
+// Journal constants define the file output formats and rotation policies.
+const (
+	JournalFormatMarkdown = "markdown"
+	JournalFormatJSONL    = "jsonl"
+	JournalRotationDaily  = "daily" // One file per day (e.g., 2025-01-31.md)
+	JournalRotationNone   = "none"  // Single file (journal.md)
+)
+
+// PostProcessing constants define how a raw transcript is cleaned up before output.
+const (
+	PostProcessingDefault = "default" // Strip whisper placeholders and normalize whitespace
+	PostProcessingCode    = "code"    // Code dictation: symbol words and identifier casing
+)
+
+// SessionOutput constants define where the transcript of a single dictation goes.
+const (
+	SessionOutputNone       = "none"        // Deliver to no target; the transcript is still kept in history
+	SessionOutputClipboard  = "clipboard"   // Copy to the clipboard instead of the configured targets
+	SessionOutputType       = "type"        // Type into the active window instead of the configured targets
+	SessionOutputStdoutOnly = "stdout-only" // Return the transcript to the requesting client only, without history
+)
+
+// Task constants define what whisper does with the recorded speech.
+const (
+	TaskTranscribe = "transcribe" // Transcribe in the spoken language
+	TaskTranslate  = "translate"  // Translate the speech to English
+)
+
+// TypeToolAuto selects the best typing tool for the session.
+const TypeToolAuto = "auto"
+
+// TypingTools lists the type_tool names besides "auto": the typing tools registered in
+// output/outputters (which cannot be imported here) and the built-in "uinput" typer.
+var TypingTools = []string{"xdotool", "wtype", "wlrctl", "ydotool", "dotool", "kdotool", "uinput"}
+
+// OutputTarget is one destination in the ordered output target list.
+type OutputTarget struct {
+	Mode      string `yaml:"mode" json:"mode"`             // "clipboard", "active_window", "paste", "file" or "webhook"
+	OnFailure string `yaml:"on_failure" json:"on_failure"` // "continue" (default) or "stop"
+	Fallback  string `yaml:"fallback" json:"fallback"`     // Mode tried for this dictation only if the target fails (empty for none)
+}
+
+// Profile overrides dictation settings for windows matching its class or title pattern.
+// Empty override fields inherit the global configuration.
+type Profile struct {
+	Name           string `yaml:"name" json:"name"`                       // Human-readable profile name used in logs
+	WindowClass    string `yaml:"window_class" json:"window_class"`       // Regex matched against the focused window class (e.g., "(?i)kitty|alacritty")
+	WindowTitle    string `yaml:"window_title" json:"window_title"`       // Regex matched against the focused window title
+	OutputMode     string `yaml:"output_mode" json:"output_mode"`         // Output mode override: "clipboard", "active_window", "paste", "file" or "webhook"
+	Language       string `yaml:"language" json:"language"`               // Recognition language override (e.g., "de")
+	PostProcessing string `yaml:"post_processing" json:"post_processing"` // Post-processing override: "default" or "code"
+	TypeTool       string `yaml:"type_tool" json:"type_tool"`             // Typing tool override (e.g., "wtype")
+	PasteKeys      string `yaml:"paste_keys" json:"paste_keys"`           // Paste mode key override (e.g., "ctrl+shift+v" for terminals)
+}
+
+// SessionOptions overrides settings for a single dictation, e.g. `dabri start --lang de --output none`.
+// Empty fields inherit the matched profile and the global configuration.
+type SessionOptions struct {
+	Language       string `json:"language,omitempty"`        // Recognition language (e.g., "de")
+	Output         string `json:"output,omitempty"`          // "none", "clipboard", "type" or "stdout-only"
+	PostProcessing string `json:"post_processing,omitempty"` // "default" or "code"
+	Task           string `json:"task,omitempty"`            // "transcribe" or "translate"
+}
+
+// Merge returns the options with the non-empty fields of override applied
+func (o SessionOptions) Merge(override SessionOptions) SessionOptions {
+	if override.Language != "" {
+		o.Language = override.Language
+	}
+	if override.Output != "" {
+		o.Output = override.Output
+	}
+	if override.PostProcessing != "" {
+		o.PostProcessing = override.PostProcessing
+	}
+	if override.Task != "" {
+		o.Task = override.Task
+	}
+	return o
+}
+
+// OutputMode returns the output mode replacing the configured targets, or "" for none
+func (o SessionOptions) OutputMode() string {
+	switch o.Output {
+	case SessionOutputClipboard:
+		return OutputModeClipboard
+	case SessionOutputType:
+		return OutputModeActiveWindow
+	}
+	return ""
+}
+
+// SkipsOutput reports whether the transcript is delivered to no output target
+func (o SessionOptions) SkipsOutput() bool {
+	return o.Output == SessionOutputNone || o.Output == SessionOutputStdoutOnly
+}
+
 // Config defines the application's configuration structure, organized into logical groups.
 // It uses YAML tags for serialization and deserialization.
 type Config struct {
@@ -18,6 +139,8 @@ type Config struct {
 		WhisperModel  string `yaml:"whisper_model"`   // The specific Whisper model to use (e.g., "small-q5_1")
 		TempAudioPath string `yaml:"temp_audio_path"` // Directory to store temporary audio files
 		Language      string `yaml:"language"`        // Language for speech recognition (e.g., "en", "ru")
+		// Transcript post-processing: "default" or "code" (symbol words, identifier casing)
+		PostProcessing string `yaml:"post_processing"`
 	} `yaml:"general"`
 
 	Hotkeys struct {
@@ -27,6 +150,9 @@ type Config struct {
 		StopRecording   string `yaml:"stop_recording"`
 		ShowConfig      string `yaml:"show_config"`
 		ResetToDefaults string `yaml:"reset_to_defaults"`
+		CodeRecording   string `yaml:"code_recording"` // Start/stop recording in code dictation mode (empty to disable)
+		Undo            string `yaml:"undo"`           // Erase the last typed output or restore the clipboard (empty to disable)
+		Cancel          string `yaml:"cancel"`         // Discard the recording or pending transcription (empty to disable)
 	} `yaml:"hotkeys"`
 
 	Audio struct {
@@ -40,15 +166,64 @@ type Config struct {
 	} `yaml:"audio"`
 
 	Output struct {
-		DefaultMode   string `yaml:"default_mode"`   // Default output mode: "clipboard" or "active_window"
+		DefaultMode   string `yaml:"default_mode"`   // Default output mode: "clipboard", "active_window", "paste", "file" or "webhook"
 		ClipboardTool string `yaml:"clipboard_tool"` // Tool for clipboard operations (e.g., "wl-copy", "xsel"). "auto" for detection
-		TypeTool      string `yaml:"type_tool"`      // Tool for typing text (e.g., "xdotool", "wtype"). "auto" for detection
+		TypeTool      string `yaml:"type_tool"`      // Tool for typing text (e.g., "xdotool", "wtype", "uinput" built-in). "auto" for detection
+		// Typing: delay between key strokes in milliseconds (0 uses the typing tool's default)
+		TypeDelayMs int `yaml:"type_delay_ms"`
+		// Typing: characters per typing command; long texts are typed in chunks (0 types all at once)
+		TypeChunkSize int `yaml:"type_chunk_size"`
+		// Typing: refocus the window that was focused when recording started, so text
+		// does not land in another app (off by default); unsupported sessions (GNOME Wayland)
+		// type into the focused window
+		TypeToOriginWindow bool `yaml:"type_to_origin_window"`
+		// Undo: erase typed text even when the focused window cannot be verified
+		// (GNOME Wayland); the BackSpaces go to whatever has focus
+		UndoWithoutFocusCheck bool `yaml:"undo_without_focus_check"`
+		// Selections written by clipboard output: "clipboard", "primary" (middle-click paste) or "both"
+		ClipboardSelection string `yaml:"clipboard_selection"`
+		// Paste mode: key combination sent to paste ("ctrl+v", "shift+insert", "ctrl+shift+v" for terminals)
+		PasteKeys string `yaml:"paste_keys"`
+		// Paste mode: delay before restoring the previous clipboard in milliseconds (0 keeps the transcript)
+		PasteRestoreDelayMs int `yaml:"paste_restore_delay_ms"`
+		// Clipboard mode: restore the clipboard from before the recording after N seconds (0 keeps the transcript)
+		ClipboardRestoreSeconds int `yaml:"clipboard_restore_seconds"`
+
+		// Ordered output targets (e.g., type, then clipboard, then file). Empty uses
+		// default_mode with its built-in fallback (typing <-> clipboard)
+		Targets []OutputTarget `yaml:"targets"`
+
+		// Journal file output, used by default_mode "file" or as a secondary target
+		Journal struct {
+			Enabled   bool   `yaml:"enabled"`   // Also append transcripts to the journal when default_mode is not "file"
+			Directory string `yaml:"directory"` // Journal directory ("~" is expanded). Empty uses $XDG_DATA_HOME/dabri/journal
+			Format    string `yaml:"format"`    // "markdown" or "jsonl"
+			Rotation  string `yaml:"rotation"`  // "daily" or "none"
+		} `yaml:"journal"`
+
+		// Webhook output: each transcript is POSTed as JSON to a local service
+		Webhook struct {
+			URL            string `yaml:"url"`             // Endpoint (http or https), e.g. "http://127.0.0.1:8123/api/webhook/dictation"
+			Secret         string `yaml:"secret"`          // Optional HMAC-SHA256 key; signature sent in the X-Dabri-Signature header
+			TimeoutSeconds int    `yaml:"timeout_seconds"` // Per-request timeout (default: 5)
+			MaxRetries     int    `yaml:"max_retries"`     // Retries with exponential backoff after the first attempt (default: 3)
+			QueueSize      int    `yaml:"queue_size"`      // Undelivered transcripts kept on disk for a later retry (0 disables)
+			AllowRemote    bool   `yaml:"allow_remote"`    // Allow a url outside of localhost and private networks
+		} `yaml:"webhook"`
 	} `yaml:"output"`
 
 	Notifications struct {
 		EnableWorkflowNotifications bool `yaml:"enable_workflow_notifications"` // If true, show notifications for events like "Recording started"
 	} `yaml:"notifications"`
 
+	// Transcript history in $XDG_DATA_HOME/dabri/history.jsonl for search and re-output
+	History struct {
+		Enabled    bool `yaml:"enabled"`      // Record transcripts; disable for privacy ("dabri history clear" wipes existing entries)
+		MaxEntries int  `yaml:"max_entries"`  // Oldest entries are dropped beyond this count (0 for no limit)
+		MaxAgeDays int  `yaml:"max_age_days"` // Entries older than this are dropped (0 keeps them)
+		MaxSizeKB  int  `yaml:"max_size_kb"`  // Oldest entries are dropped while the file exceeds this size (0 for no limit)
+	} `yaml:"history"`
+
 	WebServer struct {
 		Enabled     bool   `yaml:"enabled"`
 		Port        int    `yaml:"port"`
@@ -60,6 +235,22 @@ type Config struct {
 		MaxClients  int    `yaml:"max_clients"`  // Maximum number of concurrent WebSocket clients
 	} `yaml:"web_server"`
 
+	// Prometheus metrics: /metrics on the web server, or a Unix socket while it is disabled
+	Metrics struct {
+		Enabled bool   `yaml:"enabled"` // Opt-in; counters are kept either way but not served
+		Socket  string `yaml:"socket"`  // Unix socket used without the web server (empty for $XDG_RUNTIME_DIR/dabri-metrics.sock)
+	} `yaml:"metrics"`
+
+	Logging struct {
+		Format     string `yaml:"format"`      // Log line format: "text" (key=value) or "json"
+		File       string `yaml:"file"`        // Also write logs to this absolute path (empty for stderr only)
+		MaxSizeMB  int    `yaml:"max_size_mb"` // Rotate the log file beyond this size
+		MaxBackups int    `yaml:"max_backups"` // Rotated log files kept (file.1 … file.N)
+	} `yaml:"logging"`
+
+	// Per-application overrides, evaluated in order against the focused window at recording start
+	Profiles []Profile `yaml:"profiles"`
+
 	Security struct {
 		AllowedCommands []string `yaml:"allowed_commands"`   // Whitelist of external commands the application is allowed to execute
 		CheckIntegrity  bool     `yaml:"check_integrity"`    // If true, verify the config file's hash on startup
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrUnknownKey is returned for a dotted path that names no setting
+var ErrUnknownKey = errors.New("unknown config key")
+
+// secretKeys are masked when settings are listed
+var secretKeys = map[string]bool{
+	"web_server.auth_token": true,
+	"output.webhook.secret": true,
+}
+
+// IsSecretKey reports whether the setting holds a credential
+func IsSecretKey(path string) bool {
+	return secretKeys[path]
+}
+
+// Keys returns the dotted path of every setting (e.g., "audio.device") in
+// file order. Lists such as output.targets are a single setting
+func Keys() []string {
+	var keys []string
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name := yamlName(field)
+			if name == "" {
+				continue
+			}
+			if field.Type.Kind() == reflect.Struct {
+				walk(field.Type, prefix+name+".")
+				continue
+			}
+			keys = append(keys, prefix+name)
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return keys
+}
+
+// GetValue returns the setting at a dotted path
+func GetValue(cfg *Config, path string) (any, error) {
+	field, err := lookupField(cfg, path)
+	if err != nil {
+		return nil, err
+	}
+	return field.Interface(), nil
+}
+
+// SetValue changes the setting at a dotted path. Strings are taken verbatim;
+// numbers, booleans and lists are parsed as YAML ("8080", "true", "[xsel, wl-copy]")
+func SetValue(cfg *Config, path, value string) error {
+	field, err := lookupField(cfg, path)
+	if err != nil {
+		return err
+	}
+	if field.Kind() == reflect.String {
+		field.SetString(value)
+		return nil
+	}
+	parsed := reflect.New(field.Type())
+	if err := yaml.UnmarshalStrict([]byte(value), parsed.Interface()); err != nil {
+		return fmt.Errorf("invalid value for %s (%s expected): %w", path, describeType(field.Type()), err)
+	}
+	field.Set(parsed.Elem())
+	return nil
+}
+
+// UnsetValue restores the default of the setting at a dotted path
+func UnsetValue(cfg *Config, path string) error {
+	field, err := lookupField(cfg, path)
+	if err != nil {
+		return err
+	}
+	defaults := &Config{}
+	SetDefaultConfig(defaults)
+	def, _ := lookupField(defaults, path)
+	field.Set(def)
+	return nil
+}
+
+// ChangedKeys returns the dotted paths of the settings that differ between
+// two configurations, in file order. Empty and missing lists are equal
+func ChangedKeys(old, next *Config) []string {
+	var changed []string
+	for _, key := range Keys() {
+		a, _ := lookupField(old, key)
+		b, _ := lookupField(next, key)
+		switch a.Kind() {
+		case reflect.Slice, reflect.Map:
+			if a.Len() == 0 && b.Len() == 0 {
+				continue
+			}
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// Clone returns a deep copy of the configuration
+func Clone(cfg *Config) (*Config, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Config{}
+	if err := yaml.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// lookupField resolves a dotted path of YAML names to a settable leaf field
+func lookupField(cfg *Config, path string) (reflect.Value, error) {
+	value := reflect.ValueOf(cfg).Elem()
+	parts := strings.Split(strings.TrimSpace(path), ".")
+	for i, part := range parts {
+		if value.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+		next, ok := fieldByYAMLName(value, part)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w: %s", ErrUnknownKey, path)
+		}
+		// Sections are not settings; "audio" alone cannot be read or set
+		if next.Kind() == reflect.Struct && i == len(parts)-1 {
+			return reflect.Value{}, fmt.Errorf("%w: %s is a section (try 'dabri config list %s')", ErrUnknownKey, path, path)
+		}
+		value = next
+	}
+	return value, nil
+}
+
+func fieldByYAMLName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := range t.NumField() {
+		if yamlName(t.Field(i)) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func yamlName(field reflect.StructField) string {
+	tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if tag == "-" {
+		return ""
+	}
+	return tag
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	case reflect.Slice:
+		return "YAML list"
+	default:
+		return t.Kind().String()
+	}
+}
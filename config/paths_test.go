@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	for _, want := range []string{"general.whisper_model", "audio.device", "output.type_tool", "output.webhook.secret", "output.targets", "web_server.port", "profiles"} {
+		if !slices.Contains(keys, want) {
+			t.Errorf("Keys() missing %s", want)
+		}
+	}
+	if slices.Contains(keys, "output.webhook") {
+		t.Error("sections must not be listed as keys")
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		path    string
+		value   string
+		want    any
+		wantErr bool
+	}{
+		{"audio.device", "hw:1,0", "hw:1,0", false},
+		{"web_server.port", "9090", 9090, false},
+		{"web_server.enabled", "true", true, false},
+		{"output.type_delay_ms", "fast", nil, true},
+		{"security.allowed_commands", "[xsel, wtype]", []string{"xsel", "wtype"}, false},
+		{"output.targets", "[{mode: clipboard, on_failure: stop}]", []OutputTarget{{Mode: "clipboard", OnFailure: "stop"}}, false},
+		{"output.targets", "[{mode: clipboard, typo: x}]", nil, true},
+		{"audio.missing", "x", nil, true},
+		{"audio", "x", nil, true},
+		{"audio.device.name", "x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+"="+tt.value, func(t *testing.T) {
+			cfg := &Config{}
+			SetDefaultConfig(cfg)
+			err := SetValue(cfg, tt.path, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue: %v", err)
+			}
+			got, err := GetValue(cfg, tt.path)
+			if err != nil {
+				t.Fatalf("GetValue: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetValue = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	cfg := &Config{}
+	if _, err := GetValue(cfg, "nope.key"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestUnsetValueAndClone(t *testing.T) {
+	cfg := &Config{}
+	SetDefaultConfig(cfg)
+	if err := SetValue(cfg, "web_server.port", "1234"); err != nil {
+		t.Fatal(err)
+	}
+	clone, err := Clone(cfg)
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if err := UnsetValue(cfg, "web_server.port"); err != nil {
+		t.Fatalf("UnsetValue: %v", err)
+	}
+	defaults := &Config{}
+	SetDefaultConfig(defaults)
+	if cfg.WebServer.Port != defaults.WebServer.Port {
+		t.Errorf("port = %d, want default %d", cfg.WebServer.Port, defaults.WebServer.Port)
+	}
+	if clone.WebServer.Port != 1234 {
+		t.Errorf("clone changed with the original: port = %d", clone.WebServer.Port)
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	old := &Config{}
+	SetDefaultConfig(old)
+	next, err := Clone(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := ChangedKeys(old, next); len(changed) != 0 {
+		t.Errorf("ChangedKeys of a clone = %v, want none", changed)
+	}
+
+	next.Audio.Device = "hw:1,0"
+	next.WebServer.Port = old.WebServer.Port + 1
+	next.Security.AllowedCommands = append(next.Security.AllowedCommands, "xsel")
+	old.Profiles, next.Profiles = nil, []Profile{}
+	want := []string{"audio.device", "web_server.port", "security.allowed_commands"}
+	if changed := ChangedKeys(old, next); !reflect.DeepEqual(changed, want) {
+		t.Errorf("ChangedKeys = %v, want %v", changed, want)
+	}
+}
@@ -5,8 +5,11 @@ package validators
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/AshBuk/dabri/config/models"
@@ -29,6 +32,14 @@ func validateGeneralConfig(config *models.Config, errors *[]string) {
 			*errors = append(*errors, "suspicious temp audio path sanitized to /tmp")
 		}
 	}
+
+	// Empty means the field predates post-processing modes; fall back silently
+	if config.General.PostProcessing == "" {
+		config.General.PostProcessing = models.PostProcessingDefault
+	} else if !isValidPostProcessing(config.General.PostProcessing) {
+		*errors = append(*errors, fmt.Sprintf("invalid post_processing: %s, using '%s'", config.General.PostProcessing, models.PostProcessingDefault))
+		config.General.PostProcessing = models.PostProcessingDefault
+	}
 }
 
 // validateAudioConfig validates audio configuration settings
@@ -56,6 +67,185 @@ func validateAudioConfig(config *models.Config, errors *[]string) {
 	}
 }
 
+// validateOutputConfig validates output configuration settings
+func validateOutputConfig(config *models.Config, errors *[]string) {
+	if config.Output.PasteKeys == "" {
+		config.Output.PasteKeys = "ctrl+v"
+	} else if !isValidPasteKeys(config.Output.PasteKeys) {
+		*errors = append(*errors, fmt.Sprintf("invalid paste_keys: %s, correcting to 'ctrl+v'", config.Output.PasteKeys))
+		config.Output.PasteKeys = "ctrl+v"
+	}
+	// Restore delay is capped so the saved clipboard is not held indefinitely
+	if config.Output.PasteRestoreDelayMs < 0 || config.Output.PasteRestoreDelayMs > 10000 {
+		*errors = append(*errors, fmt.Sprintf("invalid paste_restore_delay_ms: %d, correcting to 500", config.Output.PasteRestoreDelayMs))
+		config.Output.PasteRestoreDelayMs = 500
+	}
+	// Typing is capped so a long transcript cannot block output for minutes
+	if config.Output.TypeDelayMs < 0 || config.Output.TypeDelayMs > 1000 {
+		*errors = append(*errors, fmt.Sprintf("invalid type_delay_ms: %d, correcting to 0", config.Output.TypeDelayMs))
+		config.Output.TypeDelayMs = 0
+	}
+	if config.Output.TypeChunkSize < 0 || config.Output.TypeChunkSize > 10000 {
+		*errors = append(*errors, fmt.Sprintf("invalid type_chunk_size: %d, correcting to 100", config.Output.TypeChunkSize))
+		config.Output.TypeChunkSize = 100
+	}
+	switch config.Output.ClipboardSelection {
+	case models.ClipboardSelectionClipboard, models.ClipboardSelectionPrimary, models.ClipboardSelectionBoth:
+	case "":
+		config.Output.ClipboardSelection = models.ClipboardSelectionClipboard
+	default:
+		*errors = append(*errors, fmt.Sprintf("invalid clipboard_selection: %s, correcting to '%s'", config.Output.ClipboardSelection, models.ClipboardSelectionClipboard))
+		config.Output.ClipboardSelection = models.ClipboardSelectionClipboard
+	}
+	if config.Output.ClipboardRestoreSeconds < 0 || config.Output.ClipboardRestoreSeconds > 3600 {
+		*errors = append(*errors, fmt.Sprintf("invalid clipboard_restore_seconds: %d, correcting to 0", config.Output.ClipboardRestoreSeconds))
+		config.Output.ClipboardRestoreSeconds = 0
+	}
+
+	validateOutputTargets(config, errors)
+	validateWebhookConfig(config, errors)
+
+	journal := &config.Output.Journal
+	switch journal.Format {
+	case models.JournalFormatMarkdown, models.JournalFormatJSONL:
+	case "":
+		journal.Format = models.JournalFormatMarkdown
+	default:
+		*errors = append(*errors, fmt.Sprintf("invalid journal format: %s, correcting to '%s'", journal.Format, models.JournalFormatMarkdown))
+		journal.Format = models.JournalFormatMarkdown
+	}
+	switch journal.Rotation {
+	case models.JournalRotationDaily, models.JournalRotationNone:
+	case "":
+		journal.Rotation = models.JournalRotationDaily
+	default:
+		*errors = append(*errors, fmt.Sprintf("invalid journal rotation: %s, correcting to '%s'", journal.Rotation, models.JournalRotationDaily))
+		journal.Rotation = models.JournalRotationDaily
+	}
+	if journal.Directory != "" {
+		// Sanitize path to prevent directory traversal
+		journal.Directory = filepath.Clean(journal.Directory)
+		if strings.Contains(journal.Directory, "..") {
+			*errors = append(*errors, "suspicious journal directory reset to default")
+			journal.Directory = ""
+		}
+	}
+}
+
+// validateOutputTargets drops targets with unknown modes and corrects invalid policies
+func validateOutputTargets(config *models.Config, errors *[]string) {
+	if len(config.Output.Targets) == 0 {
+		return
+	}
+	valid := make([]models.OutputTarget, 0, len(config.Output.Targets))
+	for i, target := range config.Output.Targets {
+		if !isValidOutputMode(target.Mode) {
+			*errors = append(*errors, fmt.Sprintf("output target #%d has invalid mode: %s, ignoring it", i+1, target.Mode))
+			continue
+		}
+		switch target.OnFailure {
+		case models.OutputFailureContinue, models.OutputFailureStop:
+		case "":
+			target.OnFailure = models.OutputFailureContinue
+		default:
+			*errors = append(*errors, fmt.Sprintf("output target %s has invalid on_failure: %s, correcting to '%s'", target.Mode, target.OnFailure, models.OutputFailureContinue))
+			target.OnFailure = models.OutputFailureContinue
+		}
+		if target.Fallback != "" && (!isValidOutputMode(target.Fallback) || target.Fallback == target.Mode) {
+			*errors = append(*errors, fmt.Sprintf("output target %s has invalid fallback: %s, disabling it", target.Mode, target.Fallback))
+			target.Fallback = ""
+		}
+		valid = append(valid, target)
+	}
+	config.Output.Targets = valid
+}
+
+// validateWebhookConfig validates the webhook output settings
+func validateWebhookConfig(config *models.Config, errors *[]string) {
+	webhook := &config.Output.Webhook
+	if webhook.URL != "" {
+		u, err := url.Parse(webhook.URL)
+		switch {
+		case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			*errors = append(*errors, fmt.Sprintf("invalid webhook url: %s, disabling webhook output", webhook.URL))
+			webhook.URL = ""
+		case !webhook.AllowRemote && !isLocalWebhookHost(u.Hostname()):
+			*errors = append(*errors, fmt.Sprintf("webhook url %s is not on localhost or a private network (set output.webhook.allow_remote to send transcripts there), disabling webhook output", webhook.URL))
+			webhook.URL = ""
+		}
+	}
+	if webhook.TimeoutSeconds == 0 {
+		webhook.TimeoutSeconds = 5
+	} else if webhook.TimeoutSeconds < 0 || webhook.TimeoutSeconds > 60 {
+		*errors = append(*errors, fmt.Sprintf("invalid webhook timeout_seconds: %d, correcting to 5", webhook.TimeoutSeconds))
+		webhook.TimeoutSeconds = 5
+	}
+	if webhook.MaxRetries < 0 || webhook.MaxRetries > 10 {
+		*errors = append(*errors, fmt.Sprintf("invalid webhook max_retries: %d, correcting to 3", webhook.MaxRetries))
+		webhook.MaxRetries = 3
+	}
+	if webhook.QueueSize < 0 || webhook.QueueSize > 10000 {
+		*errors = append(*errors, fmt.Sprintf("invalid webhook queue_size: %d, correcting to 100", webhook.QueueSize))
+		webhook.QueueSize = 100
+	}
+}
+
+// isLocalWebhookHost reports whether a webhook host stays on this machine or
+// the local network: loopback, private and link-local addresses, localhost and
+// local-only domains. Other names would need DNS, which can point anywhere
+func isLocalWebhookHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" {
+		return true
+	}
+	for _, suffix := range []string{".localhost", ".local", ".home.arpa", ".internal", ".lan"} {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidPasteKeys reports whether the paste key combination is supported
+func isValidPasteKeys(keys string) bool {
+	switch keys {
+	case "ctrl+v", "shift+insert", "ctrl+shift+v":
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidOutputMode reports whether the output mode is supported
+func isValidOutputMode(mode string) bool {
+	switch mode {
+	case models.OutputModeClipboard, models.OutputModeActiveWindow, models.OutputModePaste, models.OutputModeFile, models.OutputModeWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateHistoryConfig validates transcript history retention limits
+func validateHistoryConfig(config *models.Config, errors *[]string) {
+	history := &config.History
+	if history.MaxEntries < 0 {
+		*errors = append(*errors, fmt.Sprintf("invalid history max_entries: %d, correcting to 500", history.MaxEntries))
+		history.MaxEntries = 500
+	}
+	if history.MaxAgeDays < 0 {
+		*errors = append(*errors, fmt.Sprintf("invalid history max_age_days: %d, correcting to 30", history.MaxAgeDays))
+		history.MaxAgeDays = 30
+	}
+	if history.MaxSizeKB < 0 {
+		*errors = append(*errors, fmt.Sprintf("invalid history max_size_kb: %d, correcting to 1024", history.MaxSizeKB))
+		history.MaxSizeKB = 1024
+	}
+}
+
 // validateWebServerConfig validates web server configuration settings
 func validateWebServerConfig(config *models.Config, errors *[]string) {
 	if !config.WebServer.Enabled {
@@ -78,6 +268,41 @@ func validateWebServerConfig(config *models.Config, errors *[]string) {
 	}
 }
 
+// validateMetricsConfig validates the metrics socket path
+func validateMetricsConfig(config *models.Config, errors *[]string) {
+	if config.Metrics.Socket != "" && !filepath.IsAbs(config.Metrics.Socket) {
+		*errors = append(*errors, fmt.Sprintf("metrics socket must be an absolute path: %s, using the default", config.Metrics.Socket))
+		config.Metrics.Socket = ""
+	}
+}
+
+// validateLoggingConfig validates the log format, file path and rotation limits
+func validateLoggingConfig(config *models.Config, errors *[]string) {
+	logging := &config.Logging
+	switch logging.Format {
+	case models.LogFormatText, models.LogFormatJSON:
+	case "":
+		logging.Format = models.LogFormatText
+	default:
+		*errors = append(*errors, fmt.Sprintf("invalid log format: %s, correcting to '%s'", logging.Format, models.LogFormatText))
+		logging.Format = models.LogFormatText
+	}
+	if logging.File != "" && !filepath.IsAbs(logging.File) {
+		*errors = append(*errors, fmt.Sprintf("log file must be an absolute path: %s, logging to stderr only", logging.File))
+		logging.File = ""
+	}
+	if logging.MaxSizeMB == 0 {
+		logging.MaxSizeMB = 10
+	} else if logging.MaxSizeMB < 0 || logging.MaxSizeMB > 1024 {
+		*errors = append(*errors, fmt.Sprintf("invalid log max_size_mb: %d, correcting to 10", logging.MaxSizeMB))
+		logging.MaxSizeMB = 10
+	}
+	if logging.MaxBackups < 0 || logging.MaxBackups > 100 {
+		*errors = append(*errors, fmt.Sprintf("invalid log max_backups: %d, correcting to 3", logging.MaxBackups))
+		logging.MaxBackups = 3
+	}
+}
+
 // validateSecurityConfig validates security configuration settings
 func validateSecurityConfig(config *models.Config, errors *[]string) {
 	// Ensure there's always a baseline of allowed commands for security
@@ -87,6 +312,93 @@ func validateSecurityConfig(config *models.Config, errors *[]string) {
 	}
 }
 
+// validateProfilesConfig drops per-application profiles that can never match
+// or carry invalid overrides, keeping the remaining profiles in order
+func validateProfilesConfig(config *models.Config, errors *[]string) {
+	if len(config.Profiles) == 0 {
+		return
+	}
+	valid := config.Profiles[:0]
+	for i, profile := range config.Profiles {
+		name := profile.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if profile.WindowClass == "" && profile.WindowTitle == "" {
+			*errors = append(*errors, fmt.Sprintf("profile %s has no window_class or window_title, ignoring it", name))
+			continue
+		}
+		if _, err := regexp.Compile(profile.WindowClass); err != nil {
+			*errors = append(*errors, fmt.Sprintf("profile %s has invalid window_class pattern: %v, ignoring it", name, err))
+			continue
+		}
+		if _, err := regexp.Compile(profile.WindowTitle); err != nil {
+			*errors = append(*errors, fmt.Sprintf("profile %s has invalid window_title pattern: %v, ignoring it", name, err))
+			continue
+		}
+		if profile.OutputMode != "" && !isValidOutputMode(profile.OutputMode) {
+			*errors = append(*errors, fmt.Sprintf("profile %s has invalid output_mode: %s, inheriting global mode", name, profile.OutputMode))
+			profile.OutputMode = ""
+		}
+		if profile.Language != "" && constants.LanguageByCode(profile.Language) == nil {
+			*errors = append(*errors, fmt.Sprintf("profile %s has unsupported language: %s, inheriting global language", name, profile.Language))
+			profile.Language = ""
+		}
+		if profile.TypeTool != "" && !isValidTypeTool(profile.TypeTool) {
+			*errors = append(*errors, fmt.Sprintf("profile %s has unknown type_tool: %s, inheriting global tool", name, profile.TypeTool))
+			profile.TypeTool = ""
+		}
+		if profile.PostProcessing != "" && !isValidPostProcessing(profile.PostProcessing) {
+			*errors = append(*errors, fmt.Sprintf("profile %s has invalid post_processing: %s, using '%s'", name, profile.PostProcessing, models.PostProcessingDefault))
+			profile.PostProcessing = models.PostProcessingDefault
+		}
+		if profile.PasteKeys != "" && !isValidPasteKeys(profile.PasteKeys) {
+			*errors = append(*errors, fmt.Sprintf("profile %s has invalid paste_keys: %s, inheriting global keys", name, profile.PasteKeys))
+			profile.PasteKeys = ""
+		}
+		valid = append(valid, profile)
+	}
+	config.Profiles = valid
+}
+
+// isValidTypeTool reports whether the typing tool is "auto" or a known tool
+func isValidTypeTool(tool string) bool {
+	return tool == models.TypeToolAuto || slices.Contains(models.TypingTools, tool)
+}
+
+// isValidPostProcessing reports whether the post-processing mode is supported
+func isValidPostProcessing(mode string) bool {
+	switch mode {
+	case models.PostProcessingDefault, models.PostProcessingCode:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateSessionOptions rejects unsupported per-dictation overrides.
+// Unlike the configuration they are not corrected: a request asking for an
+// unknown language or output must fail rather than dictate somewhere else
+func ValidateSessionOptions(opts models.SessionOptions) error {
+	if opts.Language != "" && constants.LanguageByCode(opts.Language) == nil {
+		return fmt.Errorf("unsupported language: %s", opts.Language)
+	}
+	switch opts.Output {
+	case "", models.SessionOutputNone, models.SessionOutputClipboard, models.SessionOutputType, models.SessionOutputStdoutOnly:
+	default:
+		return fmt.Errorf("invalid output: %s (must be 'none', 'clipboard', 'type' or 'stdout-only')", opts.Output)
+	}
+	if opts.PostProcessing != "" && !isValidPostProcessing(opts.PostProcessing) {
+		return fmt.Errorf("invalid post_processing: %s (must be 'default' or 'code')", opts.PostProcessing)
+	}
+	switch opts.Task {
+	case "", models.TaskTranscribe, models.TaskTranslate:
+	default:
+		return fmt.Errorf("invalid task: %s (must be 'transcribe' or 'translate')", opts.Task)
+	}
+	return nil
+}
+
 // Inspect the configuration for invalid or unsafe values.
 // It automatically corrects offending values to safe defaults and returns an error
 // that aggregates all validation issues found. This ensures the application can
@@ -95,7 +407,12 @@ func ValidateConfig(config *models.Config) error {
 	var errors []string
 	validateGeneralConfig(config, &errors)
 	validateAudioConfig(config, &errors)
+	validateOutputConfig(config, &errors)
+	validateHistoryConfig(config, &errors)
 	validateWebServerConfig(config, &errors)
+	validateMetricsConfig(config, &errors)
+	validateLoggingConfig(config, &errors)
+	validateProfilesConfig(config, &errors)
 	validateSecurityConfig(config, &errors)
 
 	if len(errors) > 0 {
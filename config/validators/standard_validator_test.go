@@ -137,3 +137,271 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig_Profiles(t *testing.T) {
+	config := &models.Config{}
+	setDefaultConfigForTest(config)
+	config.Profiles = []models.Profile{
+		{Name: "terminal", WindowClass: "(?i)kitty|alacritty", OutputMode: models.OutputModeClipboard},
+		{Name: "no-pattern", OutputMode: models.OutputModeClipboard},
+		{Name: "bad-regex", WindowTitle: "([unclosed"},
+		{Name: "browser", WindowClass: "firefox", OutputMode: "teleport", PostProcessing: "shout", Language: "klingon", TypeTool: "teletype"},
+	}
+
+	if err := ValidateConfig(config); err == nil {
+		t.Fatal("expected validation issues for invalid profiles")
+	}
+	if len(config.Profiles) != 2 {
+		t.Fatalf("expected 2 profiles to survive validation, got %d", len(config.Profiles))
+	}
+	if config.Profiles[0].Name != "terminal" || config.Profiles[1].Name != "browser" {
+		t.Errorf("unexpected profile order: %+v", config.Profiles)
+	}
+	if config.Profiles[1].OutputMode != "" {
+		t.Errorf("expected invalid output mode to be cleared, got %q", config.Profiles[1].OutputMode)
+	}
+	if config.Profiles[1].PostProcessing != models.PostProcessingDefault {
+		t.Errorf("expected invalid post-processing to be reset, got %q", config.Profiles[1].PostProcessing)
+	}
+	if config.Profiles[1].Language != "" || config.Profiles[1].TypeTool != "" {
+		t.Errorf("expected invalid language and type tool to be cleared, got %q and %q", config.Profiles[1].Language, config.Profiles[1].TypeTool)
+	}
+}
+
+func TestValidateSessionOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    models.SessionOptions
+		wantErr bool
+	}{
+		{"empty inherits everything", models.SessionOptions{}, false},
+		{"all overrides", models.SessionOptions{Language: "de", Output: models.SessionOutputNone, PostProcessing: models.PostProcessingCode, Task: models.TaskTranslate}, false},
+		{"stdout only", models.SessionOptions{Output: models.SessionOutputStdoutOnly}, false},
+		{"unknown language", models.SessionOptions{Language: "German"}, true},
+		{"output mode instead of session output", models.SessionOptions{Output: models.OutputModeActiveWindow}, true},
+		{"unknown post-processing", models.SessionOptions{PostProcessing: "shout"}, true},
+		{"unknown task", models.SessionOptions{Task: "summarize"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSessionOptions(tt.opts); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSessionOptions(%+v) error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_PasteSettings(t *testing.T) {
+	config := &models.Config{}
+	setDefaultConfigForTest(config)
+	config.Output.PasteKeys = "alt+v"
+	config.Output.PasteRestoreDelayMs = -1
+	config.Output.ClipboardSelection = "secondary"
+	config.Output.TypeDelayMs = 5000
+	config.Output.TypeChunkSize = -1
+
+	if err := ValidateConfig(config); err == nil {
+		t.Fatal("expected validation issues for invalid paste settings")
+	}
+	if config.Output.PasteKeys != "ctrl+v" {
+		t.Errorf("expected paste_keys reset to ctrl+v, got %q", config.Output.PasteKeys)
+	}
+	if config.Output.PasteRestoreDelayMs != 500 {
+		t.Errorf("expected paste_restore_delay_ms reset to 500, got %d", config.Output.PasteRestoreDelayMs)
+	}
+	if config.Output.ClipboardSelection != models.ClipboardSelectionClipboard {
+		t.Errorf("expected clipboard_selection reset to clipboard, got %q", config.Output.ClipboardSelection)
+	}
+	if config.Output.TypeDelayMs != 0 || config.Output.TypeChunkSize != 100 {
+		t.Errorf("expected typing settings reset, got delay %d chunk %d", config.Output.TypeDelayMs, config.Output.TypeChunkSize)
+	}
+}
+
+func TestValidateConfig_OutputTargets(t *testing.T) {
+	config := &models.Config{}
+	setDefaultConfigForTest(config)
+	config.Output.Targets = []models.OutputTarget{
+		{Mode: models.OutputModeActiveWindow, OnFailure: models.OutputFailureStop, Fallback: models.OutputModeClipboard},
+		{Mode: "teleport"},
+		{Mode: models.OutputModeClipboard, OnFailure: "panic", Fallback: models.OutputModeClipboard},
+		{Mode: models.OutputModeFile},
+	}
+
+	if err := ValidateConfig(config); err == nil {
+		t.Fatal("expected validation issues for invalid output targets")
+	}
+	expected := []models.OutputTarget{
+		{Mode: models.OutputModeActiveWindow, OnFailure: models.OutputFailureStop, Fallback: models.OutputModeClipboard},
+		{Mode: models.OutputModeClipboard, OnFailure: models.OutputFailureContinue},
+		{Mode: models.OutputModeFile, OnFailure: models.OutputFailureContinue},
+	}
+	if len(config.Output.Targets) != len(expected) {
+		t.Fatalf("expected %d targets to survive validation, got %+v", len(expected), config.Output.Targets)
+	}
+	for i, want := range expected {
+		if config.Output.Targets[i] != want {
+			t.Errorf("target %d: expected %+v, got %+v", i, want, config.Output.Targets[i])
+		}
+	}
+}
+
+func TestValidateConfig_Webhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		allowRemote bool
+		timeout     int
+		retries     int
+		wantURL     string
+		wantErr     bool
+	}{
+		{"valid local url", "http://127.0.0.1:8123/hook", false, 5, 3, "http://127.0.0.1:8123/hook", false},
+		{"https url", "https://notes.local/api", false, 10, 0, "https://notes.local/api", false},
+		{"localhost", "http://localhost:5000/", false, 5, 3, "http://localhost:5000/", false},
+		{"ipv6 loopback", "http://[::1]:5000/", false, 5, 3, "http://[::1]:5000/", false},
+		{"private network", "http://192.168.1.20:8123/api/webhook/x", false, 5, 3, "http://192.168.1.20:8123/api/webhook/x", false},
+		{"remote host", "https://example.com/hook", false, 5, 3, "", true},
+		{"public address", "http://8.8.8.8/hook", false, 5, 3, "", true},
+		{"remote host allowed", "https://example.com/hook", true, 5, 3, "https://example.com/hook", false},
+		{"unsupported scheme", "file:///etc/passwd", false, 5, 3, "", true},
+		{"missing host", "http://", false, 5, 3, "", true},
+		{"timeout out of range", "", false, 600, 3, "", true},
+		{"retries out of range", "", false, 5, 50, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			setDefaultConfigForTest(config)
+			config.Output.Webhook.URL = tt.url
+			config.Output.Webhook.AllowRemote = tt.allowRemote
+			config.Output.Webhook.TimeoutSeconds = tt.timeout
+			config.Output.Webhook.MaxRetries = tt.retries
+
+			err := ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if config.Output.Webhook.URL != tt.wantURL {
+				t.Errorf("expected url %q, got %q", tt.wantURL, config.Output.Webhook.URL)
+			}
+			if config.Output.Webhook.TimeoutSeconds < 1 || config.Output.Webhook.TimeoutSeconds > 60 {
+				t.Errorf("timeout not corrected: %d", config.Output.Webhook.TimeoutSeconds)
+			}
+			if config.Output.Webhook.MaxRetries < 0 || config.Output.Webhook.MaxRetries > 10 {
+				t.Errorf("max_retries not corrected: %d", config.Output.Webhook.MaxRetries)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_History(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxEntries  int
+		maxAgeDays  int
+		maxSizeKB   int
+		wantEntries int
+		wantAge     int
+		wantSize    int
+		wantErr     bool
+	}{
+		{"defaults", 500, 30, 1024, 500, 30, 1024, false},
+		{"zero disables limits", 0, 0, 0, 0, 0, 0, false},
+		{"negative entries", -1, 30, 1024, 500, 30, 1024, true},
+		{"negative age", 500, -7, 1024, 500, 30, 1024, true},
+		{"negative size", 500, 30, -1, 500, 30, 1024, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			setDefaultConfigForTest(config)
+			config.History.MaxEntries = tt.maxEntries
+			config.History.MaxAgeDays = tt.maxAgeDays
+			config.History.MaxSizeKB = tt.maxSizeKB
+
+			err := ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if config.History.MaxEntries != tt.wantEntries || config.History.MaxAgeDays != tt.wantAge || config.History.MaxSizeKB != tt.wantSize {
+				t.Errorf("got limits %d/%d/%d, want %d/%d/%d",
+					config.History.MaxEntries, config.History.MaxAgeDays, config.History.MaxSizeKB,
+					tt.wantEntries, tt.wantAge, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_Metrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		socket     string
+		wantSocket string
+		wantErr    bool
+	}{
+		{"default socket", "", "", false},
+		{"absolute socket", "/run/user/1000/metrics.sock", "/run/user/1000/metrics.sock", false},
+		{"relative socket", "metrics.sock", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			setDefaultConfigForTest(config)
+			config.Metrics.Enabled = true
+			config.Metrics.Socket = tt.socket
+
+			err := ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if config.Metrics.Socket != tt.wantSocket {
+				t.Errorf("socket = %q, want %q", config.Metrics.Socket, tt.wantSocket)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_Logging(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		file           string
+		maxSizeMB      int
+		maxBackups     int
+		wantFormat     string
+		wantFile       string
+		wantMaxSizeMB  int
+		wantMaxBackups int
+		wantErr        bool
+	}{
+		{"defaults", "text", "", 10, 3, "text", "", 10, 3, false},
+		{"json with file", "json", "/var/log/dabri.log", 5, 0, "json", "/var/log/dabri.log", 5, 0, false},
+		{"empty format", "", "", 10, 3, "text", "", 10, 3, false},
+		{"invalid format", "xml", "", 10, 3, "text", "", 10, 3, true},
+		{"relative file", "text", "dabri.log", 10, 3, "text", "", 10, 3, true},
+		{"zero size", "text", "", 0, 3, "text", "", 10, 3, false},
+		{"oversized", "text", "", 4096, 3, "text", "", 10, 3, true},
+		{"negative backups", "text", "", 10, -1, "text", "", 10, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &models.Config{}
+			setDefaultConfigForTest(config)
+			config.Logging.Format = tt.format
+			config.Logging.File = tt.file
+			config.Logging.MaxSizeMB = tt.maxSizeMB
+			config.Logging.MaxBackups = tt.maxBackups
+
+			err := ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			logging := config.Logging
+			if logging.Format != tt.wantFormat || logging.File != tt.wantFile ||
+				logging.MaxSizeMB != tt.wantMaxSizeMB || logging.MaxBackups != tt.wantMaxBackups {
+				t.Errorf("logging = %+v, want format=%q file=%q max_size_mb=%d max_backups=%d",
+					logging, tt.wantFormat, tt.wantFile, tt.wantMaxSizeMB, tt.wantMaxBackups)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ type ConfigAdapter struct {
 	provider        string
 	showConfig      string
 	resetToDefaults string
+	codeRecording   string
+	undo            string
+	cancel          string
 }
 
 // Create a new adapter from the given values
@@ -34,6 +37,24 @@ func (c *ConfigAdapter) WithAdditionalHotkeys(showConfig, resetToDefaults string
 	return c
 }
 
+// Set the code dictation recording hotkey
+func (c *ConfigAdapter) WithCodeRecordingHotkey(codeRecording string) *ConfigAdapter {
+	c.codeRecording = codeRecording
+	return c
+}
+
+// Set the hotkey that undoes the last output
+func (c *ConfigAdapter) WithUndoHotkey(undo string) *ConfigAdapter {
+	c.undo = undo
+	return c
+}
+
+// Set the hotkey that cancels the recording or pending transcription
+func (c *ConfigAdapter) WithCancelHotkey(cancel string) *ConfigAdapter {
+	c.cancel = cancel
+	return c
+}
+
 // Return the start recording hotkey
 func (c *ConfigAdapter) GetStartRecordingHotkey() string {
 	return c.startRecording
@@ -54,6 +75,12 @@ func (c *ConfigAdapter) GetActionHotkey(action string) string {
 		return c.showConfig
 	case "reset_to_defaults":
 		return c.resetToDefaults
+	case "code_recording":
+		return c.codeRecording
+	case "undo":
+		return c.undo
+	case "cancel":
+		return c.cancel
 	default:
 		return ""
 	}
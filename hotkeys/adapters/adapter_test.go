@@ -202,3 +202,29 @@ func TestConfigAdapter_GetProvider_DefaultAuto(t *testing.T) {
 		t.Errorf("expected default provider 'auto', got '%s'", p)
 	}
 }
+
+func TestConfigAdapter_UndoHotkey(t *testing.T) {
+	adapter := NewConfigAdapter("alt+r", "auto").WithUndoHotkey("alt+z")
+	if hk := adapter.GetActionHotkey("undo"); hk != "alt+z" {
+		t.Errorf("expected undo hotkey 'alt+z', got '%s'", hk)
+	}
+}
+
+func TestConfigAdapter_CancelHotkey(t *testing.T) {
+	adapter := NewConfigAdapter("alt+r", "auto").WithCancelHotkey("esc")
+	if hk := adapter.GetActionHotkey("cancel"); hk != "esc" {
+		t.Errorf("expected cancel hotkey 'esc', got '%s'", hk)
+	}
+}
+
+func TestConfigAdapter_CodeRecordingHotkey(t *testing.T) {
+	adapter := NewConfigAdapter("alt+r", "auto").
+		WithAdditionalHotkeys("alt+c", "alt+d").
+		WithCodeRecordingHotkey("alt+shift+r")
+	if hk := adapter.GetActionHotkey("code_recording"); hk != "alt+shift+r" {
+		t.Errorf("expected code_recording hotkey 'alt+shift+r', got '%s'", hk)
+	}
+	if hk := NewConfigAdapter("alt+r", "auto").GetActionHotkey("code_recording"); hk != "" {
+		t.Errorf("expected empty code_recording hotkey by default, got '%s'", hk)
+	}
+}
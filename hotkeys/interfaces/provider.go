@@ -4,11 +4,16 @@
 package interfaces
 
 import (
+	"errors"
 	"time"
 
 	"github.com/AshBuk/dabri/internal/platform"
 )
 
+// ErrCaptureCancelled is returned by CaptureOnce when the user pressed Esc
+// or no key combination was pressed before the timeout
+var ErrCaptureCancelled = errors.New("capture cancelled")
+
 // KeyboardEventProvider defines the contract for a keyboard event source
 type KeyboardEventProvider interface {
 	// Start listening for keyboard events
@@ -25,6 +30,20 @@ type KeyboardEventProvider interface {
 	SupportsCaptureOnce() bool
 }
 
+// ModifierTracker is implemented by providers that see key releases, so
+// callers can wait until a hotkey's modifiers are let go
+type ModifierTracker interface {
+	// ModifiersHeld reports whether any modifier key is still pressed
+	ModifiersHeld() bool
+}
+
+// HotkeyUnregisterer is implemented by providers that can drop a hotkey
+// while listening (the D-Bus portal binds its shortcuts once per session)
+type HotkeyUnregisterer interface {
+	// UnregisterHotkey removes the callback of a hotkey
+	UnregisterHotkey(hotkey string)
+}
+
 // KeyCombination represents a hotkey combination
 type KeyCombination struct {
 	Modifiers []string // Modifier keys like "ctrl", "alt", "shift"
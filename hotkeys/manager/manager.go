@@ -5,6 +5,7 @@ package manager
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,11 +13,19 @@ import (
 	"github.com/AshBuk/dabri/hotkeys/interfaces"
 	"github.com/AshBuk/dabri/hotkeys/providers"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
 )
 
 // HotkeyAction represents a hotkey action callback
 type HotkeyAction func() error
 
+const (
+	modifierReleasePoll = 10 * time.Millisecond
+	// modifierReleaseGrace is waited for providers that cannot see key
+	// releases (D-Bus portal); a hotkey is usually let go within it
+	modifierReleaseGrace = 300 * time.Millisecond
+)
+
 // Manages keyboard shortcuts, providers, and actions
 type HotkeyManager struct {
 	config           adapters.HotkeyConfig
@@ -31,6 +40,17 @@ type HotkeyManager struct {
 	provider         interfaces.KeyboardEventProvider
 	modifierState    map[string]bool // Tracks the state of modifier keys
 	logger           logger.Logger
+
+	// On-demand actions are bound only while enabled; guarded separately
+	// because they are toggled from within the start/stop hotkey callback
+	onDemandMutex   sync.Mutex
+	onDemandActions map[string]*onDemandAction
+}
+
+// onDemandAction is a custom action whose hotkey is bound only while enabled
+type onDemandAction struct {
+	action  HotkeyAction
+	enabled bool
 }
 
 // Create a new instance of the HotkeyManager
@@ -44,6 +64,8 @@ func NewHotkeyManager(config adapters.HotkeyConfig, environment interfaces.Envir
 		modifierState: make(map[string]bool),
 		hotkeyActions: make(map[string]HotkeyAction),
 		logger:        logger,
+
+		onDemandActions: make(map[string]*onDemandAction),
 	}
 	// Initialize the appropriate keyboard provider
 	manager.provider = selectProviderForEnvironment(manager.config, manager.environment, manager.logger)
@@ -76,6 +98,49 @@ func (h *HotkeyManager) UnregisterHotkeyAction(hotkey string) {
 	delete(h.hotkeyActions, hotkey)
 }
 
+// Register a custom action whose hotkey is bound only while enabled with
+// SetActionEnabled. Providers that cannot unbind a hotkey while listening
+// (D-Bus portal) keep it bound, so the action must ignore untimely presses
+func (h *HotkeyManager) RegisterOnDemandAction(hotkey string, action HotkeyAction) {
+	h.onDemandMutex.Lock()
+	h.onDemandActions[hotkey] = &onDemandAction{action: action}
+	h.onDemandMutex.Unlock()
+	h.RegisterHotkeyAction(hotkey, action)
+}
+
+// Bind or unbind the hotkey of an on-demand action on the running provider
+func (h *HotkeyManager) SetActionEnabled(actionName string, enabled bool) error {
+	h.onDemandMutex.Lock()
+	defer h.onDemandMutex.Unlock()
+	od, ok := h.onDemandActions[actionName]
+	if !ok {
+		return fmt.Errorf("unknown on-demand action: %s", actionName)
+	}
+	if od.enabled == enabled {
+		return nil
+	}
+	od.enabled = enabled
+	provider, canUnregister := h.provider.(interfaces.HotkeyUnregisterer)
+	hk := h.config.GetActionHotkey(actionName)
+	if !canUnregister || strings.TrimSpace(hk) == "" {
+		return nil
+	}
+	if !enabled {
+		provider.UnregisterHotkey(hk)
+		return nil
+	}
+	return h.provider.RegisterHotkey(hk, h.actionCallback(actionName, hk, od.action))
+}
+
+// onDemandEnabled reports whether an action may be bound now; regular
+// actions always are
+func (h *HotkeyManager) onDemandEnabled(actionName string) bool {
+	h.onDemandMutex.Lock()
+	defer h.onDemandMutex.Unlock()
+	od, ok := h.onDemandActions[actionName]
+	return !ok || od.enabled
+}
+
 // Return all registered hotkeys
 func (h *HotkeyManager) GetRegisteredHotkeys() []string {
 	h.hotkeysMutex.Lock()
@@ -134,9 +199,14 @@ func (h *HotkeyManager) IsRecording() bool {
 
 // Forcefully set the recording state to false
 func (h *HotkeyManager) ResetRecordingState() {
+	h.SetRecordingState(false)
+}
+
+// Set the recording state after recording started or stopped outside of the start/stop hotkey
+func (h *HotkeyManager) SetRecordingState(recording bool) {
 	h.hotkeysMutex.Lock()
 	defer h.hotkeysMutex.Unlock()
-	h.isRecording = false
+	h.isRecording = recording
 }
 
 // Simulate a hotkey press for testing purposes
@@ -180,6 +250,7 @@ func (h *HotkeyManager) ReloadConfig(newConfig adapters.HotkeyConfig) error {
 	if err := h.registerAllHotkeysOn(h.provider); err != nil {
 		return err
 	}
+	metrics.HotkeyProviderRestarts.Inc(providerName(h.provider))
 	if err := h.provider.Start(); err != nil {
 		return startFallbackAfterRegistration(h, err)
 	}
@@ -215,6 +286,25 @@ func (h *HotkeyManager) CaptureOnce(timeout time.Duration) (string, error) {
 	return "", fmt.Errorf("capture not supported")
 }
 
+// Wait until no modifier key is held, so keys synthesized by an action are
+// not combined with the hotkey's modifiers (Ctrl+BackSpace deletes a word).
+// Returns false if modifiers are still held after timeout
+func (h *HotkeyManager) WaitModifiersReleased(timeout time.Duration) bool {
+	tracker, ok := h.provider.(interfaces.ModifierTracker)
+	if !ok {
+		time.Sleep(min(modifierReleaseGrace, timeout))
+		return true
+	}
+	deadline := time.Now().Add(timeout)
+	for tracker.ModifiersHeld() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(modifierReleasePoll)
+	}
+	return true
+}
+
 // Check if the active provider supports the capture-once functionality
 func (h *HotkeyManager) SupportsCaptureOnce() bool {
 	if h.provider == nil {
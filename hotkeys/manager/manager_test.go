@@ -6,6 +6,7 @@ package manager
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/AshBuk/dabri/hotkeys/adapters"
 	"github.com/AshBuk/dabri/hotkeys/interfaces"
@@ -371,3 +372,96 @@ func TestHotkeyManager_ConcurrentAccess(t *testing.T) {
 	<-done
 	<-done
 }
+
+func TestProviderName(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider interfaces.KeyboardEventProvider
+		expected string
+	}{
+		{"nil provider", nil, "none"},
+		{"mock provider", mocks.NewMockHotkeyProvider(), "mockhotkeyprovider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := providerName(tt.provider); got != tt.expected {
+				t.Errorf("providerName() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// trackingProvider reports modifiers held for a number of polls
+type trackingProvider struct {
+	*mocks.MockHotkeyProvider
+	heldPolls int
+}
+
+func (p *trackingProvider) ModifiersHeld() bool {
+	if p.heldPolls > 0 {
+		p.heldPolls--
+		return true
+	}
+	return false
+}
+
+func TestHotkeyManager_WaitModifiersReleased(t *testing.T) {
+	tests := []struct {
+		name      string
+		heldPolls int
+		timeout   time.Duration
+		want      bool
+	}{
+		{"already released", 0, time.Second, true},
+		{"released while waiting", 3, time.Second, true},
+		{"still held at timeout", 1 << 20, 50 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &HotkeyManager{provider: &trackingProvider{mocks.NewMockHotkeyProvider(), tt.heldPolls}}
+			if got := manager.WaitModifiersReleased(tt.timeout); got != tt.want {
+				t.Errorf("WaitModifiersReleased() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// unregisteringProvider can drop hotkeys while listening, like evdev
+type unregisteringProvider struct {
+	*mocks.MockHotkeyProvider
+	unregistered []string
+}
+
+func (p *unregisteringProvider) UnregisterHotkey(hotkey string) {
+	p.unregistered = append(p.unregistered, hotkey)
+}
+
+func TestHotkeyManager_OnDemandAction(t *testing.T) {
+	config := adapters.NewConfigAdapter("ctrl+shift+r", "auto").WithCancelHotkey("esc")
+	provider := &unregisteringProvider{MockHotkeyProvider: mocks.NewMockHotkeyProvider()}
+	manager := NewHotkeyManager(config, interfaces.EnvironmentX11, testutils.NewMockLogger())
+	manager.provider = provider
+	manager.RegisterOnDemandAction("cancel", func() error { return nil })
+
+	if err := manager.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if provider.IsHotkeyRegistered("esc") {
+		t.Fatal("on-demand hotkey bound before it was enabled")
+	}
+	if err := manager.SetActionEnabled("cancel", true); err != nil {
+		t.Fatalf("SetActionEnabled(true) failed: %v", err)
+	}
+	if !provider.IsHotkeyRegistered("esc") {
+		t.Error("on-demand hotkey not bound after enabling")
+	}
+	if err := manager.SetActionEnabled("cancel", false); err != nil {
+		t.Fatalf("SetActionEnabled(false) failed: %v", err)
+	}
+	if len(provider.unregistered) != 1 || provider.unregistered[0] != "esc" {
+		t.Errorf("unregistered = %v, want [esc]", provider.unregistered)
+	}
+	if err := manager.SetActionEnabled("undo", true); err == nil {
+		t.Error("expected an error for an action that is not on-demand")
+	}
+}
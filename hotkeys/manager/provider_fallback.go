@@ -9,6 +9,7 @@ import (
 
 	"github.com/AshBuk/dabri/hotkeys/interfaces"
 	"github.com/AshBuk/dabri/hotkeys/providers"
+	"github.com/AshBuk/dabri/internal/metrics"
 )
 
 // Register all configured hotkeys on the given provider
@@ -40,22 +41,18 @@ func (h *HotkeyManager) registerAllHotkeysOn(provider interfaces.KeyboardEventPr
 	// Register any additional custom hotkeys
 	h.hotkeysMutex.Lock()
 	defer h.hotkeysMutex.Unlock()
+	_, canUnregister := provider.(interfaces.HotkeyUnregisterer)
 	for actionName, action := range h.hotkeyActions {
 		hk := h.config.GetActionHotkey(actionName)
 		if strings.TrimSpace(hk) == "" {
 			// Skip actions that do not have a configured hotkey
 			continue
 		}
-
-		act := action
-		if err := provider.RegisterHotkey(hk, func() error {
-			h.logger.Info("Custom hotkey detected: %s (%s)", actionName, hk)
-			if err := act(); err != nil {
-				h.logger.Error("Error executing hotkey action for %s: %v", actionName, err)
-				return err
-			}
-			return nil
-		}); err != nil {
+		if canUnregister && !h.onDemandEnabled(actionName) {
+			// Bound later by SetActionEnabled
+			continue
+		}
+		if err := provider.RegisterHotkey(hk, h.actionCallback(actionName, hk, action)); err != nil {
 			return fmt.Errorf("failed to register hotkey %s for action %s: %w", hk, actionName, err)
 		}
 	}
@@ -63,6 +60,18 @@ func (h *HotkeyManager) registerAllHotkeysOn(provider interfaces.KeyboardEventPr
 	return nil
 }
 
+// actionCallback wraps a custom action with logging for its provider callback
+func (h *HotkeyManager) actionCallback(actionName, hk string, action HotkeyAction) func() error {
+	return func() error {
+		h.logger.Info("Custom hotkey detected: %s (%s)", actionName, hk)
+		if err := action(); err != nil {
+			h.logger.Error("Error executing hotkey action for %s: %v", actionName, err)
+			return err
+		}
+		return nil
+	}
+}
+
 // Attempt to switch to a fallback provider if the primary one fails
 func startFallbackAfterRegistration(h *HotkeyManager, startErr error) error {
 	h.logger.Error("Primary keyboard provider failed to start: %v", startErr)
@@ -71,6 +80,7 @@ func startFallbackAfterRegistration(h *HotkeyManager, startErr error) error {
 		fallback := providers.NewEvdevKeyboardProvider(h.logger)
 		if fallback != nil && fallback.IsSupported() {
 			h.logger.Info("Falling back to evdev keyboard provider")
+			metrics.FallbackSwitches.Inc("hotkey_provider", providerName(h.provider), providerName(fallback))
 			h.provider = fallback
 			if err := h.registerAllHotkeysOn(h.provider); err != nil {
 				return fmt.Errorf("failed to register hotkeys on fallback provider: %w", err)
@@ -84,3 +94,13 @@ func startFallbackAfterRegistration(h *HotkeyManager, startErr error) error {
 	}
 	return fmt.Errorf("failed to start keyboard provider: %w", startErr)
 }
+
+// providerName labels a keyboard provider in metrics: evdev, dbus or dummy
+func providerName(provider interfaces.KeyboardEventProvider) string {
+	if provider == nil {
+		return "none"
+	}
+	name := fmt.Sprintf("%T", provider)
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.ToLower(strings.TrimSuffix(name, "KeyboardProvider"))
+}
@@ -15,6 +15,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/AshBuk/dabri/hotkeys/interfaces"
 	"github.com/AshBuk/dabri/hotkeys/utils"
 	"github.com/AshBuk/dabri/internal/logger"
 	evdev "github.com/holoplot/go-evdev"
@@ -252,6 +253,18 @@ func (p *EvdevKeyboardProvider) handleKeyEvent(_ int, event *evdev.InputEvent) {
 	}
 }
 
+// Report whether any modifier key is still pressed on a listened device
+func (p *EvdevKeyboardProvider) ModifiersHeld() bool {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	for _, pressed := range p.modifierState {
+		if pressed {
+			return true
+		}
+	}
+	return false
+}
+
 // Register a callback for a hotkey
 func (p *EvdevKeyboardProvider) RegisterHotkey(hotkey string, callback func() error) error {
 	p.mutex.Lock()
@@ -260,6 +273,13 @@ func (p *EvdevKeyboardProvider) RegisterHotkey(hotkey string, callback func() er
 	return nil
 }
 
+// Remove the callback of a hotkey; takes effect for the next key event
+func (p *EvdevKeyboardProvider) UnregisterHotkey(hotkey string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.callbacks, hotkey)
+}
+
 // Stop listening for keyboard events and close all devices
 func (p *EvdevKeyboardProvider) Stop() {
 	p.mutex.Lock()
@@ -469,7 +489,7 @@ func (p *EvdevKeyboardProvider) CaptureOnce(timeout time.Duration) (string, erro
 	}
 	session.cleanup()
 	if strings.TrimSpace(result) == "" {
-		return "", fmt.Errorf("capture cancelled")
+		return "", interfaces.ErrCaptureCancelled
 	}
 	return result, nil
 }
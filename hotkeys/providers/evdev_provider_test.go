@@ -250,4 +250,12 @@ func TestModifierStateTracking(t *testing.T) {
 	if !provider.modifierState["shift"] {
 		t.Error("expected shift to still be pressed")
 	}
+	if !provider.ModifiersHeld() {
+		t.Error("expected ModifiersHeld with shift pressed")
+	}
+
+	provider.modifierState["shift"] = false
+	if provider.ModifiersHeld() {
+		t.Error("expected no modifiers held after release")
+	}
 }
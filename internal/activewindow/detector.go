@@ -0,0 +1,355 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+// Package activewindow detects the currently focused window so that dictation
+// settings can be tailored per application (see config Profiles), and
+// refocuses it so typed text lands in the window where dictation started.
+//
+// Backends:
+//   - X11:             xdotool (getactivewindow, getwindowclassname, getwindowname)
+//   - Sway:            swaymsg -t get_tree (focused node)
+//   - Hyprland:        hyprctl activewindow -j
+//   - KDE Plasma:      kdotool (KWin scripting)
+//   - GNOME Wayland:   unsupported, returns ErrUnsupported
+package activewindow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/platform"
+)
+
+// ErrUnsupported indicates that the current session offers no way to query the focused window
+var ErrUnsupported = errors.New("active window detection not supported in this environment")
+
+// ErrWindowClosed indicates that a window to activate no longer exists
+var ErrWindowClosed = errors.New("window no longer exists")
+
+// ErrTimeout indicates that a backend tool did not answer in time, e.g.
+// xdotool windowactivate --sync under a window manager without EWMH activation
+var ErrTimeout = errors.New("window tool timed out")
+
+// Deadlines for backend tools. Detection runs at recording start, activation
+// before every typed dictation; a hung tool must stall neither
+const (
+	detectTimeout   = 500 * time.Millisecond
+	activateTimeout = time.Second
+)
+
+// Info describes the focused window
+type Info struct {
+	ID    string // Backend-specific window identifier (X11 window ID, sway con_id, Hyprland address)
+	Class string // Window class or Wayland app_id
+	Title string // Window title
+}
+
+// Detector queries the focused window
+type Detector interface {
+	// Detect returns information about the currently focused window
+	Detect() (Info, error)
+	// Activate focuses the window with an ID returned by Detect.
+	// Returns ErrWindowClosed when the window is gone
+	Activate(id string) error
+	// Name returns the backend tool name for diagnostics
+	Name() string
+}
+
+// commandRunner executes an external tool and returns its standard output
+type commandRunner func(name string, args ...string) ([]byte, error)
+
+// execFunc executes an external tool until ctx is done
+type execFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// runCommand is the default execFunc backed by os/exec
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	// #nosec G204 -- Tool is selected from a fixed set and allowlist-checked; args are constant or tool-provided IDs.
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// withTimeout returns a commandRunner giving every command its own deadline
+func withTimeout(exec execFunc, timeout time.Duration) commandRunner {
+	return func(name string, args ...string) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		out, err := exec(ctx, name, args...)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s %s: %w after %v", name, strings.Join(args, " "), ErrTimeout, timeout)
+		}
+		return out, err
+	}
+}
+
+// toolDetector implements Detector for a single backend tool
+type toolDetector struct {
+	tool     string
+	cfg      *config.Live
+	exec     execFunc
+	detect   func(run commandRunner) (Info, error)
+	activate func(run commandRunner, id string) error
+}
+
+// NewDetector selects the detection backend for the given display environment.
+// Returns a Detector that reports ErrUnsupported when no backend is usable
+func NewDetector(cfg *config.Live, env platform.EnvironmentType) Detector {
+	return newDetector(cfg, env, runCommand)
+}
+
+func newDetector(cfg *config.Live, env platform.EnvironmentType, exec execFunc) Detector {
+	var d *toolDetector
+	switch env {
+	case platform.EnvironmentX11:
+		d = &toolDetector{tool: "xdotool", detect: detectXdotool, activate: activateXdotool}
+	case platform.EnvironmentWayland:
+		switch {
+		case os.Getenv("SWAYSOCK") != "":
+			d = &toolDetector{tool: "swaymsg", detect: detectSway, activate: activateSway}
+		case os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != "":
+			d = &toolDetector{tool: "hyprctl", detect: detectHyprland, activate: activateHyprland}
+		case strings.Contains(strings.ToUpper(platform.DetectDesktopEnvironment()), "KDE"):
+			d = &toolDetector{tool: "kdotool", detect: detectKdotool, activate: activateKdotool}
+		}
+	}
+	if d == nil {
+		return unsupportedDetector{}
+	}
+	d.cfg = cfg
+	d.exec = exec
+	return d
+}
+
+// Detect runs the backend after verifying it is allowed and installed
+func (d *toolDetector) Detect() (Info, error) {
+	if err := d.checkTool(); err != nil {
+		return Info{}, err
+	}
+	return d.detect(withTimeout(d.exec, detectTimeout))
+}
+
+// Activate focuses a window through the same backend.
+// Returns ErrTimeout when the backend did not confirm the activation in time
+func (d *toolDetector) Activate(id string) error {
+	if err := d.checkTool(); err != nil {
+		return err
+	}
+	return d.activate(withTimeout(d.exec, activateTimeout), id)
+}
+
+// checkTool verifies the backend tool is allowed and installed
+func (d *toolDetector) checkTool() error {
+	if d.cfg != nil && !config.IsCommandAllowed(d.cfg.Get(), d.tool) {
+		return fmt.Errorf("window detection tool not allowed: %s", d.tool)
+	}
+	if _, err := exec.LookPath(d.tool); err != nil {
+		return fmt.Errorf("window detection tool not found: %s", d.tool)
+	}
+	return nil
+}
+
+// Name returns the backend tool name
+func (d *toolDetector) Name() string { return d.tool }
+
+// unsupportedDetector is used on sessions without a focus query API (e.g., GNOME Wayland)
+type unsupportedDetector struct{}
+
+func (unsupportedDetector) Detect() (Info, error) { return Info{}, ErrUnsupported }
+func (unsupportedDetector) Activate(string) error { return ErrUnsupported }
+func (unsupportedDetector) Name() string          { return "none" }
+
+// detectXdotool queries X11 (or XWayland) via xdotool
+func detectXdotool(run commandRunner) (Info, error) {
+	return detectXdoStyle(run, "xdotool")
+}
+
+// detectKdotool queries KWin via kdotool, which mirrors the xdotool command set
+func detectKdotool(run commandRunner) (Info, error) {
+	return detectXdoStyle(run, "kdotool")
+}
+
+// detectXdoStyle resolves the active window ID first, then its class and title
+func detectXdoStyle(run commandRunner, tool string) (Info, error) {
+	out, err := run(tool, "getactivewindow")
+	if err != nil {
+		return Info{}, fmt.Errorf("%s getactivewindow failed: %w", tool, err)
+	}
+	id := strings.TrimSpace(string(out))
+	if id == "" {
+		return Info{}, fmt.Errorf("%s returned no active window", tool)
+	}
+	info := Info{ID: id}
+	if out, err := run(tool, "getwindowclassname", id); err == nil {
+		info.Class = strings.TrimSpace(string(out))
+	}
+	if out, err := run(tool, "getwindowname", id); err == nil {
+		info.Title = strings.TrimSpace(string(out))
+	}
+	return info, nil
+}
+
+// swayNode is the subset of the sway tree needed to locate the focused window
+type swayNode struct {
+	ID               int64   `json:"id"`
+	Name             string  `json:"name"`
+	Focused          bool    `json:"focused"`
+	AppID            *string `json:"app_id"`
+	WindowProperties *struct {
+		Class string `json:"class"`
+	} `json:"window_properties"`
+	Nodes         []swayNode `json:"nodes"`
+	FloatingNodes []swayNode `json:"floating_nodes"`
+}
+
+// detectSway walks the sway layout tree for the focused container
+func detectSway(run commandRunner) (Info, error) {
+	out, err := run("swaymsg", "-t", "get_tree", "--raw")
+	if err != nil {
+		return Info{}, fmt.Errorf("swaymsg get_tree failed: %w", err)
+	}
+	var root swayNode
+	if err := json.Unmarshal(out, &root); err != nil {
+		return Info{}, fmt.Errorf("failed to parse sway tree: %w", err)
+	}
+	node := findFocusedSwayNode(&root)
+	if node == nil {
+		return Info{}, fmt.Errorf("sway reported no focused window")
+	}
+	info := Info{ID: strconv.FormatInt(node.ID, 10), Title: node.Name}
+	switch {
+	case node.AppID != nil && *node.AppID != "":
+		info.Class = *node.AppID
+	case node.WindowProperties != nil:
+		info.Class = node.WindowProperties.Class // XWayland client
+	}
+	return info, nil
+}
+
+func findFocusedSwayNode(node *swayNode) *swayNode {
+	if node.Focused {
+		return node
+	}
+	for i := range node.Nodes {
+		if found := findFocusedSwayNode(&node.Nodes[i]); found != nil {
+			return found
+		}
+	}
+	for i := range node.FloatingNodes {
+		if found := findFocusedSwayNode(&node.FloatingNodes[i]); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// detectHyprland queries the focused client via hyprctl JSON output
+func detectHyprland(run commandRunner) (Info, error) {
+	out, err := run("hyprctl", "activewindow", "-j")
+	if err != nil {
+		return Info{}, fmt.Errorf("hyprctl activewindow failed: %w", err)
+	}
+	var window struct {
+		Address string `json:"address"`
+		Class   string `json:"class"`
+		Title   string `json:"title"`
+	}
+	if err := json.Unmarshal(out, &window); err != nil {
+		return Info{}, fmt.Errorf("failed to parse hyprctl output: %w", err)
+	}
+	if window.Address == "" {
+		return Info{}, fmt.Errorf("hyprland reported no focused window")
+	}
+	return Info{ID: window.Address, Class: window.Class, Title: window.Title}, nil
+}
+
+// Activation backends. A failed activation is followed by an existence
+// check so a closed window is told apart from a tool or protocol error
+
+// activateXdotool raises and focuses an X11 window, waiting until it is active
+func activateXdotool(run commandRunner, id string) error {
+	return activateXdoStyle(run, "xdotool", "windowactivate", "--sync", id)
+}
+
+// activateKdotool activates a window through KWin scripting
+func activateKdotool(run commandRunner, id string) error {
+	return activateXdoStyle(run, "kdotool", "windowactivate", id)
+}
+
+func activateXdoStyle(run commandRunner, tool string, args ...string) error {
+	if _, err := run(tool, args...); err != nil {
+		if errors.Is(err, ErrTimeout) {
+			// --sync never returned: the window may exist but cannot be raised
+			return err
+		}
+		id := args[len(args)-1]
+		if _, nameErr := run(tool, "getwindowname", id); nameErr != nil {
+			return fmt.Errorf("%s window %s: %w", tool, id, ErrWindowClosed)
+		}
+		return fmt.Errorf("%s windowactivate failed: %w", tool, err)
+	}
+	return nil
+}
+
+// activateSway focuses a container by its con_id
+func activateSway(run commandRunner, id string) error {
+	if _, err := run("swaymsg", fmt.Sprintf("[con_id=%s]", id), "focus"); err != nil {
+		out, treeErr := run("swaymsg", "-t", "get_tree", "--raw")
+		if treeErr != nil {
+			return fmt.Errorf("swaymsg focus failed: %w", err)
+		}
+		var root swayNode
+		if jsonErr := json.Unmarshal(out, &root); jsonErr == nil && findSwayNode(&root, id) == nil {
+			return fmt.Errorf("sway container %s: %w", id, ErrWindowClosed)
+		}
+		return fmt.Errorf("swaymsg focus failed: %w", err)
+	}
+	return nil
+}
+
+// findSwayNode returns the container with the given con_id
+func findSwayNode(node *swayNode, id string) *swayNode {
+	if strconv.FormatInt(node.ID, 10) == id {
+		return node
+	}
+	for _, children := range [][]swayNode{node.Nodes, node.FloatingNodes} {
+		for i := range children {
+			if found := findSwayNode(&children[i], id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// activateHyprland focuses a client by address. hyprctl reports dispatch
+// errors on stdout rather than through its exit status
+func activateHyprland(run commandRunner, id string) error {
+	out, err := run("hyprctl", "dispatch", "focuswindow", "address:"+id)
+	if err == nil && strings.TrimSpace(string(out)) == "ok" {
+		return nil
+	}
+	if clients, listErr := run("hyprctl", "clients", "-j"); listErr == nil {
+		var list []struct {
+			Address string `json:"address"`
+		}
+		if json.Unmarshal(clients, &list) == nil {
+			found := false
+			for _, client := range list {
+				found = found || client.Address == id
+			}
+			if !found {
+				return fmt.Errorf("hyprland window %s: %w", id, ErrWindowClosed)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("hyprctl dispatch focuswindow failed: %w", err)
+	}
+	return fmt.Errorf("hyprctl dispatch focuswindow failed: %s", strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package activewindow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/internal/platform"
+)
+
+// fakeRunner returns canned output keyed by the joined command line
+func fakeRunner(outputs map[string]string) commandRunner {
+	return func(name string, args ...string) ([]byte, error) {
+		key := strings.Join(append([]string{name}, args...), " ")
+		if out, ok := outputs[key]; ok {
+			return []byte(out), nil
+		}
+		return nil, errors.New("unexpected command: " + key)
+	}
+}
+
+func TestDetectXdotool(t *testing.T) {
+	run := fakeRunner(map[string]string{
+		"xdotool getactivewindow":             "62914567\n",
+		"xdotool getwindowclassname 62914567": "kitty\n",
+		"xdotool getwindowname 62914567":      "~/src: vim\n",
+	})
+	info, err := detectXdotool(run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ID != "62914567" || info.Class != "kitty" || info.Title != "~/src: vim" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestDetectXdotool_NoWindow(t *testing.T) {
+	run := fakeRunner(map[string]string{"xdotool getactivewindow": "\n"})
+	if _, err := detectXdotool(run); err == nil {
+		t.Error("expected error when no active window is reported")
+	}
+}
+
+func TestDetectSway(t *testing.T) {
+	tree := `{"id":1,"name":"root","focused":false,"nodes":[
+		{"id":4,"name":"1","focused":false,"nodes":[
+			{"id":7,"name":"Mozilla Firefox","focused":false,"app_id":"firefox","nodes":[]},
+			{"id":9,"name":"IntelliJ IDEA","focused":true,"app_id":null,"window_properties":{"class":"jetbrains-idea"},"nodes":[]}
+		],"floating_nodes":[]}
+	],"floating_nodes":[]}`
+	run := fakeRunner(map[string]string{"swaymsg -t get_tree --raw": tree})
+	info, err := detectSway(run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ID != "9" || info.Class != "jetbrains-idea" || info.Title != "IntelliJ IDEA" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+}
+
+func TestDetectSway_FloatingAppID(t *testing.T) {
+	tree := `{"id":1,"focused":false,"nodes":[],"floating_nodes":[
+		{"id":12,"name":"Terminal","focused":true,"app_id":"foot","nodes":[]}
+	]}`
+	run := fakeRunner(map[string]string{"swaymsg -t get_tree --raw": tree})
+	info, err := detectSway(run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Class != "foot" {
+		t.Errorf("expected class foot, got %q", info.Class)
+	}
+}
+
+func TestDetectHyprland(t *testing.T) {
+	run := fakeRunner(map[string]string{
+		"hyprctl activewindow -j": `{"address":"0x55d4c1a0","class":"code","title":"main.go - Code"}`,
+	})
+	info, err := detectHyprland(run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.ID != "0x55d4c1a0" || info.Class != "code" || info.Title != "main.go - Code" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	empty := fakeRunner(map[string]string{"hyprctl activewindow -j": `{}`})
+	if _, err := detectHyprland(empty); err == nil {
+		t.Error("expected error when hyprland reports no window")
+	}
+}
+
+func TestNewDetector_Selection(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      platform.EnvironmentType
+		vars     map[string]string
+		expected string
+	}{
+		{name: "x11", env: platform.EnvironmentX11, expected: "xdotool"},
+		{name: "sway", env: platform.EnvironmentWayland, vars: map[string]string{"SWAYSOCK": "/run/sway.sock"}, expected: "swaymsg"},
+		{name: "hyprland", env: platform.EnvironmentWayland, vars: map[string]string{"HYPRLAND_INSTANCE_SIGNATURE": "abc"}, expected: "hyprctl"},
+		{name: "kde", env: platform.EnvironmentWayland, vars: map[string]string{"XDG_CURRENT_DESKTOP": "KDE"}, expected: "kdotool"},
+		{name: "gnome wayland", env: platform.EnvironmentWayland, vars: map[string]string{"XDG_CURRENT_DESKTOP": "GNOME"}, expected: "none"},
+		{name: "unknown", env: platform.EnvironmentUnknown, expected: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SWAYSOCK", "")
+			t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+			t.Setenv("XDG_CURRENT_DESKTOP", "")
+			for k, v := range tt.vars {
+				t.Setenv(k, v)
+			}
+			d := newDetector(nil, tt.env, runCommand)
+			if d.Name() != tt.expected {
+				t.Errorf("expected %s backend, got %s", tt.expected, d.Name())
+			}
+		})
+	}
+}
+
+func TestUnsupportedDetector(t *testing.T) {
+	if _, err := (unsupportedDetector{}).Detect(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+	if err := (unsupportedDetector{}).Activate("1"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestActivate(t *testing.T) {
+	swayTree := `{"id":1,"nodes":[{"id":4,"nodes":[{"id":9,"name":"vim","nodes":[]}]}],"floating_nodes":[]}`
+	hyprClients := `[{"address":"0x55d4c1a0"}]`
+	tests := []struct {
+		name     string
+		activate func(run commandRunner, id string) error
+		id       string
+		outputs  map[string]string
+		wantErr  bool
+		closed   bool
+	}{
+		{"xdotool", activateXdotool, "62914567", map[string]string{"xdotool windowactivate --sync 62914567": ""}, false, false},
+		{"xdotool closed", activateXdotool, "62914567", nil, true, true},
+		{"xdotool refused", activateXdotool, "62914567", map[string]string{"xdotool getwindowname 62914567": "vim\n"}, true, false},
+		{"kdotool", activateKdotool, "{a1}", map[string]string{"kdotool windowactivate {a1}": ""}, false, false},
+		{"kdotool closed", activateKdotool, "{a1}", nil, true, true},
+		{"sway", activateSway, "9", map[string]string{"swaymsg [con_id=9] focus": ""}, false, false},
+		{"sway closed", activateSway, "12", map[string]string{"swaymsg -t get_tree --raw": swayTree}, true, true},
+		{"sway refused", activateSway, "9", map[string]string{"swaymsg -t get_tree --raw": swayTree}, true, false},
+		{"hyprland", activateHyprland, "0x55d4c1a0", map[string]string{"hyprctl dispatch focuswindow address:0x55d4c1a0": "ok\n"}, false, false},
+		{"hyprland closed", activateHyprland, "0x1", map[string]string{
+			"hyprctl dispatch focuswindow address:0x1": "No such window found\n",
+			"hyprctl clients -j":                       hyprClients,
+		}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.activate(fakeRunner(tt.outputs), tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if errors.Is(err, ErrWindowClosed) != tt.closed {
+				t.Errorf("expected closed=%v, got %v", tt.closed, err)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	hung := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	run := withTimeout(hung, 10*time.Millisecond)
+	if _, err := run("xdotool", "windowactivate", "--sync", "62914567"); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+
+	// A hung --sync is reported as a timeout, not as a closed window
+	err := activateXdotool(run, "62914567")
+	if !errors.Is(err, ErrTimeout) || errors.Is(err, ErrWindowClosed) {
+		t.Errorf("expected ErrTimeout for hung activation, got %v", err)
+	}
+
+	quick := withTimeout(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("ok"), nil
+	}, time.Second)
+	if out, err := quick("hyprctl", "activewindow", "-j"); err != nil || string(out) != "ok" {
+		t.Errorf("expected output from a quick command, got %q, %v", out, err)
+	}
+}
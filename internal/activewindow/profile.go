@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package activewindow
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/AshBuk/dabri/config"
+)
+
+// MatchProfile returns the first profile whose patterns match the window.
+// A profile matches when every non-empty pattern (class, title) matches.
+// Returns nil if no profile applies
+func MatchProfile(profiles []config.Profile, info Info) *config.Profile {
+	for i := range profiles {
+		profile := &profiles[i]
+		if profile.WindowClass == "" && profile.WindowTitle == "" {
+			continue
+		}
+		if !patternMatches(profile.WindowClass, info.Class) {
+			continue
+		}
+		if !patternMatches(profile.WindowTitle, info.Title) {
+			continue
+		}
+		return profile
+	}
+	return nil
+}
+
+// patterns caches compiled profile patterns, so each configured pattern is
+// compiled once instead of on every recording (nil for invalid patterns)
+var patterns sync.Map // string → *regexp.Regexp
+
+// patternMatches treats an empty pattern as a wildcard and invalid patterns as non-matching
+func patternMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	re := compilePattern(pattern)
+	return re != nil && re.MatchString(value)
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if cached, ok := patterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	patterns.Store(pattern, re)
+	return re
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package activewindow
+
+import (
+	"testing"
+
+	"github.com/AshBuk/dabri/config"
+)
+
+func TestMatchProfile(t *testing.T) {
+	profiles := []config.Profile{
+		{Name: "terminal", WindowClass: "(?i)^(kitty|alacritty|foot)$", OutputMode: config.OutputModeClipboard},
+		{Name: "ide-go", WindowClass: "(?i)code", WindowTitle: `\.go\b`, Language: "en"},
+		{Name: "ide", WindowClass: "(?i)code|jetbrains"},
+		{Name: "broken", WindowClass: "([", OutputMode: config.OutputModeClipboard},
+	}
+
+	tests := []struct {
+		name     string
+		info     Info
+		expected string
+	}{
+		{name: "terminal by class", info: Info{Class: "Alacritty"}, expected: "terminal"},
+		{name: "class and title must both match", info: Info{Class: "code", Title: "main.go - Code"}, expected: "ide-go"},
+		{name: "falls through to next profile", info: Info{Class: "code", Title: "README.md - Code"}, expected: "ide"},
+		{name: "no match", info: Info{Class: "firefox", Title: "GitHub"}, expected: ""},
+		{name: "invalid pattern never matches", info: Info{Class: "(["}, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := MatchProfile(profiles, tt.info)
+			got := ""
+			if profile != nil {
+				got = profile.Name
+			}
+			if got != tt.expected {
+				t.Errorf("expected profile %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchProfile_SkipsPatternlessProfiles(t *testing.T) {
+	profiles := []config.Profile{{Name: "catch-all"}}
+	if profile := MatchProfile(profiles, Info{Class: "anything"}); profile != nil {
+		t.Errorf("expected nil for profile without patterns, got %s", profile.Name)
+	}
+}
+
+func TestCompilePattern_Cached(t *testing.T) {
+	first := compilePattern("^kitty$")
+	if first == nil || first != compilePattern("^kitty$") {
+		t.Error("expected the compiled pattern to be reused")
+	}
+	if compilePattern("([") != nil || patternMatches("([", "([") {
+		t.Error("expected invalid patterns to never match")
+	}
+}
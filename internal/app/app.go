@@ -8,13 +8,20 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/ipc"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
 	"github.com/AshBuk/dabri/internal/platform"
 	"github.com/AshBuk/dabri/internal/services"
+	"github.com/AshBuk/dabri/internal/systemd"
 )
 
 // Manages application lifecycle and context
@@ -22,6 +29,7 @@ type RuntimeContext struct {
 	Ctx        context.Context    // Main application context for cancellation propagation
 	Cancel     context.CancelFunc // Function to cancel the context and trigger shutdown
 	ShutdownCh chan os.Signal     // Channel receiving OS signals (SIGINT, SIGTERM)
+	ReloadCh   chan os.Signal     // Channel receiving SIGHUP (re-read the config file)
 	Logger     logger.Logger      // Application-wide logger instance
 }
 
@@ -30,28 +38,47 @@ func NewRuntimeContext(logger logger.Logger) *RuntimeContext {
 	ctx, cancel := context.WithCancel(context.Background())
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, os.Interrupt, syscall.SIGTERM)
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
 
 	return &RuntimeContext{
 		Ctx:        ctx,
 		Cancel:     cancel,
 		ShutdownCh: shutdownCh,
+		ReloadCh:   reloadCh,
 		Logger:     logger,
 	}
 }
 
+// shutdownResponseDelay lets the IPC response of daemon-stop/restart reach the
+// client before the IPC server closes its connections
+const shutdownResponseDelay = 200 * time.Millisecond
+
 // Represents the main application and its services
 type App struct {
 	Services  *services.ServiceContainer // DI Pattern. Container for: Audio, UI, IO, Config, Hotkeys, TempFileManager
 	Runtime   *RuntimeContext            // Application lifecycle management (context, cancel, shutdown channel, logger)
 	ipcServer *ipc.Server                // Inter-process communication server for CLI commands
+	startedAt time.Time                  // Daemon start, reported by daemon-pid to tell instances apart
+	restart   atomic.Bool                // Set by daemon-restart; the caller re-executes after shutdown
+	// Sockets passed by systemd socket activation, kept open for a restart (see systemd.go)
+	listenFiles []*os.File
+	// Serves metrics on a Unix socket while the web server is disabled (see metrics.go)
+	metricsServer *metrics.SocketServer
+	// Serializes config changes and their application to the running services
+	// (SIGHUP, daemon-reload, config-set), so services never end up rebuilt from an older config
+	configMu sync.Mutex
 }
 
 // NewApp Constructor - creates a new application instance
 // Initializes empty service container and runtime context
 func NewApp(logger logger.Logger) *App {
 	return &App{
-		Services: services.NewServiceContainer(),
-		Runtime:  NewRuntimeContext(logger),
+		Services:  services.NewServiceContainer(),
+		Runtime:   NewRuntimeContext(logger),
+		startedAt: time.Now(),
+		// Claimed first so helper processes never inherit the sockets
+		listenFiles: systemd.ListenFiles(),
 	}
 }
 
@@ -82,6 +109,9 @@ func (a *App) initializeConfig(configFile string, debug bool) (*config.Config, e
 	if debug {
 		cfg.General.Debug = true
 	}
+	if err := a.configureLogging(cfg, debugLevel(cfg.General.Debug)); err != nil {
+		a.Runtime.Logger.Warning("Log file not opened: %v", err)
+	}
 
 	a.Runtime.Logger.Info("Configuration loaded successfully")
 	return cfg, nil
@@ -95,11 +125,11 @@ func (a *App) initializeServices(cfg *config.Config, cfgFilePath string) error {
 
 	// Factory Constructor: NewServiceFactory(config) → *ServiceFactory (stores configuration)
 	factory := services.NewServiceFactory(services.ServiceFactoryConfig{
-		Ctx:         a.Runtime.Ctx,    // Application context for cancellation propagation
-		Logger:      a.Runtime.Logger, // Application-wide logger for all services
-		Config:      cfg,              // Loaded YAML configuration
-		ConfigFile:  cfgFilePath,      // Path to config file for reloading
-		Environment: environment,      // Detected runtime environment (Native/AppImage/etc)
+		Ctx:         a.Runtime.Ctx,       // Application context for cancellation propagation
+		Logger:      a.Runtime.Logger,    // Application-wide logger for all services
+		Config:      config.NewLive(cfg), // Running configuration, published as immutable snapshots
+		ConfigFile:  cfgFilePath,         // Path to config file for reloading
+		Environment: environment,         // Detected runtime environment (Native/AppImage/etc)
 	})
 	// Factory Method: factory.CreateServices() → *ServiceContainer (all services ready)
 	serviceContainer, err := factory.CreateServices()
@@ -117,10 +147,22 @@ func (a *App) initializeServices(cfg *config.Config, cfgFilePath string) error {
 	return nil
 }
 
-// setupServiceDependencies is a no-op placeholder for future cross-service wiring
-// Note: All dependency injection is handled in FactoryAssembler.Assemble()
+// setupServiceDependencies wires services to app-level handlers
+// Note: Service-to-service injection is handled in FactoryAssembler.Assemble()
 func (a *App) setupServiceDependencies() {
-	// Wiring done in factory_assembler.go (Step 4: Late wiring)
+	if a.Services == nil {
+		return
+	}
+	// handlers.go: WebSocket cancel also resyncs the start/stop hotkey toggle
+	if a.Services.IO != nil {
+		a.Services.IO.SetRemoteCancelHandler(a.handleCancelRecording)
+	}
+	// The cancel hotkey is bound only while a dictation is active
+	if a.Services.Audio != nil && a.Services.Hotkeys != nil {
+		a.Services.Audio.SetActivityCallback(func(active bool) {
+			a.Services.Hotkeys.SetActionEnabled("cancel", active)
+		})
+	}
 }
 
 // Connect hotkey manager events to their corresponding handler methods
@@ -130,15 +172,28 @@ func (a *App) setupHotkeyCallbacks() error {
 		return fmt.Errorf("services not initialized")
 	}
 
-	// Register callback functions defined in handlers.go
+	// Register callback functions defined in handlers.go.
+	// Every hotkey press first aborts a transcript still being typed
 	if err := a.Services.Hotkeys.SetupHotkeyCallbacks(
-		a.handleStartRecording,             // handlers.go: Start audio recording
-		a.handleStopRecordingAndTranscribe, // handlers.go: Stop recording and transcribe
-		a.handleShowConfig,                 // handlers.go: Display configuration
-		a.handleResetToDefaults,            // handlers.go: Reset settings to defaults
+		a.interruptTyping(a.handleStartRecording),             // handlers.go: Start audio recording
+		a.interruptTyping(a.handleStopRecordingAndTranscribe), // handlers.go: Stop recording and transcribe
+		a.interruptTyping(a.handleShowConfig),                 // handlers.go: Display configuration
+		a.interruptTyping(a.handleResetToDefaults),            // handlers.go: Reset settings to defaults
 	); err != nil {
 		return fmt.Errorf("failed to set up hotkey callbacks: %w", err)
 	}
+	// handlers.go: Toggle recording in code dictation mode
+	if err := a.Services.Hotkeys.RegisterAction("code_recording", a.interruptTyping(a.handleToggleCodeRecording)); err != nil {
+		return fmt.Errorf("failed to set up code recording hotkey: %w", err)
+	}
+	// handlers.go: Undo the last dictation output
+	if err := a.Services.Hotkeys.RegisterAction("undo", a.interruptTyping(a.handleUndoHotkey)); err != nil {
+		return fmt.Errorf("failed to set up undo hotkey: %w", err)
+	}
+	// handlers.go: Discard the recording or pending transcription (aborts typing itself)
+	if err := a.Services.Hotkeys.RegisterOnDemandAction("cancel", a.handleCancelHotkey); err != nil {
+		return fmt.Errorf("failed to set up cancel hotkey: %w", err)
+	}
 	return nil
 }
 
@@ -159,6 +214,10 @@ func (a *App) startServices() error {
 		}
 	}
 
+	if err := a.startMetricsServer(); err != nil {
+		a.Runtime.Logger.Warning("Metrics server not started: %v", err)
+	}
+
 	if a.Services.Hotkeys != nil {
 		if err := a.Services.Hotkeys.RegisterHotkeys(); err != nil {
 			a.Runtime.Logger.Warning("Hotkeys not registered: %v", err)
@@ -179,15 +238,107 @@ func (a *App) RunAndWait() error {
 	if err := a.startIPCServer(); err != nil {
 		a.Runtime.Logger.Warning("CLI IPC server not started: %v", err)
 	}
+	a.notifyReady() // systemd.go: READY=1, watchdog, status line
 
-	select { // Blocks here waiting for shutdown signal
-	case <-a.Runtime.ShutdownCh: // OS signal (Ctrl+C, SIGTERM)
-		a.Runtime.Logger.Info("Received shutdown signal")
-	case <-a.Runtime.Ctx.Done(): // Programmatic cancellation
-		a.Runtime.Logger.Info("Context cancelled")
+	for { // Blocks here waiting for shutdown signal
+		select {
+		case <-a.Runtime.ReloadCh: // SIGHUP: re-read the config file, keep running
+			a.Runtime.Logger.Info("Received SIGHUP, reloading configuration")
+			if _, err := a.reloadConfig(); err != nil {
+				a.Runtime.Logger.Error("Configuration reload: %v", err)
+			}
+			continue
+		case <-a.Runtime.ShutdownCh: // OS signal (Ctrl+C, SIGTERM)
+			a.Runtime.Logger.Info("Received shutdown signal")
+		case <-a.Runtime.Ctx.Done(): // Programmatic cancellation (daemon-stop, daemon-restart)
+			a.Runtime.Logger.Info("Context cancelled")
+		}
+		return a.Shutdown()
 	}
+}
+
+// requestShutdown stops the daemon shortly after the current IPC response is
+// sent; with restart set, RestartRequested tells the caller to start it again
+func (a *App) requestShutdown(restart bool) {
+	a.restart.Store(restart)
+	if restart {
+		a.notifySystemd(systemd.Status("Restarting"))
+	}
+	time.AfterFunc(shutdownResponseDelay, a.Runtime.Cancel)
+}
+
+// RestartRequested reports whether the daemon stopped to restart (dabri daemon restart)
+func (a *App) RestartRequested() bool {
+	return a.restart.Load()
+}
+
+// configReload reports the outcome of a config file reload
+type configReload struct {
+	Changed         []string // Settings that differ from the running configuration
+	RestartRequired []string // Changed settings read only at startup
+	Warnings        []string // Settings saved in the file that could not be applied
+}
 
-	return a.Shutdown()
+// reloadConfig re-reads the config file and re-applies it to the running services
+// Hotkeys, output and recorder are rebuilt even when unchanged, so a reload also
+// recovers from a lost input device or output tool; the WebSocket and metrics
+// servers restart and the logger is reconfigured only when their settings changed.
+// The loaded whisper model is kept unless the file names another one. Services are
+// re-applied only after the new config is published. Fails only when the file cannot be read
+func (a *App) reloadConfig() (configReload, error) {
+	if a.Services == nil || a.Services.Config == nil || a.Services.Audio == nil || a.Services.IO == nil {
+		return configReload{}, fmt.Errorf("services not available")
+	}
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+	a.notifySystemd(systemd.Reloading, systemd.Status("Reloading configuration"))
+	defer a.notifySystemd(systemd.Ready, a.idleStatus())
+	previousModel := a.Services.Config.GetConfig().General.WhisperModel
+	changed, err := a.Services.Config.Reload()
+	if err != nil {
+		return configReload{}, err
+	}
+	result := configReload{Changed: changed}
+	if slices.Contains(changed, "general.whisper_model") {
+		model := a.Services.Config.GetConfig().General.WhisperModel
+		if err := a.Services.Audio.SwitchModel(a.Runtime.Ctx, model); err != nil {
+			// Keep the loaded model so status reports the model actually in use
+			a.Services.Config.SetLoadedModel(previousModel)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("model %s not loaded, keeping %s: %v", model, previousModel, err))
+		}
+	}
+	for _, key := range changed {
+		if restartRequired(key) {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+	sections := []string{"hotkeys", "output", "audio"}
+	if slices.ContainsFunc(changed, func(key string) bool {
+		return strings.HasPrefix(key, "web_server.") || strings.HasPrefix(key, "metrics.")
+	}) {
+		sections = append(sections, "web_server")
+	}
+	if slices.ContainsFunc(changed, func(key string) bool {
+		return strings.HasPrefix(key, "logging.")
+	}) {
+		sections = append(sections, "logging")
+	}
+	if slices.Contains(changed, "general.debug") {
+		sections = append(sections, "general.debug")
+	}
+	for _, section := range sections {
+		if _, err := a.applyConfigChange(section); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %v", section, err))
+		}
+	}
+	a.Runtime.Logger.Info("Configuration reloaded: %d settings changed", len(changed))
+	for _, warning := range result.Warnings {
+		a.Runtime.Logger.Warning("Reloaded setting not applied: %s", warning)
+	}
+	if len(result.RestartRequired) > 0 {
+		a.Runtime.Logger.Warning("Restart dabri to apply: %s", strings.Join(result.RestartRequired, ", "))
+	}
+	return result, nil
 }
 
 // Graceful Shutdown - ensures clean resource cleanup
@@ -195,10 +346,14 @@ func (a *App) RunAndWait() error {
 // Each service is responsible for waiting on its own goroutines via WaitGroup
 func (a *App) Shutdown() error {
 	a.Runtime.Logger.Info("Shutting down application...")
+	if !a.RestartRequested() {
+		a.notifySystemd(systemd.Stopping)
+	}
 	a.Runtime.Cancel() // Signal all goroutines to stop
 	if a.ipcServer != nil {
 		a.ipcServer.Stop()
 	}
+	a.stopMetricsServer()
 	if a.Services != nil {
 		if err := a.Services.Shutdown(); err != nil {
 			a.Runtime.Logger.Error("Error during service shutdown: %v", err)
@@ -4,11 +4,18 @@
 package app
 
 import (
+	"io"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/history"
+	"github.com/AshBuk/dabri/internal/ipc"
+	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/services"
 	"github.com/AshBuk/dabri/internal/testutils"
+	"github.com/AshBuk/dabri/tests/mocks"
 )
 
 func TestRuntimeContext_NewRuntimeContext(t *testing.T) {
@@ -224,3 +231,179 @@ func TestApp_HandlerMethods_NilSpecificServices(t *testing.T) {
 		t.Error("handleResetToDefaults should fail with nil config service")
 	}
 }
+
+// toggleAudioService tracks the recording state set by start and stop
+type toggleAudioService struct {
+	mocks.MockAudioService
+	recording bool
+	options   config.SessionOptions
+}
+
+func (r *toggleAudioService) IsRecording() bool { return r.recording }
+
+func (r *toggleAudioService) HandleStartRecordingWithOptions(opts config.SessionOptions) error {
+	r.recording, r.options = true, opts
+	return nil
+}
+
+func (r *toggleAudioService) HandleStopRecording() error {
+	r.recording = false
+	return nil
+}
+
+// toggleHotkeyService records the start/stop hotkey toggle state
+type toggleHotkeyService struct {
+	mocks.MockHotkeyService
+	recording bool
+}
+
+func (r *toggleHotkeyService) SetRecordingState(recording bool) { r.recording = recording }
+
+func TestApp_HandleToggleCodeRecordingSyncsHotkeyToggle(t *testing.T) {
+	app := NewApp(testutils.NewMockLogger())
+	audio := &toggleAudioService{}
+	hotkeys := &toggleHotkeyService{}
+	app.Services.Audio = audio
+	app.Services.Hotkeys = hotkeys
+
+	if err := app.handleToggleCodeRecording(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if !audio.recording || audio.options.PostProcessing != config.PostProcessingCode {
+		t.Errorf("code recording not started: recording=%t options=%+v", audio.recording, audio.options)
+	}
+	if !hotkeys.recording {
+		t.Error("start/stop hotkey toggle should stop the code recording on its next press")
+	}
+
+	if err := app.handleToggleCodeRecording(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if audio.recording || hotkeys.recording {
+		t.Errorf("after stop: audio recording=%t, hotkey toggle=%t", audio.recording, hotkeys.recording)
+	}
+}
+
+// recordingIOService captures re-output requests from history handlers
+type recordingIOService struct {
+	mocks.MockIOService
+	text, mode string
+}
+
+func (r *recordingIOService) OutputToMode(text, mode string) error {
+	r.text, r.mode = text, mode
+	return nil
+}
+
+func TestApp_IPCHistory(t *testing.T) {
+	app := NewApp(testutils.NewMockLogger())
+	store := history.NewStore(filepath.Join(t.TempDir(), history.FileName), nil)
+	io := &recordingIOService{}
+	app.Services.History = store
+	app.Services.IO = io
+	for _, text := range []string{"first note", "second note", "unrelated"} {
+		if _, err := store.Add(history.Entry{Text: text}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     ipc.Request
+		handler func(ipc.Request) (ipc.Response, error)
+		wantErr bool
+		wantLen int
+	}{
+		{"list", ipc.Request{Command: "history-list"}, app.ipcHandleHistoryList, false, 3},
+		{"list limit", ipc.Request{Command: "history-list", Params: map[string]string{"limit": "1"}}, app.ipcHandleHistoryList, false, 1},
+		{"invalid limit", ipc.Request{Command: "history-list", Params: map[string]string{"limit": "x"}}, app.ipcHandleHistoryList, true, 0},
+		{"typed limit", typedRequest(t, "history-list", map[string]any{"limit": 2}), app.ipcHandleHistoryList, false, 2},
+		{"fractional limit", typedRequest(t, "history-list", map[string]any{"limit": 1.5}), app.ipcHandleHistoryList, true, 0},
+		{"search", ipc.Request{Command: "history-search", Params: map[string]string{"query": "NOTE"}}, app.ipcHandleHistoryList, false, 2},
+		{"search without query", ipc.Request{Command: "history-search"}, app.ipcHandleHistoryList, true, 0},
+		{"show missing id", ipc.Request{Command: "history-show"}, app.ipcHandleHistoryShow, true, 0},
+		{"show unknown id", ipc.Request{Command: "history-show", Params: map[string]string{"id": "99"}}, app.ipcHandleHistoryShow, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.handler(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			entries, _ := resp.Data.(map[string]any)["entries"].([]history.Entry)
+			if len(entries) != tt.wantLen {
+				t.Errorf("got %d entries, want %d", len(entries), tt.wantLen)
+			}
+		})
+	}
+
+	if _, err := app.ipcHandleHistoryOutput(ipc.Request{Command: "history-type", Params: map[string]string{"id": "2"}}); err != nil {
+		t.Fatalf("history-type: %v", err)
+	}
+	if io.text != "second note" || io.mode != config.OutputModeActiveWindow {
+		t.Errorf("history-type output %q via %q", io.text, io.mode)
+	}
+	if _, err := app.ipcHandleHistoryOutput(typedRequest(t, "history-copy", map[string]any{"id": 1})); err != nil {
+		t.Fatalf("history-copy: %v", err)
+	}
+	if io.text != "first note" || io.mode != config.OutputModeClipboard {
+		t.Errorf("history-copy output %q via %q", io.text, io.mode)
+	}
+
+	if _, err := app.ipcHandleHistoryClear(ipc.Request{Command: "history-clear"}); err != nil {
+		t.Fatalf("history-clear: %v", err)
+	}
+	if entries, _ := store.List(0); len(entries) != 0 {
+		t.Errorf("history not cleared: %v", entries)
+	}
+}
+
+func TestApp_IPCLogLevel(t *testing.T) {
+	if _, err := NewApp(testutils.NewMockLogger()).ipcHandleLogLevel(ipc.Request{Command: "log-level"}); err == nil {
+		t.Error("Expected an error for a logger without runtime levels")
+	}
+
+	sl := logger.NewStructuredLogger(logger.Options{Level: logger.InfoLevel, Output: io.Discard})
+	app := NewApp(sl)
+	tests := []struct {
+		name      string
+		params    map[string]string
+		wantErr   bool
+		wantLevel logger.LogLevel
+	}{
+		{"report", nil, false, logger.InfoLevel},
+		{"set debug", map[string]string{"level": "debug"}, false, logger.DebugLevel},
+		{"set warn", map[string]string{"level": "warn"}, false, logger.WarningLevel},
+		{"invalid level", map[string]string{"level": "loud"}, true, logger.WarningLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.ipcHandleLogLevel(ipc.Request{Command: "log-level", Params: tt.params})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if sl.Level() != tt.wantLevel {
+				t.Errorf("level = %v, want %v", sl.Level(), tt.wantLevel)
+			}
+			if tt.wantErr {
+				return
+			}
+			if level := resp.Data.(map[string]any)["level"]; level != tt.wantLevel.String() {
+				t.Errorf("response level = %v, want %v", level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+// typedRequest builds a request with typed params, as a protocol v2 client sends them
+func typedRequest(t *testing.T, command string, params map[string]any) ipc.Request {
+	t.Helper()
+	req, err := ipc.NewRequest(command, params)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	return req
+}
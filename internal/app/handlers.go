@@ -4,9 +4,13 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/AshBuk/dabri/hotkeys/adapters"
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/services"
 )
 
 // Hotkey Handlers - Adapter layer between HotkeyService and Business Services
@@ -25,20 +29,110 @@ import (
 //       ↓
 //   Business Services (AudioService/ConfigService/UIService)
 
+// undoReleaseTimeout bounds how long the undo hotkey waits for its modifiers to be released
+const undoReleaseTimeout = 2 * time.Second
+
+// interruptTyping Decorator - aborts an in-flight typing job before running the hotkey handler
+// A long transcript would otherwise keep typing into whatever window is focused next
+func (a *App) interruptTyping(handler func() error) func() error {
+	return func() error {
+		if a.Services != nil && a.Services.IO != nil && a.Services.IO.CancelTyping() {
+			a.Runtime.Logger.Info("Typing aborted by hotkey press")
+		}
+		return handler()
+	}
+}
+
 // handleStartRecording Adapter - delegates start recording hotkey to AudioService
 func (a *App) handleStartRecording() error {
+	return a.handleStartRecordingWithOptions(config.SessionOptions{})
+}
+
+// handleStartRecordingWithOptions Adapter - starts recording with per-dictation overrides (IPC)
+func (a *App) handleStartRecordingWithOptions(opts config.SessionOptions) error {
 	if a.Services == nil || a.Services.Audio == nil {
 		return fmt.Errorf("audio service not available")
 	}
-	return a.Services.Audio.HandleStartRecording()
+	return a.Services.Audio.HandleStartRecordingWithOptions(opts)
 }
 
 // handleStopRecordingAndTranscribe Adapter - delegates stop recording hotkey to AudioService
 func (a *App) handleStopRecordingAndTranscribe() error {
+	return a.handleStopRecordingWithOptions(config.SessionOptions{})
+}
+
+// handleStopRecordingWithOptions Adapter - stops recording, overriding the start options (IPC)
+func (a *App) handleStopRecordingWithOptions(opts config.SessionOptions) error {
+	if a.Services == nil || a.Services.Audio == nil {
+		return fmt.Errorf("audio service not available")
+	}
+	return a.Services.Audio.HandleStopRecordingWithOptions(opts)
+}
+
+// handleToggleCodeRecording Adapter - toggles recording in code dictation mode
+// Start applies code post-processing to this session only; stop is the regular stop path.
+// The start/stop hotkey toggle follows, so either hotkey can end the recording
+func (a *App) handleToggleCodeRecording() error {
 	if a.Services == nil || a.Services.Audio == nil {
 		return fmt.Errorf("audio service not available")
 	}
-	return a.Services.Audio.HandleStopRecording()
+	var err error
+	if a.Services.Audio.IsRecording() {
+		err = a.Services.Audio.HandleStopRecording()
+	} else {
+		err = a.Services.Audio.HandleStartRecordingWithOptions(config.SessionOptions{PostProcessing: config.PostProcessingCode})
+	}
+	if a.Services.Hotkeys != nil {
+		a.Services.Hotkeys.SetRecordingState(a.Services.Audio.IsRecording())
+	}
+	return err
+}
+
+// handleCancelRecording Adapter - discards the recording or pending transcription
+// Resets the start/stop hotkey toggle so its next press starts a new recording
+func (a *App) handleCancelRecording() error {
+	if a.Services == nil || a.Services.Audio == nil {
+		return fmt.Errorf("audio service not available")
+	}
+	if err := a.Services.Audio.CancelRecording(); err != nil {
+		return err
+	}
+	if a.Services.Hotkeys != nil {
+		a.Services.Hotkeys.ResetRecordingState()
+	}
+	return nil
+}
+
+// handleCancelHotkey Adapter - the cancel hotkey does nothing outside of a dictation,
+// so a plain key such as Escape can be bound to it
+func (a *App) handleCancelHotkey() error {
+	if err := a.handleCancelRecording(); err != nil && !errors.Is(err, services.ErrNothingToCancel) {
+		return err
+	}
+	return nil
+}
+
+// handleUndo Adapter - delegates the undo hotkey to IOService
+// Erases the last typed output or restores the clipboard it replaced
+func (a *App) handleUndo() error {
+	if a.Services == nil || a.Services.IO == nil {
+		return fmt.Errorf("IO service not available")
+	}
+	result, err := a.Services.IO.UndoLastOutput()
+	if err != nil {
+		return err
+	}
+	a.Runtime.Logger.Info("Undo: erased %d characters, clipboard restored: %t", result.Erased, result.ClipboardRestored)
+	return nil
+}
+
+// handleUndoHotkey waits for the undo hotkey to be let go before erasing:
+// BackSpace sent while Ctrl or Alt is still held deletes whole words
+func (a *App) handleUndoHotkey() error {
+	if a.Services != nil && a.Services.Hotkeys != nil && !a.Services.Hotkeys.WaitModifiersReleased(undoReleaseTimeout) {
+		return fmt.Errorf("undo canceled: hotkey modifiers still held")
+	}
+	return a.handleUndo()
 }
 
 // handleShowConfig Adapter - delegates show config hotkey to UIService
@@ -53,27 +147,63 @@ func (a *App) handleShowConfig() error {
 // Multi-service coordination: ConfigService.ResetToDefaults() → HotkeyService.ReloadFromConfig()
 // Ensures new default hotkey bindings are applied immediately without restart
 func (a *App) handleResetToDefaults() error {
-	if a.Services == nil || a.Services.Config == nil {
-		return fmt.Errorf("config service not available")
-	}
-	if err := a.Services.Config.ResetToDefaults(); err != nil {
+	actions, err := a.settingsActions()
+	if err != nil {
 		return err
 	}
-	if a.Services.Hotkeys != nil {
-		cfgProvider := func() adapters.HotkeyConfig {
-			if cfg := a.Services.Config.GetConfig(); cfg != nil {
-				return adapters.NewConfigAdapter(cfg.Hotkeys.StartRecording, cfg.Hotkeys.Provider).
-					WithAdditionalHotkeys(cfg.Hotkeys.ShowConfig, cfg.Hotkeys.ResetToDefaults)
-			}
-			return adapters.NewConfigAdapter("", "auto")
+	return actions.ResetToDefaults()
+}
+
+// settingsActions returns the settings actions shared with the tray menu
+func (a *App) settingsActions() (*services.SettingsActions, error) {
+	if a.Services == nil || a.Services.Actions == nil {
+		return nil, fmt.Errorf("services not available")
+	}
+	return a.Services.Actions, nil
+}
+
+// restartRequired reports whether a setting is read only at startup
+func restartRequired(key string) bool {
+	return key == "general.temp_audio_path"
+}
+
+// applyConfigChange applies a saved setting to the service that uses it
+// Settings read on every use (notifications, profiles) need no action; outputters
+// and recorders are built from a config snapshot and are rebuilt for the settings
+// they read. Returns true when the setting only takes effect after a restart
+func (a *App) applyConfigChange(key string) (bool, error) {
+	section, _, _ := strings.Cut(key, ".")
+	switch section {
+	case "hotkeys":
+		return false, a.Services.Actions.ReloadHotkeys()
+	case "output":
+		return false, a.Services.IO.ReloadOutput()
+	case "web_server", "metrics":
+		// The server is always created; Start is a no-op while disabled.
+		// /metrics moves between the web server and its own socket
+		if err := a.Services.IO.StopWebSocketServer(); err != nil {
+			return false, err
 		}
-		if err := a.Services.Hotkeys.ReloadFromConfig(
-			a.handleStartRecording,
-			a.handleStopRecordingAndTranscribe,
-			cfgProvider,
-		); err != nil {
-			return err
+		if err := a.Services.IO.StartWebSocketServer(); err != nil {
+			return false, err
 		}
+		return false, a.restartMetricsServer()
+	case "audio":
+		a.Services.Audio.ReloadRecorder()
+		return false, nil
+	case "security":
+		// Command allowlists are checked by the recorder and the outputters
+		a.Services.Audio.ReloadRecorder()
+		return false, a.Services.IO.ReloadOutput()
+	case "logging":
+		return false, a.applyLoggingChange(key)
 	}
-	return nil
+	switch key {
+	case "general.debug":
+		return false, a.applyLoggingChange(key)
+	case "general.language":
+		// The journal and webhook outputters record the language
+		return false, a.Services.IO.ReloadOutput()
+	}
+	return restartRequired(key), nil
 }
@@ -6,15 +6,22 @@ package app
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/history"
 	"github.com/AshBuk/dabri/internal/ipc"
+	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/services"
 	"github.com/AshBuk/dabri/internal/utils"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
 )
 
 // IPC Handlers - Protocol adapter between Unix socket IPC and Business Services
-// Request-response IPC commands (sync, wait-for-result with timeout)
+// Request-response IPC commands (sync, wait-for-result with timeout);
+// the subscribe command is served by the IPC server from Services.Events
 //
 // Architecture Flow:
 //   CLI client (dabri start-recording)
@@ -31,16 +38,24 @@ import (
 
 const (
 	ipcTranscriptionTimeout = 45 * time.Second
+	defaultHistoryLimit     = 20
+	hotkeyCaptureTimeoutMs  = 10000
 )
 
 // startIPCServer Initializes Unix socket IPC server for CLI client communication
-// Creates server on default socket path, registers command handlers, starts listening
+// Creates server on default socket path (or adopts the systemd activation socket), registers command handlers, starts listening
 func (a *App) startIPCServer() error {
 	if a.Services == nil {
 		return fmt.Errorf("services not initialized")
 	}
 	socketPath := utils.GetDefaultSocketPath()
-	server := ipc.NewServer(socketPath, a.Runtime.Logger)
+	server := ipc.NewServer(socketPath, logger.WithComponent(a.Runtime.Logger, "ipc"))
+	if ln, err := a.activationListener(); err != nil {
+		a.Runtime.Logger.Warning("%v, binding %s instead", err, socketPath)
+	} else if ln != nil {
+		server.Adopt(ln)
+	}
+	server.SetEventBus(a.Services.Events)
 	a.registerIPCHandlers(server)
 	if err := server.Start(); err != nil {
 		return err
@@ -59,24 +74,60 @@ func (a *App) registerIPCHandlers(server *ipc.Server) {
 	server.Register("last-transcript", a.ipcHandleLastTranscript)
 	server.Register("set-model", a.ipcHandleSetModel)
 	server.Register("delete-model", a.ipcHandleDeleteModel)
+	server.Register("set-mode", a.ipcHandleSetMode)
+	server.Register("history-list", a.ipcHandleHistoryList)
+	server.Register("history-search", a.ipcHandleHistoryList)
+	server.Register("history-show", a.ipcHandleHistoryShow)
+	server.Register("history-copy", a.ipcHandleHistoryOutput)
+	server.Register("history-type", a.ipcHandleHistoryOutput)
+	server.Register("history-clear", a.ipcHandleHistoryClear)
+	server.Register("undo", a.ipcHandleUndo)
+	server.Register("cancel", a.ipcHandleCancel)
+	server.Register("config-get", a.ipcHandleConfigGet)
+	server.Register("config-set", a.ipcHandleConfigSet)
+	server.Register("config-unset", a.ipcHandleConfigSet)
+	server.Register("config-list", a.ipcHandleConfigList)
+	server.Register("set-language", a.ipcHandleSetLanguage)
+	server.Register("set-output-mode", a.ipcHandleSetOutputMode)
+	server.Register("set-recorder", a.ipcHandleSetRecorder)
+	server.Register("set-notifications", a.ipcHandleSetNotifications)
+	server.Register("bind-hotkey", a.ipcHandleBindHotkey)
+	server.Register("reset-to-defaults", a.ipcHandleResetToDefaults)
+	server.Register("daemon-stop", a.ipcHandleDaemonStop)
+	server.Register("daemon-restart", a.ipcHandleDaemonStop)
+	server.Register("daemon-reload", a.ipcHandleDaemonReload)
+	server.Register("daemon-pid", a.ipcHandleDaemonPID)
+	server.Register("log-level", a.ipcHandleLogLevel)
 }
 
-// ipcHandleStartRecording Command handler - starts audio recording via handlers.go
+// ipcHandleStartRecording Command handler - starts audio recording with per-dictation options
+// Params (all optional): language, output, post_processing, task
 // Returns JSON response with recording state
-func (a *App) ipcHandleStartRecording(ipc.Request) (ipc.Response, error) {
-	if err := a.handleStartRecording(); err != nil {
+func (a *App) ipcHandleStartRecording(req ipc.Request) (ipc.Response, error) {
+	opts, err := sessionOptions(req)
+	if err != nil {
 		return ipc.Response{}, err
 	}
-	return ipc.NewSuccessResponse("recording started", map[string]any{
-		"recording": true,
-	}), nil
+	if err := a.handleStartRecordingWithOptions(opts); err != nil {
+		return ipc.Response{}, err
+	}
+	data := map[string]any{"recording": true}
+	if opts != (config.SessionOptions{}) {
+		data["options"] = opts
+	}
+	return ipc.NewSuccessResponse("recording started", data), nil
 }
 
 // ipcHandleStopRecording Command handler - stops recording and waits for transcript
 // Synchronous: blocks CLI until transcription ready (45s timeout) or returns last transcript
 // Graceful errors: ErrNoRecordingInProgress returns success (not error) for idempotency
-func (a *App) ipcHandleStopRecording(ipc.Request) (ipc.Response, error) {
-	if err := a.handleStopRecordingAndTranscribe(); err != nil {
+// Params override the start options for this dictation (see ipcHandleStartRecording)
+func (a *App) ipcHandleStopRecording(req ipc.Request) (ipc.Response, error) {
+	opts, err := sessionOptions(req)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if err := a.handleStopRecordingWithOptions(opts); err != nil {
 		if errors.Is(err, services.ErrNoRecordingInProgress) {
 			return ipc.NewSuccessResponse("recording already stopped", map[string]any{
 				"recording":  false,
@@ -85,7 +136,7 @@ func (a *App) ipcHandleStopRecording(ipc.Request) (ipc.Response, error) {
 		}
 		return ipc.Response{}, err
 	}
-	transcript, err := a.waitForTranscription(ipcTranscriptionTimeout)
+	result, err := a.waitForTranscription(ipcTranscriptionTimeout)
 	if err != nil {
 		return ipc.NewSuccessResponse("recording stopped", map[string]any{
 			"recording":  false,
@@ -95,20 +146,33 @@ func (a *App) ipcHandleStopRecording(ipc.Request) (ipc.Response, error) {
 	}
 	return ipc.NewSuccessResponse("recording stopped", map[string]any{
 		"recording":  false,
-		"transcript": transcript,
+		"transcript": result.Text,
+		"outputs":    result.Outputs,
 	}), nil
 }
 
 // ipcHandleToggleRecording Command handler - toggles recording state
 // Same logic as tray toggle (see factory_wirer.go makeToggleCallback)
-func (a *App) ipcHandleToggleRecording(ipc.Request) (ipc.Response, error) {
+func (a *App) ipcHandleToggleRecording(req ipc.Request) (ipc.Response, error) {
 	if a.Services == nil || a.Services.Audio == nil {
 		return ipc.Response{}, fmt.Errorf("audio service not available")
 	}
 	if a.Services.Audio.IsRecording() {
-		return a.ipcHandleStopRecording(ipc.Request{})
+		return a.ipcHandleStopRecording(req)
+	}
+	return a.ipcHandleStartRecording(req)
+}
+
+// sessionOptions reads per-dictation overrides from request params
+// Rejected up front so a bad option never starts or ends a recording
+func sessionOptions(req ipc.Request) (config.SessionOptions, error) {
+	opts := config.SessionOptions{
+		Language:       req.Params["language"],
+		Output:         req.Params["output"],
+		PostProcessing: req.Params["post_processing"],
+		Task:           req.Params["task"],
 	}
-	return a.ipcHandleStartRecording(ipc.Request{})
+	return opts, config.ValidateSessionOptions(opts)
 }
 
 // ipcHandleStatus Command handler - returns current state and configuration
@@ -132,6 +196,7 @@ func (a *App) ipcHandleStatus(ipc.Request) (ipc.Response, error) {
 				"language":     cfg.General.Language,
 				"output_mode":  cfg.Output.DefaultMode,
 				"audio_method": cfg.Audio.RecordingMethod,
+				"mode":         cfg.General.PostProcessing,
 			}
 			status["hotkeys"] = map[string]any{
 				"start_stop":  cfg.Hotkeys.StartRecording,
@@ -157,32 +222,16 @@ func (a *App) ipcHandleLastTranscript(ipc.Request) (ipc.Response, error) {
 
 // ipcHandleSetModel Command handler - switches whisper model and persists config
 func (a *App) ipcHandleSetModel(req ipc.Request) (ipc.Response, error) {
-	if a.Services == nil || a.Services.Audio == nil || a.Services.Config == nil {
-		return ipc.Response{}, fmt.Errorf("services not available")
-	}
 	modelID := req.Params["model"]
 	if modelID == "" {
 		return ipc.Response{}, fmt.Errorf("missing required parameter: model")
 	}
-	// Remember previous model so we can rollback on persist failure
-	previousModel := ""
-	if cfg := a.Services.Config.GetConfig(); cfg != nil {
-		previousModel = cfg.General.WhisperModel
-	}
-	if err := a.Services.Audio.SwitchModel(a.Runtime.Ctx, modelID); err != nil {
-		return ipc.Response{}, fmt.Errorf("model switch failed: %w", err)
-	}
-	if err := a.Services.Config.UpdateWhisperModel(modelID); err != nil {
-		// Rollback engine to previous model so runtime state matches config
-		if previousModel != "" {
-			if rbErr := a.Services.Audio.SwitchModel(a.Runtime.Ctx, previousModel); rbErr != nil {
-				a.Runtime.Logger.Error("Failed to rollback model after config save error: %v", rbErr)
-			}
-		}
-		return ipc.Response{}, fmt.Errorf("failed to persist model setting: %w", err)
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
 	}
-	if a.Services.UI != nil {
-		a.Services.UI.UpdateSettings(a.Services.Config.GetConfig())
+	if err := actions.SwitchModel(a.Runtime.Ctx, modelID); err != nil {
+		return ipc.Response{}, err
 	}
 	return ipc.NewSuccessResponse("model switched", map[string]any{
 		"model": modelID,
@@ -206,11 +255,458 @@ func (a *App) ipcHandleDeleteModel(req ipc.Request) (ipc.Response, error) {
 	}), nil
 }
 
+// ipcHandleSetMode Command handler - switches the default post-processing mode and persists config
+func (a *App) ipcHandleSetMode(req ipc.Request) (ipc.Response, error) {
+	if a.Services == nil || a.Services.Config == nil {
+		return ipc.Response{}, fmt.Errorf("services not available")
+	}
+	mode := req.Params["mode"]
+	if mode == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: mode")
+	}
+	if err := a.Services.Config.UpdatePostProcessing(mode); err != nil {
+		return ipc.Response{}, err
+	}
+	if a.Services.UI != nil {
+		a.Services.UI.UpdateSettings(a.Services.Config.GetConfig())
+	}
+	return ipc.NewSuccessResponse("mode set", map[string]any{
+		"mode": mode,
+	}), nil
+}
+
+// ipcHandleHistoryList Command handler - lists recent transcripts, newest first
+// Serves history-list and history-search; optional params: query, limit (default 20)
+func (a *App) ipcHandleHistoryList(req ipc.Request) (ipc.Response, error) {
+	store, err := a.historyStore()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	limit := defaultHistoryLimit
+	if raw := req.Params["limit"]; raw != "" {
+		if _, err := req.Param("limit", &limit); err != nil || limit < 0 {
+			return ipc.Response{}, fmt.Errorf("invalid limit: %s", raw)
+		}
+	}
+	query := req.Params["query"]
+	if req.Command == "history-search" && strings.TrimSpace(query) == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: query")
+	}
+	entries, err := store.Search(query, limit)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("history", map[string]any{
+		"entries": entries,
+		"enabled": a.historyEnabled(),
+	}), nil
+}
+
+// ipcHandleHistoryShow Command handler - returns one transcript with its metadata
+func (a *App) ipcHandleHistoryShow(req ipc.Request) (ipc.Response, error) {
+	entry, err := a.historyEntry(req)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("history entry", map[string]any{
+		"entry": entry,
+	}), nil
+}
+
+// ipcHandleHistoryOutput Command handler - re-outputs a transcript
+// history-copy copies it to the clipboard, history-type types it into the focused window
+func (a *App) ipcHandleHistoryOutput(req ipc.Request) (ipc.Response, error) {
+	if a.Services == nil || a.Services.IO == nil {
+		return ipc.Response{}, fmt.Errorf("io service not available")
+	}
+	entry, err := a.historyEntry(req)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	mode := config.OutputModeClipboard
+	if req.Command == "history-type" {
+		mode = config.OutputModeActiveWindow
+	}
+	if err := a.Services.IO.OutputToMode(entry.Text, mode); err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("transcript output", map[string]any{
+		"id":   entry.ID,
+		"mode": mode,
+	}), nil
+}
+
+// ipcHandleHistoryClear Command handler - deletes every recorded transcript
+func (a *App) ipcHandleHistoryClear(ipc.Request) (ipc.Response, error) {
+	store, err := a.historyStore()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if err := store.Clear(); err != nil {
+		return ipc.Response{}, err
+	}
+	if a.Services.UI != nil {
+		a.Services.UI.RefreshHistory()
+	}
+	return ipc.NewSuccessResponse("history cleared", nil), nil
+}
+
+// ipcHandleUndo Command handler - erases the last typed output or restores the clipboard it replaced
+func (a *App) ipcHandleUndo(ipc.Request) (ipc.Response, error) {
+	if a.Services == nil || a.Services.IO == nil {
+		return ipc.Response{}, fmt.Errorf("io service not available")
+	}
+	result, err := a.Services.IO.UndoLastOutput()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("output undone", map[string]any{
+		"erased":             result.Erased,
+		"clipboard_restored": result.ClipboardRestored,
+	}), nil
+}
+
+// ipcHandleCancel Command handler - discards the recording or pending transcription
+// Idempotent like stop: nothing to cancel is reported as success with cancelled=false
+func (a *App) ipcHandleCancel(ipc.Request) (ipc.Response, error) {
+	if a.Services == nil || a.Services.Audio == nil {
+		return ipc.Response{}, fmt.Errorf("audio service not available")
+	}
+	stage := "transcription"
+	if a.Services.Audio.IsRecording() {
+		stage = "recording"
+	}
+	if err := a.handleCancelRecording(); err != nil {
+		if errors.Is(err, services.ErrNothingToCancel) {
+			return ipc.NewSuccessResponse("nothing to cancel", map[string]any{
+				"cancelled": false,
+			}), nil
+		}
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse(stage+" cancelled", map[string]any{
+		"cancelled": true,
+		"stage":     stage,
+	}), nil
+}
+
+// ipcHandleConfigGet Command handler - reads one setting by dotted path (param: key)
+func (a *App) ipcHandleConfigGet(req ipc.Request) (ipc.Response, error) {
+	cfg, err := a.currentConfig()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	key := req.Params["key"]
+	if key == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: key")
+	}
+	value, err := config.GetValue(cfg, key)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("config value", map[string]any{
+		"key":   key,
+		"value": value,
+	}), nil
+}
+
+// ipcHandleConfigSet Command handler - serves config-set (params: key, value) and
+// config-unset (param: key), then applies the change to the running services
+// The whisper model goes through the model switch so the engine loads it before saving
+func (a *App) ipcHandleConfigSet(req ipc.Request) (ipc.Response, error) {
+	if _, err := a.currentConfig(); err != nil {
+		return ipc.Response{}, err
+	}
+	key := req.Params["key"]
+	if key == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: key")
+	}
+	value, hasValue := req.Params["value"]
+	unset := req.Command == "config-unset"
+	if !unset && !hasValue {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: value")
+	}
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+
+	if key == "general.whisper_model" {
+		if unset {
+			defaults := &config.Config{}
+			config.SetDefaultConfig(defaults)
+			value = defaults.General.WhisperModel
+		}
+		actions, err := a.settingsActions()
+		if err != nil {
+			return ipc.Response{}, err
+		}
+		if err := actions.SwitchModel(a.Runtime.Ctx, value); err != nil {
+			return ipc.Response{}, err
+		}
+		return a.configSetResponse(key, false)
+	}
+
+	var err error
+	if unset {
+		err = a.Services.Config.UnsetValue(key)
+	} else {
+		err = a.Services.Config.SetValue(key, value)
+	}
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	restart, err := a.applyConfigChange(key)
+	if err != nil {
+		// The setting is saved; report that it will apply on the next start
+		a.Runtime.Logger.Warning("Saved %s but failed to apply it: %v", key, err)
+		restart = true
+	}
+	return a.configSetResponse(key, restart)
+}
+
+// configSetResponse reports the saved value and whether it is already in effect
+func (a *App) configSetResponse(key string, restart bool) (ipc.Response, error) {
+	value, err := config.GetValue(a.Services.Config.GetConfig(), key)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if config.IsSecretKey(key) {
+		value = maskSecret(value)
+	}
+	message := "config updated"
+	if restart {
+		message = "config saved; restart dabri to apply"
+	}
+	return ipc.NewSuccessResponse(message, map[string]any{
+		"key":              key,
+		"value":            value,
+		"applied":          !restart,
+		"restart_required": restart,
+	}), nil
+}
+
+// ipcHandleConfigList Command handler - lists settings in file order (optional param: prefix)
+// Credentials are masked; read them with config-get
+func (a *App) ipcHandleConfigList(req ipc.Request) (ipc.Response, error) {
+	cfg, err := a.currentConfig()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	prefix := strings.TrimSuffix(req.Params["prefix"], ".")
+	var entries []map[string]any
+	for _, key := range config.Keys() {
+		if prefix != "" && key != prefix && !strings.HasPrefix(key, prefix+".") {
+			continue
+		}
+		value, err := config.GetValue(cfg, key)
+		if err != nil {
+			return ipc.Response{}, err
+		}
+		if config.IsSecretKey(key) {
+			value = maskSecret(value)
+		}
+		entries = append(entries, map[string]any{"key": key, "value": value})
+	}
+	if len(entries) == 0 && prefix != "" {
+		return ipc.Response{}, fmt.Errorf("%w: %s", config.ErrUnknownKey, prefix)
+	}
+	return ipc.NewSuccessResponse("config", map[string]any{
+		"settings": entries,
+	}), nil
+}
+
+// ipcHandleSetLanguage Command handler - changes the recognition language
+// Same code path as the tray Settings → Language menu
+func (a *App) ipcHandleSetLanguage(req ipc.Request) (ipc.Response, error) {
+	language := req.Params["language"]
+	if language == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: language")
+	}
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if err := actions.SetLanguage(language); err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("language set", map[string]any{
+		"language": language,
+	}), nil
+}
+
+// ipcHandleSetOutputMode Command handler - switches the output mode (clipboard, active_window, ...)
+func (a *App) ipcHandleSetOutputMode(req ipc.Request) (ipc.Response, error) {
+	mode := req.Params["mode"]
+	if mode == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: mode")
+	}
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if err := actions.SetOutputMode(mode); err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("output mode set", map[string]any{
+		"mode": mode,
+	}), nil
+}
+
+// ipcHandleSetRecorder Command handler - switches the recording backend (arecord/ffmpeg)
+func (a *App) ipcHandleSetRecorder(req ipc.Request) (ipc.Response, error) {
+	method := req.Params["method"]
+	if method == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: method")
+	}
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	if err := actions.SetRecordingMethod(method); err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("recorder set", map[string]any{
+		"method": method,
+	}), nil
+}
+
+// ipcHandleSetNotifications Command handler - enables or disables workflow notifications
+// Optional param enabled (on/off/true/false); toggles when absent
+func (a *App) ipcHandleSetNotifications(req ipc.Request) (ipc.Response, error) {
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	raw, ok := req.Params["enabled"]
+	if !ok || raw == "" {
+		if _, err := actions.ToggleWorkflowNotifications(); err != nil {
+			return ipc.Response{}, err
+		}
+	} else {
+		var enabled bool
+		// A JSON boolean, or the on/off words the CLI passes through
+		if _, err := req.Param("enabled", &enabled); err != nil {
+			switch strings.ToLower(raw) {
+			case "on", "1", "yes":
+				enabled = true
+			case "off", "0", "no":
+			default:
+				return ipc.Response{}, fmt.Errorf("invalid enabled value: %s (must be on or off)", raw)
+			}
+		}
+		if err := actions.SetWorkflowNotifications(enabled); err != nil {
+			return ipc.Response{}, err
+		}
+	}
+	return ipc.NewSuccessResponse("notifications updated", map[string]any{
+		"enabled": actions.WorkflowNotificationsEnabled(),
+	}), nil
+}
+
+// ipcHandleBindHotkey Command handler - rebinds a hotkey action
+// Without a combo param the daemon captures the next key press, like the tray rebind menu
+func (a *App) ipcHandleBindHotkey(req ipc.Request) (ipc.Response, error) {
+	action := req.Params["action"]
+	if action == "" {
+		return ipc.Response{}, fmt.Errorf("missing required parameter: action")
+	}
+	actions, err := a.settingsActions()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	combo := req.Params["combo"]
+	if combo == "" {
+		if combo, err = actions.CaptureHotkey(hotkeyCaptureTimeoutMs); err != nil {
+			return ipc.Response{}, err
+		}
+		if combo == "" {
+			return ipc.Response{}, fmt.Errorf("hotkey capture cancelled or timed out")
+		}
+	}
+	if err := actions.BindHotkey(action, combo); err != nil {
+		return ipc.Response{}, err
+	}
+	cfg, err := a.currentConfig()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	// Report the normalized combo as saved
+	bound, err := config.GetValue(cfg, "hotkeys."+action)
+	if err != nil {
+		bound = combo
+	}
+	return ipc.NewSuccessResponse("hotkey bound", map[string]any{
+		"action": action,
+		"combo":  bound,
+	}), nil
+}
+
+// ipcHandleResetToDefaults Command handler - restores the default configuration
+func (a *App) ipcHandleResetToDefaults(ipc.Request) (ipc.Response, error) {
+	if err := a.handleResetToDefaults(); err != nil {
+		return ipc.Response{}, err
+	}
+	return ipc.NewSuccessResponse("configuration reset to defaults", nil), nil
+}
+
+// currentConfig returns the live config or an error when services are not ready
+func (a *App) currentConfig() (*config.Config, error) {
+	if a.Services == nil || a.Services.Config == nil {
+		return nil, fmt.Errorf("services not available")
+	}
+	cfg := a.Services.Config.GetConfig()
+	if cfg == nil {
+		return nil, fmt.Errorf("config not loaded")
+	}
+	return cfg, nil
+}
+
+// maskSecret hides a credential while showing whether one is set
+func maskSecret(value any) any {
+	if s, ok := value.(string); ok && s != "" {
+		return "********"
+	}
+	return value
+}
+
+// historyStore returns the transcript history store
+func (a *App) historyStore() (*history.Store, error) {
+	if a.Services == nil || a.Services.History == nil {
+		return nil, fmt.Errorf("transcript history not available")
+	}
+	return a.Services.History, nil
+}
+
+// historyEntry looks up the entry named by the "id" parameter
+func (a *App) historyEntry(req ipc.Request) (history.Entry, error) {
+	store, err := a.historyStore()
+	if err != nil {
+		return history.Entry{}, err
+	}
+	raw := req.Params["id"]
+	if raw == "" {
+		return history.Entry{}, fmt.Errorf("missing required parameter: id")
+	}
+	var id int64
+	if _, err := req.Param("id", &id); err != nil {
+		return history.Entry{}, fmt.Errorf("invalid id: %s", raw)
+	}
+	return store.Get(id)
+}
+
+// historyEnabled reports whether new transcripts are being recorded
+func (a *App) historyEnabled() bool {
+	if a.Services == nil || a.Services.Config == nil {
+		return false
+	}
+	cfg := a.Services.Config.GetConfig()
+	return cfg != nil && cfg.History.Enabled
+}
+
 // waitForTranscription Blocks until transcription ready or timeout expires
 // Used by ipcHandleStopRecording to provide synchronous CLI response
-func (a *App) waitForTranscription(timeout time.Duration) (string, error) {
+func (a *App) waitForTranscription(timeout time.Duration) (outputInterfaces.TranscriptionResult, error) {
 	if a.Services == nil || a.Services.IO == nil {
-		return "", fmt.Errorf("io service not available")
+		return outputInterfaces.TranscriptionResult{}, fmt.Errorf("io service not available")
 	}
 	return a.Services.IO.WaitForTranscription(timeout)
 }
@@ -223,3 +719,47 @@ func (a *App) getLastTranscript() string {
 	}
 	return a.Services.Audio.GetLastTranscript()
 }
+
+// ipcHandleDaemonStop Command handler - serves daemon-stop and daemon-restart
+// Responds first, then shuts down like SIGTERM; a restart re-executes the daemon
+// with its original arguments (see runDaemonCobra)
+func (a *App) ipcHandleDaemonStop(req ipc.Request) (ipc.Response, error) {
+	restart := req.Command == "daemon-restart"
+	a.requestShutdown(restart)
+	message := "daemon stopping"
+	if restart {
+		message = "daemon restarting"
+	}
+	return ipc.NewSuccessResponse(message, a.daemonInfo()), nil
+}
+
+// ipcHandleDaemonReload Command handler - re-reads the config file, same as SIGHUP
+func (a *App) ipcHandleDaemonReload(ipc.Request) (ipc.Response, error) {
+	result, err := a.reloadConfig()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	message := "config reloaded"
+	if len(result.Warnings) > 0 {
+		message = "config reloaded with warnings"
+	}
+	return ipc.NewSuccessResponse(message, map[string]any{
+		"changed":          result.Changed,
+		"restart_required": result.RestartRequired,
+		"warnings":         result.Warnings,
+	}), nil
+}
+
+// ipcHandleDaemonPID Command handler - reports the daemon process
+func (a *App) ipcHandleDaemonPID(ipc.Request) (ipc.Response, error) {
+	return ipc.NewSuccessResponse("daemon running", a.daemonInfo()), nil
+}
+
+// daemonInfo identifies the running daemon; started_at changes on restart
+// while the PID stays the same (the daemon re-executes itself)
+func (a *App) daemonInfo() map[string]any {
+	return map[string]any{
+		"pid":        os.Getpid(),
+		"started_at": a.startedAt.Format(time.RFC3339Nano),
+	}
+}
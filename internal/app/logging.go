@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/ipc"
+	"github.com/AshBuk/dabri/internal/logger"
+)
+
+// The daemon logs through a logger.StructuredLogger created in cmd/dabri:
+//   - logging.* (format, file, rotation) and general.debug apply at startup and on change
+//   - "dabri log-level" changes verbosity until the next restart
+// Other loggers (tests, logger.DefaultLogger) are left as they are
+
+// structuredLogger returns the runtime-configurable logger, if the daemon uses one
+func (a *App) structuredLogger() (*logger.StructuredLogger, bool) {
+	sl, ok := a.Runtime.Logger.(*logger.StructuredLogger)
+	return sl, ok
+}
+
+// configureLogging applies the logging section at the given level
+// When the log file cannot be opened, logging continues on stderr only
+func (a *App) configureLogging(cfg *config.Config, level logger.LogLevel) error {
+	sl, ok := a.structuredLogger()
+	if !ok {
+		return nil
+	}
+	opts := logger.Options{
+		Level:      level,
+		Format:     logger.Format(cfg.Logging.Format),
+		File:       cfg.Logging.File,
+		MaxSize:    int64(cfg.Logging.MaxSizeMB) << 20,
+		MaxBackups: cfg.Logging.MaxBackups,
+	}
+	err := sl.Configure(opts)
+	if err != nil {
+		opts.File = ""
+		_ = sl.Configure(opts)
+	}
+	return err
+}
+
+// applyLoggingChange re-applies the logging section (keeping the current level)
+// or the level implied by general.debug
+func (a *App) applyLoggingChange(key string) error {
+	sl, ok := a.structuredLogger()
+	if !ok || a.Services == nil || a.Services.Config == nil {
+		return nil
+	}
+	cfg := a.Services.Config.GetConfig()
+	if key == "general.debug" {
+		sl.SetLevel(debugLevel(cfg.General.Debug))
+		return nil
+	}
+	return a.configureLogging(cfg, sl.Level())
+}
+
+// debugLevel maps general.debug to the logger level
+func debugLevel(debug bool) logger.LogLevel {
+	if debug {
+		return logger.DebugLevel
+	}
+	return logger.InfoLevel
+}
+
+// ipcHandleLogLevel Command handler - reports or changes the log level at runtime
+// Params (optional): level (debug, info, warning, error); not saved to the config
+func (a *App) ipcHandleLogLevel(req ipc.Request) (ipc.Response, error) {
+	sl, ok := a.structuredLogger()
+	if !ok {
+		return ipc.Response{}, fmt.Errorf("log level cannot be changed at runtime")
+	}
+	name := req.Params["level"]
+	if name == "" {
+		return ipc.NewSuccessResponse("log level", map[string]any{
+			"level": sl.Level().String(),
+		}), nil
+	}
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	previous := sl.Level()
+	sl.SetLevel(level)
+	a.Runtime.Logger.Info("Log level changed from %s to %s", previous, level)
+	return ipc.NewSuccessResponse("log level set", map[string]any{
+		"level":    level.String(),
+		"previous": previous.String(),
+	}), nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package app
+
+import (
+	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
+	"github.com/AshBuk/dabri/internal/utils"
+)
+
+// Prometheus metrics are collected in internal/metrics and served only when
+// metrics.enabled is set:
+//   - web server enabled → /metrics on it (websocket/server.go)
+//   - web server disabled → HTTP on a Unix socket, started here
+
+// startMetricsServer serves metrics on the Unix socket when the web server is off
+func (a *App) startMetricsServer() error {
+	if a.Services == nil || a.Services.Config == nil {
+		return nil
+	}
+	cfg := a.Services.Config.GetConfig()
+	if !cfg.Metrics.Enabled || cfg.WebServer.Enabled {
+		return nil
+	}
+	path := cfg.Metrics.Socket
+	if path == "" {
+		path = utils.GetDefaultMetricsSocketPath()
+	}
+	server := metrics.NewSocketServer(path, metrics.Default, logger.WithComponent(a.Runtime.Logger, "metrics"))
+	if err := server.Start(); err != nil {
+		return err
+	}
+	a.metricsServer = server
+	return nil
+}
+
+// stopMetricsServer stops the Unix socket server if it is running
+func (a *App) stopMetricsServer() {
+	if a.metricsServer != nil {
+		a.metricsServer.Stop()
+		a.metricsServer = nil
+	}
+}
+
+// restartMetricsServer re-applies the metrics and web server settings
+func (a *App) restartMetricsServer() error {
+	a.stopMetricsServer()
+	return a.startMetricsServer()
+}
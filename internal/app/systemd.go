@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/systemd"
+)
+
+// systemd user service integration (internal/systemd implements the protocol)
+//   - READY=1 once services run and the IPC socket is served (Type=notify)
+//   - STATUS= follows the dictation state from the event bus
+//   - WATCHDOG=1 pings at half the WatchdogSec interval
+//   - the IPC server adopts a socket passed by dabri.socket (LISTEN_FDS)
+// Outside systemd every step is a no-op
+
+// activationListener returns the IPC socket passed by socket activation, or nil
+// The files (claimed in NewApp) stay open for the daemon's lifetime so a
+// restart can pass them on
+func (a *App) activationListener() (net.Listener, error) {
+	if len(a.listenFiles) == 0 {
+		return nil, nil
+	}
+	if len(a.listenFiles) > 1 {
+		a.Runtime.Logger.Warning("Socket activation passed %d sockets, using %s", len(a.listenFiles), a.listenFiles[0].Name())
+	}
+	ln, err := net.FileListener(a.listenFiles[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to use activation socket %s: %w", a.listenFiles[0].Name(), err)
+	}
+	return ln, nil
+}
+
+// RestartEnv returns the environment for re-executing the daemon, handing the
+// socket-activated IPC socket on to the new image
+func (a *App) RestartEnv() ([]string, error) {
+	return systemd.InheritEnv(a.listenFiles, os.Environ())
+}
+
+// notifySystemd sends states to the service manager; failures are not fatal
+func (a *App) notifySystemd(states ...string) {
+	if _, err := systemd.Notify(states...); err != nil {
+		a.Runtime.Logger.Debug("systemd notify: %v", err)
+	}
+}
+
+// notifyReady tells systemd the daemon is up and starts the watchdog and status updates
+func (a *App) notifyReady() {
+	if os.Getenv("NOTIFY_SOCKET") == "" {
+		return
+	}
+	a.notifySystemd(systemd.Ready, a.idleStatus())
+	if interval, ok := systemd.WatchdogInterval(); ok {
+		a.Runtime.Logger.Info("systemd watchdog enabled, interval %s", interval)
+		go a.runWatchdog(interval / 2)
+	}
+	if a.Services != nil && a.Services.Events != nil {
+		go a.followStatus(a.Services.Events.Subscribe(0,
+			events.RecordingStarted, events.Transcribing, events.Transcript,
+			events.RecordingCancelled, events.Error, events.ModelSwitched))
+	}
+}
+
+// runWatchdog pings the systemd watchdog until shutdown
+func (a *App) runWatchdog(every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			a.notifySystemd(systemd.Watchdog)
+		case <-a.Runtime.Ctx.Done():
+			return
+		}
+	}
+}
+
+// followStatus mirrors the dictation state into the unit's status line
+func (a *App) followStatus(sub *events.Subscription) {
+	defer sub.Close()
+	for {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			switch ev.Type {
+			case events.RecordingStarted:
+				a.notifySystemd(systemd.Status("Recording"))
+			case events.Transcribing:
+				a.notifySystemd(systemd.Status("Transcribing"))
+			default:
+				a.notifySystemd(a.idleStatus())
+			}
+		case <-a.Runtime.Ctx.Done():
+			return
+		}
+	}
+}
+
+// idleStatus is the status line between dictations
+func (a *App) idleStatus() string {
+	if a.Services != nil && a.Services.Config != nil {
+		return systemd.Status("Ready, model %s", a.Services.Config.GetConfig().General.WhisperModel)
+	}
+	return systemd.Status("Ready")
+}
@@ -20,14 +20,15 @@ const (
 
 // Notification Titles
 const (
-	NotifyError             = "Error"
-	NotifySuccess           = "Success"
-	NotifyNoSpeech          = "No Speech"
-	NotifyConfigReset       = "Configuration Reset"
-	NotifyRecordingStarted  = "Recording Started"
-	NotifyRecordingStopped  = "Recording stopped"
-	NotifyTranscriptionDone = "Transcription Complete"
-	NotifyTranscriptionErr  = "Transcription Error"
+	NotifyError              = "Error"
+	NotifySuccess            = "Success"
+	NotifyNoSpeech           = "No Speech"
+	NotifyConfigReset        = "Configuration Reset"
+	NotifyRecordingStarted   = "Recording Started"
+	NotifyRecordingStopped   = "Recording stopped"
+	NotifyRecordingCancelled = "Recording Cancelled"
+	NotifyTranscriptionDone  = "Transcription Complete"
+	NotifyTranscriptionErr   = "Transcription Error"
 )
 
 // Notification Messages
@@ -39,8 +40,11 @@ const (
 	NotifyConfigResetSuccess     = "Settings reset to defaults successfully"
 	NotifyRecordingStartMsg      = "Recording started"
 	NotifyRecordingStopMsg       = "Transcribing audio..."
+	NotifyRecordingCancelMsg     = "Recording discarded without transcribing"
 	NotifyTranscriptionMsg       = "Text copied to clipboard"
 	NotifyTranscriptionTypedMsg  = "Text typed to active window"
+	NotifyTranscriptionSavedMsg  = "Text saved to journal"
+	NotifyTranscriptionSentMsg   = "Text sent to webhook"
 	NotifyAppName                = "Dabri"
 )
 
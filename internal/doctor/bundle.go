@@ -0,0 +1,190 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package doctor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/version"
+)
+
+const (
+	redacted        = "[redacted]"
+	logLines        = "500"
+	logReadTimeout  = 5 * time.Second
+	transcriptLabel = "Transcription result: "
+)
+
+// bundleEnvVars are the environment variables relevant to session detection
+var bundleEnvVars = []string{
+	"XDG_SESSION_TYPE", "XDG_CURRENT_DESKTOP", "DESKTOP_SESSION", "WAYLAND_DISPLAY", "DISPLAY",
+	"XDG_RUNTIME_DIR", "XDG_CONFIG_HOME", "XDG_DATA_HOME", "YDOTOOL_SOCKET",
+	"SWAYSOCK", "HYPRLAND_INSTANCE_SIGNATURE", "APPDIR", "LANG",
+}
+
+// bundleFile is a tarball entry and the function producing its content
+type bundleFile struct {
+	name string
+	data func() ([]byte, error)
+}
+
+// WriteBundle writes a support tarball with the check results, the redacted
+// config, session environment, recent journal lines and the logging.file log
+// with its rotated files. Credentials, the home directory, the user name and
+// transcripts are redacted
+func (d *Doctor) WriteBundle(path string, results []Result) error {
+	redact := newRedactor()
+	files := []bundleFile{
+		{"doctor.json", func() ([]byte, error) { return json.MarshalIndent(results, "", "  ") }},
+		{"config.yaml", d.redactedConfig},
+		{"environment.txt", environmentReport},
+		{"logs.txt", d.readLogs},
+	}
+	files = append(files, d.logFiles()...)
+
+	// #nosec G304 -- Path is chosen by the user running the command.
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, file := range files {
+		data, err := file.data()
+		if err != nil {
+			data = fmt.Appendf(nil, "unavailable: %v\n", err)
+		}
+		data = redact(data)
+		header := &tar.Header{Name: "dabri-doctor/" + file.name, Mode: 0600, Size: int64(len(data)), ModTime: now}
+		if err = tw.WriteHeader(header); err == nil {
+			_, err = tw.Write(data)
+		}
+		if err != nil {
+			_ = out.Close()
+			return fmt.Errorf("failed to write %s to bundle: %w", file.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// redactedConfig returns the config as YAML with credentials replaced
+func (d *Doctor) redactedConfig() ([]byte, error) {
+	clone, err := config.Clone(d.config)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range config.Keys() {
+		if value, _ := config.GetValue(clone, key); config.IsSecretKey(key) && value != "" {
+			if err := config.SetValue(clone, key, redacted); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return yaml.Marshal(clone)
+}
+
+// environmentReport lists versions, the OS and session variables
+func environmentReport() ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "dabri %s (%s %s/%s)\n", version.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		fmt.Fprintf(&b, "kernel %s\n", strings.TrimSpace(string(data)))
+	}
+	if data, err := os.ReadFile("/etc/os-release"); err == nil {
+		for line := range strings.SplitSeq(string(data), "\n") {
+			if name, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+				fmt.Fprintf(&b, "os %s\n", strings.Trim(name, `"`))
+			}
+		}
+	}
+	b.WriteString("\n")
+	for _, name := range bundleEnvVars {
+		fmt.Fprintf(&b, "%s=%s\n", name, os.Getenv(name))
+	}
+	return []byte(b.String()), nil
+}
+
+// journalLogs reads the recent daemon log from the user journal; the daemon
+// logs to stderr, which systemd user services and most launchers capture there
+func journalLogs() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), logReadTimeout)
+	defer cancel()
+	// #nosec G204 -- Fixed command and arguments.
+	out, err := exec.CommandContext(ctx, "journalctl", "--user", "--no-pager", "-o", "short-iso", "-n", logLines, "_COMM=dabri").Output()
+	if err != nil {
+		return nil, fmt.Errorf("journalctl: %w", err)
+	}
+	if len(out) == 0 {
+		return []byte("no dabri entries in the user journal\n"), nil
+	}
+	return out, nil
+}
+
+// logFiles lists logging.file and its rotated files (file.1 … file.N) under
+// logs/; rotated files that do not exist yet are left out
+func (d *Doctor) logFiles() []bundleFile {
+	path := d.config.Logging.File
+	if path == "" {
+		return nil
+	}
+	read := func(name string) func() ([]byte, error) {
+		// #nosec G304 -- Path comes from the user's own logging.file setting.
+		return func() ([]byte, error) { return os.ReadFile(name) }
+	}
+	base := filepath.Base(path)
+	files := []bundleFile{{"logs/" + base, read(path)}}
+	for i := 1; i <= d.config.Logging.MaxBackups; i++ {
+		rotated := fmt.Sprintf("%s.%d", path, i)
+		if _, err := os.Stat(rotated); err != nil {
+			continue
+		}
+		files = append(files, bundleFile{fmt.Sprintf("logs/%s.%d", base, i), read(rotated)})
+	}
+	return files
+}
+
+// newRedactor returns a filter replacing transcripts, the home directory and the user name
+func newRedactor() func([]byte) []byte {
+	var replacements []string
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		replacements = append(replacements, home, "~")
+	}
+	if current, err := user.Current(); err == nil && len(current.Username) > 2 {
+		replacements = append(replacements, current.Username, "$USER")
+	}
+	replacer := strings.NewReplacer(replacements...)
+	return func(data []byte) []byte {
+		lines := strings.Split(string(data), "\n")
+		for i, line := range lines {
+			if idx := strings.Index(line, transcriptLabel); idx >= 0 {
+				line = line[:idx+len(transcriptLabel)] + redacted
+			}
+			lines[i] = replacer.Replace(line)
+		}
+		return []byte(strings.Join(lines, "\n"))
+	}
+}
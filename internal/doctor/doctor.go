@@ -0,0 +1,459 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+// Package doctor diagnoses the environment dabri depends on: display server,
+// input devices, the hotkey portal, external tools, audio, models and the daemon
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/constants"
+	"github.com/AshBuk/dabri/internal/ipc"
+	"github.com/AshBuk/dabri/internal/platform"
+	"github.com/AshBuk/dabri/internal/utils"
+	"github.com/AshBuk/dabri/output/outputters"
+	"github.com/AshBuk/dabri/whisper/providers"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn" // Degraded or not needed in this session
+	StatusFail Status = "fail" // A feature cannot work until fixed
+)
+
+// Result is the outcome of one check with a hint on how to fix it
+type Result struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// Failed reports whether any check failed
+func Failed(results []Result) bool {
+	return slices.ContainsFunc(results, func(r Result) bool { return r.Status == StatusFail })
+}
+
+const (
+	inputGroup        = "input"
+	audioProbeTimeout = 3 * time.Second
+	ipcProbeTimeout   = 2 * time.Second
+	minFreeSpace      = 100 * 1024 * 1024
+)
+
+// Doctor runs environment checks against a loaded configuration
+type Doctor struct {
+	config *config.Config
+
+	// Host access, replaced in tests
+	env        platform.EnvironmentType
+	desktop    string
+	inputGlob  string
+	hasPortal  func() bool
+	lookPath   func(name string) (string, error)
+	probeAudio func(cfg *config.Config) error
+	readLogs   func() ([]byte, error)
+	socketPath string
+	lockPath   string
+}
+
+// New creates a doctor for the given configuration and the current session
+func New(cfg *config.Config) *Doctor {
+	return &Doctor{
+		config:     cfg,
+		env:        platform.DetectEnvironment(),
+		desktop:    platform.DetectDesktopEnvironment(),
+		inputGlob:  "/dev/input/event*",
+		hasPortal:  platform.HasGlobalShortcutsPortal,
+		lookPath:   exec.LookPath,
+		probeAudio: probeAudioDevice,
+		readLogs:   journalLogs,
+		socketPath: utils.GetDefaultSocketPath(),
+		lockPath:   utils.GetDefaultLockPath(),
+	}
+}
+
+// Run executes every check in display → hotkeys → tools → audio → model → daemon order
+func (d *Doctor) Run() []Result {
+	results := []Result{d.checkDisplay()}
+	results = append(results, d.checkHotkeys()...)
+	results = append(results, d.checkRecorder(), d.checkAudioDevice())
+	results = append(results, d.checkClipboard(), d.checkTyping())
+	if ydotoold, ok := d.checkYdotoold(); ok {
+		results = append(results, ydotoold)
+	}
+	results = append(results, d.checkModel(), d.checkDiskSpace(), d.checkDaemon())
+	return results
+}
+
+func (d *Doctor) checkDisplay() Result {
+	r := Result{Name: "display", Status: StatusPass, Detail: fmt.Sprintf("%s session, desktop %s", d.env, d.desktop)}
+	if d.env == platform.EnvironmentUnknown {
+		r.Status = StatusFail
+		r.Detail = "neither WAYLAND_DISPLAY nor DISPLAY is set"
+		r.Fix = "run dabri inside a graphical session (systemd --user services need 'systemctl --user import-environment WAYLAND_DISPLAY DISPLAY')"
+	}
+	return r
+}
+
+// checkHotkeys reports the evdev and portal backends; one working backend is enough
+func (d *Doctor) checkHotkeys() []Result {
+	devices, _ := filepath.Glob(d.inputGlob)
+	readable := 0
+	for _, path := range devices {
+		if f, err := os.Open(path); err == nil {
+			readable++
+			_ = f.Close()
+		}
+	}
+	portal := d.env == platform.EnvironmentWayland && d.hasPortal()
+	provider := d.config.Hotkeys.Provider
+
+	input := Result{Name: "input devices", Status: StatusPass,
+		Detail: fmt.Sprintf("%d of %d %s readable", readable, len(devices), d.inputGlob)}
+	switch {
+	case len(devices) == 0:
+		input.Status, input.Detail = StatusWarn, "no input devices found at "+d.inputGlob
+	case readable == 0:
+		input.Status = StatusWarn
+		input.Fix = "add yourself to the input group: sudo usermod -aG input $USER, then log out and back in"
+	}
+	if input.Status != StatusPass && (provider == "evdev" || (provider == "auto" && !portal)) {
+		input.Status = StatusFail
+		input.Detail += "; evdev hotkeys cannot work"
+	}
+
+	group := d.checkInputGroup()
+
+	portalResult := Result{Name: "GlobalShortcuts portal", Status: StatusPass, Detail: "available"}
+	switch {
+	case d.env != platform.EnvironmentWayland:
+		portalResult.Detail = "not needed outside Wayland"
+	case !portal:
+		portalResult.Status = StatusWarn
+		portalResult.Detail = "org.freedesktop.portal.GlobalShortcuts not found on the session bus"
+		portalResult.Fix = "install and start xdg-desktop-portal with a backend for your desktop, or use evdev hotkeys (provider: evdev)"
+		if provider == "dbus" {
+			portalResult.Status = StatusFail
+		}
+	}
+	return []Result{input, group, portalResult}
+}
+
+// checkInputGroup compares the group database with the groups of this session;
+// a freshly added group only applies after logging in again
+func (d *Doctor) checkInputGroup() Result {
+	r := Result{Name: "input group", Status: StatusPass, Detail: "member of " + inputGroup}
+	grp, err := user.LookupGroup(inputGroup)
+	if err != nil {
+		r.Status, r.Detail = StatusWarn, "group "+inputGroup+" does not exist"
+		return r
+	}
+	gid, _ := strconv.Atoi(grp.Gid)
+	if groups, err := os.Getgroups(); err == nil && slices.Contains(groups, gid) {
+		return r
+	}
+	r.Status = StatusWarn
+	r.Detail = "not a member of " + inputGroup + " (only needed for evdev hotkeys and uinput typing)"
+	r.Fix = "sudo usermod -aG input $USER, then log out and back in"
+	if current, err := user.Current(); err == nil {
+		if ids, err := current.GroupIds(); err == nil && slices.Contains(ids, grp.Gid) {
+			r.Detail = "added to " + inputGroup + " but this session predates it"
+			r.Fix = "log out and back in"
+		}
+	}
+	return r
+}
+
+// checkTool reports a tool required for a role: on PATH and allowlisted
+func (d *Doctor) checkTool(name, role, pkgHint string) Result {
+	r := Result{Name: role, Status: StatusPass, Detail: name}
+	if path, err := d.lookPath(name); err != nil {
+		r.Status = StatusFail
+		r.Detail = name + " not found in PATH"
+		r.Fix = "install " + pkgHint
+	} else {
+		r.Detail = path
+	}
+	if r.Status == StatusPass && !config.IsCommandAllowed(d.config, name) {
+		r.Status = StatusFail
+		r.Detail = name + " is not in security.allowed_commands"
+		r.Fix = "add " + name + " to security.allowed_commands (see dabri config get security.allowed_commands)"
+	}
+	return r
+}
+
+func (d *Doctor) checkRecorder() Result {
+	method := d.config.Audio.RecordingMethod
+	pkg := map[string]string{"arecord": "alsa-utils", "ffmpeg": "ffmpeg"}[method]
+	if pkg == "" {
+		pkg = method
+	}
+	return d.checkTool(method, "recorder", pkg)
+}
+
+func (d *Doctor) checkAudioDevice() Result {
+	r := Result{Name: "audio device", Status: StatusPass,
+		Detail: fmt.Sprintf("%s opened with %s", d.config.Audio.Device, d.config.Audio.RecordingMethod)}
+	if _, err := d.lookPath(d.config.Audio.RecordingMethod); err != nil {
+		r.Status, r.Detail = StatusWarn, "skipped, recorder not installed"
+		return r
+	}
+	if err := d.probeAudio(d.config); err != nil {
+		r.Status = StatusFail
+		r.Detail = fmt.Sprintf("cannot record from %s: %v", d.config.Audio.Device, err)
+		r.Fix = "check the microphone with 'arecord -l' or 'pactl list short sources' and set it with dabri config set audio.device <name>"
+	}
+	return r
+}
+
+func (d *Doctor) checkClipboard() Result {
+	tool := d.config.Output.ClipboardTool
+	if tool == "" || tool == "auto" {
+		tool = "xsel"
+		if d.env == platform.EnvironmentWayland {
+			tool = "wl-copy"
+		}
+	}
+	pkg := map[string]string{"wl-copy": "wl-clipboard", "xsel": "xsel"}[tool]
+	if pkg == "" {
+		pkg = tool
+	}
+	return d.checkTool(tool, "clipboard", pkg)
+}
+
+// checkTyping finds the typing tool the output factory would pick for this session
+func (d *Doctor) checkTyping() Result {
+	r := Result{Name: "typing", Status: StatusPass}
+	if tool := d.config.Output.TypeTool; tool != "" && tool != "auto" {
+		if tool == outputters.UinputTypeTool {
+			r.Detail = "built-in uinput typer"
+			if err := writable("/dev/uinput"); err != nil {
+				r.Status, r.Detail = StatusFail, err.Error()
+				r.Fix = "sudo usermod -aG input $USER or add a udev rule granting write access to /dev/uinput"
+			}
+			return r
+		}
+		return d.checkTool(tool, "typing", tool)
+	}
+	paste := d.config.Output.DefaultMode == config.OutputModePaste
+	ranked := outputters.RankTypingTools(outputters.DetectTypingSession(d.env), paste)
+	var tried []string
+	for _, tool := range ranked {
+		if _, err := d.lookPath(tool.Name); err != nil {
+			continue
+		}
+		if !config.IsCommandAllowed(d.config, tool.Name) {
+			tried = append(tried, tool.Name+" (not allowlisted)")
+			continue
+		}
+		if err := tool.Available(); err != nil {
+			tried = append(tried, fmt.Sprintf("%s (%v)", tool.Name, err))
+			continue
+		}
+		r.Detail = tool.Name
+		return r
+	}
+	// Typing is only needed for active_window and paste output
+	r.Status = StatusWarn
+	if mode := d.config.Output.DefaultMode; mode == config.OutputModeActiveWindow || paste {
+		r.Status = StatusFail
+	}
+	r.Detail = "no usable typing tool"
+	if len(tried) > 0 {
+		r.Detail += ": " + strings.Join(tried, ", ")
+	}
+	if len(ranked) > 0 {
+		r.Fix = "install " + ranked[0].Name
+	}
+	return r
+}
+
+// checkYdotoold verifies the ydotool daemon socket when ydotool is installed
+func (d *Doctor) checkYdotoold() (Result, bool) {
+	if _, err := d.lookPath("ydotool"); err != nil {
+		return Result{}, false
+	}
+	r := Result{Name: "ydotoold socket", Status: StatusPass}
+	candidates := []string{os.Getenv("YDOTOOL_SOCKET")}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append(candidates, filepath.Join(runtimeDir, ".ydotool_socket"))
+	}
+	candidates = append(candidates, "/tmp/.ydotool_socket")
+	for _, path := range candidates {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.Mode()&fs.ModeSocket == 0 {
+			continue
+		}
+		r.Detail = path
+		if err := writable(path); err != nil {
+			r.Status, r.Detail = StatusWarn, err.Error()
+			r.Fix = "start ydotoold as your user or make its socket writable (ydotoold --socket-own)"
+		}
+		return r, true
+	}
+	r.Status = StatusWarn
+	r.Detail = "ydotool is installed but ydotoold is not running"
+	r.Fix = "start it with 'systemctl --user enable --now ydotool' or run ydotoold"
+	return r, true
+}
+
+func (d *Doctor) checkModel() Result {
+	id := d.config.General.WhisperModel
+	r := Result{Name: "model", Status: StatusPass}
+	model := constants.ModelByID(id)
+	if model == nil {
+		r.Status, r.Detail = StatusFail, "unknown model "+id
+		r.Fix = "pick one from 'dabri model list'"
+		return r
+	}
+	path := providers.NewModelPathResolver(d.config, model.FileName).GetBundledModelPath()
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("%s not downloaded (%s)", id, path)
+		r.Fix = "dabri downloads it on the next start; check network access"
+	case info.Size() < model.MinSize:
+		r.Status = StatusFail
+		r.Detail = fmt.Sprintf("%s is incomplete (%d bytes)", path, info.Size())
+		r.Fix = "delete the file so it is downloaded again"
+	default:
+		r.Detail = fmt.Sprintf("%s (%d MB)", path, info.Size()/(1024*1024))
+	}
+	return r
+}
+
+// checkDiskSpace needs room for the model download plus temporary audio
+func (d *Doctor) checkDiskSpace() Result {
+	r := Result{Name: "disk space", Status: StatusPass}
+	dir, err := config.DataDir()
+	if err != nil {
+		r.Status, r.Detail = StatusWarn, err.Error()
+		return r
+	}
+	// Measure the nearest existing parent; the data dir is created on first start
+	for {
+		if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	free, err := utils.FreeDiskSpace(dir)
+	if err != nil {
+		r.Status, r.Detail = StatusWarn, err.Error()
+		return r
+	}
+	r.Detail = fmt.Sprintf("%d MB free in %s", free/(1024*1024), dir)
+	required := uint64(minFreeSpace)
+	if model := constants.ModelByID(d.config.General.WhisperModel); model != nil {
+		path := providers.NewModelPathResolver(d.config, model.FileName).GetBundledModelPath()
+		if _, err := os.Stat(path); err != nil {
+			required += uint64(model.MinSize)
+		}
+	}
+	if free < required {
+		r.Status = StatusFail
+		r.Fix = fmt.Sprintf("free at least %d MB", required/(1024*1024))
+	}
+	return r
+}
+
+// checkDaemon reconciles the lock file with the IPC socket
+func (d *Doctor) checkDaemon() Result {
+	r := Result{Name: "daemon", Status: StatusPass}
+	running, pid, err := utils.NewLockFile(d.lockPath).CheckExistingInstance()
+	if err != nil {
+		r.Status, r.Detail = StatusWarn, err.Error()
+		return r
+	}
+	_, socketErr := os.Stat(d.socketPath)
+	socketExists := socketErr == nil
+	switch {
+	case running && !socketExists:
+		r.Status = StatusFail
+		r.Detail = fmt.Sprintf("running as PID %d but %s is missing", pid, d.socketPath)
+		r.Fix = "restart dabri; check that XDG_RUNTIME_DIR matches between the daemon and this shell"
+	case running:
+		if _, err := ipc.SendRequest(d.socketPath, ipc.Request{Command: "status"}, ipcProbeTimeout); err != nil {
+			r.Status = StatusFail
+			r.Detail = fmt.Sprintf("running as PID %d but not answering on %s: %v", pid, d.socketPath, err)
+			r.Fix = "restart dabri"
+			return r
+		}
+		r.Detail = fmt.Sprintf("running as PID %d, answering on %s", pid, d.socketPath)
+	case pid > 0:
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("stale lock file %s (PID %d is not running)", d.lockPath, pid)
+		r.Fix = "remove " + d.lockPath
+	case socketExists:
+		r.Status = StatusWarn
+		r.Detail = "not running; stale socket " + d.socketPath
+		r.Fix = "start dabri (the stale socket is replaced on start)"
+	default:
+		r.Status = StatusWarn
+		r.Detail = "not running"
+		r.Fix = "start dabri"
+	}
+	return r
+}
+
+// probeAudioDevice records a short sample with the configured recorder,
+// mirroring audio/factory TestRecorderMethod
+func probeAudioDevice(cfg *config.Config) error {
+	var args []string
+	switch cfg.Audio.RecordingMethod {
+	case "arecord":
+		args = []string{"-D", cfg.Audio.Device, "-f", "S16_LE", "-r", "16000", "-c", "1", "-d", "1", "-q", "/dev/null"}
+	case "ffmpeg":
+		args = []string{"-hide_banner", "-loglevel", "error", "-f", "pulse", "-i", cfg.Audio.Device,
+			"-ar", "16000", "-ac", "1", "-t", "0.5", "-f", "null", "-"}
+	default:
+		return fmt.Errorf("unsupported recording method: %s", cfg.Audio.RecordingMethod)
+	}
+	if !config.IsCommandAllowed(cfg, cfg.Audio.RecordingMethod) {
+		return fmt.Errorf("command not allowed: %s", cfg.Audio.RecordingMethod)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), audioProbeTimeout)
+	defer cancel()
+	// #nosec G204 -- Safe: command is allowlisted and arguments are sanitized.
+	cmd := exec.CommandContext(ctx, cfg.Audio.RecordingMethod, config.SanitizeCommandArgs(args)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			lines := strings.Split(msg, "\n")
+			return errors.New(lines[len(lines)-1])
+		}
+		return err
+	}
+	return nil
+}
+
+// writable checks write access without opening the file, which a socket does not allow
+func writable(path string) error {
+	const wOK = 0x2 // W_OK from access(2)
+	if err := syscall.Access(path, wOK); err != nil {
+		return fmt.Errorf("cannot write %s: %w", path, err)
+	}
+	return nil
+}
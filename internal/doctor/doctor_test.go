@@ -0,0 +1,216 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package doctor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/platform"
+)
+
+// newTestDoctor returns a doctor isolated from the host session
+func newTestDoctor(t *testing.T, tools ...string) *Doctor {
+	t.Helper()
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	dir := t.TempDir()
+	return &Doctor{
+		config:    cfg,
+		env:       platform.EnvironmentWayland,
+		desktop:   "sway",
+		inputGlob: filepath.Join(dir, "event*"),
+		hasPortal: func() bool { return false },
+		lookPath: func(name string) (string, error) {
+			for _, tool := range tools {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		probeAudio: func(*config.Config) error { return nil },
+		readLogs:   func() ([]byte, error) { return nil, errors.New("no journal") },
+		socketPath: filepath.Join(dir, "dabri.sock"),
+		lockPath:   filepath.Join(dir, "dabri.lock"),
+	}
+}
+
+func findResult(t *testing.T, results []Result, name string) Result {
+	t.Helper()
+	for _, r := range results {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, results)
+	return Result{}
+}
+
+func TestCheckHotkeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		portal     bool
+		devices    int
+		wantInput  Status
+		wantPortal Status
+	}{
+		{"readable devices", "auto", false, 2, StatusPass, StatusWarn},
+		{"no devices but portal", "auto", true, 0, StatusWarn, StatusPass},
+		{"no backend at all", "auto", false, 0, StatusFail, StatusWarn},
+		{"forced evdev without devices", "evdev", true, 0, StatusFail, StatusPass},
+		{"forced dbus without portal", "dbus", false, 1, StatusPass, StatusFail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDoctor(t)
+			d.config.Hotkeys.Provider = tt.provider
+			d.hasPortal = func() bool { return tt.portal }
+			for i := range tt.devices {
+				path := strings.Replace(d.inputGlob, "*", string(rune('0'+i)), 1)
+				if err := os.WriteFile(path, nil, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			results := d.checkHotkeys()
+			if got := findResult(t, results, "input devices"); got.Status != tt.wantInput {
+				t.Errorf("input devices = %+v, want %s", got, tt.wantInput)
+			}
+			if got := findResult(t, results, "GlobalShortcuts portal"); got.Status != tt.wantPortal {
+				t.Errorf("portal = %+v, want %s", got, tt.wantPortal)
+			}
+		})
+	}
+}
+
+func TestCheckTools(t *testing.T) {
+	d := newTestDoctor(t, "arecord", "wl-copy")
+	if r := d.checkRecorder(); r.Status != StatusPass || r.Detail != "/usr/bin/arecord" {
+		t.Errorf("recorder = %+v", r)
+	}
+	if r := d.checkClipboard(); r.Status != StatusPass {
+		t.Errorf("clipboard = %+v", r)
+	}
+
+	d.config.Security.AllowedCommands = []string{"ffmpeg"}
+	if r := d.checkRecorder(); r.Status != StatusFail || !strings.Contains(r.Detail, "allowed_commands") {
+		t.Errorf("recorder not allowlisted = %+v", r)
+	}
+
+	d = newTestDoctor(t)
+	if r := d.checkClipboard(); r.Status != StatusFail || r.Fix != "install wl-clipboard" {
+		t.Errorf("missing clipboard = %+v", r)
+	}
+	if r := d.checkAudioDevice(); r.Status != StatusWarn {
+		t.Errorf("audio device without recorder = %+v", r)
+	}
+}
+
+func TestCheckAudioDevice(t *testing.T) {
+	d := newTestDoctor(t, "arecord")
+	d.probeAudio = func(*config.Config) error { return errors.New("audio open error: No such file or directory") }
+	r := d.checkAudioDevice()
+	if r.Status != StatusFail || !strings.Contains(r.Detail, "No such file") || r.Fix == "" {
+		t.Errorf("audio device = %+v", r)
+	}
+}
+
+func TestCheckDaemon(t *testing.T) {
+	d := newTestDoctor(t)
+	if r := d.checkDaemon(); r.Status != StatusWarn || r.Detail != "not running" {
+		t.Errorf("no daemon = %+v", r)
+	}
+
+	// A PID beyond pid_max never runs
+	if err := os.WriteFile(d.lockPath, []byte("4194303"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if r := d.checkDaemon(); r.Status != StatusWarn || !strings.Contains(r.Detail, "stale lock file") {
+		t.Errorf("stale lock = %+v", r)
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	d := newTestDoctor(t)
+	d.config.WebServer.AuthToken = "s3cret-token"
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	d.readLogs = func() ([]byte, error) {
+		return []byte("[INFO] Transcription result: my private note\n[INFO] Loaded " + home + "/model.bin\n"), nil
+	}
+	logDir := t.TempDir()
+	d.config.Logging.File = filepath.Join(logDir, "dabri.log")
+	d.config.Logging.MaxBackups = 3
+	for name, line := range map[string]string{
+		"dabri.log":   "[INFO] Transcription result: current note\n",
+		"dabri.log.1": "[INFO] Transcription result: older note\n[INFO] Loaded " + home + "/model.bin\n",
+	} {
+		if err := os.WriteFile(filepath.Join(logDir, name), []byte(line), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := d.WriteBundle(path, []Result{{Name: "display", Status: StatusPass}}); err != nil {
+		t.Fatalf("WriteBundle: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		contents[header.Name] = string(data)
+	}
+
+	for _, name := range []string{"doctor.json", "config.yaml", "environment.txt", "logs.txt", "logs/dabri.log", "logs/dabri.log.1"} {
+		if _, ok := contents["dabri-doctor/"+name]; !ok {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	for _, name := range []string{"logs/dabri.log.2", "logs/dabri.log.3"} {
+		if _, ok := contents["dabri-doctor/"+name]; ok {
+			t.Errorf("bundle contains missing rotated file %s", name)
+		}
+	}
+	all := strings.Join([]string{
+		contents["dabri-doctor/config.yaml"], contents["dabri-doctor/logs.txt"],
+		contents["dabri-doctor/logs/dabri.log"], contents["dabri-doctor/logs/dabri.log.1"],
+	}, "\n")
+	for _, secret := range []string{"s3cret-token", "my private note", "current note", "older note", home + "/"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("bundle leaks %q", secret)
+		}
+	}
+	if !strings.Contains(contents["dabri-doctor/logs.txt"], "~/model.bin") {
+		t.Errorf("logs.txt = %q", contents["dabri-doctor/logs.txt"])
+	}
+	if !strings.Contains(contents["dabri-doctor/logs/dabri.log.1"], "~/model.bin") {
+		t.Errorf("logs/dabri.log.1 = %q", contents["dabri-doctor/logs/dabri.log.1"])
+	}
+}
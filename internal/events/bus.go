@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event types published by the daemon
+const (
+	RecordingStarted = "recording_started"
+	RecordingStopped = "recording_stopped"
+	// RecordingCancelled follows RecordingStopped or Transcribing when the dictation was discarded
+	RecordingCancelled = "recording_cancelled"
+	AudioLevel         = "audio_level"
+	Transcribing       = "transcribing"
+	Transcript         = "transcript"
+	Error              = "error"
+	ModelSwitched      = "model_switched"
+	ConfigChanged      = "config_changed"
+
+	// Overrun is the last event sent to a subscriber disconnected for falling behind
+	Overrun = "overrun"
+)
+
+// Types lists every event type in publication order of a dictation
+var Types = []string{
+	RecordingStarted, AudioLevel, RecordingStopped, Transcribing, RecordingCancelled, Transcript,
+	Error, ModelSwitched, ConfigChanged,
+}
+
+// DefaultBuffer is the number of events queued per subscriber
+const DefaultBuffer = 64
+
+// Event is one state change, sent to subscribers as a JSON line
+type Event struct {
+	Type string         `json:"type"`
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// lossy reports whether an event may be dropped for a slow subscriber.
+// Audio levels are superseded by the next sample, other events are not
+func lossy(eventType string) bool {
+	return eventType == AudioLevel
+}
+
+// Bus fans events out to subscribers without ever blocking the publisher.
+// A subscriber whose queue is full loses audio levels; any other event
+// overflowing its queue disconnects it, so it never sees a gap silently
+type Bus struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives published events on C until closed
+type Subscription struct {
+	C <-chan Event
+
+	bus     *Bus
+	ch      chan Event
+	types   []string // Event types to deliver (empty for all)
+	dropped int      // Lossy events dropped while the queue was full
+	overrun bool     // Closed because a non-lossy event did not fit
+	closed  bool
+}
+
+// Subscribe registers a subscriber with a queue of buffer events, receiving
+// only the given event types (all types when none are given)
+func (b *Bus) Subscribe(buffer int, types ...string) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, bus: b, ch: ch, types: types}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish sends an event to every interested subscriber. Safe on a nil bus
+func (b *Bus) Publish(eventType string, data map[string]any) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ev := Event{Type: eventType, Time: time.Now(), Data: data}
+	for sub := range b.subs {
+		if len(sub.types) > 0 && !slices.Contains(sub.types, eventType) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			if lossy(eventType) {
+				sub.dropped++
+				continue
+			}
+			sub.overrun = true
+			b.closeLocked(sub)
+		}
+	}
+}
+
+// Subscribers returns the number of active subscriptions
+func (b *Bus) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Close unregisters the subscription and closes C
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.closeLocked(s)
+}
+
+// Overrun reports whether the subscription was closed for falling behind
+func (s *Subscription) Overrun() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.overrun
+}
+
+// Dropped returns the number of audio level events skipped for this subscriber
+func (s *Subscription) Dropped() int {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.dropped
+}
+
+func (b *Bus) closeLocked(s *Subscription) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(b.subs, s)
+	close(s.ch)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"testing"
+)
+
+func drain(sub *Subscription) []string {
+	var got []string
+	for {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				return got
+			}
+			got = append(got, ev.Type)
+		default:
+			return got
+		}
+	}
+}
+
+func TestBus_PublishFiltersByType(t *testing.T) {
+	bus := NewBus()
+	all := bus.Subscribe(0)
+	transcripts := bus.Subscribe(0, Transcript, Error)
+
+	bus.Publish(RecordingStarted, nil)
+	bus.Publish(Transcript, map[string]any{"text": "hello"})
+
+	if got := drain(all); len(got) != 2 || got[0] != RecordingStarted || got[1] != Transcript {
+		t.Errorf("unfiltered subscriber got %v", got)
+	}
+	if got := drain(transcripts); len(got) != 1 || got[0] != Transcript {
+		t.Errorf("filtered subscriber got %v", got)
+	}
+}
+
+func TestBus_SlowConsumer(t *testing.T) {
+	tests := []struct {
+		name        string
+		publish     []string
+		wantOverrun bool
+		wantDropped int
+		wantQueued  int
+	}{
+		{
+			name:        "audio levels are dropped",
+			publish:     []string{RecordingStarted, AudioLevel, AudioLevel, AudioLevel},
+			wantDropped: 2,
+			wantQueued:  2,
+		},
+		{
+			name:        "state event overflow disconnects",
+			publish:     []string{RecordingStarted, RecordingStopped, Transcript},
+			wantOverrun: true,
+			wantQueued:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus()
+			slow := bus.Subscribe(2)
+			fast := bus.Subscribe(len(tt.publish))
+			for _, typ := range tt.publish {
+				bus.Publish(typ, nil)
+			}
+
+			if slow.Overrun() != tt.wantOverrun {
+				t.Errorf("Overrun() = %t, want %t", slow.Overrun(), tt.wantOverrun)
+			}
+			if slow.Dropped() != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", slow.Dropped(), tt.wantDropped)
+			}
+			if got := drain(slow); len(got) != tt.wantQueued {
+				t.Errorf("slow subscriber received %v, want %d events", got, tt.wantQueued)
+			}
+			// A slow subscriber never holds back the others
+			if got := drain(fast); len(got) != len(tt.publish) {
+				t.Errorf("fast subscriber received %v, want all %d events", got, len(tt.publish))
+			}
+			wantSubs := 2
+			if tt.wantOverrun {
+				wantSubs = 1
+			}
+			if n := bus.Subscribers(); n != wantSubs {
+				t.Errorf("Subscribers() = %d, want %d", n, wantSubs)
+			}
+		})
+	}
+}
+
+func TestBus_CloseAndNilBus(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	sub.Close()
+	sub.Close()
+	if _, ok := <-sub.C; ok {
+		t.Error("expected closed channel")
+	}
+	bus.Publish(Transcript, nil)
+	if bus.Subscribers() != 0 {
+		t.Error("expected no subscribers after Close")
+	}
+
+	var nilBus *Bus
+	nilBus.Publish(Transcript, nil)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+)
+
+// FileName is the history file inside the application data directory
+const FileName = "history.jsonl"
+
+// ErrNotFound is returned when no entry has the requested ID
+var ErrNotFound = errors.New("history entry not found")
+
+// Entry is one recorded transcript with the metadata of its session
+type Entry struct {
+	ID         int64     `json:"id"`
+	Timestamp  time.Time `json:"timestamp"`
+	Text       string    `json:"text"`
+	DurationMs int64     `json:"duration_ms"`
+	Model      string    `json:"model,omitempty"`
+	Language   string    `json:"language,omitempty"`
+	Targets    []string  `json:"targets,omitempty"` // Output modes that delivered the transcript
+	SessionID  string    `json:"session_id,omitempty"`
+}
+
+// Limits bound the history file; zero values disable a limit
+type Limits struct {
+	MaxEntries int
+	MaxAge     time.Duration
+	MaxBytes   int64
+}
+
+// LimitsFromConfig converts the history config section into store limits
+func LimitsFromConfig(cfg *config.Config) Limits {
+	return Limits{
+		MaxEntries: cfg.History.MaxEntries,
+		MaxAge:     time.Duration(cfg.History.MaxAgeDays) * 24 * time.Hour,
+		MaxBytes:   int64(cfg.History.MaxSizeKB) * 1024,
+	}
+}
+
+// Preview returns the text on a single line, shortened to max runes with an ellipsis
+func Preview(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if max <= 0 || len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
+
+// Store keeps transcripts in a JSONL file, one entry per line, oldest first.
+// The file is rewritten atomically on every change so limits always hold
+type Store struct {
+	path   string
+	mu     sync.Mutex
+	limits func() Limits
+	// now returns the current time; replaced in tests
+	now func() time.Time
+}
+
+// NewStore creates a store backed by the given file. Limits are read on
+// every write so config changes apply without a restart
+func NewStore(path string, limits func() Limits) *Store {
+	return &Store{path: path, limits: limits, now: time.Now}
+}
+
+// DefaultPath returns $XDG_DATA_HOME/dabri/history.jsonl
+func DefaultPath() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Path returns the backing file path
+func (s *Store) Path() string { return s.path }
+
+// Add records a transcript, assigning its ID and timestamp, and prunes
+// entries beyond the retention limits
+func (s *Store) Add(e Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	e.ID = 1
+	if n := len(entries); n > 0 {
+		e.ID = entries[n-1].ID + 1
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = s.now()
+	}
+	entries = s.prune(append(entries, e))
+	if err := s.save(entries); err != nil {
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// List returns up to limit entries, newest first (0 returns all)
+func (s *Store) List(limit int) ([]Entry, error) {
+	return s.Search("", limit)
+}
+
+// Search returns up to limit entries whose text contains query, ignoring
+// case, newest first (0 returns all matches)
+func (s *Store) Search(query string, limit int) ([]Entry, error) {
+	s.mu.Lock()
+	entries, err := s.load()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var result []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if query != "" && !strings.Contains(strings.ToLower(entries[i].Text), query) {
+			continue
+		}
+		result = append(result, entries[i])
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Get returns the entry with the given ID
+func (s *Store) Get(id int64) (Entry, error) {
+	s.mu.Lock()
+	entries, err := s.load()
+	s.mu.Unlock()
+	if err != nil {
+		return Entry{}, err
+	}
+	i := slices.IndexFunc(entries, func(e Entry) bool { return e.ID == id })
+	if i < 0 {
+		return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return entries[i], nil
+}
+
+// Clear removes the history file and every recorded transcript
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(nil)
+}
+
+// prune drops entries older than MaxAge, then the oldest entries beyond
+// MaxEntries, then the oldest entries while the file exceeds MaxBytes
+func (s *Store) prune(entries []Entry) []Entry {
+	var limits Limits
+	if s.limits != nil {
+		limits = s.limits()
+	}
+	if limits.MaxAge > 0 {
+		cutoff := s.now().Add(-limits.MaxAge)
+		entries = slices.DeleteFunc(entries, func(e Entry) bool { return e.Timestamp.Before(cutoff) })
+	}
+	if limits.MaxEntries > 0 && len(entries) > limits.MaxEntries {
+		entries = entries[len(entries)-limits.MaxEntries:]
+	}
+	if limits.MaxBytes > 0 && len(entries) > 0 {
+		// The newest entry is kept even when it alone exceeds the limit
+		var size int64
+		keep := len(entries) - 1
+		for i := len(entries) - 1; i >= 0; i-- {
+			data, err := json.Marshal(entries[i])
+			if err != nil {
+				continue
+			}
+			size += int64(len(data)) + 1
+			if size > limits.MaxBytes && i < len(entries)-1 {
+				break
+			}
+			keep = i
+		}
+		entries = entries[keep:]
+	}
+	return entries
+}
+
+// load reads all entries, skipping lines that fail to decode
+func (s *Store) load() ([]Entry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	var entries []Entry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var e Entry
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// save atomically rewrites the history file, removing it when empty
+func (s *Store) save(entries []Entry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear history: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	var buf bytes.Buffer
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
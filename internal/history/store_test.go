@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T, limits Limits) *Store {
+	t.Helper()
+	return NewStore(filepath.Join(t.TempDir(), FileName), func() Limits { return limits })
+}
+
+func TestStore_AddListGet(t *testing.T) {
+	s := newTestStore(t, Limits{})
+	for _, text := range []string{"first note", "Second NOTE", "third"} {
+		if _, err := s.Add(Entry{Text: text, Model: "small-q5_1", Language: "en"}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	entries, err := s.List(2)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Text != "third" || entries[1].Text != "Second NOTE" {
+		t.Fatalf("List(2) = %+v, want newest first", entries)
+	}
+	if entries[0].ID != 3 || entries[0].Timestamp.IsZero() {
+		t.Errorf("entry not assigned ID/timestamp: %+v", entries[0])
+	}
+
+	got, err := s.Get(1)
+	if err != nil || got.Text != "first note" || got.Model != "small-q5_1" {
+		t.Errorf("Get(1) = %+v, %v", got, err)
+	}
+	if _, err := s.Get(42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(42) error = %v, want ErrNotFound", err)
+	}
+
+	info, err := os.Stat(s.Path())
+	if err != nil {
+		t.Fatalf("stat history: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("history file mode = %o, want 600", perm)
+	}
+}
+
+func TestStore_Search(t *testing.T) {
+	s := newTestStore(t, Limits{})
+	for _, text := range []string{"buy milk", "call Bob", "Milk and bread"} {
+		if _, err := s.Add(Entry{Text: text}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	tests := []struct {
+		query string
+		limit int
+		want  []string
+	}{
+		{"milk", 0, []string{"Milk and bread", "buy milk"}},
+		{"MILK", 1, []string{"Milk and bread"}},
+		{"bob", 0, []string{"call Bob"}},
+		{"nothing", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			entries, err := s.Search(tt.query, tt.limit)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		limits Limits
+		add    []Entry
+		want   []string
+	}{
+		{
+			name:   "max entries drops oldest",
+			limits: Limits{MaxEntries: 2},
+			add:    []Entry{{Text: "a"}, {Text: "b"}, {Text: "c"}},
+			want:   []string{"c", "b"},
+		},
+		{
+			name:   "max age drops expired",
+			limits: Limits{MaxAge: 24 * time.Hour},
+			add:    []Entry{{Text: "old", Timestamp: now.Add(-48 * time.Hour)}, {Text: "new"}},
+			want:   []string{"new"},
+		},
+		{
+			name:   "max size keeps newest",
+			limits: Limits{MaxBytes: 200},
+			add:    []Entry{{Text: strings.Repeat("x", 100)}, {Text: strings.Repeat("y", 100)}},
+			want:   []string{strings.Repeat("y", 100)},
+		},
+		{
+			name:   "max size keeps an oversized newest entry",
+			limits: Limits{MaxBytes: 50},
+			add:    []Entry{{Text: "a"}, {Text: strings.Repeat("z", 100)}},
+			want:   []string{strings.Repeat("z", 100)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t, tt.limits)
+			s.now = func() time.Time { return now }
+			for _, e := range tt.add {
+				if _, err := s.Add(e); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+			entries, err := s.List(0)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("entries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore_Clear(t *testing.T) {
+	s := newTestStore(t, Limits{})
+	if _, err := s.Add(Entry{Text: "secret"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, err := os.Stat(s.Path()); !os.IsNotExist(err) {
+		t.Errorf("history file still present after Clear: %v", err)
+	}
+	entries, err := s.List(0)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("List after Clear = %v, %v", entries, err)
+	}
+	if err := s.Clear(); err != nil {
+		t.Errorf("Clear on empty history: %v", err)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"multi\nline   text", 20, "multi line text"},
+		{"hello wonderful world", 10, "hello won…"},
+		{"привет мир", 5, "прив…"},
+		{"unbounded", 0, "unbounded"},
+	}
+	for _, tt := range tests {
+		if got := Preview(tt.text, tt.max); got != tt.want {
+			t.Errorf("Preview(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
@@ -5,10 +5,17 @@ package ipc
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/AshBuk/dabri/internal/events"
 )
 
 const (
@@ -61,3 +68,266 @@ func SendRequest(socketPath string, req Request, timeout time.Duration) (Respons
 
 	return resp, nil
 }
+
+// ErrOverrun is returned by Subscribe when the daemon dropped a client that fell behind
+var ErrOverrun = errors.New("event stream overrun: client too slow")
+
+// Subscribe opens an event stream and calls onEvent for every event until ctx
+// is canceled, onEvent returns an error or the daemon closes the stream.
+// params may select event types ("events": "transcript,error")
+func Subscribe(ctx context.Context, socketPath string, params map[string]string, onEvent func(events.Event) error) error {
+	if socketPath == "" {
+		return fmt.Errorf("ipc socket path not provided")
+	}
+	conn, err := net.DialTimeout("unix", socketPath, defaultDialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to ipc server: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err := conn.SetDeadline(time.Now().Add(defaultDialTimeout)); err != nil {
+		return fmt.Errorf("failed to set ipc deadline: %w", err)
+	}
+	payload, err := json.Marshal(Request{Command: SubscribeCommand, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := conn.Write(append(payload, '\n')); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("%s", resp.Message)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("failed to clear ipc deadline: %w", err)
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("daemon closed the event stream")
+			}
+			return fmt.Errorf("failed to read event: %w", err)
+		}
+		var ev events.Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if ev.Type == events.Overrun {
+			return ErrOverrun
+		}
+		if err := onEvent(ev); err != nil {
+			return err
+		}
+	}
+}
+
+// ErrClientClosed is returned for calls on a closed or disconnected Client
+var ErrClientClosed = errors.New("ipc client closed")
+
+// Client keeps one connection to the daemon open and multiplexes requests
+// over it (protocol version 2). It is safe for concurrent use
+type Client struct {
+	conn    net.Conn
+	version int
+	// Bounds writing one request (the Dial timeout), so a daemon that stopped
+	// reading cannot block every caller behind writeMu
+	writeTimeout time.Duration
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]chan Response
+	nextID  uint64
+	err     error         // Why the connection ended
+	done    chan struct{} // Closed when the read loop exits
+}
+
+// Dial connects to the daemon and negotiates a persistent connection
+func Dial(socketPath string, timeout time.Duration) (*Client, error) {
+	if socketPath == "" {
+		return nil, fmt.Errorf("ipc socket path not provided")
+	}
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
+	}
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ipc server: %w", err)
+	}
+	fail := func(err error) (*Client, error) {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return fail(fmt.Errorf("failed to set ipc deadline: %w", err))
+	}
+	hello, err := NewRequest(HelloCommand, map[string]any{"version": ProtocolVersion})
+	if err != nil {
+		return fail(err)
+	}
+	payload, err := json.Marshal(hello)
+	if err != nil {
+		return fail(fmt.Errorf("failed to encode request: %w", err))
+	}
+	if _, err := conn.Write(append(payload, '\n')); err != nil {
+		return fail(fmt.Errorf("failed to send request: %w", err))
+	}
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return fail(fmt.Errorf("failed to read response: %w", err))
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fail(fmt.Errorf("failed to decode response: %w", err))
+	}
+	if !resp.OK {
+		return fail(fmt.Errorf("protocol negotiation failed: %s", resp.Message))
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return fail(fmt.Errorf("failed to clear ipc deadline: %w", err))
+	}
+
+	c := &Client{
+		conn:         conn,
+		version:      ProtocolVersion,
+		writeTimeout: timeout,
+		pending:      make(map[string]chan Response),
+		done:         make(chan struct{}),
+	}
+	if data, ok := resp.Data.(map[string]any); ok {
+		if v, ok := data["version"].(float64); ok {
+			c.version = int(v)
+		}
+	}
+	go c.readLoop(reader)
+	return c, nil
+}
+
+// Version returns the negotiated protocol version
+func (c *Client) Version() int { return c.version }
+
+// Call sends a command with typed params and waits for its response or ctx.
+// A response with ok=false is returned together with an error
+func (c *Client) Call(ctx context.Context, command string, params map[string]any) (Response, error) {
+	req, err := NewRequest(command, params)
+	if err != nil {
+		return Response{}, err
+	}
+	return c.Send(ctx, req)
+}
+
+// Send issues a prepared request; its ID is assigned by the client
+func (c *Client) Send(ctx context.Context, req Request) (Response, error) {
+	ch := make(chan Response, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return Response{}, err
+	}
+	c.nextID++
+	req.ID = strconv.FormatUint(c.nextID, 10)
+	c.pending[req.ID] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, req.ID)
+		c.mu.Unlock()
+	}()
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+	c.writeMu.Lock()
+	err = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	if err == nil {
+		_, err = c.conn.Write(append(payload, '\n'))
+	}
+	c.writeMu.Unlock()
+	if err != nil {
+		// A failed or partial write leaves the stream unusable; end the connection
+		err = fmt.Errorf("%w: failed to send request: %v", ErrClientClosed, err)
+		c.mu.Lock()
+		if c.err == nil {
+			c.err = err
+		}
+		c.mu.Unlock()
+		_ = c.conn.Close()
+		return Response{}, err
+	}
+
+	select {
+	case resp := <-ch:
+		if !resp.OK {
+			if resp.Message != "" {
+				return resp, fmt.Errorf("%s", resp.Message)
+			}
+			return resp, fmt.Errorf("command failed")
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return Response{}, ctx.Err()
+	case <-c.done:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return Response{}, c.err
+	}
+}
+
+// Close closes the connection; pending calls fail with ErrClientClosed
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = ErrClientClosed
+	}
+	c.mu.Unlock()
+	err := c.conn.Close()
+	<-c.done
+	return err
+}
+
+// readLoop routes responses to waiting calls by request ID
+func (c *Client) readLoop(reader *bufio.Reader) {
+	defer close(c.done)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			c.mu.Lock()
+			if c.err == nil {
+				c.err = fmt.Errorf("%w: %v", ErrClientClosed, err)
+			}
+			c.mu.Unlock()
+			return
+		}
+		var resp Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			continue
+		}
+		c.mu.Lock()
+		ch := c.pending[resp.ID]
+		c.mu.Unlock()
+		if ch != nil {
+			select {
+			case ch <- resp:
+			default: // Duplicate response for the same ID
+			}
+		}
+	}
+}
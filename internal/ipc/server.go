@@ -11,26 +11,44 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/AshBuk/dabri/internal/events"
 	"github.com/AshBuk/dabri/internal/logger"
 )
 
 const (
 	defaultIdleTimeout = 30 * time.Second
+	// Time a subscriber gets to accept one event before it is disconnected
+	defaultEventWriteTimeout = 5 * time.Second
+)
+
+const (
+	// SubscribeCommand keeps the connection open and streams events as JSON lines
+	SubscribeCommand = "subscribe"
+	// HelloCommand negotiates the protocol version and makes the connection persistent
+	HelloCommand = "hello"
+	// Requests handled concurrently per persistent connection; reading pauses beyond it
+	maxInFlightRequests = 16
 )
 
 // Server provides a simple Unix domain socket server for local IPC control.
 type Server struct {
 	path     string
 	listener net.Listener
+	adopted  bool // listener was passed in (socket activation); its socket file is not ours
 	handlers map[string]Handler
 	log      logger.Logger
+	events   *events.Bus // Source of the subscribe stream (nil disables it)
+	// eventWriteTimeout bounds each event write; replaced in tests
+	eventWriteTimeout time.Duration
 
 	mu       sync.RWMutex
+	conns    map[net.Conn]struct{} // Persistent connections, closed on Stop
 	stopCh   chan struct{}
 	stopOnce sync.Once
 	wg       sync.WaitGroup // Tracks acceptLoop and connection handlers
@@ -42,10 +60,20 @@ func NewServer(path string, log logger.Logger) *Server {
 		path:     path,
 		handlers: make(map[string]Handler),
 		log:      log,
+		conns:    make(map[net.Conn]struct{}),
 		stopCh:   make(chan struct{}),
+
+		eventWriteTimeout: defaultEventWriteTimeout,
 	}
 }
 
+// SetEventBus enables the subscribe command, streaming events from the bus
+func (s *Server) SetEventBus(bus *events.Bus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = bus
+}
+
 // Register associates a handler with a command name.
 func (s *Server) Register(command string, handler Handler) {
 	cmd := strings.TrimSpace(strings.ToLower(command))
@@ -58,8 +86,25 @@ func (s *Server) Register(command string, handler Handler) {
 	s.handlers[cmd] = handler
 }
 
+// Adopt makes Start serve an already-listening socket, such as one passed by
+// systemd socket activation, instead of binding the path. The socket file
+// belongs to whoever created it, so Stop leaves it in place.
+func (s *Server) Adopt(ln net.Listener) {
+	s.listener = ln
+	s.adopted = true
+	if addr, ok := ln.Addr().(*net.UnixAddr); ok && addr.Name != "" {
+		s.path = addr.Name
+	}
+}
+
 // Start launches the server in a background goroutine.
 func (s *Server) Start() error {
+	if s.adopted {
+		s.wg.Add(1)
+		go s.acceptLoop()
+		s.log.Info("IPC server listening on %s (socket activated)", s.path)
+		return nil
+	}
 	if s.path == "" {
 		return fmt.Errorf("ipc server requires a socket path")
 	}
@@ -141,18 +186,128 @@ func (s *Server) handleConnection(conn net.Conn) {
 		return
 	}
 
+	switch strings.TrimSpace(strings.ToLower(req.Command)) {
+	case SubscribeCommand:
+		s.serveSubscription(conn, reader, req)
+	case HelloCommand:
+		s.servePersistent(conn, reader, req)
+	default:
+		// Protocol version 1: one request, one response, then close
+		s.writeResponse(conn, s.dispatch(req))
+	}
+}
+
+// dispatch runs the handler for a request and tags the response with its ID
+func (s *Server) dispatch(req Request) Response {
 	handler := s.getHandler(req.Command)
 	if handler == nil {
-		s.writeResponse(conn, NewErrorResponse("unknown command"))
-		return
+		return Response{ID: req.ID, Message: "unknown command"}
 	}
-
 	resp, err := handler(req)
 	if err != nil {
-		s.writeResponse(conn, NewErrorResponse(err.Error()))
+		resp = NewErrorResponse(err.Error())
+	}
+	resp.ID = req.ID
+	return resp
+}
+
+// servePersistent answers the hello handshake and then serves requests from
+// the connection until the client closes it or the server stops. Requests run
+// concurrently and responses are written as they complete, carrying the ID
+// of their request. Event streams still need a dedicated connection
+func (s *Server) servePersistent(conn net.Conn, reader *bufio.Reader, hello Request) {
+	version, err := negotiateVersion(hello)
+	if err != nil {
+		resp := NewErrorResponse(err.Error())
+		resp.ID = hello.ID
+		s.writeResponse(conn, resp)
+		return
+	}
+	if !s.trackConn(conn) {
 		return
 	}
-	s.writeResponse(conn, resp)
+	defer s.untrackConn(conn)
+
+	var (
+		writeMu  sync.Mutex
+		inflight sync.WaitGroup
+		slots    = make(chan struct{}, maxInFlightRequests)
+	)
+	respond := func(resp Response) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		s.writeResponse(conn, resp)
+	}
+	ack := NewSuccessResponse("hello", map[string]any{"version": version})
+	ack.ID = hello.ID
+	respond(ack)
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		s.log.Debug("IPC clear read deadline failed: %v", err)
+	}
+
+	defer inflight.Wait()
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			respond(NewErrorResponse("invalid request payload"))
+			continue
+		}
+		switch strings.TrimSpace(strings.ToLower(req.Command)) {
+		case HelloCommand:
+			respond(Response{ID: req.ID, Message: "protocol already negotiated"})
+			continue
+		case SubscribeCommand:
+			respond(Response{ID: req.ID, Message: "subscribe requires a dedicated connection"})
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-s.stopCh:
+			return
+		}
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			defer func() { <-slots }()
+			respond(s.dispatch(req))
+		}()
+	}
+}
+
+// negotiateVersion picks the highest protocol version both sides support
+func negotiateVersion(hello Request) (int, error) {
+	version := ProtocolVersion
+	if _, err := hello.Param("version", &version); err != nil {
+		return 0, err
+	}
+	if version < ProtocolVersion {
+		return 0, fmt.Errorf("unsupported protocol version %d (server supports %d-%d; omit hello for version %d)",
+			version, ProtocolVersion, ProtocolVersion, ProtocolVersionSingleShot)
+	}
+	return min(version, ProtocolVersion), nil
+}
+
+// trackConn registers a persistent connection; false if the server is stopping
+func (s *Server) trackConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.stopCh:
+		return false
+	default:
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
 }
 
 func isTransientAcceptError(err error) bool {
@@ -192,7 +347,13 @@ func (s *Server) writeResponse(conn net.Conn, resp Response) {
 // Stop shuts down the server and removes the socket file.
 func (s *Server) Stop() {
 	s.stopOnce.Do(func() {
+		s.mu.Lock()
 		close(s.stopCh)
+		// Unblock persistent connections waiting for the next request
+		for conn := range s.conns {
+			_ = conn.Close()
+		}
+		s.mu.Unlock()
 		if s.listener != nil {
 			_ = s.listener.Close()
 		}
@@ -211,10 +372,96 @@ func (s *Server) Stop() {
 			s.log.Warning("IPC server stop timeout - handlers may still be running")
 		}
 
-		if s.path != "" {
+		if s.path != "" && !s.adopted {
 			if err := os.RemoveAll(s.path); err != nil && !os.IsNotExist(err) {
 				s.log.Debug("Failed to remove IPC socket: %v", err)
 			}
 		}
 	})
 }
+
+// serveSubscription acknowledges a subscribe request and streams events until
+// the client disconnects or the server stops. Optional param: events, a
+// comma-separated list of event types. A client that falls behind is sent an
+// overrun event and disconnected rather than slowing down the daemon
+func (s *Server) serveSubscription(conn net.Conn, reader *bufio.Reader, req Request) {
+	s.mu.RLock()
+	bus := s.events
+	s.mu.RUnlock()
+	if bus == nil {
+		s.writeResponse(conn, NewErrorResponse("event stream not available"))
+		return
+	}
+	types, err := parseEventTypes(req.Params["events"])
+	if err != nil {
+		s.writeResponse(conn, NewErrorResponse(err.Error()))
+		return
+	}
+
+	sub := bus.Subscribe(events.DefaultBuffer, types...)
+	defer sub.Close()
+	if len(types) == 0 {
+		types = events.Types
+	}
+	s.writeResponse(conn, NewSuccessResponse("subscribed", map[string]any{"events": types}))
+	if err := conn.SetReadDeadline(time.Time{}); err != nil {
+		s.log.Debug("IPC clear read deadline failed: %v", err)
+	}
+
+	// The client sends nothing more; a finished read means it went away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		_, _ = reader.ReadBytes('\n')
+	}()
+
+	for {
+		select {
+		case ev, ok := <-sub.C:
+			if !ok {
+				if sub.Overrun() {
+					s.log.Warning("IPC subscriber too slow, disconnecting")
+					_ = s.writeEvent(conn, events.Event{Type: events.Overrun, Time: time.Now()})
+				}
+				return
+			}
+			if err := s.writeEvent(conn, ev); err != nil {
+				s.log.Debug("IPC subscriber write failed, disconnecting: %v", err)
+				return
+			}
+		case <-gone:
+			return
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// writeEvent sends one event line within the event write timeout
+func (s *Server) writeEvent(conn net.Conn, ev events.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(s.eventWriteTimeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(append(data, '\n'))
+	return err
+}
+
+// parseEventTypes validates a comma-separated event type filter
+func parseEventTypes(raw string) ([]string, error) {
+	var types []string
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if t == "" {
+			continue
+		}
+		if !slices.Contains(events.Types, t) {
+			return nil, fmt.Errorf("unknown event type: %s (valid: %s)", t, strings.Join(events.Types, ", "))
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/testutils"
+)
+
+func startTestServer(t *testing.T, bus *events.Bus) (*Server, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dabri.sock")
+	server := NewServer(path, testutils.NewMockLogger())
+	server.SetEventBus(bus)
+	server.Register("status", func(Request) (Response, error) {
+		return NewSuccessResponse("ok", nil), nil
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(server.Stop)
+	return server, path
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_SendRequest(t *testing.T) {
+	_, path := startTestServer(t, nil)
+	if _, err := SendRequest(path, Request{Command: "status"}, time.Second); err != nil {
+		t.Errorf("status: %v", err)
+	}
+	if _, err := SendRequest(path, Request{Command: "missing"}, time.Second); err == nil || err.Error() != "unknown command" {
+		t.Errorf("expected unknown command error, got %v", err)
+	}
+}
+
+func TestServer_AdoptListener(t *testing.T) {
+	// Stands in for a socket systemd created and passed via LISTEN_FDS
+	path := filepath.Join(t.TempDir(), "activated.sock")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatalf("ListenUnix: %v", err)
+	}
+	ln.SetUnlinkOnClose(false)
+
+	server := NewServer("", testutils.NewMockLogger())
+	server.Register("status", func(Request) (Response, error) {
+		return NewSuccessResponse("ok", nil), nil
+	})
+	server.Adopt(ln)
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := SendRequest(path, Request{Command: "status"}, time.Second); err != nil {
+		t.Errorf("status over adopted socket: %v", err)
+	}
+	server.Stop()
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Stop removed the adopted socket file: %v", err)
+	}
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	bus := events.NewBus()
+	_, path := startTestServer(t, bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan events.Event, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Subscribe(ctx, path, map[string]string{"events": "recording_started,transcript"}, func(ev events.Event) error {
+			received <- ev
+			return nil
+		})
+	}()
+	waitFor(t, "subscriber", func() bool { return bus.Subscribers() == 1 })
+
+	bus.Publish(events.RecordingStarted, nil)
+	bus.Publish(events.AudioLevel, map[string]any{"level": 0.5}) // filtered out
+	bus.Publish(events.Transcript, map[string]any{"text": "hello"})
+
+	first, second := <-received, <-received
+	if first.Type != events.RecordingStarted || second.Type != events.Transcript {
+		t.Fatalf("received %s, %s", first.Type, second.Type)
+	}
+	if second.Data["text"] != "hello" {
+		t.Errorf("transcript data = %v", second.Data)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Subscribe after cancel: %v", err)
+	}
+	// The server notices the client left and releases the subscription
+	waitFor(t, "unsubscribe", func() bool { return bus.Subscribers() == 0 })
+}
+
+func TestServer_SubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		bus     *events.Bus
+		params  map[string]string
+		wantErr string
+	}{
+		{"no event bus", nil, nil, "event stream not available"},
+		{"unknown event type", events.NewBus(), map[string]string{"events": "transcript,bogus"}, "unknown event type: bogus"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, path := startTestServer(t, tt.bus)
+			err := Subscribe(context.Background(), path, tt.params, func(events.Event) error { return nil })
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestServer_SlowSubscriberIsDisconnected(t *testing.T) {
+	bus := events.NewBus()
+	server, path := startTestServer(t, bus)
+	server.eventWriteTimeout = 50 * time.Millisecond
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Write([]byte(`{"command":"subscribe"}` + "\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("read ack: %v", err)
+	}
+	var ack Response
+	if err := json.Unmarshal(line, &ack); err != nil || !ack.OK {
+		t.Fatalf("ack = %s, %v", line, err)
+	}
+
+	// The client stops reading: publishing must never block, and once the
+	// socket and queue are full the subscriber is dropped
+	payload := map[string]any{"text": strings.Repeat("x", 4096)}
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for range 2000 {
+			bus.Publish(events.Transcript, payload)
+		}
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a slow subscriber")
+	}
+	waitFor(t, "slow subscriber removal", func() bool { return bus.Subscribers() == 0 })
+
+	// Reading resumes: the stream ends instead of delivering every event
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for {
+		if _, err := reader.ReadBytes('\n'); err != nil {
+			if errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "timeout") {
+				t.Fatalf("expected the server to close the stream, got %v", err)
+			}
+			break
+		}
+		count++
+	}
+	if count >= 2000 {
+		t.Errorf("slow subscriber received all %d events", count)
+	}
+}
+
+func TestRequest_TypedParams(t *testing.T) {
+	var req Request
+	payload := `{"id":"7","command":"history-list","params":{"limit":20,"query":"milk","tags":["a","b"],"opts":{"x":1},"raw":true,"none":null}}`
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if req.ID != "7" || req.Params["limit"] != "20" || req.Params["query"] != "milk" || req.Params["raw"] != "true" {
+		t.Errorf("string view = %+v", req.Params)
+	}
+	if _, ok := req.Params["none"]; ok {
+		t.Error("null params must be omitted")
+	}
+	var tags []string
+	if ok, err := req.Param("tags", &tags); !ok || err != nil || len(tags) != 2 || tags[1] != "b" {
+		t.Errorf("Param(tags) = %v, %t, %v", tags, ok, err)
+	}
+	var opts struct{ X int }
+	if ok, err := req.Param("opts", &opts); !ok || err != nil || opts.X != 1 {
+		t.Errorf("Param(opts) = %+v, %t, %v", opts, ok, err)
+	}
+	var limit int
+	if _, err := req.Param("query", &limit); err == nil {
+		t.Error("expected a type error decoding a string into an int")
+	}
+	if ok, _ := req.Param("missing", &limit); ok {
+		t.Error("expected missing param to be reported absent")
+	}
+
+	// String params set in Go decode into typed values too
+	legacy := Request{Command: "history-list", Params: map[string]string{"limit": "5", "query": "true"}}
+	var query string
+	if _, err := legacy.Param("limit", &limit); err != nil || limit != 5 {
+		t.Errorf("legacy Param(limit) = %d, %v", limit, err)
+	}
+	if _, err := legacy.Param("query", &query); err != nil || query != "true" {
+		t.Errorf("legacy Param(query) = %q, %v", query, err)
+	}
+
+	// Round trip keeps types
+	typed, err := NewRequest("set", map[string]any{"limit": 3, "name": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"command":"set","params":{"limit":3,"name":"x"}}` {
+		t.Errorf("Marshal = %s", got)
+	}
+}
+
+func TestClient_Multiplexed(t *testing.T) {
+	server, path := startTestServer(t, nil)
+	release := make(chan struct{})
+	server.Register("slow", func(Request) (Response, error) {
+		<-release
+		return NewSuccessResponse("slow done", nil), nil
+	})
+	server.Register("echo", func(req Request) (Response, error) {
+		var values []int
+		if _, err := req.Param("values", &values); err != nil {
+			return Response{}, err
+		}
+		return NewSuccessResponse("echo", map[string]any{"sum": values[0] + values[1]}), nil
+	})
+
+	client, err := Dial(path, time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	if client.Version() != ProtocolVersion {
+		t.Errorf("Version() = %d", client.Version())
+	}
+
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := client.Call(context.Background(), "slow", nil)
+		slowDone <- err
+	}()
+
+	// Answered while "slow" is still in flight on the same connection
+	resp, err := client.Call(context.Background(), "echo", map[string]any{"values": []int{2, 3}})
+	if err != nil {
+		t.Fatalf("echo: %v", err)
+	}
+	if sum := resp.Data.(map[string]any)["sum"]; sum != float64(5) {
+		t.Errorf("sum = %v", sum)
+	}
+	select {
+	case err := <-slowDone:
+		t.Fatalf("slow finished early: %v", err)
+	default:
+	}
+	close(release)
+	if err := <-slowDone; err != nil {
+		t.Errorf("slow: %v", err)
+	}
+
+	if _, err := client.Call(context.Background(), "missing", nil); err == nil || err.Error() != "unknown command" {
+		t.Errorf("expected unknown command, got %v", err)
+	}
+	if _, err := client.Call(context.Background(), SubscribeCommand, nil); err == nil {
+		t.Error("expected subscribe to be refused on a persistent connection")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	server.Register("stuck", func(Request) (Response, error) {
+		time.Sleep(200 * time.Millisecond)
+		return NewSuccessResponse("late", nil), nil
+	})
+	if _, err := client.Call(ctx, "stuck", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	// The late response is discarded and the connection stays usable
+	if _, err := client.Call(context.Background(), "status", nil); err != nil {
+		t.Errorf("status after timeout: %v", err)
+	}
+}
+
+func TestClient_HelloAndShutdown(t *testing.T) {
+	server, path := startTestServer(t, nil)
+
+	// Unsupported versions are refused
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Write([]byte(`{"id":"h","command":"hello","params":{"version":1}}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.OK || resp.ID != "h" || !strings.Contains(resp.Message, "unsupported protocol version") {
+		t.Errorf("hello v1 = %+v", resp)
+	}
+
+	client, err := Dial(path, time.Second)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	server.Stop()
+	if _, err := client.Call(context.Background(), "status", nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected ErrClientClosed after server stop, got %v", err)
+	}
+	_ = client.Close()
+}
+
+func TestClient_WriteTimeout(t *testing.T) {
+	// A daemon that answers hello and then stops reading
+	path := filepath.Join(t.TempDir(), "stalled.sock")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		var hello Request
+		if err := json.NewDecoder(conn).Decode(&hello); err != nil {
+			return
+		}
+		_ = json.NewEncoder(conn).Encode(Response{ID: hello.ID, OK: true, Data: map[string]any{"version": ProtocolVersion}})
+		<-stop
+	}()
+
+	client, err := Dial(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+	// Larger than the socket buffer, so the write blocks
+	big := strings.Repeat("x", 8<<20)
+	start := time.Now()
+	if _, err := client.Call(context.Background(), "echo", map[string]any{"text": big}); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected ErrClientClosed for a stalled write, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("write was not bounded by the timeout: %v", elapsed)
+	}
+	if _, err := client.Call(context.Background(), "status", nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected the connection to be closed after a failed write, got %v", err)
+	}
+}
@@ -3,14 +3,131 @@
 
 package ipc
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Protocol versions. Version 1 is one request per connection; version 2 is
+// negotiated with the hello command and keeps the connection open for
+// concurrent requests matched to responses by ID.
+const (
+	ProtocolVersionSingleShot = 1
+	ProtocolVersion           = 2
+)
+
 // Request represents a command sent over the IPC channel.
+// Params holds every parameter as a string (numbers and booleans in their
+// JSON form, lists and objects as JSON text) so handlers can read simple
+// values directly; Param decodes the original typed value.
 type Request struct {
-	Command string            `json:"command"`
-	Params  map[string]string `json:"params,omitempty"`
+	ID      string            // Echoed in the response on persistent connections
+	Command string            // Handler name
+	Params  map[string]string // String view of the parameters
+	typed   map[string]json.RawMessage
+}
+
+// NewRequest builds a request with typed parameters (numbers, lists, objects).
+func NewRequest(command string, params map[string]any) (Request, error) {
+	req := Request{Command: command}
+	for key, value := range params {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return Request{}, fmt.Errorf("failed to encode param %s: %w", key, err)
+		}
+		if err := req.setRaw(key, raw); err != nil {
+			return Request{}, err
+		}
+	}
+	return req, nil
+}
+
+// Param decodes the parameter key into v and reports whether it was present.
+func (r Request) Param(key string, v any) (bool, error) {
+	raw, ok := r.typed[key]
+	if !ok {
+		// Set as a string in Go: accept JSON text ("20", "[1,2]") or a plain string
+		str, ok := r.Params[key]
+		if !ok {
+			return false, nil
+		}
+		if json.Unmarshal([]byte(str), v) == nil {
+			return true, nil
+		}
+		raw, _ = json.Marshal(str)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("invalid param %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// setRaw stores a JSON parameter and its string view.
+func (r *Request) setRaw(key string, raw json.RawMessage) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	str := string(raw)
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return fmt.Errorf("invalid param %s: %w", key, err)
+		}
+	}
+	if r.Params == nil {
+		r.Params = make(map[string]string)
+	}
+	if r.typed == nil {
+		r.typed = make(map[string]json.RawMessage)
+	}
+	r.Params[key] = str
+	r.typed[key] = raw
+	return nil
+}
+
+type requestWire struct {
+	ID      string                     `json:"id,omitempty"`
+	Command string                     `json:"command"`
+	Params  map[string]json.RawMessage `json:"params,omitempty"`
+}
+
+// MarshalJSON encodes typed parameters as sent and string parameters as strings.
+func (r Request) MarshalJSON() ([]byte, error) {
+	wire := requestWire{ID: r.ID, Command: r.Command}
+	if len(r.Params) > 0 || len(r.typed) > 0 {
+		wire.Params = make(map[string]json.RawMessage, len(r.Params))
+		for key, value := range r.Params {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			wire.Params[key] = raw
+		}
+		maps.Copy(wire.Params, r.typed)
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON accepts parameters of any JSON type.
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var wire requestWire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*r = Request{ID: wire.ID, Command: wire.Command}
+	for key, raw := range wire.Params {
+		if err := r.setRaw(key, raw); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Response represents the result of handling an IPC command.
 type Response struct {
+	ID      string `json:"id,omitempty"` // Request ID on persistent connections
 	OK      bool   `json:"ok"`
 	Message string `json:"message,omitempty"`
 	Data    any    `json:"data,omitempty"`
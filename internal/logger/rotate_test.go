@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFile_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dabri.log")
+	r, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	defer r.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for file, want := range expected {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(file), data, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("Expected backups beyond maxBackups to be dropped")
+	}
+}
+
+func TestRotatingFile_AppendsExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dabri.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenRotatingFile(path, 100, 1)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	_, _ = r.Write([]byte("new\n"))
+	_ = r.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "old\nnew\n" {
+		t.Errorf("Expected append to existing file, got %q", data)
+	}
+	if _, err := r.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format selects how StructuredLogger renders records
+type Format string
+
+const (
+	FormatText Format = "text" // key=value pairs, one record per line
+	FormatJSON Format = "json" // one JSON object per line
+)
+
+// FieldLogger is a Logger that can attach key/value fields to its messages
+type FieldLogger interface {
+	Logger
+	With(fields ...any) Logger // Returns a logger adding the fields (key, value, ...) to every message
+}
+
+// LevelController is implemented by loggers whose level can change at runtime
+type LevelController interface {
+	Level() LogLevel
+	SetLevel(level LogLevel)
+}
+
+// With adds key/value fields to l's messages; loggers without field support are returned unchanged
+func With(l Logger, fields ...any) Logger {
+	if fl, ok := l.(FieldLogger); ok {
+		return fl.With(fields...)
+	}
+	return l
+}
+
+// WithComponent tags l's messages with component=name (audio, hotkeys, ipc, ws, ...)
+func WithComponent(l Logger, name string) Logger {
+	return With(l, "component", name)
+}
+
+// Options configures a StructuredLogger
+type Options struct {
+	Level      LogLevel
+	Format     Format    // FormatText when empty
+	Output     io.Writer // Console output, os.Stderr when nil
+	File       string    // Also append to this file (empty for none)
+	MaxSize    int64     // Rotate the file beyond this many bytes (0 for DefaultMaxSize)
+	MaxBackups int       // Rotated files kept as File.1 … File.N
+}
+
+// StructuredLogger implements Logger on log/slog with fields, JSON or text
+// output, an optional rotating log file and a level adjustable at runtime.
+// Loggers derived with With share the output, format and level of their parent
+type StructuredLogger struct {
+	core  *loggerCore
+	attrs []slog.Attr // Fields added by With
+}
+
+// loggerCore is the state shared by a logger and everything derived from it
+type loggerCore struct {
+	level slog.LevelVar
+
+	mu      sync.RWMutex
+	handler slog.Handler
+	file    *RotatingFile // Open log file, closed when replaced
+}
+
+// NewStructuredLogger Constructor - creates a logger writing to opts.Output (stderr by default)
+// The log file is left out if it cannot be opened; Configure reports that error
+func NewStructuredLogger(opts Options) *StructuredLogger {
+	l := &StructuredLogger{core: &loggerCore{}}
+	if err := l.Configure(opts); err != nil {
+		opts.File = ""
+		_ = l.Configure(opts)
+		l.Warning("Log file not opened: %v", err)
+	}
+	return l
+}
+
+// Configure replaces level, format and outputs; derived loggers follow
+func (l *StructuredLogger) Configure(opts Options) error {
+	output := opts.Output
+	if output == nil {
+		output = os.Stderr
+	}
+	var file *RotatingFile
+	if opts.File != "" {
+		var err error
+		if file, err = OpenRotatingFile(opts.File, opts.MaxSize, opts.MaxBackups); err != nil {
+			return err
+		}
+		output = io.MultiWriter(output, file)
+	}
+	handlerOpts := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       &l.core.level,
+		ReplaceAttr: replaceAttr,
+	}
+	var handler slog.Handler = slog.NewTextHandler(output, handlerOpts)
+	if opts.Format == FormatJSON {
+		handler = slog.NewJSONHandler(output, handlerOpts)
+	}
+
+	l.core.level.Set(opts.Level.slogLevel())
+	l.core.mu.Lock()
+	previous := l.core.file
+	l.core.handler, l.core.file = handler, file
+	l.core.mu.Unlock()
+	if previous != nil {
+		_ = previous.Close()
+	}
+	return nil
+}
+
+// Close closes the log file, if any; console output continues
+func (l *StructuredLogger) Close() error {
+	l.core.mu.Lock()
+	file := l.core.file
+	l.core.file = nil
+	l.core.mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	return file.Close()
+}
+
+// Level returns the current minimum level
+func (l *StructuredLogger) Level() LogLevel {
+	return levelFromSlog(l.core.level.Level())
+}
+
+// SetLevel changes the minimum level for this logger and all derived loggers
+func (l *StructuredLogger) SetLevel(level LogLevel) {
+	l.core.level.Set(level.slogLevel())
+}
+
+// With returns a logger adding fields (key, value, ...) to every message
+func (l *StructuredLogger) With(fields ...any) Logger {
+	record := slog.NewRecord(time.Time{}, slog.LevelInfo, "", 0)
+	record.Add(fields...)
+	attrs := make([]slog.Attr, 0, len(l.attrs)+record.NumAttrs())
+	attrs = append(attrs, l.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return &StructuredLogger{core: l.core, attrs: attrs}
+}
+
+// Debug logs detailed diagnostic information
+func (l *StructuredLogger) Debug(format string, args ...any) {
+	l.logf(DebugLevel, format, args)
+}
+
+// Info logs general operational messages
+func (l *StructuredLogger) Info(format string, args ...any) {
+	l.logf(InfoLevel, format, args)
+}
+
+// Warning logs potential issues or degraded functionality
+func (l *StructuredLogger) Warning(format string, args ...any) {
+	l.logf(WarningLevel, format, args)
+}
+
+// Error logs critical failures requiring attention
+func (l *StructuredLogger) Error(format string, args ...any) {
+	l.logf(ErrorLevel, format, args)
+}
+
+// Log writes msg with key/value fields, e.g. Log(InfoLevel, "transcribed", "model", m, "ms", 820)
+func (l *StructuredLogger) Log(level LogLevel, msg string, fields ...any) {
+	if !l.enabled(level) {
+		return
+	}
+	l.write(level, msg, fields, 0)
+}
+
+func (l *StructuredLogger) logf(level LogLevel, format string, args []any) {
+	if !l.enabled(level) {
+		return
+	}
+	l.write(level, fmt.Sprintf(format, args...), nil, 1)
+}
+
+func (l *StructuredLogger) enabled(level LogLevel) bool {
+	return level.slogLevel() >= l.core.level.Level()
+}
+
+// write emits one record; skip counts frames between write and the public
+// method so the source points at the caller of Debug/Info/Warning/Error/Log
+func (l *StructuredLogger) write(level LogLevel, msg string, fields []any, skip int) {
+	var pcs [1]uintptr
+	runtime.Callers(3+skip, pcs[:]) // Callers, write, public method
+	record := slog.NewRecord(time.Now(), level.slogLevel(), msg, pcs[0])
+	record.AddAttrs(l.attrs...)
+	record.Add(fields...)
+
+	l.core.mu.RLock()
+	handler := l.core.handler
+	l.core.mu.RUnlock()
+	_ = handler.Handle(context.Background(), record)
+}
+
+// replaceAttr shortens the source to file:line and names levels as Logger does
+func replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	switch attr.Key {
+	case slog.SourceKey:
+		if source, ok := attr.Value.Any().(*slog.Source); ok {
+			attr.Value = slog.StringValue(filepath.Base(source.File) + ":" + strconv.Itoa(source.Line))
+		}
+	case slog.LevelKey:
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			attr.Value = slog.StringValue(strings.ToUpper(levelFromSlog(level).String()))
+		}
+	}
+	return attr
+}
+
+// String returns the level name used in config and the log-level command
+func (level LogLevel) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarningLevel:
+		return "warning"
+	case ErrorLevel:
+		return "error"
+	}
+	return "level(" + strconv.Itoa(int(level)) + ")"
+}
+
+// ParseLevel parses debug, info, warning (or warn) and error
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warning", "warn":
+		return WarningLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level: %s (must be debug, info, warning or error)", name)
+}
+
+func (level LogLevel) slogLevel() slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case WarningLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+func levelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarningLevel
+	}
+	return ErrorLevel
+}
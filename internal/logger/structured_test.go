@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStructuredLogger_JSONFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(Options{Level: InfoLevel, Format: FormatJSON, Output: &buf})
+
+	WithComponent(l, "audio").Warning("device %s busy", "hw:0")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Output is not JSON: %v (%q)", err, buf.String())
+	}
+	expected := map[string]string{
+		"level":     "WARNING",
+		"msg":       "device hw:0 busy",
+		"component": "audio",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("Expected %s=%q, got %v", key, value, record[key])
+		}
+	}
+	if source, _ := record["source"].(string); !strings.HasPrefix(source, "structured_test.go:") {
+		t.Errorf("Expected source in this file, got %v", record["source"])
+	}
+}
+
+func TestStructuredLogger_TextFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(Options{Level: InfoLevel, Output: &buf})
+
+	l.With("component", "ipc", "client", 3).(*StructuredLogger).Log(InfoLevel, "request", "command", "status")
+
+	line := buf.String()
+	for _, want := range []string{"level=INFO", "msg=request", "component=ipc", "client=3", "command=status", "source=structured_test.go:"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+}
+
+func TestStructuredLogger_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStructuredLogger(Options{Level: InfoLevel, Output: &buf})
+	child := WithComponent(l, "hotkeys")
+
+	child.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("Debug logged at info level: %q", buf.String())
+	}
+
+	l.SetLevel(DebugLevel)
+	if l.Level() != DebugLevel {
+		t.Errorf("Expected level debug, got %v", l.Level())
+	}
+	child.Debug("shown")
+	if !strings.Contains(buf.String(), "msg=shown") {
+		t.Errorf("Derived logger did not follow level change: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetLevel(ErrorLevel)
+	child.Warning("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Warning logged at error level: %q", buf.String())
+	}
+}
+
+func TestStructuredLogger_ConfigureFile(t *testing.T) {
+	var buf bytes.Buffer
+	path := filepath.Join(t.TempDir(), "logs", "dabri.log")
+	l := NewStructuredLogger(Options{Level: InfoLevel, Output: &buf, File: path})
+	defer l.Close()
+
+	l.Info("to both")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "msg=\"to both\"") || !strings.Contains(buf.String(), "msg=\"to both\"") {
+		t.Errorf("Expected message in console and file, got %q and %q", buf.String(), data)
+	}
+
+	if err := l.Configure(Options{Level: InfoLevel, Format: FormatJSON, Output: &buf}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	l.Info("console only")
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "console only") {
+		t.Error("Message written to file after it was removed from the configuration")
+	}
+}
+
+func TestWith_NonStructuredLogger(t *testing.T) {
+	l := NewDefaultLogger(InfoLevel)
+	if got := WithComponent(l, "ws"); got != Logger(l) {
+		t.Error("Expected loggers without field support to be returned unchanged")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"debug", DebugLevel, false},
+		{"INFO", InfoLevel, false},
+		{"warn", WarningLevel, false},
+		{"warning", WarningLevel, false},
+		{" error ", ErrorLevel, false},
+		{"verbose", InfoLevel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import "github.com/AshBuk/dabri/internal/version"
+
+// Daemon metrics, updated where the events happen and exported by Default.
+// Durations are in seconds, per Prometheus conventions
+var (
+	RecordingDuration = Default.NewHistogram("dabri_recording_duration_seconds",
+		"Length of finished recordings.",
+		[]float64{1, 2, 5, 10, 20, 30, 60, 120, 300})
+
+	TranscriptionDuration = Default.NewHistogram("dabri_transcription_duration_seconds",
+		"Whisper processing time per transcription, by model.",
+		[]float64{0.25, 0.5, 1, 2, 3, 5, 10, 20, 30, 60}, "model")
+
+	RealTimeFactor = Default.NewHistogram("dabri_transcription_real_time_factor",
+		"Transcription time divided by recording length, by model (below 1 is faster than real time).",
+		[]float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 1.5, 2, 3, 5}, "model")
+
+	TranscriptionQueueDepth = Default.NewGauge("dabri_transcription_queue_depth",
+		"Transcriptions waiting for or running in whisper.")
+
+	OutputFailures = Default.NewCounter("dabri_output_failures_total",
+		"Failed transcript deliveries, by output target and the tool behind it.", "target", "tool")
+
+	FallbackSwitches = Default.NewCounter("dabri_fallback_switches_total",
+		"Automatic switches to a fallback: recorder (ffmpeg to arecord), output (typing and clipboard), typing_tool and hotkey_provider.",
+		"kind", "from", "to")
+
+	HotkeyProviderRestarts = Default.NewCounter("dabri_hotkey_provider_restarts_total",
+		"Keyboard provider restarts after config reloads and hotkey capture, by provider.", "provider")
+
+	ModelDownloadBytes = Default.NewCounter("dabri_model_download_bytes_total",
+		"Bytes received while downloading whisper models, by model file.", "model")
+
+	buildInfo = Default.NewGauge("dabri_build_info",
+		"Always 1; the version label identifies the running build.", "version")
+)
+
+func init() {
+	buildInfo.Set(1, version.Version)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+// Package metrics implements the subset of the Prometheus client the daemon
+// needs: counters, gauges and histograms with labels, rendered in the text
+// exposition format (version 0.0.4) without third-party dependencies
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry renders every metric registered with it
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default holds the daemon metrics declared in dabri.go
+var Default = NewRegistry()
+
+// family is one metric name with its series, keyed by label values
+type family struct {
+	name    string
+	help    string
+	kind    string // counter, gauge or histogram
+	labels  []string
+	buckets []float64 // Upper bounds, histograms only
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is one combination of label values
+type series struct {
+	labelValues []string
+	value       float64  // Counter and gauge value
+	counts      []uint64 // Per-bucket observations (not cumulative), histograms only
+	sum         float64
+	count       uint64
+}
+
+func (r *Registry) register(f *family) *family {
+	f.series = make(map[string]*series)
+	if len(f.labels) == 0 {
+		// Unlabelled metrics are exported as zero before the first update
+		f.get(nil)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series for labelValues, or nil when their count does not
+// match the declared labels (the update is dropped)
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		return nil
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: slices.Clone(labelValues)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// update applies fn to a series under the family lock
+func (f *family) update(labelValues []string, fn func(*series)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s := f.get(labelValues); s != nil {
+		fn(s)
+	}
+}
+
+// Counter is a monotonically increasing value
+type Counter struct{ f *family }
+
+// NewCounter registers a counter; by convention the name ends in _total
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(&family{name: name, help: help, kind: "counter", labels: labels})}
+}
+
+// Inc adds one to the series of labelValues
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v (ignored when negative) to the series of labelValues
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.update(labelValues, func(s *series) { s.value += v })
+}
+
+// Gauge is a value that goes up and down
+type Gauge struct{ f *family }
+
+// NewGauge registers a gauge
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(&family{name: name, help: help, kind: "gauge", labels: labels})}
+}
+
+// Set replaces the value of the series of labelValues
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.f.update(labelValues, func(s *series) { s.value = v })
+}
+
+// Add adds v (may be negative) to the series of labelValues
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.f.update(labelValues, func(s *series) { s.value += v })
+}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct{ f *family }
+
+// NewHistogram registers a histogram with the given ascending bucket upper bounds
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{r.register(&family{name: name, help: help, kind: "histogram", labels: labels, buckets: buckets})}
+}
+
+// Observe records v in the series of labelValues
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.f.update(labelValues, func(s *series) {
+		if i, _ := slices.BinarySearch(h.f.buckets, v); i < len(s.counts) {
+			s.counts[i]++
+		}
+		s.sum += v
+		s.count++
+	})
+}
+
+// WriteText renders all metrics in the Prometheus text format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := slices.Clone(r.families)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.writeText(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves WriteText over HTTP
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.WriteText(w)
+	})
+}
+
+func (f *family) writeText(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, _ = w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	_, _ = w.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != "histogram" {
+			writeSample(w, f.name, f.labels, s.labelValues, "", "", s.value)
+			continue
+		}
+		var cumulative uint64
+		for i, bound := range f.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, f.name+"_bucket", f.labels, s.labelValues, "le", formatFloat(bound), float64(cumulative))
+		}
+		writeSample(w, f.name+"_bucket", f.labels, s.labelValues, "le", "+Inf", float64(s.count))
+		writeSample(w, f.name+"_sum", f.labels, s.labelValues, "", "", s.sum)
+		writeSample(w, f.name+"_count", f.labels, s.labelValues, "", "", float64(s.count))
+	}
+}
+
+// writeSample writes one line; extraName/extraValue add the histogram "le" label
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraName, extraValue string, v float64) {
+	_, _ = w.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		_ = w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = w.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		_ = w.WriteByte('}')
+	}
+	_, _ = w.WriteString(" " + formatFloat(v) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	return b.String()
+}
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	failures := r.NewCounter("test_failures_total", "Failures.", "target", "tool")
+	queue := r.NewGauge("test_queue_depth", "Queue depth.")
+	latency := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.5, 1}, "model")
+
+	failures.Inc("clipboard", "xsel")
+	failures.Add(2, "active_window", "wtype")
+	failures.Add(-1, "active_window", "wtype") // counters never decrease
+	failures.Inc("too", "many", "labels")      // dropped
+	queue.Add(2)
+	queue.Add(-1)
+	latency.Observe(0.5, "base.en")
+	latency.Observe(0.7, "base.en")
+	latency.Observe(3, "base.en")
+
+	expected := `# HELP test_failures_total Failures.
+# TYPE test_failures_total counter
+test_failures_total{target="active_window",tool="wtype"} 2
+test_failures_total{target="clipboard",tool="xsel"} 1
+# HELP test_queue_depth Queue depth.
+# TYPE test_queue_depth gauge
+test_queue_depth 1
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{model="base.en",le="0.5"} 1
+test_latency_seconds_bucket{model="base.en",le="1"} 2
+test_latency_seconds_bucket{model="base.en",le="+Inf"} 3
+test_latency_seconds_sum{model="base.en"} 4.2
+test_latency_seconds_count{model="base.en"} 3
+`
+	if got := render(t, r); got != expected {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestRegistry_EscapesLabelValues(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Help with \\ and\nnewline.", "name").Inc("say \"hi\"\n")
+
+	got := render(t, r)
+	for _, want := range []string{
+		`# HELP test_total Help with \\ and\nnewline.`,
+		`test_total{name="say \"hi\"\n"} 1`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_up", "Up.").Set(1)
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "test_up 1\n") {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestDefault_DaemonMetrics(t *testing.T) {
+	got := render(t, Default)
+	for _, name := range []string{
+		"dabri_recording_duration_seconds", "dabri_transcription_duration_seconds",
+		"dabri_transcription_real_time_factor", "dabri_transcription_queue_depth",
+		"dabri_output_failures_total", "dabri_fallback_switches_total",
+		"dabri_hotkey_provider_restarts_total", "dabri_model_download_bytes_total", "dabri_build_info",
+	} {
+		if !strings.Contains(got, "# TYPE "+name+" ") {
+			t.Errorf("Default is missing %s", name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/AshBuk/dabri/internal/logger"
+)
+
+const (
+	socketReadTimeout     = 5 * time.Second
+	socketShutdownTimeout = 2 * time.Second
+)
+
+// SocketServer serves /metrics over HTTP on a Unix socket, for when the web
+// server is disabled (scrape with curl --unix-socket or a local exporter)
+type SocketServer struct {
+	path   string
+	server *http.Server
+	log    logger.Logger
+}
+
+// NewSocketServer creates a server for registry on the socket at path
+func NewSocketServer(path string, registry *Registry, log logger.Logger) *SocketServer {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	return &SocketServer{
+		path:   path,
+		server: &http.Server{Handler: mux, ReadHeaderTimeout: socketReadTimeout},
+		log:    log,
+	}
+}
+
+// Start binds the socket (owner-only access) and serves in the background
+func (s *SocketServer) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create metrics socket directory: %w", err)
+	}
+	if err := os.RemoveAll(s.path); err != nil {
+		return fmt.Errorf("failed to remove stale metrics socket: %w", err)
+	}
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics socket: %w", err)
+	}
+	if err := os.Chmod(s.path, 0o600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("failed to set metrics socket permissions: %w", err)
+	}
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("Metrics server error: %v", err)
+		}
+	}()
+	s.log.Info("Metrics available on unix socket %s", s.path)
+	return nil
+}
+
+// Stop shuts the server down; the listener removes the socket file
+func (s *SocketServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), socketShutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.log.Debug("Metrics server shutdown: %v", err)
+	}
+}
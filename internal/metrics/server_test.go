@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AshBuk/dabri/internal/testutils"
+)
+
+func TestSocketServer(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test_up", "Up.").Set(1)
+	path := filepath.Join(t.TempDir(), "metrics.sock")
+	server := NewSocketServer(path, r, testutils.NewMockLogger())
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://unix/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "test_up 1\n") {
+		t.Errorf("GET /metrics = %d %q", resp.StatusCode, body)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket permissions: %v, %v", info, err)
+	}
+
+	server.Stop()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Stop left the socket file behind: %v", err)
+	}
+}
@@ -11,10 +11,10 @@ import (
 )
 
 // Helper function to create test config
-func createTestConfig() *config.Config {
+func createTestConfig() *config.Live {
 	cfg := &config.Config{}
 	config.SetDefaultConfig(cfg)
-	return cfg
+	return config.NewLive(cfg)
 }
 
 func TestNewNotificationManager(t *testing.T) {
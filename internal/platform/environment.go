@@ -6,6 +6,7 @@ package platform
 import (
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -93,6 +94,21 @@ func HasStatusNotifierWatcher() bool {
 	return false
 }
 
+// HasGlobalShortcutsPortal checks if the desktop portal on the session D-Bus
+// implements the GlobalShortcuts interface used by the dbus hotkey provider
+func HasGlobalShortcutsPortal() bool {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+	obj := conn.Object("org.freedesktop.portal.Desktop", "/org/freedesktop/portal/desktop")
+	var introspectData string
+	if err := obj.Call("org.freedesktop.DBus.Introspectable.Introspect", 0).Store(&introspectData); err != nil {
+		return false
+	}
+	return strings.Contains(introspectData, "org.freedesktop.portal.GlobalShortcuts")
+}
+
 // UtilityExists checks if a specified command/utility exists in PATH
 func UtilityExists(name string) bool {
 	_, err := exec.LookPath(name)
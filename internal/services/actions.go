@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AshBuk/dabri/hotkeys/adapters"
+	hotkeyInterfaces "github.com/AshBuk/dabri/hotkeys/interfaces"
+	hotkeyUtils "github.com/AshBuk/dabri/hotkeys/utils"
+	"github.com/AshBuk/dabri/internal/constants"
+	"github.com/AshBuk/dabri/internal/logger"
+)
+
+// SettingsActions implements the settings changes offered by the tray menu
+// Shared by the tray callbacks (FactoryWirer) and the IPC handlers (internal/app),
+// so tray-less and headless setups change settings through the same code path
+//
+// Each action validates its input, persists through ConfigService with rollback,
+// applies the change to the running service and refreshes the UI
+type SettingsActions struct {
+	container *ServiceContainer
+	logger    logger.Logger
+}
+
+// NewSettingsActions creates actions operating on an assembled container
+func NewSettingsActions(container *ServiceContainer, logger logger.Logger) *SettingsActions {
+	return &SettingsActions{container: container, logger: logger}
+}
+
+// SetLanguage changes the recognition language (ISO 639-1 code, e.g. "de")
+func (sa *SettingsActions) SetLanguage(code string) error {
+	if sa.container.Config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if constants.LanguageByCode(code) == nil {
+		return fmt.Errorf("unsupported language: %s", code)
+	}
+	if err := sa.container.Config.UpdateLanguage(code); err != nil {
+		return err
+	}
+	// Outputters are built from a config snapshot; the journal and webhook record the language
+	sa.reloadOutput()
+	sa.updateUISettings()
+	return nil
+}
+
+// SetOutputMode switches the output mode and rebuilds the outputter
+func (sa *SettingsActions) SetOutputMode(mode string) error {
+	if sa.container.IO == nil {
+		return fmt.Errorf("IO service not available")
+	}
+	// SetOutputMethod validates, persists via ConfigService and refreshes the UI
+	return sa.container.IO.SetOutputMethod(mode)
+}
+
+// SetRecordingMethod switches the audio backend (arecord/ffmpeg)
+// The recorder is recreated at the next recording
+func (sa *SettingsActions) SetRecordingMethod(method string) error {
+	if sa.container.Config == nil || sa.container.Audio == nil {
+		return fmt.Errorf("services not available")
+	}
+	if err := sa.container.Config.UpdateRecordingMethod(method); err != nil {
+		return err
+	}
+	sa.container.Audio.ClearSession()
+	sa.updateUISettings()
+	return nil
+}
+
+// ToggleWorkflowNotifications flips workflow notifications and returns the new state
+func (sa *SettingsActions) ToggleWorkflowNotifications() (bool, error) {
+	if sa.container.Config == nil {
+		return false, fmt.Errorf("config service not available")
+	}
+	if err := sa.container.Config.ToggleWorkflowNotifications(); err != nil {
+		return false, err
+	}
+	sa.updateUISettings()
+	return sa.WorkflowNotificationsEnabled(), nil
+}
+
+// SetWorkflowNotifications enables or disables workflow notifications
+func (sa *SettingsActions) SetWorkflowNotifications(enabled bool) error {
+	if sa.WorkflowNotificationsEnabled() == enabled {
+		return nil
+	}
+	_, err := sa.ToggleWorkflowNotifications()
+	return err
+}
+
+// WorkflowNotificationsEnabled reports the current workflow notification setting
+func (sa *SettingsActions) WorkflowNotificationsEnabled() bool {
+	if sa.container.Config == nil {
+		return false
+	}
+	cfg := sa.container.Config.GetConfig()
+	return cfg != nil && cfg.Notifications.EnableWorkflowNotifications
+}
+
+// SwitchModel loads a whisper model and persists it
+// The engine switches first; a failed save rolls the engine back so runtime state matches config
+func (sa *SettingsActions) SwitchModel(ctx context.Context, modelID string) error {
+	if sa.container.Audio == nil || sa.container.Config == nil {
+		return fmt.Errorf("services not available")
+	}
+	// Remember previous model so we can rollback on persist failure
+	previousModel := ""
+	if cfg := sa.container.Config.GetConfig(); cfg != nil {
+		previousModel = cfg.General.WhisperModel
+	}
+	if err := sa.container.Audio.SwitchModel(ctx, modelID); err != nil {
+		return fmt.Errorf("model switch failed: %w", err)
+	}
+	if err := sa.container.Config.UpdateWhisperModel(modelID); err != nil {
+		// Rollback engine to previous model so runtime state matches config
+		if previousModel != "" {
+			if rbErr := sa.container.Audio.SwitchModel(ctx, previousModel); rbErr != nil {
+				sa.logger.Error("Failed to rollback model after config save error: %v", rbErr)
+			}
+		}
+		return fmt.Errorf("failed to persist model setting: %w", err)
+	}
+	// The journal records the model; rebuild outputters from the new snapshot
+	sa.reloadOutput()
+	sa.updateUISettings()
+	return nil
+}
+
+// CaptureHotkey waits for the user to press a key combination
+// Returns an empty combo when capture was cancelled (Esc) or timed out
+func (sa *SettingsActions) CaptureHotkey(timeoutMs int) (string, error) {
+	if sa.container.Hotkeys == nil {
+		return "", fmt.Errorf("hotkey service not available")
+	}
+	if !sa.container.Hotkeys.SupportsCaptureOnce() {
+		return "", fmt.Errorf("the active hotkey provider cannot capture key presses; pass the combination explicitly")
+	}
+	combo, err := sa.container.Hotkeys.CaptureOnce(timeoutMs)
+	if errors.Is(err, hotkeyInterfaces.ErrCaptureCancelled) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("hotkey capture failed: %w", err)
+	}
+	return strings.TrimSpace(combo), nil
+}
+
+// BindHotkey rebinds an action (start_recording, show_config, ...) and re-registers hotkeys
+func (sa *SettingsActions) BindHotkey(action, combo string) error {
+	if sa.container.Config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	combo = hotkeyUtils.NormalizeHotkey(combo)
+	if err := hotkeyUtils.ValidateHotkey(combo); err != nil {
+		return fmt.Errorf("invalid hotkey %q: %w", combo, err)
+	}
+	if err := sa.container.Config.UpdateHotkey(action, combo); err != nil {
+		return err
+	}
+	return sa.ReloadHotkeys()
+}
+
+// ResetToDefaults restores the default configuration, rebuilds the outputter
+// and recorder from it and re-registers hotkeys
+func (sa *SettingsActions) ResetToDefaults() error {
+	if sa.container.Config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	if err := sa.container.Config.ResetToDefaults(); err != nil {
+		return err
+	}
+	sa.reloadOutput()
+	if sa.container.Audio != nil {
+		sa.container.Audio.ReloadRecorder()
+	}
+	return sa.ReloadHotkeys()
+}
+
+// ReloadHotkeys re-registers every hotkey binding from the current config
+func (sa *SettingsActions) ReloadHotkeys() error {
+	if sa.container.Hotkeys == nil || sa.container.Audio == nil {
+		return nil
+	}
+	return sa.container.Hotkeys.ReloadFromConfig(
+		func() error { return sa.container.Audio.HandleStartRecording() },
+		func() error { return sa.container.Audio.HandleStopRecording() },
+		sa.hotkeyConfigProvider,
+	)
+}
+
+// hotkeyConfigProvider adapts Config → HotkeyConfig
+func (sa *SettingsActions) hotkeyConfigProvider() adapters.HotkeyConfig {
+	if sa.container.Config == nil {
+		return adapters.NewConfigAdapter("", "auto") // Graceful Degradation - return default
+	}
+	cfg := sa.container.Config.GetConfig()
+	if cfg == nil {
+		return adapters.NewConfigAdapter("", "auto")
+	}
+	return adapters.NewConfigAdapter(cfg.Hotkeys.StartRecording, cfg.Hotkeys.Provider).
+		WithAdditionalHotkeys(cfg.Hotkeys.ShowConfig, cfg.Hotkeys.ResetToDefaults).
+		WithCodeRecordingHotkey(cfg.Hotkeys.CodeRecording).
+		WithUndoHotkey(cfg.Hotkeys.Undo).
+		WithCancelHotkey(cfg.Hotkeys.Cancel)
+}
+
+// reloadOutput rebuilds the outputter from the current config; failures keep the previous one
+func (sa *SettingsActions) reloadOutput() {
+	if sa.container.IO == nil {
+		return
+	}
+	if err := sa.container.IO.ReloadOutput(); err != nil {
+		sa.logger.Warning("Output settings not applied: %v", err)
+	}
+}
+
+func (sa *SettingsActions) updateUISettings() {
+	if sa.container.UI == nil || sa.container.Config == nil {
+		return
+	}
+	if cfg := sa.container.Config.GetConfig(); cfg != nil {
+		sa.container.UI.UpdateSettings(cfg)
+	}
+}
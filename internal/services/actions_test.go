@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/AshBuk/dabri/config"
+	hotkeyInterfaces "github.com/AshBuk/dabri/hotkeys/interfaces"
+	"github.com/AshBuk/dabri/internal/testutils"
+	"github.com/AshBuk/dabri/tests/mocks"
+)
+
+// newTestActions returns actions backed by a real ConfigService saving to a temp file
+func newTestActions(t *testing.T) (*SettingsActions, *ConfigService) {
+	t.Helper()
+	mockLogger := testutils.NewMockLogger()
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := config.SaveConfig(configPath, cfg); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	configSvc := NewConfigService(mockLogger, config.NewLive(cfg), configPath)
+	container := &ServiceContainer{
+		Config:  configSvc,
+		Audio:   &mocks.MockAudioService{},
+		Hotkeys: &mocks.MockHotkeyService{},
+	}
+	return NewSettingsActions(container, mockLogger), configSvc
+}
+
+func TestSettingsActions_SetLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{"supported code", "de", "de", false},
+		{"unknown code", "xx", "en", true},
+		{"language name instead of code", "German", "en", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, configSvc := newTestActions(t)
+			err := actions.SetLanguage(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetLanguage(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if got := configSvc.GetConfig().General.Language; got != tt.want {
+				t.Errorf("language = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingsActions_SetWorkflowNotifications(t *testing.T) {
+	actions, _ := newTestActions(t)
+	initial := actions.WorkflowNotificationsEnabled()
+
+	// Setting the current state is a no-op, not a toggle
+	if err := actions.SetWorkflowNotifications(initial); err != nil {
+		t.Fatalf("SetWorkflowNotifications(%t) failed: %v", initial, err)
+	}
+	if actions.WorkflowNotificationsEnabled() != initial {
+		t.Errorf("setting the current state flipped notifications")
+	}
+
+	if err := actions.SetWorkflowNotifications(!initial); err != nil {
+		t.Fatalf("SetWorkflowNotifications(%t) failed: %v", !initial, err)
+	}
+	if actions.WorkflowNotificationsEnabled() == initial {
+		t.Errorf("notifications were not changed")
+	}
+
+	enabled, err := actions.ToggleWorkflowNotifications()
+	if err != nil {
+		t.Fatalf("ToggleWorkflowNotifications failed: %v", err)
+	}
+	if enabled != initial {
+		t.Errorf("toggle returned %t, want %t", enabled, initial)
+	}
+}
+
+func TestSettingsActions_BindHotkey(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  string
+		combo   string
+		want    string
+		wantErr bool
+	}{
+		{"valid combo", "start_recording", "ctrl+alt+r", "ctrl+alt+r", false},
+		{"normalized combo", "undo", "Ctrl + Shift + Z", "ctrl+shift+z", false},
+		{"unknown action", "open_browser", "ctrl+alt+b", "", true},
+		{"empty combo", "show_config", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions, configSvc := newTestActions(t)
+			err := actions.BindHotkey(tt.action, tt.combo)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BindHotkey(%q, %q) error = %v, wantErr %v", tt.action, tt.combo, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := config.GetValue(configSvc.GetConfig(), "hotkeys."+tt.action)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("hotkeys.%s = %q, want %q", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSettingsActions_CaptureHotkey(t *testing.T) {
+	actions, _ := newTestActions(t)
+	combo, err := actions.CaptureHotkey(100)
+	if err != nil {
+		t.Fatalf("CaptureHotkey failed: %v", err)
+	}
+	if combo != "alt+r" {
+		t.Errorf("combo = %q, want alt+r", combo)
+	}
+
+	hotkeys := &mocks.MockHotkeyService{}
+	actions.container.Hotkeys = hotkeys
+	hotkeys.SetCaptureError(hotkeyInterfaces.ErrCaptureCancelled)
+	if combo, err := actions.CaptureHotkey(100); err != nil || combo != "" {
+		t.Errorf("cancelled capture = %q, %v; want empty combo without error", combo, err)
+	}
+	hotkeys.SetCaptureError(errors.New("no keyboard devices found"))
+	if _, err := actions.CaptureHotkey(100); err == nil {
+		t.Error("CaptureHotkey should report capture failures")
+	}
+
+	actions.container.Hotkeys = nil
+	if _, err := actions.CaptureHotkey(100); err == nil {
+		t.Error("CaptureHotkey should fail without a hotkey service")
+	}
+}
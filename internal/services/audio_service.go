@@ -5,8 +5,11 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,26 +17,41 @@ import (
 	"github.com/AshBuk/dabri/audio/interfaces"
 	"github.com/AshBuk/dabri/audio/processing"
 	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/activewindow"
 	"github.com/AshBuk/dabri/internal/constants"
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/history"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
 	"github.com/AshBuk/dabri/internal/utils"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
 	"github.com/AshBuk/dabri/whisper"
 )
 
 // Orchestrates recording, transcription, and output workflows
 type AudioService struct {
 	logger        logger.Logger
-	config        *config.Config
+	config        *config.Live // Running configuration; read a snapshot per use
 	recorder      interfaces.AudioRecorder
 	whisperEngine *whisper.WhisperEngine
 	modelManager  whisper.ModelManager
 	tempManager   *processing.TempFileManager
+	windowDetect  activewindow.Detector
+	history       *history.Store // Transcript history (nil disables recording)
+	events        *events.Bus    // State change stream for IPC subscribers (nil disables)
 
 	// State management
 	mu                       sync.RWMutex
 	isRecording              bool
 	lastTranscript           string
 	audioRecorderNeedsReinit bool
+	session                  recordingSession                   // Settings resolved at recording start
+	jobs                     map[string]context.CancelCauseFunc // Pending transcriptions by session ID
+
+	// Reports transitions between idle and an active dictation
+	activityMu sync.Mutex
+	active     bool
+	onActivity func(active bool)
 
 	// Guards whisperEngine while transcription is using it.
 	engineMu sync.RWMutex
@@ -54,10 +72,60 @@ type AudioService struct {
 // ErrNoRecordingInProgress indicates a stop request when no session is active.
 var ErrNoRecordingInProgress = errors.New("no recording in progress")
 
+// ErrNothingToCancel indicates a cancel request with no recording or pending transcription.
+var ErrNothingToCancel = errors.New("nothing to cancel")
+
+// ErrRecordingCancelled is the cause of transcriptions aborted by CancelRecording.
+var ErrRecordingCancelled = errors.New("recording cancelled")
+
+// recordingSession holds per-recording settings fixed when recording starts
+type recordingSession struct {
+	id             string                // Random session identifier reported to outputs
+	startedAt      time.Time             // Recording start, used to report the duration
+	duration       time.Duration         // Recording duration, set when recording stops
+	profile        *config.Profile       // Per-application profile, nil if none matched
+	windowID       string                // Window focused at start, refocused before typing
+	postProcessing string                // Post-processing mode applied to the transcript
+	options        config.SessionOptions // Per-dictation overrides requested by the client
+	clipboardToken uint64                // Clipboard ownership token from IOService.BeginTranscription
+}
+
+// applyOptions merges per-dictation overrides into the session. Language and
+// output mode reuse the profile overrides on a copy of the matched profile
+func (s *recordingSession) applyOptions(opts config.SessionOptions) {
+	s.options = s.options.Merge(opts)
+	if opts.PostProcessing != "" {
+		s.postProcessing = opts.PostProcessing
+	}
+	if opts.Language == "" && opts.OutputMode() == "" {
+		return
+	}
+	profile := config.Profile{Name: "session options"}
+	if s.profile != nil {
+		profile = *s.profile
+	}
+	if opts.Language != "" {
+		profile.Language = opts.Language
+	}
+	if mode := opts.OutputMode(); mode != "" {
+		profile.OutputMode = mode
+	}
+	s.profile = &profile
+}
+
+// newSessionID returns a random identifier for a recording session
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
 // Create a new AudioService instance
 func NewAudioService(
 	logger logger.Logger,
-	config *config.Config,
+	config *config.Live,
 	recorder interfaces.AudioRecorder,
 	whisperEngine *whisper.WhisperEngine,
 	modelManager whisper.ModelManager,
@@ -86,8 +154,55 @@ func (as *AudioService) SetDependencies(ui UIServiceInterface, io IOServiceInter
 // Wire config service for runtime setting persistence
 func (as *AudioService) SetConfig(cfg ConfigServiceInterface) { as.cfg = cfg }
 
+// Wire the focused-window detector used to select per-application profiles
+func (as *AudioService) SetWindowDetector(detector activewindow.Detector) { as.windowDetect = detector }
+
+// Wire the transcript history store
+func (as *AudioService) SetHistory(store *history.Store) { as.history = store }
+
+// Wire the event bus that receives recording and transcription events
+func (as *AudioService) SetEventBus(bus *events.Bus) { as.events = bus }
+
+// Wire a callback told when a dictation becomes active (recording starts)
+// and when it ends (no recording, pending transcription or typing remains)
+func (as *AudioService) SetActivityCallback(callback func(active bool)) {
+	as.activityMu.Lock()
+	as.onActivity = callback
+	as.activityMu.Unlock()
+}
+
+// updateActivity reports a transition between idle and an active dictation.
+// Called without as.mu held after every change of the recording or job state
+func (as *AudioService) updateActivity() {
+	as.activityMu.Lock()
+	defer as.activityMu.Unlock()
+	as.mu.RLock()
+	active := as.isRecording || len(as.jobs) > 0
+	as.mu.RUnlock()
+	if active == as.active {
+		return
+	}
+	as.active = active
+	if as.onActivity != nil {
+		as.onActivity(active)
+	}
+}
+
 // HandleStartRecording starts audio recording
 func (as *AudioService) HandleStartRecording() error {
+	return as.HandleStartRecordingWithOptions(config.SessionOptions{})
+}
+
+// HandleStartRecordingWithOptions starts audio recording with per-dictation
+// overrides (e.g., code dictation, `dabri start --lang de`). Empty fields use profile or config
+func (as *AudioService) HandleStartRecordingWithOptions(opts config.SessionOptions) error {
+	if err := config.ValidateSessionOptions(opts); err != nil {
+		return err
+	}
+	// Detection runs external tools; keep it outside the lock so a slow
+	// tool cannot block status queries and stop requests
+	window, detected := as.detectWindow()
+	defer as.updateActivity()
 	as.mu.Lock()
 	defer as.mu.Unlock()
 	as.logger.Info("Starting recording...")
@@ -105,15 +220,44 @@ func (as *AudioService) HandleStartRecording() error {
 		return fmt.Errorf("audio recorder not available: %w", err)
 	}
 	// Standard recording
-	return as.startStandardRecording()
+	if err := as.startStandardRecording(); err != nil {
+		return err
+	}
+	profile := as.resolveProfile(window, detected)
+	as.session = recordingSession{
+		id:             newSessionID(),
+		startedAt:      time.Now(),
+		profile:        profile,
+		postProcessing: as.resolvePostProcessing(opts.PostProcessing, profile),
+	}
+	as.session.applyOptions(opts)
+	if detected && as.config.Get().Output.TypeToOriginWindow {
+		as.session.windowID = window.ID
+	}
+	as.events.Publish(events.RecordingStarted, map[string]any{
+		"session_id": as.session.id,
+		"mode":       as.session.postProcessing,
+	})
+	return nil
 }
 
 // HandleStopRecording stops recording and starts async transcription.
 func (as *AudioService) HandleStopRecording() error {
-	audioFile, err := as.stopAndPrepareTranscription(true)
+	return as.HandleStopRecordingWithOptions(config.SessionOptions{})
+}
+
+// HandleStopRecordingWithOptions stops recording and starts async transcription.
+// Non-empty fields of opts replace the options the recording was started with.
+func (as *AudioService) HandleStopRecordingWithOptions(opts config.SessionOptions) error {
+	if err := config.ValidateSessionOptions(opts); err != nil {
+		return err
+	}
+	defer as.updateActivity()
+	audioFile, session, err := as.stopAndPrepareTranscription(true)
 	if err != nil || audioFile == "" {
 		return err
 	}
+	session.applyOptions(opts)
 	select {
 	case <-as.ctx.Done():
 		as.logger.Warning("Shutdown in progress, skipping transcription")
@@ -121,41 +265,118 @@ func (as *AudioService) HandleStopRecording() error {
 	default:
 	}
 	if as.io != nil {
-		as.io.BeginTranscription()
+		session.clipboardToken = as.io.BeginTranscription()
 	}
+	as.events.Publish(events.Transcribing, map[string]any{"session_id": session.id})
+	jobCtx, done := as.trackJob(as.ctx, session.id)
 	as.wg.Add(1)
 	go func() {
 		defer as.wg.Done()
-		as.transcribeAsync(audioFile)
+		defer done()
+		as.transcribeAsync(jobCtx, audioFile, session)
 	}()
 	return nil
 }
 
-// HandleStopRecordingSync stops recording and returns the transcript.
-func (as *AudioService) HandleStopRecordingSync(ctx context.Context) (string, error) {
-	audioFile, err := as.stopAndPrepareTranscription(false)
+// HandleStopRecordingSync stops recording and returns the transcript without
+// delivering it, with the effective options of the session (start options
+// overridden by the non-empty fields of opts) so the caller can deliver it.
+func (as *AudioService) HandleStopRecordingSync(ctx context.Context, opts config.SessionOptions) (string, config.SessionOptions, error) {
+	if err := config.ValidateSessionOptions(opts); err != nil {
+		return "", opts, err
+	}
+	defer as.updateActivity()
+	audioFile, session, err := as.stopAndPrepareTranscription(false)
 	if err != nil || audioFile == "" {
-		return "", err
+		return "", opts, err
 	}
+	session.applyOptions(opts)
 	// Always release the recorded WAV; TempFileManager would eventually do
 	// it, but eager cleanup keeps disk usage bounded under heavy API use.
 	defer as.cleanupRecording(audioFile)
 
 	select {
 	case <-as.ctx.Done():
-		return "", fmt.Errorf("shutdown in progress")
+		return "", session.options, fmt.Errorf("shutdown in progress")
 	default:
 	}
 
-	transcript, err := as.transcribeWithCurrentEngine(ctx, audioFile)
+	as.events.Publish(events.Transcribing, map[string]any{"session_id": session.id})
+	ctx, done := as.trackJob(ctx, session.id)
+	defer done()
+	transcript, err := as.transcribeWithCurrentEngine(ctx, audioFile, session)
+	if errors.Is(context.Cause(ctx), ErrRecordingCancelled) {
+		as.handleRecordingCancelled(session, constants.NotifyTranscriptionCancelled)
+		return "", session.options, ErrRecordingCancelled
+	}
 	if err != nil {
-		return "", err
+		as.publishError("transcription", err.Error())
+		return "", session.options, err
+	}
+	sanitized := utils.PostProcessTranscript(transcript, session.postProcessing)
+	as.rememberTranscript(sanitized, session)
+	as.recordHistory(sanitized, session, nil)
+	as.events.Publish(events.Transcript, transcriptEventData(sanitized, session))
+	return sanitized, session.options, nil
+}
+
+// CancelRecording aborts a dictation without output. An active recording is
+// stopped and its audio deleted without transcribing; otherwise pending
+// transcriptions are discarded and a transcript still being typed is aborted
+func (as *AudioService) CancelRecording() error {
+	defer as.updateActivity()
+	as.mu.Lock()
+	if as.isRecording {
+		session := as.session
+		as.session = recordingSession{}
+		as.isRecording = false
+		audioFile, err := as.recorder.StopRecording()
+		as.mu.Unlock()
+		if err != nil {
+			as.logger.Warning("StopRecording returned error while cancelling: %v", err)
+		}
+		as.cleanupRecording(audioFile)
+		if as.ui != nil {
+			as.ui.SetRecordingState(false)
+		}
+		as.events.Publish(events.RecordingStopped, map[string]any{"session_id": session.id})
+		as.handleRecordingCancelled(session, constants.NotifyRecordingCancelMsg)
+		return nil
 	}
-	sanitized := utils.SanitizeTranscript(transcript)
+	jobs := as.jobs
+	as.jobs = nil
+	as.mu.Unlock()
+
+	// Each job reports its own cancellation once whisper returns
+	for _, cancel := range jobs {
+		cancel(ErrRecordingCancelled)
+	}
+	typing := as.io != nil && as.io.CancelTyping()
+	if len(jobs) == 0 && !typing {
+		return ErrNothingToCancel
+	}
+	return nil
+}
+
+// trackJob registers a transcription that CancelRecording can abort.
+// The returned func must be called when the job ends
+func (as *AudioService) trackJob(parent context.Context, sessionID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
 	as.mu.Lock()
-	as.lastTranscript = sanitized
+	if as.jobs == nil {
+		as.jobs = make(map[string]context.CancelCauseFunc)
+	}
+	as.jobs[sessionID] = cancel
 	as.mu.Unlock()
-	return sanitized, nil
+	metrics.TranscriptionQueueDepth.Add(1)
+	return ctx, func() {
+		cancel(nil)
+		as.mu.Lock()
+		delete(as.jobs, sessionID)
+		as.mu.Unlock()
+		metrics.TranscriptionQueueDepth.Add(-1)
+		as.updateActivity()
+	}
 }
 
 // cleanupRecording removes the captured WAV. Errors are ignored: temp
@@ -166,15 +387,21 @@ func (as *AudioService) cleanupRecording(audioFile string) {
 	}
 }
 
-// stopAndPrepareTranscription stops recording and returns the captured file.
-func (as *AudioService) stopAndPrepareTranscription(swallowStopError bool) (string, error) {
+// stopAndPrepareTranscription stops recording and returns the captured file
+// together with the settings resolved for the session.
+func (as *AudioService) stopAndPrepareTranscription(swallowStopError bool) (string, recordingSession, error) {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
 	if !as.isRecording {
-		return "", ErrNoRecordingInProgress
+		return "", recordingSession{}, ErrNoRecordingInProgress
 	}
 	as.logger.Info("Stopping recording and transcribing...")
+	session := as.session
+	as.session = recordingSession{}
+	if !session.startedAt.IsZero() {
+		session.duration = time.Since(session.startedAt)
+	}
 
 	audioFile, err := as.recorder.StopRecording()
 	if err != nil {
@@ -182,38 +409,50 @@ func (as *AudioService) stopAndPrepareTranscription(swallowStopError bool) (stri
 		as.handleRecordingError(err)
 
 		// Auto-fallback to arecord if using ffmpeg
-		if as.config.Audio.RecordingMethod == "ffmpeg" {
-			if as.cfg != nil {
-				_ = as.cfg.UpdateRecordingMethod("arecord")
+		if as.config.Get().Audio.RecordingMethod == "ffmpeg" {
+			// Persist via ConfigService; keep the switch for this run if that fails
+			if as.cfg == nil || as.cfg.UpdateRecordingMethod("arecord") != nil {
+				_, _, _ = as.config.Update(func(next *config.Config) error {
+					next.Audio.RecordingMethod = "arecord"
+					return nil
+				})
 			}
-			as.config.Audio.RecordingMethod = "arecord"
 			as.ClearSession()
 			if as.ui != nil {
 				as.ui.ShowNotification("Audio Fallback", "Switched to arecord due to ffmpeg capture error. Try recording again.")
-				as.ui.UpdateSettings(as.config)
+				as.ui.UpdateSettings(as.config.Get())
 			}
 			as.logger.Info("Auto-fallback: switched to arecord due to ffmpeg failure")
+			metrics.FallbackSwitches.Inc("recorder", "ffmpeg", "arecord")
 		}
 		// Ensure state is reset so the hotkey toggle can recover
 		as.isRecording = false
 		if as.ui != nil {
 			as.ui.SetRecordingState(false)
 		}
+		as.events.Publish(events.RecordingStopped, map[string]any{"session_id": session.id})
 		if swallowStopError {
 			// Keep hotkey/tray stop idempotent so users can recover with the next start.
-			return "", nil
+			return "", recordingSession{}, nil
 		}
-		return "", err
+		return "", recordingSession{}, err
 	}
 	as.isRecording = false
 	if as.ui != nil {
 		as.ui.SetRecordingState(false)
 		as.ui.ShowNotification(constants.NotifyRecordingStopped, constants.NotifyRecordingStopMsg)
 	}
+	as.events.Publish(events.RecordingStopped, map[string]any{
+		"session_id":  session.id,
+		"duration_ms": session.duration.Milliseconds(),
+	})
+	if session.duration > 0 {
+		metrics.RecordingDuration.Observe(session.duration.Seconds())
+	}
 	if audioFile == "" {
-		return "", fmt.Errorf("recording produced no audio file")
+		return "", recordingSession{}, fmt.Errorf("recording produced no audio file")
 	}
-	return audioFile, nil
+	return audioFile, session, nil
 }
 
 // IsRecording returns current recording state
@@ -244,7 +483,7 @@ func (as *AudioService) SwitchModel(ctx context.Context, modelID string) error {
 		return fmt.Errorf("model switch failed: %w", err)
 	}
 
-	newEngine, err := whisper.NewWhisperEngine(as.config, newPath, as.logger)
+	newEngine, err := whisper.NewWhisperEngine(as.config.Get(), newPath, as.logger)
 	if err != nil {
 		return fmt.Errorf("failed to create engine for model %s: %w", modelID, err)
 	}
@@ -264,6 +503,7 @@ func (as *AudioService) SwitchModel(ctx context.Context, modelID string) error {
 	if oldEngine != nil {
 		_ = oldEngine.Close()
 	}
+	as.events.Publish(events.ModelSwitched, map[string]any{"model": modelID})
 	return nil
 }
 
@@ -293,11 +533,19 @@ func (as *AudioService) ensureModelAvailable() error {
 	return nil
 }
 
+// ReloadRecorder marks the recorder for recreation at the next recording start,
+// so changed audio settings apply without interrupting a running recording
+func (as *AudioService) ReloadRecorder() {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.audioRecorderNeedsReinit = true
+}
+
 // ensureAudioRecorderAvailable ensures audio recorder is ready
 func (as *AudioService) ensureAudioRecorderAvailable() error {
 	if as.audioRecorderNeedsReinit || as.recorder == nil {
 		as.logger.Info("Reinitializing audio recorder...")
-		recorder, err := factory.GetRecorder(as.config, as.logger, as.tempManager)
+		recorder, err := factory.GetRecorder(as.config.Get(), as.logger, as.tempManager)
 		if err != nil {
 			return fmt.Errorf("failed to reinitialize audio recorder: %w", err)
 		}
@@ -350,6 +598,7 @@ func (as *AudioService) startStandardRecording() error {
 		if as.ui != nil {
 			as.ui.UpdateRecordingUI(true, level)
 		}
+		as.events.Publish(events.AudioLevel, map[string]any{"level": level})
 		as.logger.Debug("Audio level: %.2f", level)
 	})
 	// Start recording
@@ -370,8 +619,8 @@ func (as *AudioService) startStandardRecording() error {
 // by the WaitGroup. Whisper.cpp CGO calls cannot be cancelled, so tracking them would
 // cause Shutdown() to block for up to 2 minutes. Instead, we accept that the CGO work
 // may outlive shutdown (bounded to ~30s max). See whisper/engine.go for details.
-func (as *AudioService) transcribeAsync(audioFile string) {
-	ctx, cancel := context.WithTimeout(as.ctx, 2*time.Minute)
+func (as *AudioService) transcribeAsync(jobCtx context.Context, audioFile string, session recordingSession) {
+	ctx, cancel := context.WithTimeout(jobCtx, 2*time.Minute)
 	defer cancel()
 
 	type result struct {
@@ -381,7 +630,7 @@ func (as *AudioService) transcribeAsync(audioFile string) {
 
 	resultChan := make(chan result, 1)
 	go func() {
-		transcript, err := as.transcribeWithCurrentEngine(ctx, audioFile)
+		transcript, err := as.transcribeWithCurrentEngine(ctx, audioFile, session)
 		select {
 		case resultChan <- result{transcript: transcript, err: err}:
 		case <-ctx.Done():
@@ -391,76 +640,205 @@ func (as *AudioService) transcribeAsync(audioFile string) {
 	case res := <-resultChan:
 		// Inner goroutine finished; safe to release the WAV.
 		as.cleanupRecording(audioFile)
-		as.handleTranscriptionResult(res.transcript, res.err)
+		if errors.Is(context.Cause(ctx), ErrRecordingCancelled) {
+			// Cancelled while whisper was finishing; drop the transcript before output
+			as.handleRecordingCancelled(session, constants.NotifyTranscriptionCancelled)
+			return
+		}
+		as.handleTranscriptionResult(res.transcript, res.err, session)
 	case <-ctx.Done():
 		// Inner CGO call may still be reading the file — leave it for
 		// TempFileManager rather than risk a use-after-delete.
+		if errors.Is(context.Cause(ctx), ErrRecordingCancelled) {
+			as.handleRecordingCancelled(session, constants.NotifyTranscriptionCancelled)
+			return
+		}
 		as.handleTranscriptionCancellation(ctx.Err())
 	}
 }
 
-func (as *AudioService) transcribeWithCurrentEngine(ctx context.Context, audioFile string) (string, error) {
+func (as *AudioService) transcribeWithCurrentEngine(ctx context.Context, audioFile string, session recordingSession) (string, error) {
 	as.engineMu.RLock()
 	defer as.engineMu.RUnlock()
 	if as.whisperEngine == nil {
 		return "", fmt.Errorf("whisper engine not available")
 	}
-	return as.whisperEngine.TranscribeWithContext(ctx, audioFile)
+	cfg := as.config.Get()
+	language := cfg.General.Language
+	if session.profile != nil && session.profile.Language != "" {
+		language = session.profile.Language
+	}
+	started := time.Now()
+	var transcript string
+	var err error
+	if session.options.Task == config.TaskTranslate {
+		transcript, err = as.whisperEngine.TranslateWithLanguage(ctx, audioFile, language)
+	} else {
+		transcript, err = as.whisperEngine.TranscribeWithLanguage(ctx, audioFile, language)
+	}
+	if err == nil {
+		observeTranscription(cfg.General.WhisperModel, time.Since(started), session.duration)
+	}
+	return transcript, err
+}
+
+// observeTranscription records whisper latency and, when the recording length
+// is known, the real-time factor used to compare models
+func observeTranscription(model string, elapsed, recorded time.Duration) {
+	metrics.TranscriptionDuration.Observe(elapsed.Seconds(), model)
+	if recorded > 0 {
+		metrics.RealTimeFactor.Observe(elapsed.Seconds()/recorded.Seconds(), model)
+	}
 }
 
 // handleTranscriptionResult processes transcription results
-func (as *AudioService) handleTranscriptionResult(transcript string, err error) {
+func (as *AudioService) handleTranscriptionResult(transcript string, err error, session recordingSession) {
 	if err != nil {
 		as.handleTranscriptionError(err)
 		return
 	}
-	sanitized := utils.SanitizeTranscript(transcript)
-	as.mu.Lock()
-	as.lastTranscript = sanitized
-	as.mu.Unlock()
+	sanitized := utils.PostProcessTranscript(transcript, session.postProcessing)
+	as.rememberTranscript(sanitized, session)
 
 	if sanitized == "" {
 		as.handleEmptyTranscript()
 		return
 	}
-	as.logger.Info("Transcription result: %s", sanitized)
 
+	if session.options.SkipsOutput() {
+		as.handleOutputSkipped(sanitized, session)
+		return
+	}
 	// Output text
 	if as.io != nil {
-		if err := as.io.OutputText(sanitized); err != nil {
+		transcript := outputInterfaces.Transcript{Text: sanitized, SessionID: session.id, Duration: session.duration, WindowID: session.windowID}
+		results, err := as.io.OutputTranscript(session.clipboardToken, transcript, session.profile)
+		// Complete even on failure so waiting IPC clients get per-target results
+		as.io.CompleteTranscription(outputInterfaces.TranscriptionResult{Text: sanitized, Outputs: results})
+		// Record failed deliveries too, so the text can be recovered from history
+		as.recordHistory(sanitized, session, results)
+		as.events.Publish(events.Transcript, map[string]any{
+			"session_id": session.id,
+			"text":       sanitized,
+			"outputs":    results,
+		})
+		if errors.Is(err, context.Canceled) {
+			// Aborted by a new hotkey press; the new recording owns the UI state
+			as.logger.Info("Output aborted: %v", err)
+			return
+		}
+		if err != nil {
 			as.logger.Error("Failed to output text: %v", err)
 			if as.ui != nil {
 				as.ui.SetError("Output failed")
 			}
+			as.publishError("output", err.Error())
 			return
 		}
 	}
-	// Notify IO about completion for clipboard protection release
+	// Update UI
+	if as.ui != nil {
+		as.ui.SetSuccess(constants.MsgTranscriptionComplete)
+	}
+}
+
+// handleOutputSkipped completes a dictation whose client asked for no output
+// ("none", "stdout-only"): waiting clients still receive the transcript
+func (as *AudioService) handleOutputSkipped(text string, session recordingSession) {
+	as.logger.Info("Output skipped for session %s (output: %s)", session.id, session.options.Output)
 	if as.io != nil {
-		as.io.CompleteTranscription(sanitized)
+		as.io.CompleteTranscription(outputInterfaces.TranscriptionResult{Text: text})
 	}
-	// Update UI
+	as.recordHistory(text, session, nil)
+	as.events.Publish(events.Transcript, transcriptEventData(text, session))
 	if as.ui != nil {
 		as.ui.SetSuccess(constants.MsgTranscriptionComplete)
 	}
 }
 
+// transcriptEventData builds the transcript event of an undelivered dictation.
+// stdout-only text is left out: only the requesting client receives it
+func transcriptEventData(text string, session recordingSession) map[string]any {
+	data := map[string]any{"session_id": session.id}
+	if session.options.Output != config.SessionOutputStdoutOnly {
+		data["text"] = text
+	}
+	return data
+}
+
+// rememberTranscript logs the transcript and keeps it for `dabri transcript`.
+// stdout-only transcripts are meant for the requesting client alone
+func (as *AudioService) rememberTranscript(text string, session recordingSession) {
+	if session.options.Output == config.SessionOutputStdoutOnly {
+		return
+	}
+	as.mu.Lock()
+	as.lastTranscript = text
+	as.mu.Unlock()
+	if text != "" {
+		as.logger.Info("Transcription result: %s", text)
+	}
+}
+
+// recordHistory adds the transcript to the history store with the output
+// modes that delivered it. Failures are logged; history never blocks output
+func (as *AudioService) recordHistory(text string, session recordingSession, results []outputInterfaces.TargetResult) {
+	cfg := as.config.Get()
+	if as.history == nil || !cfg.History.Enabled || text == "" {
+		return
+	}
+	// stdout-only transcripts are meant for the requesting client alone
+	if session.options.Output == config.SessionOutputStdoutOnly {
+		return
+	}
+	language := cfg.General.Language
+	if session.profile != nil && session.profile.Language != "" {
+		language = session.profile.Language
+	}
+	var targets []string
+	for _, res := range results {
+		switch res.Status {
+		case outputInterfaces.TargetStatusOK:
+			targets = append(targets, res.Target)
+		case outputInterfaces.TargetStatusFallback:
+			targets = append(targets, res.Fallback)
+		}
+	}
+	entry := history.Entry{
+		Text:       text,
+		DurationMs: session.duration.Milliseconds(),
+		Model:      cfg.General.WhisperModel,
+		Language:   language,
+		Targets:    targets,
+		SessionID:  session.id,
+	}
+	if _, err := as.history.Add(entry); err != nil {
+		as.logger.Warning("Failed to record transcript history: %v", err)
+		return
+	}
+	if as.ui != nil {
+		as.ui.RefreshHistory()
+	}
+}
+
 // handleTranscriptionError handles transcription errors
 func (as *AudioService) handleTranscriptionError(err error) {
 	as.logger.Error("Transcription error: %v", err)
+	as.publishError("transcription", err.Error())
 	if as.ui != nil {
 		as.ui.SetError(constants.MsgTranscriptionFailed)
 		as.ui.ShowNotification(constants.NotifyTranscriptionErr, err.Error())
 	}
 	// Release clipboard protection
 	if as.io != nil {
-		as.io.CompleteTranscription("")
+		as.io.CompleteTranscription(outputInterfaces.TranscriptionResult{})
 	}
 }
 
 // handleEmptyTranscript handles empty transcription results
 func (as *AudioService) handleEmptyTranscript() {
 	as.logger.Info("Empty transcript received")
+	as.publishError("transcription", constants.MsgNoSpeechDetected)
 	if as.ui != nil {
 		as.ui.SetError(constants.MsgNoSpeechDetected)
 		as.ui.ShowNotification(constants.NotifyNoSpeech, constants.MsgTranscriptionEmpty)
@@ -470,6 +848,7 @@ func (as *AudioService) handleEmptyTranscript() {
 // handleRecordingError handles recording errors
 func (as *AudioService) handleRecordingError(err error) {
 	as.logger.Error("Recording error: %v", err)
+	as.publishError("recording", err.Error())
 	if as.ui != nil {
 		as.ui.SetError("Recording error")
 		as.ui.ShowNotification("Recording Error", err.Error())
@@ -479,13 +858,27 @@ func (as *AudioService) handleRecordingError(err error) {
 // handleTranscriptionCancellation handles transcription cancellation
 func (as *AudioService) handleTranscriptionCancellation(err error) {
 	as.logger.Warning("Transcription cancelled: %v", err)
+	as.publishError("transcription", "transcription cancelled: "+err.Error())
 	if as.ui != nil {
 		as.ui.SetError("Transcription cancelled")
 		as.ui.ShowNotification("Transcription Cancelled", "Operation timed out")
 	}
 	// Release clipboard protection
 	if as.io != nil {
-		as.io.CompleteTranscription("")
+		as.io.CompleteTranscription(outputInterfaces.TranscriptionResult{})
+	}
+}
+
+// handleRecordingCancelled reports a dictation cancelled by the user
+func (as *AudioService) handleRecordingCancelled(session recordingSession, message string) {
+	as.logger.Info("Dictation cancelled: %s", message)
+	as.events.Publish(events.RecordingCancelled, map[string]any{"session_id": session.id})
+	if as.ui != nil {
+		as.ui.ShowNotification(constants.NotifyRecordingCancelled, message)
+	}
+	// Release clipboard protection and wake IPC clients waiting for the transcript
+	if as.io != nil && session.clipboardToken != 0 {
+		as.io.CompleteTranscription(outputInterfaces.TranscriptionResult{})
 	}
 }
 
@@ -498,6 +891,58 @@ func (as *AudioService) ClearSession() {
 	as.lastTranscript = ""
 }
 
+// detectWindow looks up the focused window at recording start. Detection is
+// skipped entirely when neither profiles nor origin window typing need it
+func (as *AudioService) detectWindow() (activewindow.Info, bool) {
+	cfg := as.config.Get()
+	if as.windowDetect == nil || (len(cfg.Profiles) == 0 && !cfg.Output.TypeToOriginWindow) {
+		return activewindow.Info{}, false
+	}
+	info, err := as.windowDetect.Detect()
+	if err != nil {
+		as.logger.Debug("Active window detection unavailable: %v", err)
+		return activewindow.Info{}, false
+	}
+	return info, true
+}
+
+// resolveProfile matches the focused window against configured profiles
+func (as *AudioService) resolveProfile(info activewindow.Info, detected bool) *config.Profile {
+	profiles := as.config.Get().Profiles
+	if !detected || len(profiles) == 0 {
+		return nil
+	}
+	profile := activewindow.MatchProfile(profiles, info)
+	if profile == nil {
+		as.logger.Debug("No profile matches window class=%q title=%q", info.Class, info.Title)
+		return nil
+	}
+	as.logger.Info("Using profile '%s' for window class=%q", profile.Name, info.Class)
+	// Copy so later config edits don't affect the running session
+	matched := *profile
+	return &matched
+}
+
+// resolvePostProcessing picks the session mode: explicit override, then
+// profile, then the configured default
+func (as *AudioService) resolvePostProcessing(mode string, profile *config.Profile) string {
+	switch {
+	case mode != "":
+		return mode
+	case profile != nil && profile.PostProcessing != "":
+		return profile.PostProcessing
+	case as.config.Get().General.PostProcessing != "":
+		return as.config.Get().General.PostProcessing
+	default:
+		return config.PostProcessingDefault
+	}
+}
+
+// publishError reports a failure of the given stage (recording, transcription, output) to subscribers
+func (as *AudioService) publishError(stage, message string) {
+	as.events.Publish(events.Error, map[string]any{"stage": stage, "message": message})
+}
+
 // setUIError sets UI error state
 func (as *AudioService) setUIError(message string) {
 	if as.ui != nil {
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AshBuk/dabri/audio/interfaces"
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/testutils"
+)
+
+// stubRecorder records nothing and counts stops
+type stubRecorder struct {
+	stops int
+}
+
+func (r *stubRecorder) StartRecording() error                               { return nil }
+func (r *stubRecorder) StopRecording() (string, error)                      { r.stops++; return "", nil }
+func (r *stubRecorder) GetOutputFile() string                               { return "" }
+func (r *stubRecorder) CleanupFile() error                                  { return nil }
+func (r *stubRecorder) SetAudioLevelCallback(interfaces.AudioLevelCallback) {}
+func (r *stubRecorder) GetAudioLevel() float64                              { return 0 }
+
+func newCancelTestService() (*AudioService, *stubRecorder, *events.Subscription) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	recorder := &stubRecorder{}
+	as := NewAudioService(testutils.NewMockLogger(), config.NewLive(cfg), recorder, nil, nil, nil)
+	as.SetEventBus(events.NewBus())
+	return as, recorder, as.events.Subscribe(16, events.RecordingStopped, events.RecordingCancelled)
+}
+
+func TestAudioService_CancelRecording(t *testing.T) {
+	as, recorder, sub := newCancelTestService()
+	as.isRecording = true
+	as.session = recordingSession{id: "rec"}
+
+	if err := as.CancelRecording(); err != nil {
+		t.Fatalf("CancelRecording failed: %v", err)
+	}
+	if as.IsRecording() || recorder.stops != 1 {
+		t.Errorf("recording = %t, stops = %d; want stopped once", as.IsRecording(), recorder.stops)
+	}
+	for _, want := range []string{events.RecordingStopped, events.RecordingCancelled} {
+		if ev := <-sub.C; ev.Type != want || ev.Data["session_id"] != "rec" {
+			t.Errorf("event = %+v, want %s for session rec", ev, want)
+		}
+	}
+
+	if err := as.CancelRecording(); !errors.Is(err, ErrNothingToCancel) {
+		t.Errorf("second CancelRecording = %v, want ErrNothingToCancel", err)
+	}
+}
+
+func TestAudioService_CancelRecording_PendingTranscription(t *testing.T) {
+	as, recorder, _ := newCancelTestService()
+	ctx, done := as.trackJob(context.Background(), "job")
+	defer done()
+
+	if err := as.CancelRecording(); err != nil {
+		t.Fatalf("CancelRecording failed: %v", err)
+	}
+	if recorder.stops != 0 {
+		t.Error("recorder stopped although nothing was recording")
+	}
+	if !errors.Is(context.Cause(ctx), ErrRecordingCancelled) {
+		t.Errorf("job cause = %v, want ErrRecordingCancelled", context.Cause(ctx))
+	}
+
+	// A finished job is no longer cancellable
+	_, finish := as.trackJob(context.Background(), "done")
+	finish()
+	if err := as.CancelRecording(); !errors.Is(err, ErrNothingToCancel) {
+		t.Errorf("CancelRecording after job finished = %v, want ErrNothingToCancel", err)
+	}
+}
+
+func TestAudioService_ActivityCallback(t *testing.T) {
+	as, _, _ := newCancelTestService()
+	var got []bool
+	as.SetActivityCallback(func(active bool) { got = append(got, active) })
+
+	// Recording, then a transcription outliving it, then idle
+	as.mu.Lock()
+	as.isRecording = true
+	as.mu.Unlock()
+	as.updateActivity()
+	_, done := as.trackJob(context.Background(), "job")
+	as.mu.Lock()
+	as.isRecording = false
+	as.mu.Unlock()
+	as.updateActivity()
+	done()
+
+	if len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("activity transitions = %v, want [true false]", got)
+	}
+}
+
+func TestRecordingSession_ApplyOptions(t *testing.T) {
+	matched := &config.Profile{Name: "terminal", Language: "fr", OutputMode: config.OutputModePaste, TypeTool: "wtype"}
+	tests := []struct {
+		name        string
+		profile     *config.Profile
+		start, stop config.SessionOptions
+		wantLang    string
+		wantMode    string
+		wantSkip    bool
+		wantTask    string
+	}{
+		{"language over matched profile", matched, config.SessionOptions{Language: "de"}, config.SessionOptions{}, "de", config.OutputModePaste, false, ""},
+		{"type without profile", nil, config.SessionOptions{Output: config.SessionOutputType}, config.SessionOptions{}, "", config.OutputModeActiveWindow, false, ""},
+		{"no output", matched, config.SessionOptions{Output: config.SessionOutputNone}, config.SessionOptions{}, "fr", config.OutputModePaste, true, ""},
+		{"stop overrides start", nil,
+			config.SessionOptions{Output: config.SessionOutputNone, Task: config.TaskTranslate},
+			config.SessionOptions{Output: config.SessionOutputClipboard}, "", config.OutputModeClipboard, false, config.TaskTranslate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := recordingSession{profile: tt.profile}
+			session.applyOptions(tt.start)
+			session.applyOptions(tt.stop)
+			var lang, mode string
+			if session.profile != nil {
+				lang, mode = session.profile.Language, session.profile.OutputMode
+			}
+			if lang != tt.wantLang || mode != tt.wantMode {
+				t.Errorf("profile language/mode = %q/%q, want %q/%q", lang, mode, tt.wantLang, tt.wantMode)
+			}
+			if session.options.SkipsOutput() != tt.wantSkip || session.options.Task != tt.wantTask {
+				t.Errorf("options = %+v, want skip %t task %q", session.options, tt.wantSkip, tt.wantTask)
+			}
+		})
+	}
+	if matched.Language != "fr" || matched.OutputMode != config.OutputModePaste {
+		t.Errorf("matched profile was modified: %+v", matched)
+	}
+}
+
+func TestAudioService_StdoutOnlyTranscript(t *testing.T) {
+	as, _, _ := newCancelTestService()
+	sub := as.events.Subscribe(1, events.Transcript)
+	session := recordingSession{id: "rec", postProcessing: config.PostProcessingDefault}
+	session.applyOptions(config.SessionOptions{Output: config.SessionOutputStdoutOnly})
+
+	log := testutils.NewMockLogger()
+	as.logger = log
+
+	as.handleTranscriptionResult("hello world", nil, session)
+
+	if got := as.GetLastTranscript(); got != "" {
+		t.Errorf("last transcript = %q, want it kept from stdout-only sessions", got)
+	}
+	for _, msg := range log.GetMessages() {
+		if strings.Contains(msg, "hello world") {
+			t.Errorf("transcript logged: %q", msg)
+		}
+	}
+	ev := <-sub.C
+	if _, leaked := ev.Data["text"]; leaked || ev.Data["session_id"] != "rec" {
+		t.Errorf("transcript event = %+v, want session rec without text", ev)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// clipboardAccess is the subset of an Outputter the guard needs
+type clipboardAccess interface {
+	ReadClipboard() (string, error)
+	CopyToClipboard(text string) error
+}
+
+// clipboardGuard protects the user's clipboard across async transcriptions.
+// Each transcription gets an ownership token; the clipboard content is
+// snapshotted at the start when clipboard is an output target, or just before
+// a fallback writes it, so that:
+//   - a transcript from a superseded session never overwrites a newer one
+//   - a transcript never overwrites something the user copied meanwhile
+//   - the previous clipboard can optionally be restored after a delay
+type clipboardGuard struct {
+	mu           sync.Mutex
+	token        uint64 // Token of the latest transcription session
+	snapshot     string // Clipboard content when the latest session began
+	hasSnapshot  bool   // False when the clipboard could not be read
+	owned        string // Last text written by the guard
+	restoreAfter time.Duration
+	// schedule runs the delayed restore; replaced in tests to run synchronously
+	schedule func(delay time.Duration, fn func())
+}
+
+// newClipboardGuard creates a guard; restoreAfter <= 0 disables restoring
+func newClipboardGuard(restoreAfter time.Duration) *clipboardGuard {
+	return &clipboardGuard{
+		restoreAfter: restoreAfter,
+		schedule:     func(delay time.Duration, fn func()) { time.AfterFunc(delay, fn) },
+	}
+}
+
+// Begin starts a new session, snapshots the clipboard and returns its token.
+// A nil clipboard (non-clipboard output modes) only advances the token.
+// The clipboard is read before locking, so a slow clipboard tool never blocks the guard
+func (g *clipboardGuard) Begin(clipboard clipboardAccess) uint64 {
+	var snapshot string
+	hasSnapshot := false
+	if clipboard != nil {
+		if content, err := clipboard.ReadClipboard(); err == nil {
+			snapshot, hasSnapshot = content, true
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.token++
+	g.snapshot, g.hasSnapshot = snapshot, hasSnapshot
+	return g.token
+}
+
+// Snapshot records the clipboard content read just before the first write of a
+// session that did not snapshot at Begin (clipboard used only as a fallback)
+func (g *clipboardGuard) Snapshot(token uint64, content string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if token == g.token && !g.hasSnapshot {
+		g.snapshot, g.hasSnapshot = content, true
+	}
+}
+
+// CanWrite reports whether the session owning token may write the clipboard.
+// Returns a reason when the write must be skipped
+func (g *clipboardGuard) CanWrite(token uint64, clipboard clipboardAccess) (bool, string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if token != g.token {
+		return false, "a newer transcription is in progress"
+	}
+	if !g.hasSnapshot || clipboard == nil {
+		return true, ""
+	}
+	current, err := clipboard.ReadClipboard()
+	if err != nil || current == g.snapshot || current == g.owned {
+		return true, ""
+	}
+	return false, "the clipboard changed during transcription"
+}
+
+// Written records a successful clipboard write and schedules the restore
+// of the previous content if the policy is enabled
+func (g *clipboardGuard) Written(token uint64, text string, clipboard clipboardAccess) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.owned = text
+	if g.restoreAfter <= 0 || !g.hasSnapshot || clipboard == nil {
+		return
+	}
+	snapshot := g.snapshot
+	g.schedule(g.restoreAfter, func() { g.restore(token, text, snapshot, clipboard) })
+}
+
+// restore puts the snapshot back if the session is still current and the
+// clipboard still holds the transcript it wrote
+func (g *clipboardGuard) restore(token uint64, written, snapshot string, clipboard clipboardAccess) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if token != g.token {
+		return
+	}
+	if current, err := clipboard.ReadClipboard(); err != nil || current != written {
+		return
+	}
+	if err := clipboard.CopyToClipboard(snapshot); err == nil {
+		g.owned = snapshot
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/testutils"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
+)
+
+// newClipboardConfig returns a config that outputs to the fake xsel clipboard
+func newClipboardConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xsel"}
+	cfg.Output.DefaultMode = config.OutputModeClipboard
+	return cfg
+}
+
+func newGuardedIOService(t *testing.T, cfg *config.Config) *IOService {
+	t.Helper()
+	out, err := outputters.NewClipboardOutputter("xsel", cfg)
+	if err != nil {
+		t.Fatalf("NewClipboardOutputter: %v", err)
+	}
+	return NewIOService(testutils.NewMockLogger(), config.NewLive(cfg), out, nil, nil)
+}
+
+func TestClipboardGuard_BeginPreservesClipboard(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "user data"), newClipboardConfig()
+	ios := newGuardedIOService(t, cfg)
+
+	token := ios.BeginTranscription()
+	if cb.Get() != "user data" {
+		t.Errorf("BeginTranscription must not clear the clipboard, got %q", cb.Get())
+	}
+	if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: "hello world"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if cb.Get() != "hello world" {
+		t.Errorf("expected transcript on clipboard, got %q", cb.Get())
+	}
+}
+
+func TestClipboardGuard_SkipsWhenUserCopiedMeanwhile(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "before"), newClipboardConfig()
+	ios := newGuardedIOService(t, cfg)
+
+	token := ios.BeginTranscription()
+	cb.Set("copied during transcription")
+	if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: "late transcript"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if cb.Get() != "copied during transcription" {
+		t.Errorf("newer user copy was overwritten: %q", cb.Get())
+	}
+}
+
+func TestClipboardGuard_SkipsSupersededSession(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "before"), newClipboardConfig()
+	ios := newGuardedIOService(t, cfg)
+
+	first := ios.BeginTranscription()
+	second := ios.BeginTranscription()
+	if _, err := ios.OutputTranscript(second, outputInterfaces.Transcript{Text: "second"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if _, err := ios.OutputTranscript(first, outputInterfaces.Transcript{Text: "first (late)"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if cb.Get() != "second" {
+		t.Errorf("late transcript from superseded session overwrote clipboard: %q", cb.Get())
+	}
+}
+
+func TestClipboardGuard_ConsecutiveSessions(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "before"), newClipboardConfig()
+	ios := newGuardedIOService(t, cfg)
+
+	for _, text := range []string{"one", "two"} {
+		token := ios.BeginTranscription()
+		if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: text}, nil); err != nil {
+			t.Fatalf("OutputTranscript: %v", err)
+		}
+		if cb.Get() != text {
+			t.Errorf("expected %q on clipboard, got %q", text, cb.Get())
+		}
+	}
+}
+
+func TestClipboardGuard_RestorePolicy(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "original"), newClipboardConfig()
+	cfg.Output.ClipboardRestoreSeconds = 5
+	ios := newGuardedIOService(t, cfg)
+
+	var delay time.Duration
+	var restore func()
+	ios.clipboardGuard.schedule = func(d time.Duration, fn func()) { delay, restore = d, fn }
+
+	token := ios.BeginTranscription()
+	if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: "transcript"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if restore == nil || delay != 5*time.Second {
+		t.Fatalf("expected restore scheduled after 5s, got %v", delay)
+	}
+	restore()
+	if cb.Get() != "original" {
+		t.Errorf("expected clipboard restored to %q, got %q", "original", cb.Get())
+	}
+}
+
+func TestClipboardGuard_RestoreSkippedAfterUserCopy(t *testing.T) {
+	cb, cfg := testutils.NewFakeClipboard(t, "original"), newClipboardConfig()
+	cfg.Output.ClipboardRestoreSeconds = 5
+	ios := newGuardedIOService(t, cfg)
+
+	var restore func()
+	ios.clipboardGuard.schedule = func(_ time.Duration, fn func()) { restore = fn }
+
+	token := ios.BeginTranscription()
+	if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: "transcript"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	cb.Set("user copied after paste")
+	restore()
+	if cb.Get() != "user copied after paste" {
+		t.Errorf("restore clobbered a newer user copy: %q", cb.Get())
+	}
+}
+
+// probeClipboard records whether the guard was locked while the clipboard was read
+type probeClipboard struct {
+	guard       *clipboardGuard
+	readLocked  bool
+	readContent string
+}
+
+func (p *probeClipboard) ReadClipboard() (string, error) {
+	if p.guard.mu.TryLock() {
+		p.guard.mu.Unlock()
+	} else {
+		p.readLocked = true
+	}
+	return p.readContent, nil
+}
+
+func (p *probeClipboard) CopyToClipboard(string) error { return nil }
+
+func TestClipboardGuard_BeginReadsClipboardUnlocked(t *testing.T) {
+	guard := newClipboardGuard(0)
+	cb := &probeClipboard{guard: guard, readContent: "user data"}
+
+	token := guard.Begin(cb)
+	if cb.readLocked {
+		t.Error("Begin must read the clipboard before taking the guard lock")
+	}
+	if token != 1 || !guard.hasSnapshot || guard.snapshot != "user data" {
+		t.Errorf("token = %d, snapshot = %q (%v)", token, guard.snapshot, guard.hasSnapshot)
+	}
+}
@@ -5,24 +5,29 @@ package services
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/constants"
+	"github.com/AshBuk/dabri/internal/events"
 	"github.com/AshBuk/dabri/internal/logger"
 )
 
 // Manages persistent configuration state and validation
+// The running configuration is an immutable snapshot in config.Live: every
+// change is applied to a copy, saved and then published, one writer at a time
 type ConfigService struct {
 	logger     logger.Logger
-	config     *config.Config
+	config     *config.Live
 	configFile string
 	uiService  UIServiceInterface
+	events     *events.Bus // Receives config_changed after every save (nil disables)
 }
 
 // Create a new ConfigService instance
 func NewConfigService(
 	logger logger.Logger,
-	config *config.Config,
+	config *config.Live,
 	configFile string,
 ) *ConfigService {
 	return &ConfigService{
@@ -38,6 +43,11 @@ func (cs *ConfigService) SetUIService(uiService UIServiceInterface) {
 	cs.uiService = uiService
 }
 
+// Wire the event bus notified when the configuration is saved
+func (cs *ConfigService) SetEventBus(bus *events.Bus) {
+	cs.events = bus
+}
+
 // Update active configuration path reference
 func (cs *ConfigService) LoadConfig(configFile string) error {
 	cs.logger.Info("Loading configuration from: %s", configFile)
@@ -48,22 +58,68 @@ func (cs *ConfigService) LoadConfig(configFile string) error {
 
 // Persist current configuration state to disk
 func (cs *ConfigService) SaveConfig() error {
+	_, _, err := cs.config.Update(func(next *config.Config) error {
+		return cs.save(next)
+	})
+	if err != nil {
+		return err
+	}
+	cs.publishChanged()
+	return nil
+}
+
+// save writes a configuration to the config file
+func (cs *ConfigService) save(cfg *config.Config) error {
 	cs.logger.Info("Saving configuration to: %s", cs.configFile)
 	if cs.configFile == "" {
 		return fmt.Errorf("no config file path set")
 	}
-	return config.SaveConfig(cs.configFile, cs.config)
+	return config.SaveConfig(cs.configFile, cfg)
+}
+
+// update applies a change to a copy of the config, saves it and publishes it.
+// A failed save leaves the running configuration untouched
+func (cs *ConfigService) update(change func(next *config.Config) error) error {
+	if cs == nil || cs.config == nil {
+		return fmt.Errorf("config service not available")
+	}
+	_, _, err := cs.config.Update(func(next *config.Config) error {
+		if err := change(next); err != nil {
+			return err
+		}
+		if err := cs.save(next); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	cs.publishChanged()
+	return nil
+}
+
+// publishChanged notifies subscribers of the settings shown by status
+func (cs *ConfigService) publishChanged() {
+	cfg := cs.config.Get()
+	cs.events.Publish(events.ConfigChanged, map[string]any{
+		"model":       cfg.General.WhisperModel,
+		"language":    cfg.General.Language,
+		"output_mode": cfg.Output.DefaultMode,
+		"mode":        cfg.General.PostProcessing,
+	})
 }
 
 // Restore factory defaults and notify user of successful reset
 func (cs *ConfigService) ResetToDefaults() error {
 	cs.logger.Info("Resetting configuration to defaults...")
 
-	// Reset existing config in-place to keep pointer stable across app
-	config.SetDefaultConfig(cs.config)
-
-	// Save to file immediately
-	if err := cs.SaveConfig(); err != nil {
+	err := cs.update(func(next *config.Config) error {
+		*next = config.Config{}
+		config.SetDefaultConfig(next)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save default config: %w", err)
 	}
 	cs.logger.Info("Configuration reset to defaults successfully")
@@ -71,68 +127,83 @@ func (cs *ConfigService) ResetToDefaults() error {
 	// Show success notification via UI service and refresh UI
 	if cs.uiService != nil {
 		cs.uiService.ShowNotification(constants.NotifyConfigReset, constants.NotifyConfigResetSuccess)
-		cs.uiService.UpdateSettings(cs.config)
+		cs.uiService.UpdateSettings(cs.config.Get())
 	}
 	return nil
 }
 
-// Provide read-only access to current configuration
+// Provide the current configuration snapshot; callers must not modify it
 func (cs *ConfigService) GetConfig() *config.Config {
-	return cs.config
+	return cs.config.Get()
 }
 
-// Change whisper model setting with rollback on save failure
+// Change whisper model setting; a failed save keeps the previous model
 func (cs *ConfigService) UpdateWhisperModel(modelID string) error {
 	cs.logger.Info("Updating whisper model to: %s", modelID)
-	if cs.config.General.WhisperModel == modelID {
+	if cs.config.Get().General.WhisperModel == modelID {
 		return nil
 	}
-	old := cs.config.General.WhisperModel
-	cs.config.General.WhisperModel = modelID
-	if err := cs.SaveConfig(); err != nil {
-		cs.config.General.WhisperModel = old
-		return fmt.Errorf("failed to save config: %w", err)
-	}
-	return nil
+	return cs.update(func(next *config.Config) error {
+		next.General.WhisperModel = modelID
+		return nil
+	})
+}
+
+// Record the whisper model actually loaded without saving it, e.g. when
+// the model named by a reloaded config file failed to load
+func (cs *ConfigService) SetLoadedModel(modelID string) {
+	_, _, _ = cs.config.Update(func(next *config.Config) error {
+		next.General.WhisperModel = modelID
+		return nil
+	})
 }
 
-// Change whisper language setting with rollback on save failure
+// Change whisper language setting; a failed save keeps the previous language
 func (cs *ConfigService) UpdateLanguage(language string) error {
 	cs.logger.Info("Updating language to: %s", language)
-	if cs.config.General.Language == language {
+	if cs.config.Get().General.Language == language {
 		return nil
 	}
-	old := cs.config.General.Language
-	cs.config.General.Language = language
-
-	if err := cs.SaveConfig(); err != nil {
-		cs.config.General.Language = old
-		return fmt.Errorf("failed to save config: %w", err)
-	}
-	return nil
+	return cs.update(func(next *config.Config) error {
+		next.General.Language = language
+		return nil
+	})
 }
 
-// Switch between clipboard and typing output with rollback protection
+// Switch between clipboard and typing output; a failed save keeps the previous mode
 func (cs *ConfigService) UpdateOutputMode(mode string) error {
 	cs.logger.Info("Updating output mode to: %s", mode)
-	if cs.config.Output.DefaultMode == mode {
+	if cs.config.Get().Output.DefaultMode == mode {
 		return nil
 	}
-	old := cs.config.Output.DefaultMode
-	cs.config.Output.DefaultMode = mode
+	return cs.update(func(next *config.Config) error {
+		next.Output.DefaultMode = mode
+		return nil
+	})
+}
 
-	if err := cs.SaveConfig(); err != nil {
-		cs.config.Output.DefaultMode = old
-		return fmt.Errorf("failed to save config: %w", err)
+// Change the default transcript post-processing mode; a failed save keeps the previous mode
+func (cs *ConfigService) UpdatePostProcessing(mode string) error {
+	cs.logger.Info("Updating post-processing mode to: %s", mode)
+	if mode != config.PostProcessingDefault && mode != config.PostProcessingCode {
+		return fmt.Errorf("invalid post-processing mode: %s (must be '%s' or '%s')", mode, config.PostProcessingDefault, config.PostProcessingCode)
 	}
-	return nil
+	if cs.config.Get().General.PostProcessing == mode {
+		return nil
+	}
+	return cs.update(func(next *config.Config) error {
+		next.General.PostProcessing = mode
+		return nil
+	})
 }
 
 // Enable/disable desktop notifications for workflow events
 func (cs *ConfigService) ToggleWorkflowNotifications() error {
 	cs.logger.Info("Toggling workflow notifications")
-	cs.config.Notifications.EnableWorkflowNotifications = !cs.config.Notifications.EnableWorkflowNotifications
-	return cs.SaveConfig()
+	return cs.update(func(next *config.Config) error {
+		next.Notifications.EnableWorkflowNotifications = !next.Notifications.EnableWorkflowNotifications
+		return nil
+	})
 }
 
 // Switch audio backend (arecord/ffmpeg) with validation and persistence
@@ -141,57 +212,128 @@ func (cs *ConfigService) UpdateRecordingMethod(method string) error {
 	if method != "arecord" && method != "ffmpeg" {
 		return fmt.Errorf("invalid recording method: %s", method)
 	}
-	if cs.config.Audio.RecordingMethod == method {
+	if cs.config.Get().Audio.RecordingMethod == method {
 		return nil
 	}
-	old := cs.config.Audio.RecordingMethod
-	cs.config.Audio.RecordingMethod = method
-
-	if err := cs.SaveConfig(); err != nil {
-		cs.config.Audio.RecordingMethod = old
-		return fmt.Errorf("failed to save config: %w", err)
-	}
-	return nil
+	return cs.update(func(next *config.Config) error {
+		next.Audio.RecordingMethod = method
+		return nil
+	})
 }
 
-// Rebind hotkey combinations with validation and rollback protection
+// Rebind hotkey combinations; a failed save keeps the previous bindings
 func (cs *ConfigService) UpdateHotkey(action, combo string) error {
 	if cs == nil || cs.config == nil {
 		return fmt.Errorf("config service not available")
 	}
-	oldStart := cs.config.Hotkeys.StartRecording
-	oldStop := cs.config.Hotkeys.StopRecording
-	oldShow := cs.config.Hotkeys.ShowConfig
-	oldReset := cs.config.Hotkeys.ResetToDefaults
-
-	switch action {
-	case "start_recording", "stop_recording":
-		cs.config.Hotkeys.StartRecording = combo
-		cs.config.Hotkeys.StopRecording = combo
-	case "show_config":
-		cs.config.Hotkeys.ShowConfig = combo
-	case "reset_to_defaults":
-		cs.config.Hotkeys.ResetToDefaults = combo
-	default:
-		return fmt.Errorf("unknown hotkey action: %s", action)
+	err := cs.update(func(next *config.Config) error {
+		switch action {
+		case "start_recording", "stop_recording":
+			next.Hotkeys.StartRecording = combo
+			next.Hotkeys.StopRecording = combo
+		case "show_config":
+			next.Hotkeys.ShowConfig = combo
+		case "reset_to_defaults":
+			next.Hotkeys.ResetToDefaults = combo
+		case "code_recording":
+			next.Hotkeys.CodeRecording = combo
+		case "undo":
+			next.Hotkeys.Undo = combo
+		case "cancel":
+			next.Hotkeys.Cancel = combo
+		default:
+			return fmt.Errorf("unknown hotkey action: %s", action)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := cs.SaveConfig(); err != nil {
-		// rollback
-		cs.config.Hotkeys.StartRecording = oldStart
-		cs.config.Hotkeys.StopRecording = oldStop
-		cs.config.Hotkeys.ShowConfig = oldShow
-		cs.config.Hotkeys.ResetToDefaults = oldReset
-		return fmt.Errorf("failed to save hotkey: %w", err)
+	// Refresh UI display
+	if cs.uiService != nil {
+		cs.uiService.UpdateSettings(cs.config.Get())
 	}
+	return nil
+}
 
-	// Refresh UI display
+// Change any setting by dotted path (e.g., "audio.device"). The change is
+// applied to a copy and validated first, so a rejected value leaves the
+// running configuration untouched
+func (cs *ConfigService) SetValue(path, value string) error {
+	cs.logger.Info("Setting %s", path)
+	return cs.applyChange(path, func(next *config.Config) error {
+		return config.SetValue(next, path, value)
+	})
+}
+
+// Restore the default of a setting by dotted path, with validation
+func (cs *ConfigService) UnsetValue(path string) error {
+	cs.logger.Info("Resetting %s to its default", path)
+	return cs.applyChange(path, func(next *config.Config) error {
+		return config.UnsetValue(next, path)
+	})
+}
+
+// applyChange mutates a copy of the config, validates and saves it, then
+// publishes it as the running configuration
+func (cs *ConfigService) applyChange(path string, mutate func(*config.Config) error) error {
+	err := cs.update(func(next *config.Config) error {
+		if err := mutate(next); err != nil {
+			return err
+		}
+		// Validation corrects bad values; a correction means the value is rejected
+		if err := config.ValidateConfig(next); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 	if cs.uiService != nil {
-		cs.uiService.UpdateSettings(cs.config)
+		cs.uiService.UpdateSettings(cs.config.Get())
 	}
 	return nil
 }
 
+// Re-read the config file (e.g., after SIGHUP) and publish it as the running
+// configuration. The file is read and swapped under the same writer lock as every
+// other change, so the changed settings are computed against the config it replaces.
+// Returns the changed settings; applying them to running services is up to the caller
+func (cs *ConfigService) Reload() ([]string, error) {
+	if cs == nil || cs.config == nil {
+		return nil, fmt.Errorf("config service not available")
+	}
+	// LoadConfig falls back to defaults for a missing file; a reload must not
+	if _, err := os.Stat(cs.configFile); err != nil {
+		return nil, fmt.Errorf("cannot reload config: %w", err)
+	}
+	var changed []string
+	if _, _, err := cs.config.Update(func(current *config.Config) error {
+		// Read under the lock, so a change saved concurrently is either in the file or not yet published
+		next, err := config.LoadConfig(cs.configFile, cs.logger)
+		if err != nil {
+			return err
+		}
+		// --debug from the command line outlives the reload
+		next.General.Debug = next.General.Debug || current.General.Debug
+		changed = config.ChangedKeys(current, next)
+		*current = *next
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to reload config: %w", err)
+	}
+	cs.logger.Info("Configuration reloaded from %s (%d settings changed)", cs.configFile, len(changed))
+	if len(changed) > 0 {
+		cs.publishChanged()
+	}
+	if cs.uiService != nil {
+		cs.uiService.UpdateSettings(cs.config.Get())
+	}
+	return changed, nil
+}
+
 // Ensure final configuration state is saved before termination
 func (cs *ConfigService) Shutdown() error {
 	cs.logger.Info("ConfigService shutdown complete")
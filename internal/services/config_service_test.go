@@ -6,10 +6,12 @@ package services
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/config/models"
+	"github.com/AshBuk/dabri/internal/events"
 	"github.com/AshBuk/dabri/internal/testutils"
 )
 
@@ -25,14 +27,14 @@ func TestConfigService_NewConfigService(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
 
-	service := NewConfigService(mockLogger, testConfig, "/test/config.yaml")
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), "/test/config.yaml")
 	if service == nil {
 		t.Fatal("NewConfigService returned nil")
 	}
 	if service.logger != mockLogger {
 		t.Error("Logger not set correctly")
 	}
-	if service.config != testConfig {
+	if service.GetConfig() != testConfig {
 		t.Error("Config not set correctly")
 	}
 	if service.configFile != "/test/config.yaml" {
@@ -44,7 +46,7 @@ func TestConfigService_GetConfig(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
 
-	service := NewConfigService(mockLogger, testConfig, "/test/config.yaml")
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), "/test/config.yaml")
 
 	result := service.GetConfig()
 	if result != testConfig {
@@ -63,11 +65,11 @@ func TestConfigService_UpdateLanguage(t *testing.T) {
 		t.Fatalf("Failed to create test config file: %v", err)
 	}
 
-	service := NewConfigService(mockLogger, testConfig, configPath)
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), configPath)
 	if err := service.UpdateLanguage("ru"); err != nil {
 		t.Errorf("UpdateLanguage failed: %v", err)
 	}
-	if service.config.General.Language != "ru" {
+	if service.GetConfig().General.Language != "ru" {
 		t.Error("Language not updated correctly")
 	}
 
@@ -77,6 +79,28 @@ func TestConfigService_UpdateLanguage(t *testing.T) {
 	}
 }
 
+func TestConfigService_UpdatePostProcessing(t *testing.T) {
+	testConfig := createTestConfig()
+	configPath := filepath.Join(t.TempDir(), "test_config.yaml")
+	if err := config.SaveConfig(configPath, testConfig); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(testConfig), configPath)
+	if err := service.UpdatePostProcessing(config.PostProcessingCode); err != nil {
+		t.Errorf("UpdatePostProcessing failed: %v", err)
+	}
+	if service.GetConfig().General.PostProcessing != config.PostProcessingCode {
+		t.Errorf("expected post-processing 'code', got '%s'", service.GetConfig().General.PostProcessing)
+	}
+	if err := service.UpdatePostProcessing("shout"); err == nil {
+		t.Error("expected error for invalid post-processing mode")
+	}
+	if service.GetConfig().General.PostProcessing != config.PostProcessingCode {
+		t.Error("invalid mode should not change the setting")
+	}
+}
+
 func TestConfigService_ToggleWorkflowNotifications(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
@@ -88,9 +112,9 @@ func TestConfigService_ToggleWorkflowNotifications(t *testing.T) {
 		t.Fatalf("Failed to create test config file: %v", err)
 	}
 
-	service := NewConfigService(mockLogger, testConfig, configPath)
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), configPath)
 	// Initial state should be true
-	if !service.config.Notifications.EnableWorkflowNotifications {
+	if !service.GetConfig().Notifications.EnableWorkflowNotifications {
 		t.Error("Initial workflow notifications state should be true")
 	}
 
@@ -98,7 +122,7 @@ func TestConfigService_ToggleWorkflowNotifications(t *testing.T) {
 	if err := service.ToggleWorkflowNotifications(); err != nil {
 		t.Errorf("ToggleWorkflowNotifications failed: %v", err)
 	}
-	if service.config.Notifications.EnableWorkflowNotifications {
+	if service.GetConfig().Notifications.EnableWorkflowNotifications {
 		t.Error("Workflow notifications should be toggled to false")
 	}
 
@@ -106,7 +130,7 @@ func TestConfigService_ToggleWorkflowNotifications(t *testing.T) {
 	if err := service.ToggleWorkflowNotifications(); err != nil {
 		t.Errorf("ToggleWorkflowNotifications failed: %v", err)
 	}
-	if !service.config.Notifications.EnableWorkflowNotifications {
+	if !service.GetConfig().Notifications.EnableWorkflowNotifications {
 		t.Error("Workflow notifications should be toggled to true")
 	}
 }
@@ -115,7 +139,7 @@ func TestConfigService_LoadConfig(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
 
-	service := NewConfigService(mockLogger, testConfig, "")
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), "")
 
 	if err := service.LoadConfig("/new/path/config.yaml"); err != nil {
 		t.Errorf("LoadConfig failed: %v", err)
@@ -129,13 +153,32 @@ func TestConfigService_SaveConfig_NoPath(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
 
-	service := NewConfigService(mockLogger, testConfig, "")
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), "")
 
 	if err := service.SaveConfig(); err == nil {
 		t.Error("SaveConfig should fail when no config file path is set")
 	}
 }
 
+func TestConfigService_SaveConfig_PublishesConfigChanged(t *testing.T) {
+	bus := events.NewBus()
+	sub := bus.Subscribe(1, events.ConfigChanged)
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(createTestConfig()), filepath.Join(t.TempDir(), "config.yaml"))
+	service.SetEventBus(bus)
+
+	if err := service.UpdateLanguage("de"); err != nil {
+		t.Fatalf("UpdateLanguage failed: %v", err)
+	}
+	select {
+	case ev := <-sub.C:
+		if ev.Data["language"] != "de" {
+			t.Errorf("config_changed data = %v", ev.Data)
+		}
+	default:
+		t.Error("expected a config_changed event after saving")
+	}
+}
+
 func TestConfigService_ResetToDefaults(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
@@ -148,15 +191,15 @@ func TestConfigService_ResetToDefaults(t *testing.T) {
 	defer os.Remove(tempFile.Name())
 	tempFile.Close()
 
-	service := NewConfigService(mockLogger, testConfig, tempFile.Name())
 	// Modify some settings first
-	service.config.General.Language = "fr"
+	testConfig.General.Language = "fr"
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), tempFile.Name())
 
 	if err := service.ResetToDefaults(); err != nil {
 		t.Errorf("ResetToDefaults failed: %v", err)
 	}
 	// Verify settings were reset to defaults
-	if service.config.General.Language != "en" {
+	if service.GetConfig().General.Language != "en" {
 		t.Error("Language should be reset to default 'en'")
 	}
 }
@@ -165,7 +208,7 @@ func TestConfigService_Shutdown(t *testing.T) {
 	mockLogger := testutils.NewMockLogger()
 	testConfig := createTestConfig()
 
-	service := NewConfigService(mockLogger, testConfig, "/tmp/test_config.yaml")
+	service := NewConfigService(mockLogger, config.NewLive(testConfig), "/tmp/test_config.yaml")
 
 	if err := service.Shutdown(); err != nil {
 		t.Errorf("Shutdown failed: %v", err)
@@ -174,3 +217,194 @@ func TestConfigService_Shutdown(t *testing.T) {
 	// Shutdown should complete successfully without saving
 	// (config changes are saved immediately by their respective methods)
 }
+
+func TestConfigService_SetValue(t *testing.T) {
+	newService := func(path string) *ConfigService {
+		cfg := &models.Config{}
+		config.SetDefaultConfig(cfg)
+		return NewConfigService(testutils.NewMockLogger(), config.NewLive(cfg), path)
+	}
+	tests := []struct {
+		name    string
+		path    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{"valid value", "config.yaml", "audio.device", "hw:1,0", false},
+		{"rejected by validation", "config.yaml", "audio.sample_rate", "96000", true},
+		{"unparsable value", "config.yaml", "web_server.port", "high", true},
+		{"unknown key", "config.yaml", "audio.volume", "3", true},
+		{"save failure rolls back", "", "audio.device", "hw:1,0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path != "" {
+				path = filepath.Join(t.TempDir(), path)
+			}
+			service := newService(path)
+			before, _ := config.GetValue(service.GetConfig(), tt.key)
+
+			err := service.SetValue(tt.key, tt.value)
+			after, _ := config.GetValue(service.GetConfig(), tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if after != before {
+					t.Errorf("%s changed to %v after a failed set", tt.key, after)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue: %v", err)
+			}
+			if after != tt.value {
+				t.Errorf("%s = %v, want %s", tt.key, after, tt.value)
+			}
+			saved, err := config.LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if got, _ := config.GetValue(saved, tt.key); got != tt.value {
+				t.Errorf("saved %s = %v, want %s", tt.key, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestConfigService_UnsetValue(t *testing.T) {
+	cfg := &models.Config{}
+	config.SetDefaultConfig(cfg)
+	defaultPort := cfg.WebServer.Port
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(cfg), filepath.Join(t.TempDir(), "config.yaml"))
+
+	if err := service.SetValue("web_server.port", "9090"); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if err := service.UnsetValue("web_server.port"); err != nil {
+		t.Fatalf("UnsetValue: %v", err)
+	}
+	if got := service.GetConfig().WebServer.Port; got != defaultPort {
+		t.Errorf("port = %d, want default %d", got, defaultPort)
+	}
+}
+
+func TestConfigService_Reload(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := config.SaveConfig(configPath, cfg); err != nil {
+		t.Fatalf("Failed to create test config file: %v", err)
+	}
+	running := cfg
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(running), configPath)
+
+	// Edit the file behind the service's back, as a user would before SIGHUP
+	edited, _ := config.Clone(cfg)
+	edited.Audio.Device = "hw:1,0"
+	edited.Hotkeys.StartRecording = "ctrl+alt+r"
+	if err := config.SaveConfig(configPath, edited); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := service.Reload()
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(changed) != 2 || changed[0] != "hotkeys.start_recording" || changed[1] != "audio.device" {
+		t.Errorf("changed = %v, want [hotkeys.start_recording audio.device]", changed)
+	}
+	if got := service.GetConfig().Audio.Device; got != "hw:1,0" {
+		t.Errorf("reload must publish the file's config, got device %q", got)
+	}
+	if running.Audio.Device == "hw:1,0" {
+		t.Error("reload must not modify the previous snapshot")
+	}
+
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Reload(); err == nil {
+		t.Error("Reload of a missing file should fail instead of restoring defaults")
+	}
+	if got := service.GetConfig().Audio.Device; got != "hw:1,0" {
+		t.Errorf("failed reload changed the config: device %q", got)
+	}
+}
+
+func TestConfigService_ChangesPublishNewSnapshots(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(cfg), filepath.Join(t.TempDir(), "config.yaml"))
+
+	snapshot := service.GetConfig()
+	if err := service.SetValue("audio.device", "hw:1,0"); err != nil {
+		t.Fatalf("SetValue: %v", err)
+	}
+	if err := service.UpdateLanguage("de"); err != nil {
+		t.Fatalf("UpdateLanguage: %v", err)
+	}
+	if snapshot.Audio.Device == "hw:1,0" || snapshot.General.Language == "de" {
+		t.Error("changes must not modify a snapshot readers may hold")
+	}
+	current := service.GetConfig()
+	if current.Audio.Device != "hw:1,0" || current.General.Language != "de" {
+		t.Errorf("current config = device %q, language %q", current.Audio.Device, current.General.Language)
+	}
+}
+
+func TestConfigService_ConcurrentChangesAreNotLost(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(cfg), filepath.Join(t.TempDir(), "config.yaml"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := service.SetValue("audio.device", "hw:1,0"); err != nil {
+			t.Errorf("SetValue: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := service.UpdateLanguage("de"); err != nil {
+			t.Errorf("UpdateLanguage: %v", err)
+		}
+	}()
+	wg.Wait()
+	current := service.GetConfig()
+	if current.Audio.Device != "hw:1,0" || current.General.Language != "de" {
+		t.Errorf("a concurrent change was lost: device %q, language %q", current.Audio.Device, current.General.Language)
+	}
+}
+
+func TestConfigService_ReloadDuringChangeKeepsSavedChange(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := config.SaveConfig(configPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	service := NewConfigService(testutils.NewMockLogger(), config.NewLive(cfg), configPath)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := service.Reload(); err != nil {
+			t.Errorf("Reload: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if err := service.SetValue("audio.device", "hw:1,0"); err != nil {
+			t.Errorf("SetValue: %v", err)
+		}
+	}()
+	wg.Wait()
+	if got := service.GetConfig().Audio.Device; got != "hw:1,0" {
+		t.Errorf("reload published a stale file over a saved change: device %q", got)
+	}
+}
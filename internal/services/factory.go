@@ -11,11 +11,13 @@ import (
 	"github.com/AshBuk/dabri/audio/processing"
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/hotkeys/manager"
+	"github.com/AshBuk/dabri/internal/activewindow"
 	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/notify"
 	"github.com/AshBuk/dabri/internal/platform"
 	"github.com/AshBuk/dabri/internal/tray"
 	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
 	"github.com/AshBuk/dabri/websocket"
 	"github.com/AshBuk/dabri/whisper"
 )
@@ -24,7 +26,7 @@ import (
 type ServiceFactoryConfig struct {
 	Ctx         context.Context          // Application context for cancellation propagation
 	Logger      logger.Logger            // Application-wide logger
-	Config      *config.Config           // Loaded YAML configuration
+	Config      *config.Live             // Running configuration, loaded from YAML
 	ConfigFile  string                   // Path to config file for reloading
 	Environment platform.EnvironmentType // Runtime environment (Native/AppImage)
 }
@@ -36,11 +38,13 @@ type Components struct {
 	Recorder        interfaces.AudioRecorder    // Audio recording implementation
 	WhisperEngine   *whisper.WhisperEngine      // Speech-to-text engine
 	OutputManager   outputInterfaces.Outputter  // Text output (clipboard/typing)
+	WebhookWorker   *outputters.WebhookWorker   // Background webhook delivery, owned by IOService
 	HotkeyManager   *manager.HotkeyManager      // Global hotkey registration
 	WebSocketServer *websocket.WebSocketServer  // WebSocket server for remote control
 	TrayManager     tray.Manager                // System tray icon and menu
 	NotifyManager   *notify.NotificationManager // Desktop notifications
 	TempFileManager *processing.TempFileManager // Temporary audio file management
+	WindowDetector  activewindow.Detector       // Focused window lookup for per-application profiles and refocusing
 }
 
 // ServiceFactory creates and configures all services with proper dependency injection
@@ -4,9 +4,13 @@
 package services
 
 import (
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/history"
+	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/notify"
 	"github.com/AshBuk/dabri/internal/tray"
 	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
 	"github.com/AshBuk/dabri/websocket"
 )
 
@@ -24,7 +28,7 @@ import (
 //  1. Create services from components
 //  2. Early wiring  (Config→UI, IO→UI, IO→Config)
 //  3. Pack into container
-//  4. Late wiring   (Audio→UI, Audio→IO, Audio→Config)
+//  4. Late wiring   (Audio→UI, Audio→IO, Audio→Config, Audio→History, Audio/Config→Events)
 type FactoryAssembler struct {
 	factoryConfig ServiceFactoryConfig
 }
@@ -42,7 +46,7 @@ func (sa *FactoryAssembler) Assemble(components *Components) *ServiceContainer {
 	hotkeysSvc := sa.createHotkeyService(components.HotkeyManager)
 	audioSvc := sa.createAudioService(components)
 	uiSvc := sa.createUIService(components.TrayManager, components.NotifyManager)
-	ioSvc := sa.createIOService(components.OutputManager, components.WebSocketServer)
+	ioSvc := sa.createIOService(components.OutputManager, components.WebhookWorker, components.WebSocketServer)
 
 	// Step 2: Early wiring - dependencies needed before container assembly
 	configSvc.SetUIService(uiSvc)     // Config → UI (for reload notifications)
@@ -50,6 +54,7 @@ func (sa *FactoryAssembler) Assemble(components *Components) *ServiceContainer {
 	ioSvc.SetConfigService(configSvc) // IO → Config (for output settings)
 	if components.WebSocketServer != nil {
 		components.WebSocketServer.SetAudioController(audioSvc)
+		components.WebSocketServer.SetOutputRouter(ioSvc)
 	}
 
 	// Step 3: Pack services into container
@@ -59,10 +64,18 @@ func (sa *FactoryAssembler) Assemble(components *Components) *ServiceContainer {
 	container.UI = uiSvc
 	container.IO = ioSvc
 	container.TempFileManager = components.TempFileManager
+	container.History = sa.createHistoryStore()
+	container.Events = events.NewBus()
+	container.Actions = NewSettingsActions(container, sa.factoryConfig.Logger)
 
 	// Step 4: Late wiring - cross-dependencies after container is ready
 	audioSvc.SetDependencies(container.UI, container.IO)
 	audioSvc.SetConfig(container.Config)
+	audioSvc.SetWindowDetector(components.WindowDetector)
+	ioSvc.SetWindowDetector(components.WindowDetector)
+	audioSvc.SetHistory(container.History)
+	audioSvc.SetEventBus(container.Events)
+	configSvc.SetEventBus(container.Events)
 
 	return container
 }
@@ -72,7 +85,7 @@ func (sa *FactoryAssembler) Assemble(components *Components) *ServiceContainer {
 // createConfigService creates the ConfigService
 func (sa *FactoryAssembler) createConfigService() *ConfigService {
 	return NewConfigService(
-		sa.factoryConfig.Logger,
+		logger.WithComponent(sa.factoryConfig.Logger, "config"),
 		sa.factoryConfig.Config,
 		sa.factoryConfig.ConfigFile,
 	)
@@ -81,7 +94,7 @@ func (sa *FactoryAssembler) createConfigService() *ConfigService {
 // createHotkeyService creates the HotkeyService
 func (sa *FactoryAssembler) createHotkeyService(hotkeyManager HotkeyManager) *HotkeyService {
 	return NewHotkeyService(
-		sa.factoryConfig.Logger,
+		logger.WithComponent(sa.factoryConfig.Logger, "hotkeys"),
 		hotkeyManager,
 	)
 }
@@ -89,7 +102,7 @@ func (sa *FactoryAssembler) createHotkeyService(hotkeyManager HotkeyManager) *Ho
 // createAudioService creates the AudioService
 func (sa *FactoryAssembler) createAudioService(components *Components) *AudioService {
 	return NewAudioService(
-		sa.factoryConfig.Logger,
+		logger.WithComponent(sa.factoryConfig.Logger, "audio"),
 		sa.factoryConfig.Config,
 		components.Recorder,
 		components.WhisperEngine,
@@ -101,7 +114,7 @@ func (sa *FactoryAssembler) createAudioService(components *Components) *AudioSer
 // createUIService creates the UIService
 func (sa *FactoryAssembler) createUIService(trayManager tray.Manager, notifyManager *notify.NotificationManager) *UIService {
 	return NewUIService(
-		sa.factoryConfig.Logger,
+		logger.WithComponent(sa.factoryConfig.Logger, "ui"),
 		trayManager,
 		notifyManager,
 		sa.factoryConfig.Config,
@@ -109,11 +122,28 @@ func (sa *FactoryAssembler) createUIService(trayManager tray.Manager, notifyMana
 }
 
 // createIOService creates the IOService
-func (sa *FactoryAssembler) createIOService(outputManager outputInterfaces.Outputter, webSocketServer *websocket.WebSocketServer) *IOService {
+func (sa *FactoryAssembler) createIOService(
+	outputManager outputInterfaces.Outputter,
+	webhook *outputters.WebhookWorker,
+	webSocketServer *websocket.WebSocketServer,
+) *IOService {
 	return NewIOService(
-		sa.factoryConfig.Logger,
+		logger.WithComponent(sa.factoryConfig.Logger, "output"),
 		sa.factoryConfig.Config,
 		outputManager,
+		webhook,
 		webSocketServer,
 	)
 }
+
+// createHistoryStore creates the transcript history store in the data directory.
+// Limits are read from the live config; history.enabled is checked on every write
+func (sa *FactoryAssembler) createHistoryStore() *history.Store {
+	path, err := history.DefaultPath()
+	if err != nil {
+		sa.factoryConfig.Logger.Warning("Transcript history unavailable: %v", err)
+		return nil
+	}
+	live := sa.factoryConfig.Config
+	return history.NewStore(path, func() history.Limits { return history.LimitsFromConfig(live.Get()) })
+}
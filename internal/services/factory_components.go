@@ -13,6 +13,9 @@ import (
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/hotkeys/adapters"
 	"github.com/AshBuk/dabri/hotkeys/manager"
+	"github.com/AshBuk/dabri/internal/activewindow"
+	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
 	"github.com/AshBuk/dabri/internal/notify"
 	"github.com/AshBuk/dabri/internal/tray"
 	outputFactory "github.com/AshBuk/dabri/output/factory"
@@ -40,6 +43,9 @@ func NewFactoryComponents(config ServiceFactoryConfig) *FactoryComponents {
 //  3. HotkeyManager, WebSocketServer, TrayManager, NotifyManager (UI/control)
 func (cf *FactoryComponents) InitializeComponents() (*Components, error) {
 	components := &Components{}
+	// Components rebuilt on config changes (recorder, engine, outputter, hotkeys)
+	// are built from a snapshot; long-lived ones read the live config
+	cfg := cf.config.Config.Get()
 	// Initialize model manager
 	components.ModelManager = whisper.NewModelManager(cf.config.Config)
 	if err := components.ModelManager.Initialize(cf.config.Ctx); err != nil {
@@ -56,19 +62,20 @@ func (cf *FactoryComponents) InitializeComponents() (*Components, error) {
 	}
 	cf.config.Logger.Info("Model path resolved: %s", modelFilePath)
 	// Initialize temp file manager
-	cleanupTimeout := time.Duration(cf.config.Config.Audio.TempFileCleanupTime) * time.Minute
+	cleanupTimeout := time.Duration(cfg.Audio.TempFileCleanupTime) * time.Minute
 	if cleanupTimeout <= 0 {
 		cleanupTimeout = 30 * time.Minute
 	}
-	components.TempFileManager = processing.NewTempFileManager(cleanupTimeout, cf.config.Logger)
+	audioLogger := logger.WithComponent(cf.config.Logger, "audio")
+	components.TempFileManager = processing.NewTempFileManager(cleanupTimeout, audioLogger)
 	components.TempFileManager.Start()
 	// Initialize audio recorder
-	components.Recorder, err = factory.GetRecorder(cf.config.Config, cf.config.Logger, components.TempFileManager)
+	components.Recorder, err = factory.GetRecorder(cfg, audioLogger, components.TempFileManager)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize audio recorder: %w", err)
 	}
 	// Initialize whisper engine
-	components.WhisperEngine, err = whisper.NewWhisperEngine(cf.config.Config, modelFilePath, cf.config.Logger)
+	components.WhisperEngine, err = whisper.NewWhisperEngine(cfg, modelFilePath, logger.WithComponent(cf.config.Logger, "whisper"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize whisper engine: %w", err)
 	}
@@ -76,7 +83,8 @@ func (cf *FactoryComponents) InitializeComponents() (*Components, error) {
 	// If typing fails fallback to clipboard only
 	// platform.EnvironmentType is aliased across output/hotkeys packages — no conversion needed
 	outputEnv := cf.config.Environment
-	components.OutputManager, err = outputFactory.GetOutputterFromConfig(cf.config.Config, outputEnv)
+	components.WebhookWorker = cf.createWebhookWorker()
+	components.OutputManager, err = outputFactory.GetOutputterFromConfig(cfg, outputEnv, components.WebhookWorker)
 	if err != nil {
 		cf.config.Logger.Warning("Failed to initialize text outputter: %v", err)
 		if fallbackOut := cf.createFallbackOutputManager(outputEnv); fallbackOut != nil {
@@ -85,8 +93,10 @@ func (cf *FactoryComponents) InitializeComponents() (*Components, error) {
 			return nil, fmt.Errorf("failed to initialize any output manager")
 		}
 	}
+	// Initialize focused window detector (used only when profiles are configured)
+	components.WindowDetector = activewindow.NewDetector(cf.config.Config, cf.config.Environment)
 	// Initialize hotkey manager
-	components.HotkeyManager = cf.createHotkeyManager()
+	components.HotkeyManager = cf.createHotkeyManager(cfg)
 	// Initialize WebSocket server (always initialized but may not be started).
 	// AudioController is wired in Stage 2 by FactoryAssembler once AudioService exists.
 	components.WebSocketServer = cf.createWebSocketServer()
@@ -95,7 +105,7 @@ func (cf *FactoryComponents) InitializeComponents() (*Components, error) {
 	// Start tray manager (no-op in mock). Ensures systray is initialized early.
 	if components.TrayManager != nil {
 		components.TrayManager.Start()
-		components.TrayManager.UpdateSettings(cf.config.Config)
+		components.TrayManager.UpdateSettings(cf.config.Config.Get())
 	}
 	// Initialize notification manager
 	components.NotifyManager = notify.NewNotificationManager("Dabri", cf.config.Config)
@@ -114,6 +124,16 @@ func (cf *FactoryComponents) ensureModelAvailable(modelManager whisper.ModelMana
 	return nil
 }
 
+// createWebhookWorker creates the webhook delivery worker
+// Delivery finishes after the dictation; failures are only logged
+func (cf *FactoryComponents) createWebhookWorker() *outputters.WebhookWorker {
+	outputLogger := logger.WithComponent(cf.config.Logger, "output")
+	return outputters.NewWebhookWorker(func(err error) {
+		outputLogger.Warning("Webhook delivery failed: %v", err)
+		metrics.OutputFailures.Inc(config.OutputModeWebhook, "webhook")
+	})
+}
+
 // createFallbackOutputManager creates fallback clipboard-only output manager
 func (cf *FactoryComponents) createFallbackOutputManager(outputEnv outputFactory.EnvironmentType) outputInterfaces.Outputter {
 	clipboardTool := ""
@@ -129,36 +149,42 @@ func (cf *FactoryComponents) createFallbackOutputManager(outputEnv outputFactory
 	}
 	if clipboardTool != "" {
 		cf.config.Logger.Info("Falling back to clipboard output using %s", clipboardTool)
-		oldMode := cf.config.Config.Output.DefaultMode
-		cf.config.Config.Output.DefaultMode = config.OutputModeClipboard
-		cf.config.Config.Output.ClipboardTool = clipboardTool
-
-		if out, err := outputters.NewClipboardOutputter(clipboardTool, cf.config.Config); err == nil {
+		var out outputInterfaces.Outputter
+		// The running config switches to clipboard mode only if the fallback works
+		_, _, err := cf.config.Config.Update(func(next *config.Config) error {
+			next.Output.DefaultMode = config.OutputModeClipboard
+			next.Output.ClipboardTool = clipboardTool
+			var err error
+			out, err = outputters.NewClipboardOutputter(clipboardTool, next)
+			return err
+		})
+		if err == nil {
 			return out
 		}
-		// Restore original mode if fallback failed
-		cf.config.Config.Output.DefaultMode = oldMode
 	}
 	return nil
 }
 
 // createHotkeyManager creates and configures hotkey manager
-func (cf *FactoryComponents) createHotkeyManager() *manager.HotkeyManager {
-	configAdapter := adapters.NewConfigAdapter(cf.config.Config.Hotkeys.StartRecording, cf.config.Config.Hotkeys.Provider).
+func (cf *FactoryComponents) createHotkeyManager(cfg *config.Config) *manager.HotkeyManager {
+	configAdapter := adapters.NewConfigAdapter(cfg.Hotkeys.StartRecording, cfg.Hotkeys.Provider).
 		WithAdditionalHotkeys(
-			cf.config.Config.Hotkeys.ShowConfig,
-			cf.config.Config.Hotkeys.ResetToDefaults,
-		)
-	return manager.NewHotkeyManager(configAdapter, cf.config.Environment, cf.config.Logger)
+			cfg.Hotkeys.ShowConfig,
+			cfg.Hotkeys.ResetToDefaults,
+		).
+		WithCodeRecordingHotkey(cfg.Hotkeys.CodeRecording).
+		WithUndoHotkey(cfg.Hotkeys.Undo).
+		WithCancelHotkey(cfg.Hotkeys.Cancel)
+	return manager.NewHotkeyManager(configAdapter, cf.config.Environment, logger.WithComponent(cf.config.Logger, "hotkeys"))
 }
 
 // createWebSocketServer creates WebSocket server
 func (cf *FactoryComponents) createWebSocketServer() *websocket.WebSocketServer {
-	return websocket.NewWebSocketServer(cf.config.Config, cf.config.Logger)
+	return websocket.NewWebSocketServer(cf.config.Config, logger.WithComponent(cf.config.Logger, "ws"))
 }
 
 // createTrayManager creates system tray manager.
 // Callbacks are wired later in Stage 3 (FactoryWirer).
 func (cf *FactoryComponents) createTrayManager() tray.Manager {
-	return tray.CreateTrayManagerWithConfig(cf.config.Config, cf.config.Logger)
+	return tray.CreateTrayManagerWithConfig(cf.config.Config.Get(), logger.WithComponent(cf.config.Logger, "ui"))
 }
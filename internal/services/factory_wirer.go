@@ -7,12 +7,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"syscall"
 
 	"github.com/AshBuk/dabri/config"
-	"github.com/AshBuk/dabri/hotkeys/adapters"
+	"github.com/AshBuk/dabri/internal/history"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/tray"
 )
 
 // FactoryWirer wires tray menu callbacks to service business logic
@@ -22,10 +22,12 @@ import (
 // Callback wiring (UI → Services):
 //
 //	Core Actions      → AudioService, UIService, ConfigService (toggle, config, reset)
+//	Cancel Action     → AudioService, HotkeyService (discard recording)
 //	Audio Actions     → ConfigService, AudioService (recorder selection)
 //	Settings Actions  → ConfigService, UIService, IOService (language, notifications, output)
 //	Dynamic Callbacks → IOService, HotkeyService (output tools, capture support)
 //	Hotkey Rebinding  → All Services (UI, Config, Hotkeys, Audio)
+//	History Actions   → History store, IOService (recent transcripts)
 //	Exit Action       → System Signal (SIGTERM)
 type FactoryWirer struct {
 	logger logger.Logger
@@ -37,13 +39,14 @@ func NewFactoryWirer(logger logger.Logger) *FactoryWirer {
 
 // Wire connects tray menu callbacks to services via closure pattern
 // Wiring process:
-//  1. Core actions     (toggle recording, show config/about, reset defaults)
+//  1. Core actions     (toggle/cancel recording, show config/about, reset defaults)
 //  2. Audio actions    (recorder selection)
 //  3. Settings actions (language, notifications, output mode)
 //  4. Dynamic queries  (output tools, capture once support)
 //  5. UI sync          (update tray menu with current settings)
 //  6. Hotkey rebinding (rebind hotkeys dynamically)
-//  7. Exit handler     (clean shutdown via SIGTERM)
+//  7. History actions  (recent transcripts submenu)
+//  8. Exit handler     (clean shutdown via SIGTERM)
 //
 // Each callback is a closure capturing ServiceContainer for service access
 func (cw *FactoryWirer) Wire(container *ServiceContainer, components *Components) {
@@ -57,6 +60,8 @@ func (cw *FactoryWirer) Wire(container *ServiceContainer, components *Components
 		cw.makeShowAboutCallback(container),
 		cw.makeResetToDefaultsCallback(container),
 	)
+	// Step 1b: Cancel recording (shown while recording)
+	components.TrayManager.SetCancelAction(cw.makeCancelCallback(container))
 	// Step 2: Audio actions (recorder selection)
 	components.TrayManager.SetAudioActions(cw.makeRecorderSelectionCallback(container))
 	// Step 2b: Model selection
@@ -74,7 +79,12 @@ func (cw *FactoryWirer) Wire(container *ServiceContainer, components *Components
 	cw.updateTraySettings(container, components.TrayManager)
 	// Step 6: Hotkey rebinding (rebind hotkeys dynamically)
 	components.TrayManager.SetHotkeyRebindAction(cw.makeHotkeyRebindCallback(container))
-	// Step 7: Exit handler (clean shutdown via SIGTERM)
+	// Step 7: History actions (recent transcripts submenu)
+	components.TrayManager.SetHistoryActions(
+		cw.makeListRecentCallback(container),
+		cw.makeSelectRecentCallback(container),
+	)
+	// Step 8: Exit handler (clean shutdown via SIGTERM)
 	components.TrayManager.SetExitAction(func() {
 		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
 	})
@@ -92,6 +102,21 @@ func (cw *FactoryWirer) makeToggleCallback(container *ServiceContainer) func() e
 		return container.Audio.HandleStartRecording()
 	}
 }
+func (cw *FactoryWirer) makeCancelCallback(container *ServiceContainer) func() error {
+	return func() error {
+		if container == nil || container.Audio == nil {
+			return fmt.Errorf("audio service not available")
+		}
+		if err := container.Audio.CancelRecording(); err != nil {
+			return err
+		}
+		// Keep the start/stop hotkey toggle in sync with the discarded recording
+		if container.Hotkeys != nil {
+			container.Hotkeys.ResetRecordingState()
+		}
+		return nil
+	}
+}
 func (cw *FactoryWirer) makeShowConfigCallback(container *ServiceContainer) func() error {
 	return func() error {
 		if container == nil || container.UI == nil {
@@ -110,58 +135,32 @@ func (cw *FactoryWirer) makeShowAboutCallback(container *ServiceContainer) func(
 }
 func (cw *FactoryWirer) makeResetToDefaultsCallback(container *ServiceContainer) func() error {
 	return func() error {
-		if container == nil || container.Config == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("config service not available")
 		}
-		if err := container.Config.ResetToDefaults(); err != nil {
-			return err
-		}
-		return cw.reloadHotkeysFromConfig(container)
+		return container.Actions.ResetToDefaults()
 	}
 }
 func (cw *FactoryWirer) makeRecorderSelectionCallback(container *ServiceContainer) func(string) error {
 	return func(method string) error {
-		if container == nil || container.Config == nil || container.Audio == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("services not available")
 		}
-		if err := container.Config.UpdateRecordingMethod(method); err != nil {
-			return err
-		}
-		container.Audio.ClearSession()
-		cw.updateUISettings(container)
-		return nil
+		return container.Actions.SetRecordingMethod(method)
 	}
 }
 func (cw *FactoryWirer) makeModelSelectionCallback(container *ServiceContainer) func(context.Context, string) error {
 	return func(ctx context.Context, modelID string) error {
-		if container == nil || container.Audio == nil || container.Config == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("services not available")
 		}
-		// Remember previous model so we can rollback on persist failure
-		previousModel := ""
-		if cfg := container.Config.GetConfig(); cfg != nil {
-			previousModel = cfg.General.WhisperModel
-		}
-		if err := container.Audio.SwitchModel(ctx, modelID); err != nil {
+		if err := container.Actions.SwitchModel(ctx, modelID); err != nil {
 			// Don't show error notification on user-initiated cancellation
-			if ctx.Err() != nil {
-				return err
-			}
-			if container.UI != nil {
+			if ctx.Err() == nil && container.UI != nil {
 				container.UI.ShowNotification("Model Switch Failed", err.Error())
 			}
 			return err
 		}
-		if err := container.Config.UpdateWhisperModel(modelID); err != nil {
-			// Rollback engine to previous model so runtime state matches config
-			if previousModel != "" {
-				if rbErr := container.Audio.SwitchModel(ctx, previousModel); rbErr != nil {
-					cw.logger.Error("Failed to rollback model after config save error: %v", rbErr)
-				}
-			}
-			return err
-		}
-		cw.updateUISettings(container)
 		if container.UI != nil {
 			container.UI.ShowNotification("Model Switch", fmt.Sprintf("Switched to %s", modelID))
 		}
@@ -170,40 +169,37 @@ func (cw *FactoryWirer) makeModelSelectionCallback(container *ServiceContainer)
 }
 func (cw *FactoryWirer) makeLanguageCallback(container *ServiceContainer) func(string) error {
 	return func(language string) error {
-		if container == nil || container.Config == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("config service not available")
 		}
-		return container.Config.UpdateLanguage(language)
+		return container.Actions.SetLanguage(language)
 	}
 }
 func (cw *FactoryWirer) makeToggleNotificationsCallback(container *ServiceContainer) func() error {
 	return func() error {
-		if container == nil || container.Config == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("config service not available")
 		}
-		if err := container.Config.ToggleWorkflowNotifications(); err != nil {
+		enabled, err := container.Actions.ToggleWorkflowNotifications()
+		if err != nil {
 			return err
 		}
 		if container.UI != nil {
-			enabled := cw.getNotificationStatus(container)
-			container.UI.ShowNotification("Workflow Notifications", "Now "+enabled)
+			state := "disabled"
+			if enabled {
+				state = "enabled"
+			}
+			container.UI.ShowNotification("Workflow Notifications", "Now "+state)
 		}
 		return nil
 	}
 }
 func (cw *FactoryWirer) makeOutputModeCallback(container *ServiceContainer) func(string) error {
 	return func(mode string) error {
-		if container == nil || container.Config == nil || container.IO == nil {
+		if container == nil || container.Actions == nil {
 			return fmt.Errorf("services not available")
 		}
-		if err := container.Config.UpdateOutputMode(mode); err != nil {
-			return err
-		}
-		if err := container.IO.SetOutputMethod(mode); err != nil {
-			return err
-		}
-		cw.updateUISettings(container)
-		return nil
+		return container.Actions.SetOutputMode(mode)
 	}
 }
 func (cw *FactoryWirer) makeGetOutputToolsCallback(container *ServiceContainer) func() (string, string) {
@@ -224,66 +220,66 @@ func (cw *FactoryWirer) makeCaptureOnceSupportCallback(container *ServiceContain
 }
 func (cw *FactoryWirer) makeHotkeyRebindCallback(container *ServiceContainer) func(string) error {
 	return func(action string) error {
-		if container == nil || container.UI == nil || container.Config == nil || container.Hotkeys == nil {
+		if container == nil || container.UI == nil || container.Actions == nil {
 			return fmt.Errorf("services not available")
 		}
 
 		container.UI.ShowNotification("Rebind Hotkey", "Press new hotkey… (Esc to cancel)")
-		combo, err := container.Hotkeys.CaptureOnce(3000)
-		if err != nil || strings.TrimSpace(combo) == "" {
+		combo, err := container.Actions.CaptureHotkey(3000)
+		if err != nil {
+			container.UI.ShowNotification("Rebind Hotkey", err.Error())
+			return err
+		}
+		if combo == "" {
 			container.UI.ShowNotification("Rebind Hotkey", "Cancelled or timeout")
 			return nil
 		}
-		if err := container.Config.UpdateHotkey(action, combo); err != nil {
-			return err
-		}
-		if err := cw.reloadHotkeysFromConfig(container); err != nil {
+		if err := container.Actions.BindHotkey(action, combo); err != nil {
 			return err
 		}
 		container.UI.ShowNotification("Hotkey Updated", fmt.Sprintf("%s -> %s", action, combo))
 		return nil
 	}
 }
-
-// Helper methods - shared logic for callbacks
-//   - reloadHotkeysFromConfig: reload hotkeys from config (used by reset + rebind)
-//   - makeConfigProvider: adapt Config → HotkeyConfig
-//   - updateUISettings: sync UI with config changes
-//   - updateTraySettings: sync tray menu with config changes
-//   - getNotificationStatus: query notification state from config
-func (cw *FactoryWirer) reloadHotkeysFromConfig(container *ServiceContainer) error {
-	if container.Hotkeys == nil {
-		return nil
-	}
-
-	cfgProvider := cw.makeConfigProvider(container)
-	return container.Hotkeys.ReloadFromConfig(
-		func() error { return container.Audio.HandleStartRecording() },
-		func() error { return container.Audio.HandleStopRecording() },
-		cfgProvider,
-	)
-}
-func (cw *FactoryWirer) makeConfigProvider(container *ServiceContainer) func() adapters.HotkeyConfig {
-	return func() adapters.HotkeyConfig { // Adapter Pattern - converts Config → HotkeyConfig
-		if container.Config == nil {
-			return adapters.NewConfigAdapter("", "auto") // Graceful Degradation - return default
+func (cw *FactoryWirer) makeListRecentCallback(container *ServiceContainer) func() []tray.RecentItem {
+	return func() []tray.RecentItem {
+		if container == nil || container.History == nil {
+			return nil
+		}
+		entries, err := container.History.List(10)
+		if err != nil {
+			cw.logger.Warning("Failed to read transcript history: %v", err)
+			return nil
 		}
-		cfg := container.Config.GetConfig()
-		if cfg == nil {
-			return adapters.NewConfigAdapter("", "auto")
+		items := make([]tray.RecentItem, 0, len(entries))
+		for _, e := range entries {
+			items = append(items, tray.RecentItem{ID: e.ID, Label: history.Preview(e.Text, 40)})
 		}
-		return adapters.NewConfigAdapter(cfg.Hotkeys.StartRecording, cfg.Hotkeys.Provider).
-			WithAdditionalHotkeys(cfg.Hotkeys.ShowConfig, cfg.Hotkeys.ResetToDefaults)
+		return items
 	}
 }
-func (cw *FactoryWirer) updateUISettings(container *ServiceContainer) {
-	if container.UI == nil || container.Config == nil {
-		return
-	}
-	if cfg := container.Config.GetConfig(); cfg != nil {
-		container.UI.UpdateSettings(cfg)
+func (cw *FactoryWirer) makeSelectRecentCallback(container *ServiceContainer) func(int64) error {
+	return func(id int64) error {
+		if container == nil || container.History == nil || container.IO == nil {
+			return fmt.Errorf("services not available")
+		}
+		entry, err := container.History.Get(id)
+		if err != nil {
+			return err
+		}
+		// Clicking the tray moves focus away from the target window, so copy instead of typing
+		if err := container.IO.OutputToMode(entry.Text, config.OutputModeClipboard); err != nil {
+			return err
+		}
+		if container.UI != nil {
+			container.UI.ShowNotification("Transcript Copied", history.Preview(entry.Text, 80))
+		}
+		return nil
 	}
 }
+
+// Helper methods
+//   - updateTraySettings: sync tray menu with config changes
 func (cw *FactoryWirer) updateTraySettings(container *ServiceContainer, trayManager interface{ UpdateSettings(*config.Config) }) {
 	if container == nil || container.Config == nil {
 		return
@@ -292,13 +288,3 @@ func (cw *FactoryWirer) updateTraySettings(container *ServiceContainer, trayMana
 		trayManager.UpdateSettings(cfg)
 	}
 }
-func (cw *FactoryWirer) getNotificationStatus(container *ServiceContainer) string {
-	if container.Config == nil {
-		return "disabled"
-	}
-	cfg := container.Config.GetConfig()
-	if cfg != nil && cfg.Notifications.EnableWorkflowNotifications {
-		return "enabled"
-	}
-	return "disabled"
-}
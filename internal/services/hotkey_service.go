@@ -18,9 +18,14 @@ type HotkeyManager interface {
 	Stop()
 	RegisterCallbacks(startRecording, stopRecording func() error)
 	RegisterHotkeyAction(action string, callback manager.HotkeyAction)
+	RegisterOnDemandAction(action string, callback manager.HotkeyAction)
+	SetActionEnabled(action string, enabled bool) error
 	ReloadConfig(newConfig adapters.HotkeyConfig) error
 	CaptureOnce(timeout time.Duration) (string, error)
 	SupportsCaptureOnce() bool
+	ResetRecordingState()
+	SetRecordingState(recording bool)
+	WaitModifiersReleased(timeout time.Duration) bool
 }
 
 // Bridges hotkey events to application handlers
@@ -60,6 +65,35 @@ func (hs *HotkeyService) SetupHotkeyCallbacks(
 	return nil
 }
 
+// Connect an additional named action (e.g., "code_recording") to its hotkey
+func (hs *HotkeyService) RegisterAction(action string, callback func() error) error {
+	if hs.hotkeyManager == nil {
+		return fmt.Errorf("hotkey manager not available")
+	}
+	hs.hotkeyManager.RegisterHotkeyAction(action, callback)
+	return nil
+}
+
+// Connect an action whose hotkey is bound only while enabled (e.g., "cancel"
+// during a dictation), so a plain key such as Escape is not taken otherwise
+func (hs *HotkeyService) RegisterOnDemandAction(action string, callback func() error) error {
+	if hs.hotkeyManager == nil {
+		return fmt.Errorf("hotkey manager not available")
+	}
+	hs.hotkeyManager.RegisterOnDemandAction(action, callback)
+	return nil
+}
+
+// Bind or unbind the hotkey of an on-demand action
+func (hs *HotkeyService) SetActionEnabled(action string, enabled bool) {
+	if hs.hotkeyManager == nil {
+		return
+	}
+	if err := hs.hotkeyManager.SetActionEnabled(action, enabled); err != nil {
+		hs.logger.Warning("Failed to update %s hotkey: %v", action, err)
+	}
+}
+
 // Activate hotkey capture for the current session
 func (hs *HotkeyService) RegisterHotkeys() error {
 	if hs.hotkeyManager == nil {
@@ -110,6 +144,28 @@ func (hs *HotkeyService) ReloadFromConfig(startRecording, stopRecording func() e
 	return hs.hotkeyManager.ReloadConfig(cfg)
 }
 
+// Resync the start/stop hotkey toggle after recording ended outside of it (e.g., cancel)
+func (hs *HotkeyService) ResetRecordingState() {
+	if hs.hotkeyManager != nil {
+		hs.hotkeyManager.ResetRecordingState()
+	}
+}
+
+// Sync the start/stop hotkey toggle with recording started or stopped elsewhere (e.g., code_recording)
+func (hs *HotkeyService) SetRecordingState(recording bool) {
+	if hs.hotkeyManager != nil {
+		hs.hotkeyManager.SetRecordingState(recording)
+	}
+}
+
+// Wait until the hotkey's modifiers are released before an action sends keys
+func (hs *HotkeyService) WaitModifiersReleased(timeout time.Duration) bool {
+	if hs.hotkeyManager == nil {
+		return true
+	}
+	return hs.hotkeyManager.WaitModifiersReleased(timeout)
+}
+
 // Capture single keypress for hotkey rebinding workflow
 func (hs *HotkeyService) CaptureOnce(timeoutMs int) (string, error) {
 	if hs.hotkeyManager == nil {
@@ -11,16 +11,27 @@ import (
 	"github.com/AshBuk/dabri/audio/processing"
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/hotkeys/adapters"
+	"github.com/AshBuk/dabri/internal/events"
+	"github.com/AshBuk/dabri/internal/history"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
 )
 
 // AudioServiceInterface defines the contract for audio-related operations
 type AudioServiceInterface interface {
 	// HandleStartRecording begins audio capture
 	HandleStartRecording() error
+	// HandleStartRecordingWithOptions begins audio capture with per-dictation overrides
+	HandleStartRecordingWithOptions(opts config.SessionOptions) error
 	// HandleStopRecording stops audio capture and triggers transcription
 	HandleStopRecording() error
+	// HandleStopRecordingWithOptions stops audio capture, overriding the start options
+	HandleStopRecordingWithOptions(opts config.SessionOptions) error
+	// CancelRecording discards the active recording or pending transcription without output
+	CancelRecording() error
 	// IsRecording returns whether audio capture is active
 	IsRecording() bool
+	// SetActivityCallback is told when a dictation starts and when it has fully ended
+	SetActivityCallback(callback func(active bool))
 
 	// ClearSession resets the current recording session
 	ClearSession()
@@ -29,6 +40,8 @@ type AudioServiceInterface interface {
 	SwitchModel(ctx context.Context, modelID string) error
 	// DeleteModel removes a downloaded whisper model
 	DeleteModel(modelID string) error
+	// ReloadRecorder rebuilds the recorder at the next recording to apply audio settings
+	ReloadRecorder()
 
 	// GetLastTranscript returns the most recent transcription result
 	GetLastTranscript() string
@@ -45,6 +58,8 @@ type UIServiceInterface interface {
 	ShowNotification(title, message string)
 	// UpdateSettings refreshes tray menu to reflect config changes
 	UpdateSettings(config *config.Config)
+	// RefreshHistory reloads the tray "Recent" submenu after a transcript was recorded
+	RefreshHistory()
 
 	// UpdateRecordingUI updates visual feedback during recording
 	UpdateRecordingUI(isRecording bool, level float64)
@@ -66,15 +81,25 @@ type UIServiceInterface interface {
 type IOServiceInterface interface {
 	// OutputText sends transcribed text to the configured output target
 	OutputText(text string) error
+	// OutputTranscript delivers a session transcript using profile overrides and the clipboard guard
+	OutputTranscript(token uint64, t outputInterfaces.Transcript, profile *config.Profile) ([]outputInterfaces.TargetResult, error)
+	// OutputToMode delivers text in a single output mode, e.g. to re-output a history entry
+	OutputToMode(text, mode string) error
 	// SetOutputMethod switches the output method (clipboard/typing)
 	SetOutputMethod(method string) error
-
-	// BeginTranscription signals that a transcription is in progress
-	BeginTranscription()
-	// CompleteTranscription delivers the transcription result
-	CompleteTranscription(result string)
+	// ReloadOutput rebuilds the outputter from the current output settings
+	ReloadOutput() error
+	// CancelTyping aborts a transcript still being typed; reports whether one was in flight
+	CancelTyping() bool
+	// UndoLastOutput erases the last typed output or restores the clipboard it replaced
+	UndoLastOutput() (outputInterfaces.UndoResult, error)
+
+	// BeginTranscription signals that a transcription is in progress and returns a clipboard ownership token
+	BeginTranscription() uint64
+	// CompleteTranscription hands the dictation's transcript and output results to waiting readers
+	CompleteTranscription(result outputInterfaces.TranscriptionResult)
 	// WaitForTranscription blocks until a transcription completes or times out
-	WaitForTranscription(timeout time.Duration) (string, error)
+	WaitForTranscription(timeout time.Duration) (outputInterfaces.TranscriptionResult, error)
 
 	// GetOutputToolNames returns the names of the clipboard and typing tools
 	GetOutputToolNames() (clipboardTool, typeTool string)
@@ -83,6 +108,8 @@ type IOServiceInterface interface {
 	StartWebSocketServer() error
 	// StopWebSocketServer stops the WebSocket server
 	StopWebSocketServer() error
+	// SetRemoteCancelHandler routes WebSocket cancel requests through the given handler
+	SetRemoteCancelHandler(handler func() error)
 
 	// Shutdown releases IO resources
 	Shutdown() error
@@ -103,14 +130,24 @@ type ConfigServiceInterface interface {
 	UpdateLanguage(language string) error
 	// UpdateWhisperModel changes the active whisper model
 	UpdateWhisperModel(modelID string) error
+	// SetLoadedModel records the whisper model in use without saving it
+	SetLoadedModel(modelID string)
 	// ToggleWorkflowNotifications toggles workflow notification setting
 	ToggleWorkflowNotifications() error
 	// UpdateRecordingMethod changes the audio recording method
 	UpdateRecordingMethod(method string) error
 	// UpdateOutputMode changes the text output mode
 	UpdateOutputMode(mode string) error
+	// UpdatePostProcessing changes the default transcript post-processing mode
+	UpdatePostProcessing(mode string) error
 	// UpdateHotkey changes the key binding for the given action
 	UpdateHotkey(action, combo string) error
+	// SetValue changes any setting by dotted path (e.g., "audio.device")
+	SetValue(path, value string) error
+	// UnsetValue restores the default of a setting by dotted path
+	UnsetValue(path string) error
+	// Reload re-reads the config file and returns the changed settings
+	Reload() ([]string, error)
 
 	// Shutdown releases config resources
 	Shutdown() error
@@ -126,6 +163,13 @@ type HotkeyServiceInterface interface {
 		reloadConfig func() error,
 	) error
 
+	// RegisterAction connects an additional named hotkey action
+	RegisterAction(action string, callback func() error) error
+	// RegisterOnDemandAction connects a named action bound only while enabled
+	RegisterOnDemandAction(action string, callback func() error) error
+	// SetActionEnabled binds or unbinds the hotkey of an on-demand action
+	SetActionEnabled(action string, enabled bool)
+
 	// RegisterHotkeys activates hotkey capture for the current session
 	RegisterHotkeys() error
 	// UnregisterHotkeys releases hotkey capture
@@ -140,6 +184,15 @@ type HotkeyServiceInterface interface {
 	// SupportsCaptureOnce reports whether interactive hotkey binding is available
 	SupportsCaptureOnce() bool
 
+	// ResetRecordingState makes the next start/stop hotkey press start recording
+	ResetRecordingState()
+
+	// SetRecordingState makes the next start/stop hotkey press stop (true) or start (false) recording
+	SetRecordingState(recording bool)
+
+	// WaitModifiersReleased waits until no modifier key is held; false on timeout
+	WaitModifiersReleased(timeout time.Duration) bool
+
 	// Shutdown releases hotkey resources
 	Shutdown() error
 }
@@ -152,6 +205,9 @@ type ServiceContainer struct {
 	Config          ConfigServiceInterface
 	Hotkeys         HotkeyServiceInterface
 	TempFileManager *processing.TempFileManager
+	History         *history.Store   // Transcript history (nil when the data dir is unavailable)
+	Events          *events.Bus      // State changes streamed to IPC subscribers
+	Actions         *SettingsActions // Settings changes shared by the tray and IPC
 }
 
 // Create a new service container with all services
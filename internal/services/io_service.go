@@ -4,24 +4,32 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/activewindow"
 	"github.com/AshBuk/dabri/internal/logger"
 	"github.com/AshBuk/dabri/internal/platform"
 	outputFactory "github.com/AshBuk/dabri/output/factory"
 	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
 	"github.com/AshBuk/dabri/websocket"
 )
 
+// webhookCloseTimeout bounds how long shutdown waits for a webhook delivery in flight
+const webhookCloseTimeout = 5 * time.Second
+
 // Handles text output routing and transcription synchronization
 type IOService struct {
 	logger          logger.Logger
-	config          *config.Config
+	config          *config.Live // Running configuration; read a snapshot per use
 	outputManager   outputInterfaces.Outputter
 	webSocketServer *websocket.WebSocketServer
+	// Delivers webhook output in the background; closed on shutdown (nil disables webhook mode)
+	webhook *outputters.WebhookWorker
 	// Dependencies
 	ui  UIServiceInterface
 	cfg ConfigServiceInterface
@@ -29,42 +37,67 @@ type IOService struct {
 	// Transcription synchronization
 	mu                      sync.Mutex
 	transcriptionInProgress bool
-	transcriptionResultChan chan string
+	transcriptionResultChan chan outputInterfaces.TranscriptionResult
+
+	// Protects user clipboard content across async transcriptions
+	clipboardGuard *clipboardGuard
+	// Journal writer for file mode and the secondary journal target (lazy)
+	journal *outputters.FileOutputter
+	// Cancels the typing job in flight (nil when idle); typingSeq identifies the job
+	typingCancel context.CancelFunc
+	typingSeq    uint64
+	// Refocuses the window dictation started in before typing (nil disables)
+	windowDetect activewindow.Detector
+	// How to revert the most recent output (nil when there is nothing to undo)
+	undo *undoRecord
+	// newOutputter builds outputters for non-default modes; replaced in tests
+	newOutputter func(cfg *config.Config) (outputInterfaces.Outputter, error)
 }
 
 // Create a new service instance
 func NewIOService(
 	logger logger.Logger,
-	config *config.Config,
+	config *config.Live,
 	outputManager outputInterfaces.Outputter,
+	webhook *outputters.WebhookWorker,
 	webSocketServer *websocket.WebSocketServer,
 ) *IOService {
-	return &IOService{
+	restoreAfter := time.Duration(config.Get().Output.ClipboardRestoreSeconds) * time.Second
+	ios := &IOService{
 		logger:          logger,
 		config:          config,
 		outputManager:   outputManager,
 		webSocketServer: webSocketServer,
+		webhook:         webhook,
+		clipboardGuard:  newClipboardGuard(restoreAfter),
 	}
+	ios.newOutputter = ios.newEnvironmentOutputter
+	return ios
+}
+
+// newEnvironmentOutputter builds an outputter for the current display server
+func (ios *IOService) newEnvironmentOutputter(cfg *config.Config) (outputInterfaces.Outputter, error) {
+	return outputFactory.GetOutputterFromConfig(cfg, platform.DetectEnvironment(), ios.webhook)
 }
 
-// Prevent clipboard race conditions during async transcription
-func (ios *IOService) BeginTranscription() {
+// Mark a transcription in progress and snapshot the clipboard.
+// Returns the clipboard ownership token to pass to OutputTranscript
+func (ios *IOService) BeginTranscription() uint64 {
+	// Snapshot instead of clearing, so the user's clipboard is never destroyed.
+	// Without a clipboard target, or a readable clipboard, only the token advances.
+	// Building the outputter and reading the clipboard exec external tools, so
+	// they run before taking ios.mu
+	token := ios.clipboardGuard.Begin(ios.clipboardReader())
 	ios.mu.Lock()
 	defer ios.mu.Unlock()
 	ios.transcriptionInProgress = true
 	// Reset result channel for a new transcription session
-	ios.transcriptionResultChan = make(chan string, 1)
-	// Clear clipboard to prevent race condition with old content
-	// Only when clipboard mode is active to avoid unnecessary side-effects
-	if ios.outputManager != nil && ios.config.Output.DefaultMode == "clipboard" {
-		if err := ios.outputManager.CopyToClipboard(""); err != nil {
-			ios.logger.Debug("Failed to clear clipboard: %v", err)
-		}
-	}
+	ios.transcriptionResultChan = make(chan outputInterfaces.TranscriptionResult, 1)
+	return token
 }
 
-// Release clipboard protection and notify waiting readers
-func (ios *IOService) CompleteTranscription(result string) {
+// Release clipboard protection and hand this dictation's result to waiting readers
+func (ios *IOService) CompleteTranscription(result outputInterfaces.TranscriptionResult) {
 	ios.mu.Lock()
 	inProgress := ios.transcriptionInProgress
 	ch := ios.transcriptionResultChan
@@ -80,13 +113,13 @@ func (ios *IOService) CompleteTranscription(result string) {
 }
 
 // Block until transcription completes or timeout for synchronized reads
-func (ios *IOService) WaitForTranscription(timeout time.Duration) (string, error) {
+func (ios *IOService) WaitForTranscription(timeout time.Duration) (outputInterfaces.TranscriptionResult, error) {
 	ios.mu.Lock()
 	inProgress := ios.transcriptionInProgress
 	ch := ios.transcriptionResultChan
 	ios.mu.Unlock()
 	if !inProgress || ch == nil {
-		return "", nil
+		return outputInterfaces.TranscriptionResult{}, nil
 	}
 	select {
 	case res := <-ch:
@@ -96,33 +129,94 @@ func (ios *IOService) WaitForTranscription(timeout time.Duration) (string, error
 		ios.mu.Lock()
 		ios.transcriptionInProgress = false
 		ios.mu.Unlock()
-		return "", fmt.Errorf("timeout waiting for transcription result")
+		return outputInterfaces.TranscriptionResult{}, fmt.Errorf("timeout waiting for transcription result")
 	}
 }
 
-// Route text to configured output method with fallback strategy
+// Route text to the configured output targets. Fallbacks apply to this
+// output only and are never persisted
 func (ios *IOService) OutputText(text string) error {
-	if ios.outputManager == nil {
-		return fmt.Errorf("output manager not available")
-	}
 	ios.logger.Info("Outputting text: %s", text)
-	switch ios.config.Output.DefaultMode {
-	case "clipboard":
-		if err := ios.outputManager.CopyToClipboard(text); err != nil {
-			return ios.fallbackToTyping(text, err)
-		}
-		ios.logger.Debug("Successfully copied text to clipboard")
-	case "active_window":
-		if err := ios.outputManager.TypeToActiveWindow(text); err != nil {
-			return ios.fallbackToClipboard(text, err)
+	_, err := ios.deliverTranscript(0, outputInterfaces.Transcript{Text: text}, nil)
+	return err
+}
+
+// Deliver a session transcript honoring profile overrides and clipboard ownership.
+// A transcript is not copied if a newer session started or the user copied
+// something else meanwhile. Token 0 bypasses the guard. Returns per-target results
+func (ios *IOService) OutputTranscript(token uint64, t outputInterfaces.Transcript, profile *config.Profile) ([]outputInterfaces.TargetResult, error) {
+	return ios.deliverTranscript(token, t, profile)
+}
+
+// Deliver API transcripts to the output targets and report per-target results.
+// A non-empty mode replaces the configured targets, as a profile output_mode does
+func (ios *IOService) DeliverTranscript(text, mode string) ([]outputInterfaces.TargetResult, error) {
+	var profile *config.Profile
+	if mode != "" {
+		profile = &config.Profile{OutputMode: mode}
+	}
+	return ios.deliverTranscript(0, outputInterfaces.Transcript{Text: text}, profile)
+}
+
+// Deliver text in a single output mode without fallback, e.g. to re-output a
+// history entry
+func (ios *IOService) OutputToMode(text, mode string) error {
+	ios.resetUndo()
+	t := ios.completeTranscript(outputInterfaces.Transcript{Text: text}, nil)
+	_, err := ios.deliverToMode(0, t, nil, mode)
+	return err
+}
+
+// Abort a transcript still being typed, e.g. when a new hotkey is pressed.
+// Reports whether a typing job was in flight
+func (ios *IOService) CancelTyping() bool {
+	ios.mu.Lock()
+	defer ios.mu.Unlock()
+	if ios.typingCancel == nil {
+		return false
+	}
+	ios.typingCancel()
+	ios.typingCancel = nil
+	return true
+}
+
+// beginTyping registers a cancelable typing job. The returned func must be
+// called when typing ends
+func (ios *IOService) beginTyping() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ios.mu.Lock()
+	ios.typingSeq++
+	seq := ios.typingSeq
+	ios.typingCancel = cancel
+	ios.mu.Unlock()
+	return ctx, func() {
+		cancel()
+		ios.mu.Lock()
+		if ios.typingSeq == seq {
+			ios.typingCancel = nil
 		}
-		ios.logger.Debug("Successfully typed text to active window")
-	default:
-		ios.logger.Warning("Unknown output mode '%s', using typing with clipboard fallback", ios.config.Output.DefaultMode)
-		if err := ios.outputManager.TypeToActiveWindow(text); err != nil {
-			return ios.fallbackToClipboard(text, err)
+		ios.mu.Unlock()
+	}
+}
+
+// appendJournal writes the transcript with its metadata to the journal file
+func (ios *IOService) appendJournal(t outputInterfaces.Transcript) error {
+	ios.mu.Lock()
+	if ios.journal == nil {
+		out, err := outputters.NewFileOutputter(ios.config.Get())
+		if err != nil {
+			ios.mu.Unlock()
+			return err
 		}
+		ios.journal = out.(*outputters.FileOutputter)
+	}
+	journal := ios.journal
+	ios.mu.Unlock()
+
+	if err := journal.OutputTranscript(t); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
 	}
+	ios.logger.Debug("Successfully appended text to journal")
 	return nil
 }
 
@@ -154,32 +248,52 @@ func (ios *IOService) OutputByTyping(text string) error {
 func (ios *IOService) SetOutputMethod(method string) error {
 	ios.logger.Info("Setting output method to: %s", method)
 	// Validate method
-	if method != "clipboard" && method != "active_window" {
-		return fmt.Errorf("invalid output method: %s (must be 'clipboard' or 'active_window')", method)
+	switch method {
+	case config.OutputModeClipboard, config.OutputModeActiveWindow, config.OutputModePaste, config.OutputModeFile, config.OutputModeWebhook:
+	default:
+		return fmt.Errorf("invalid output method: %s (must be 'clipboard', 'active_window', 'paste', 'file' or 'webhook')", method)
+	}
+	if method == config.OutputModeWebhook && ios.config.Get().Output.Webhook.URL == "" {
+		return fmt.Errorf("webhook output requires output.webhook.url to be configured")
 	}
 	// Persist via ConfigService if available
 	if ios.cfg != nil {
 		if err := ios.cfg.UpdateOutputMode(method); err != nil {
 			return err
 		}
-	} else {
-		ios.config.Output.DefaultMode = method
+	} else if _, _, err := ios.config.Update(func(next *config.Config) error {
+		next.Output.DefaultMode = method
+		return nil
+	}); err != nil {
+		return err
 	}
 	// Recreate output manager immediately to reflect new mode
-	env := ios.detectOutputEnvironment()
-	out, err := outputFactory.GetOutputterFromConfig(ios.config, env)
-	if err != nil {
-		return fmt.Errorf("failed to reinitialize output manager: %w", err)
+	if err := ios.ReloadOutput(); err != nil {
+		return err
 	}
-	ios.outputManager = out
 	// Notify UI to refresh settings display
 	if ios.ui != nil {
-		ios.ui.UpdateSettings(ios.config)
+		ios.ui.UpdateSettings(ios.config.Get())
 	}
 	ios.logger.Info("Output method set to: %s", method)
 	return nil
 }
 
+// Recreate the output manager so changed output settings (tools, delays, mode) apply
+func (ios *IOService) ReloadOutput() error {
+	env := ios.detectOutputEnvironment()
+	out, err := outputFactory.GetOutputterFromConfig(ios.config.Get(), env, ios.webhook)
+	if err != nil {
+		return fmt.Errorf("failed to reinitialize output manager: %w", err)
+	}
+	ios.outputManager = out
+	// The journal is rebuilt from the new settings on its next write
+	ios.mu.Lock()
+	ios.journal = nil
+	ios.mu.Unlock()
+	return nil
+}
+
 // Enable external client communication channel
 func (ios *IOService) StartWebSocketServer() error {
 	if ios.webSocketServer == nil {
@@ -201,6 +315,14 @@ func (ios *IOService) StopWebSocketServer() error {
 	return nil
 }
 
+// Route WebSocket cancel requests through an app-level handler, which also
+// resyncs the start/stop hotkey toggle
+func (ios *IOService) SetRemoteCancelHandler(handler func() error) {
+	if ios.webSocketServer != nil {
+		ios.webSocketServer.SetCancelHandler(handler)
+	}
+}
+
 // Report active clipboard/typing tool implementations for debugging
 func (ios *IOService) GetOutputToolNames() (clipboardTool, typeTool string) {
 	if ios.outputManager == nil {
@@ -217,6 +339,14 @@ func (ios *IOService) Shutdown() error {
 		ios.logger.Error("Error stopping WebSocket server: %v", err)
 		lastErr = err
 	}
+	// Undelivered webhook transcripts stay queued on disk for the next start
+	if ios.webhook != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookCloseTimeout)
+		defer cancel()
+		if err := ios.webhook.Close(ctx); err != nil {
+			ios.logger.Warning("Webhook delivery interrupted: %v", err)
+		}
+	}
 	ios.logger.Info("IOService shutdown complete")
 	return lastErr
 }
@@ -233,59 +363,5 @@ func (ios *IOService) SetUIService(ui UIServiceInterface) { ios.ui = ui }
 // Wire config service for persistent setting changes
 func (ios *IOService) SetConfigService(cfg ConfigServiceInterface) { ios.cfg = cfg }
 
-// fallbackToTyping switches to typing mode and executes the output
-func (ios *IOService) fallbackToTyping(text string, originalErr error) error {
-	ios.logger.Warning("Clipboard method failed: %v. Switching to active_window mode.", originalErr)
-	if err := ios.switchOutputMode(config.OutputModeActiveWindow, "clipboard failed"); err != nil {
-		return fmt.Errorf("failed to switch output mode: %w", err)
-	}
-	if err := ios.outputManager.TypeToActiveWindow(text); err != nil {
-		return fmt.Errorf("both clipboard and typing failed - clipboard: %w, typing: %v", originalErr, err)
-	}
-	return nil
-}
-
-// fallbackToClipboard switches to clipboard mode and executes the output
-func (ios *IOService) fallbackToClipboard(text string, originalErr error) error {
-	ios.logger.Warning("Active window method failed: %v. Switching to clipboard mode.", originalErr)
-	if err := ios.switchOutputMode(config.OutputModeClipboard, "typing failed"); err != nil {
-		return fmt.Errorf("failed to switch output mode: %w", err)
-	}
-	if err := ios.outputManager.CopyToClipboard(text); err != nil {
-		return fmt.Errorf("both typing and clipboard failed - typing: %w, clipboard: %v", originalErr, err)
-	}
-	ios.logger.Debug("Successfully copied text to clipboard (after switch)")
-	return nil
-}
-
-// switchOutputMode switches the output mode and persists it in the config
-func (ios *IOService) switchOutputMode(mode string, reason string) error {
-	ios.logger.Warning("Switching output mode to '%s' due to '%s'", mode, reason)
-	// Update and persist config if service is available
-	if ios.cfg != nil {
-		if err := ios.cfg.UpdateOutputMode(mode); err != nil {
-			ios.logger.Error("Failed to persist output mode: %v", err)
-			return fmt.Errorf("failed to persist output mode: %w", err)
-		}
-	} else {
-		old := ios.config.Output.DefaultMode
-		ios.config.Output.DefaultMode = mode
-		ios.logger.Warning("ConfigService not available; switched output mode in memory from %s to %s", old, mode)
-	}
-	// Recreate output manager for the new mode based on current environment
-	env := ios.detectOutputEnvironment()
-	out, err := outputFactory.GetOutputterFromConfig(ios.config, env)
-	if err != nil {
-		ios.logger.Error("Failed to reinitialize output manager after mode switch: %v", err)
-		return fmt.Errorf("failed to reinitialize output manager after mode switch: %w", err)
-	}
-	ios.outputManager = out
-	// Notify user
-	if ios.ui != nil {
-		msg := fmt.Sprintf("Switched to %s due to %s", mode, reason)
-		ios.ui.ShowNotification("Output Fallback", msg)
-		ios.ui.UpdateSettings(ios.config)
-	}
-	ios.logger.Info("Output mode switched to '%s' and persisted", mode)
-	return nil
-}
+// Wire the window backend used to refocus the window dictation started in
+func (ios *IOService) SetWindowDetector(detector activewindow.Detector) { ios.windowDetect = detector }
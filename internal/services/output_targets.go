@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/activewindow"
+	"github.com/AshBuk/dabri/internal/metrics"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+)
+
+// Multi-target output fan-out.
+//
+// A transcript is delivered to an ordered list of targets (output.targets).
+// Each target has its own failure policy and an optional fallback mode that
+// is used for the current dictation only; the configured mode is never
+// rewritten. Without output.targets the default_mode becomes a single
+// target that falls back between typing and the clipboard.
+
+// resolveOutputTargets returns the ordered targets for a dictation.
+// A profile output_mode replaces the configured target list
+func (ios *IOService) resolveOutputTargets(profile *config.Profile) []config.OutputTarget {
+	cfg := ios.config.Get()
+	var targets []config.OutputTarget
+	switch {
+	case profile != nil && profile.OutputMode != "":
+		targets = []config.OutputTarget{defaultTarget(profile.OutputMode)}
+	case len(cfg.Output.Targets) > 0:
+		targets = slices.Clone(cfg.Output.Targets)
+	default:
+		targets = []config.OutputTarget{defaultTarget(cfg.Output.DefaultMode)}
+	}
+	// The journal secondary target never blocks the others
+	hasFile := slices.ContainsFunc(targets, func(t config.OutputTarget) bool {
+		return t.Mode == config.OutputModeFile
+	})
+	if cfg.Output.Journal.Enabled && !hasFile {
+		targets = append(targets, config.OutputTarget{Mode: config.OutputModeFile, OnFailure: config.OutputFailureContinue})
+	}
+	return targets
+}
+
+// defaultTarget wraps a single output mode with its built-in fallback:
+// the clipboard falls back to typing, typing and paste to the clipboard
+func defaultTarget(mode string) config.OutputTarget {
+	target := config.OutputTarget{Mode: mode, OnFailure: config.OutputFailureStop}
+	switch mode {
+	case config.OutputModeClipboard:
+		target.Fallback = config.OutputModeActiveWindow
+	case config.OutputModeFile:
+	default:
+		target.Fallback = config.OutputModeClipboard
+	}
+	return target
+}
+
+// deliverTranscript fans text out to the targets in order and returns the
+// per-target results. Returns an error when a "stop" target fails or when
+// no target delivered the text
+func (ios *IOService) deliverTranscript(token uint64, t outputInterfaces.Transcript, profile *config.Profile) ([]outputInterfaces.TargetResult, error) {
+	targets := ios.resolveOutputTargets(profile)
+	t = ios.completeTranscript(t, profile)
+	ios.resetUndo()
+	results := make([]outputInterfaces.TargetResult, 0, len(targets))
+	var (
+		stopErr   error
+		delivered bool
+		failed    bool
+		canceled  bool
+	)
+	for _, target := range targets {
+		if stopErr != nil {
+			results = append(results, outputInterfaces.TargetResult{Target: target.Mode, Status: outputInterfaces.TargetStatusSkipped})
+			continue
+		}
+		res := ios.deliverToTarget(token, t, profile, target)
+		results = append(results, res)
+		switch res.Status {
+		case outputInterfaces.TargetStatusOK, outputInterfaces.TargetStatusFallback:
+			delivered = true
+		case outputInterfaces.TargetStatusFailed:
+			failed = true
+			if target.OnFailure == config.OutputFailureStop {
+				stopErr = fmt.Errorf("output target %s failed: %s", target.Mode, res.Error)
+			}
+		case outputInterfaces.TargetStatusCanceled:
+			// The user aborted typing; other targets (clipboard, journal) still deliver
+			canceled = true
+		}
+	}
+
+	if stopErr != nil {
+		return results, stopErr
+	}
+	if failed && !delivered {
+		return results, fmt.Errorf("all output targets failed")
+	}
+	if canceled {
+		return results, fmt.Errorf("typing aborted: %w", context.Canceled)
+	}
+	return results, nil
+}
+
+// completeTranscript fills in the recognition language and timestamp
+func (ios *IOService) completeTranscript(t outputInterfaces.Transcript, profile *config.Profile) outputInterfaces.Transcript {
+	if t.Language == "" {
+		t.Language = ios.config.Get().General.Language
+		if profile != nil && profile.Language != "" {
+			t.Language = profile.Language
+		}
+	}
+	if t.Timestamp.IsZero() {
+		t.Timestamp = time.Now()
+	}
+	return t
+}
+
+// deliverToTarget delivers text to one target, trying its fallback mode on failure
+func (ios *IOService) deliverToTarget(token uint64, t outputInterfaces.Transcript, profile *config.Profile, target config.OutputTarget) outputInterfaces.TargetResult {
+	res := outputInterfaces.TargetResult{Target: target.Mode}
+	if target.Mode == config.OutputModeClipboard && token != 0 {
+		var clipboard clipboardAccess
+		if out, err := ios.outputterFor(config.OutputModeClipboard, profile); err == nil {
+			clipboard = out
+		}
+		if ok, reason := ios.clipboardGuard.CanWrite(token, clipboard); !ok {
+			ios.logger.Warning("Transcript not copied to clipboard: %s", reason)
+			if ios.ui != nil {
+				ios.ui.ShowNotification("Clipboard Preserved", "Transcript not copied: "+reason)
+			}
+			res.Status, res.Error = outputInterfaces.TargetStatusSkipped, reason
+			return res
+		}
+	}
+	tool, err := ios.deliverToMode(token, t, profile, target.Mode)
+	if err == nil {
+		res.Status = outputInterfaces.TargetStatusOK
+		return res
+	}
+	if errors.Is(err, context.Canceled) {
+		// An aborted typing job must not continue through the fallback
+		ios.logger.Info("Output target %s canceled: %v", target.Mode, err)
+		res.Status, res.Error = outputInterfaces.TargetStatusCanceled, err.Error()
+		return res
+	}
+	ios.logger.Warning("Output target %s failed: %v", target.Mode, err)
+	metrics.OutputFailures.Inc(target.Mode, tool)
+	fallback := target.Fallback
+	windowClosed := errors.Is(err, activewindow.ErrWindowClosed)
+	windowStuck := errors.Is(err, activewindow.ErrTimeout)
+	if windowClosed || windowStuck {
+		// Typing into whatever has focus now would hit the wrong application
+		fallback = config.OutputModeClipboard
+	}
+	if fallback == "" {
+		res.Status, res.Error = outputInterfaces.TargetStatusFailed, err.Error()
+		return res
+	}
+	if fbTool, fbErr := ios.deliverToMode(token, t, profile, fallback); fbErr != nil {
+		ios.logger.Warning("Fallback %s for output target %s failed: %v", fallback, target.Mode, fbErr)
+		metrics.OutputFailures.Inc(fallback, fbTool)
+		res.Status = outputInterfaces.TargetStatusFailed
+		res.Error = fmt.Sprintf("%v; fallback %s: %v", err, fallback, fbErr)
+		return res
+	}
+	res.Status, res.Fallback, res.Error = outputInterfaces.TargetStatusFallback, fallback, err.Error()
+	metrics.FallbackSwitches.Inc("output", target.Mode, fallback)
+	if ios.ui != nil {
+		switch {
+		case windowClosed:
+			ios.ui.ShowNotification("Window Closed", "The window dictation started in was closed; transcript copied to clipboard")
+		case windowStuck:
+			ios.ui.ShowNotification("Window Not Focused", "The window dictation started in could not be focused; transcript copied to clipboard")
+		default:
+			msg := fmt.Sprintf("%s failed, used %s for this dictation", target.Mode, fallback)
+			ios.ui.ShowNotification("Output Fallback", msg)
+		}
+	}
+	return res
+}
+
+// outputTool names the tool of an outputter for metrics labels,
+// e.g. xsel for clipboard or wtype for active_window
+func outputTool(out outputInterfaces.Outputter, mode string) string {
+	clipboardTool, typeTool := out.GetToolNames()
+	tool := typeTool
+	if mode == config.OutputModeClipboard {
+		tool = clipboardTool
+	}
+	if tool == "" {
+		return mode
+	}
+	return tool
+}
+
+// deliverToMode performs a single delivery in the given output mode and
+// returns the tool that delivered, or failed to deliver, the text
+func (ios *IOService) deliverToMode(token uint64, t outputInterfaces.Transcript, profile *config.Profile, mode string) (string, error) {
+	if mode == config.OutputModeFile {
+		return "journal", ios.appendJournal(t)
+	}
+	out, err := ios.outputterFor(mode, profile)
+	if err != nil {
+		return "unavailable", err
+	}
+	tool := outputTool(out, mode)
+	if mode == config.OutputModeClipboard {
+		// Remember the replaced content so the output can be undone
+		previous, readErr := out.ReadClipboard()
+		if readErr == nil && token != 0 {
+			ios.clipboardGuard.Snapshot(token, previous)
+		}
+		if err := out.CopyToClipboard(t.Text); err != nil {
+			return tool, err
+		}
+		if readErr == nil {
+			ios.recordClipboard(out, previous, t.Text)
+		}
+		if token != 0 {
+			ios.clipboardGuard.Written(token, t.Text, out)
+		}
+		ios.logger.Debug("Successfully copied text to clipboard")
+		return tool, nil
+	}
+	if t.WindowID != "" && (mode == config.OutputModeActiveWindow || mode == config.OutputModePaste) {
+		if err := ios.focusOriginWindow(t.WindowID); err != nil {
+			return tool, err
+		}
+	}
+	// Outputters that record metadata (e.g., webhook) receive the full transcript
+	if recorder, ok := out.(outputInterfaces.TranscriptOutputter); ok {
+		err = recorder.OutputTranscript(t)
+	} else if typer, ok := out.(outputInterfaces.ContextTyper); ok {
+		// Typing can be aborted by CancelTyping (new hotkey press)
+		ctx, done := ios.beginTyping()
+		err = typer.TypeToActiveWindowContext(ctx, t.Text)
+		done()
+	} else {
+		// Typing and paste outputters both deliver via TypeToActiveWindow
+		err = out.TypeToActiveWindow(t.Text)
+	}
+	if err != nil {
+		return tool, err
+	}
+	if mode == config.OutputModeActiveWindow || mode == config.OutputModePaste {
+		ios.recordTyped(out, t.Text)
+	}
+	ios.logger.Debug("Successfully delivered text via %s", mode)
+	return tool, nil
+}
+
+// focusOriginWindow refocuses the window that was focused when recording
+// started. A closed window or an activation that timed out is an error (the
+// text goes to the clipboard); when activation is not possible at all the
+// text is typed into the focused window as before
+func (ios *IOService) focusOriginWindow(id string) error {
+	if ios.windowDetect == nil {
+		return nil
+	}
+	err := ios.windowDetect.Activate(id)
+	if errors.Is(err, activewindow.ErrWindowClosed) || errors.Is(err, activewindow.ErrTimeout) {
+		return err
+	}
+	if err != nil {
+		ios.logger.Warning("Could not refocus the original window: %v", err)
+	}
+	return nil
+}
+
+// outputterFor returns the outputter for a mode with profile tool overrides
+// applied. The configured default mode reuses the shared output manager
+func (ios *IOService) outputterFor(mode string, profile *config.Profile) (outputInterfaces.Outputter, error) {
+	overrides := profile != nil && (profile.TypeTool != "" || profile.PasteKeys != "")
+	current := ios.config.Get()
+	if !overrides && mode == current.Output.DefaultMode {
+		if ios.outputManager == nil {
+			return nil, fmt.Errorf("output manager not available")
+		}
+		return ios.outputManager, nil
+	}
+	cfg := *current
+	cfg.Output.DefaultMode = mode
+	if profile != nil && profile.TypeTool != "" {
+		cfg.Output.TypeTool = profile.TypeTool
+	}
+	if profile != nil && profile.PasteKeys != "" {
+		cfg.Output.PasteKeys = profile.PasteKeys
+	}
+	return ios.newOutputter(&cfg)
+}
+
+// clipboardReader returns an outputter able to read the clipboard when it is
+// an output target, or nil; a clipboard fallback is snapshotted only when used
+func (ios *IOService) clipboardReader() clipboardAccess {
+	for _, target := range ios.resolveOutputTargets(nil) {
+		if target.Mode != config.OutputModeClipboard {
+			continue
+		}
+		out, err := ios.outputterFor(config.OutputModeClipboard, nil)
+		if err != nil {
+			return nil
+		}
+		return out
+	}
+	return nil
+}
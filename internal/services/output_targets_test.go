@@ -0,0 +1,485 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/activewindow"
+	"github.com/AshBuk/dabri/internal/testutils"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
+)
+
+// newFanOutIOService returns an IOService whose outputters are mocks keyed by mode
+func newFanOutIOService(t *testing.T, targets []config.OutputTarget) (*IOService, map[string]*outputters.MockOutputter) {
+	t.Helper()
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	cfg.Output.DefaultMode = config.OutputModeActiveWindow
+	cfg.Output.Targets = targets
+	cfg.Output.Journal.Directory = t.TempDir()
+
+	mocks := map[string]*outputters.MockOutputter{
+		config.OutputModeClipboard:    outputters.NewMockOutputter(),
+		config.OutputModeActiveWindow: outputters.NewMockOutputter(),
+		config.OutputModePaste:        outputters.NewMockOutputter(),
+	}
+	ios := NewIOService(testutils.NewMockLogger(), config.NewLive(cfg), mocks[config.OutputModeActiveWindow], nil, nil)
+	ios.newOutputter = func(c *config.Config) (outputInterfaces.Outputter, error) {
+		out, ok := mocks[c.Output.DefaultMode]
+		if !ok {
+			return nil, fmt.Errorf("no outputter for %s", c.Output.DefaultMode)
+		}
+		return out, nil
+	}
+	return ios, mocks
+}
+
+// updateConfig publishes a changed copy of the service's config
+func updateConfig(t *testing.T, ios *IOService, change func(cfg *config.Config)) {
+	t.Helper()
+	_, _, err := ios.config.Update(func(next *config.Config) error {
+		change(next)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+}
+
+func resultStatuses(results []outputInterfaces.TargetResult) string {
+	s := ""
+	for i, r := range results {
+		if i > 0 {
+			s += ","
+		}
+		s += r.Target + "=" + r.Status
+	}
+	return s
+}
+
+func TestDeliverTranscript_Policies(t *testing.T) {
+	typeFails := errors.New("no focused window")
+	tests := []struct {
+		name      string
+		targets   []config.OutputTarget
+		typeErr   error
+		clipErr   error
+		expected  string
+		wantErr   bool
+		clipCalls int
+	}{
+		{
+			name: "all targets delivered",
+			targets: []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureContinue},
+				{Mode: config.OutputModeClipboard, OnFailure: config.OutputFailureContinue},
+				{Mode: config.OutputModeFile, OnFailure: config.OutputFailureContinue},
+			},
+			expected:  "active_window=ok,clipboard=ok,file=ok",
+			clipCalls: 1,
+		},
+		{
+			name: "continue policy delivers remaining targets",
+			targets: []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureContinue},
+				{Mode: config.OutputModeClipboard, OnFailure: config.OutputFailureContinue},
+			},
+			typeErr:   typeFails,
+			expected:  "active_window=failed,clipboard=ok",
+			clipCalls: 1,
+		},
+		{
+			name: "stop policy skips remaining targets",
+			targets: []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureStop},
+				{Mode: config.OutputModeClipboard, OnFailure: config.OutputFailureContinue},
+			},
+			typeErr:  typeFails,
+			expected: "active_window=failed,clipboard=skipped",
+			wantErr:  true,
+		},
+		{
+			name: "per-target fallback",
+			targets: []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureStop, Fallback: config.OutputModeClipboard},
+				{Mode: config.OutputModeFile, OnFailure: config.OutputFailureContinue},
+			},
+			typeErr:   typeFails,
+			expected:  "active_window=fallback,file=ok",
+			clipCalls: 1,
+		},
+		{
+			name: "all targets failed",
+			targets: []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureContinue},
+				{Mode: config.OutputModeClipboard, OnFailure: config.OutputFailureContinue},
+			},
+			typeErr:  typeFails,
+			clipErr:  errors.New("clipboard unavailable"),
+			expected: "active_window=failed,clipboard=failed",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, mocks := newFanOutIOService(t, tt.targets)
+			mocks[config.OutputModeActiveWindow].SetTypeError(tt.typeErr)
+			mocks[config.OutputModeClipboard].SetClipboardError(tt.clipErr)
+
+			results, err := ios.DeliverTranscript("hello", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if got := resultStatuses(results); got != tt.expected {
+				t.Errorf("expected results %s, got %s", tt.expected, got)
+			}
+			if got := mocks[config.OutputModeClipboard].GetClipboardCallCount(); got != tt.clipCalls {
+				t.Errorf("expected %d clipboard calls, got %d", tt.clipCalls, got)
+			}
+		})
+	}
+}
+
+func TestDeliverTranscript_ModeReplacesTargets(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, []config.OutputTarget{
+		{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureContinue},
+		{Mode: config.OutputModeFile, OnFailure: config.OutputFailureContinue},
+	})
+
+	results, err := ios.DeliverTranscript("hello", config.OutputModeClipboard)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resultStatuses(results); got != "clipboard=ok" {
+		t.Errorf("expected clipboard only, got %s", got)
+	}
+	if mocks[config.OutputModeActiveWindow].GetTypeCallCount() != 0 {
+		t.Errorf("configured typing target must not receive the transcript")
+	}
+}
+
+func TestDeliverTranscript_DefaultModeFallbackIsNotPersisted(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, nil)
+	mocks[config.OutputModeActiveWindow].SetTypeError(errors.New("no focused window"))
+
+	results, err := ios.DeliverTranscript("hello", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != outputInterfaces.TargetStatusFallback || results[0].Fallback != config.OutputModeClipboard {
+		t.Fatalf("expected clipboard fallback, got %+v", results)
+	}
+	if mocks[config.OutputModeClipboard].GetLastClipboardCall() != "hello" {
+		t.Errorf("expected transcript on clipboard")
+	}
+	if mode := ios.config.Get().Output.DefaultMode; mode != config.OutputModeActiveWindow {
+		t.Errorf("fallback must not change the configured mode, got %s", mode)
+	}
+}
+
+func TestResolveOutputTargets(t *testing.T) {
+	targets := []config.OutputTarget{
+		{Mode: config.OutputModePaste, OnFailure: config.OutputFailureContinue},
+		{Mode: config.OutputModeClipboard, OnFailure: config.OutputFailureContinue},
+	}
+	tests := []struct {
+		name     string
+		targets  []config.OutputTarget
+		journal  bool
+		profile  *config.Profile
+		expected string
+	}{
+		{"default mode", nil, false, nil, "active_window>clipboard"},
+		{"configured targets", targets, false, nil, "paste,clipboard"},
+		{"journal appended", targets, true, nil, "paste,clipboard,file"},
+		{"profile replaces targets", targets, false, &config.Profile{OutputMode: config.OutputModeClipboard}, "clipboard>active_window"},
+		{"file mode has no fallback", nil, true, &config.Profile{OutputMode: config.OutputModeFile}, "file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _ := newFanOutIOService(t, tt.targets)
+			updateConfig(t, ios, func(cfg *config.Config) { cfg.Output.Journal.Enabled = tt.journal })
+			got := ""
+			for i, target := range ios.resolveOutputTargets(tt.profile) {
+				if i > 0 {
+					got += ","
+				}
+				got += target.Mode
+				if target.Fallback != "" {
+					got += ">" + target.Fallback
+				}
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeliverTranscript_JournalSecondaryTarget(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, nil)
+	updateConfig(t, ios, func(cfg *config.Config) { cfg.Output.Journal.Enabled = true })
+
+	if _, err := ios.DeliverTranscript("hello", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mocks[config.OutputModeActiveWindow].GetLastTypeCall() != "hello" {
+		t.Errorf("expected transcript typed")
+	}
+	entries, err := os.ReadDir(ios.config.Get().Output.Journal.Directory)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one journal file, got %v (%v)", entries, err)
+	}
+}
+
+// blockingTyper types until its context is canceled
+type blockingTyper struct {
+	*outputters.MockOutputter
+	started chan struct{}
+}
+
+func (b *blockingTyper) TypeToActiveWindowContext(ctx context.Context, text string) error {
+	close(b.started)
+	<-ctx.Done()
+	return fmt.Errorf("typing canceled: %w", ctx.Err())
+}
+
+func TestDeliverTranscript_CancelTyping(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, []config.OutputTarget{
+		{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureStop, Fallback: config.OutputModeClipboard},
+		{Mode: config.OutputModeFile, OnFailure: config.OutputFailureContinue},
+	})
+	typer := &blockingTyper{MockOutputter: outputters.NewMockOutputter(), started: make(chan struct{})}
+	ios.outputManager = typer
+
+	if ios.CancelTyping() {
+		t.Error("expected no typing job before delivery")
+	}
+	go func() {
+		<-typer.started
+		if !ios.CancelTyping() {
+			t.Error("expected an in-flight typing job")
+		}
+	}()
+	results, err := ios.DeliverTranscript("hello", "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if got := resultStatuses(results); got != "active_window=canceled,file=ok" {
+		t.Errorf("expected typing canceled and journal delivered, got %s", got)
+	}
+	if mocks[config.OutputModeClipboard].GetClipboardCallCount() != 0 {
+		t.Error("a canceled typing job must not fall back to the clipboard")
+	}
+	if ios.CancelTyping() {
+		t.Error("expected typing job to be released after delivery")
+	}
+}
+
+// fakeWindowDetector records activations and fails them with err
+type fakeWindowDetector struct {
+	info      activewindow.Info
+	err       error
+	activated []string
+}
+
+func (d *fakeWindowDetector) Detect() (activewindow.Info, error) { return d.info, nil }
+func (d *fakeWindowDetector) Name() string                       { return "fake" }
+func (d *fakeWindowDetector) Activate(id string) error {
+	d.activated = append(d.activated, id)
+	return d.err
+}
+
+func TestDeliverTranscript_OriginWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+		typed    int
+		copied   int
+	}{
+		{"refocused", nil, "active_window=ok", 1, 0},
+		{"activation unavailable types anyway", errors.New("no _NET_ACTIVE_WINDOW"), "active_window=ok", 1, 0},
+		{"closed window goes to clipboard", fmt.Errorf("xdotool window 42: %w", activewindow.ErrWindowClosed), "active_window=fallback", 0, 1},
+		{"hung activation goes to clipboard", fmt.Errorf("xdotool windowactivate --sync 42: %w after 1s", activewindow.ErrTimeout), "active_window=fallback", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// No configured fallback: a closed window still falls back to the clipboard
+			ios, mocks := newFanOutIOService(t, []config.OutputTarget{
+				{Mode: config.OutputModeActiveWindow, OnFailure: config.OutputFailureStop},
+			})
+			detector := &fakeWindowDetector{err: tt.err}
+			ios.SetWindowDetector(detector)
+
+			results, err := ios.OutputTranscript(0, outputInterfaces.Transcript{Text: "hello", WindowID: "42"}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := resultStatuses(results); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+			if len(detector.activated) != 1 || detector.activated[0] != "42" {
+				t.Errorf("expected window 42 to be activated, got %v", detector.activated)
+			}
+			if got := mocks[config.OutputModeActiveWindow].GetTypeCallCount(); got != tt.typed {
+				t.Errorf("expected %d typing calls, got %d", tt.typed, got)
+			}
+			if got := mocks[config.OutputModeClipboard].GetClipboardCallCount(); got != tt.copied {
+				t.Errorf("expected %d clipboard calls, got %d", tt.copied, got)
+			}
+		})
+	}
+
+	// Without a captured window nothing is refocused
+	ios, _ := newFanOutIOService(t, nil)
+	detector := &fakeWindowDetector{}
+	ios.SetWindowDetector(detector)
+	if _, err := ios.DeliverTranscript("hello", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(detector.activated) != 0 {
+		t.Errorf("expected no activation, got %v", detector.activated)
+	}
+}
+
+// erasingTyper records BackSpace counts sent by undo
+type erasingTyper struct {
+	*outputters.MockOutputter
+	erased []int
+}
+
+func (e *erasingTyper) EraseTyped(count int) error {
+	e.erased = append(e.erased, count)
+	return nil
+}
+
+func TestUndoLastOutput_Typed(t *testing.T) {
+	ios, _ := newFanOutIOService(t, nil)
+	typer := &erasingTyper{MockOutputter: outputters.NewMockOutputter()}
+	ios.outputManager = typer
+	detector := &fakeWindowDetector{info: activewindow.Info{ID: "7"}}
+	ios.SetWindowDetector(detector)
+
+	if _, err := ios.UndoLastOutput(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected ErrNothingToUndo before any output, got %v", err)
+	}
+	if _, err := ios.DeliverTranscript("héllo", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another window has focus: refuse and keep the record for a retry
+	detector.info.ID = "8"
+	if _, err := ios.UndoLastOutput(); !errors.Is(err, ErrFocusChanged) {
+		t.Fatalf("expected ErrFocusChanged, got %v", err)
+	}
+	if len(typer.erased) != 0 {
+		t.Fatalf("nothing must be erased in another window, got %v", typer.erased)
+	}
+
+	detector.info.ID = "7"
+	result, err := ios.UndoLastOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Erased != 5 || len(typer.erased) != 1 || typer.erased[0] != 5 {
+		t.Errorf("expected 5 characters erased, got %+v / %v", result, typer.erased)
+	}
+	if _, err := ios.UndoLastOutput(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected a single undo per output, got %v", err)
+	}
+}
+
+func TestUndoLastOutput_UnverifiedFocus(t *testing.T) {
+	ios, _ := newFanOutIOService(t, nil)
+	typer := &erasingTyper{MockOutputter: outputters.NewMockOutputter()}
+	ios.outputManager = typer
+	// No window detection (e.g., GNOME Wayland)
+	if _, err := ios.DeliverTranscript("hello", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := ios.UndoLastOutput(); !errors.Is(err, ErrFocusUnverified) {
+		t.Fatalf("expected ErrFocusUnverified, got %v", err)
+	}
+	if len(typer.erased) != 0 {
+		t.Fatalf("nothing must be erased blindly, got %v", typer.erased)
+	}
+
+	updateConfig(t, ios, func(cfg *config.Config) { cfg.Output.UndoWithoutFocusCheck = true })
+	if result, err := ios.UndoLastOutput(); err != nil || result.Erased != 5 {
+		t.Errorf("expected opt-in erase of 5 characters, got %+v, %v", result, err)
+	}
+}
+
+func TestUndoLastOutput_Clipboard(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, nil)
+	clipboard := mocks[config.OutputModeClipboard]
+	if err := clipboard.CopyToClipboard("before"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ios.OutputToMode("dictated", config.OutputModeClipboard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := ios.UndoLastOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.ClipboardRestored || clipboard.GetClipboardContent() != "before" {
+		t.Errorf("expected previous clipboard restored, got %+v / %q", result, clipboard.GetClipboardContent())
+	}
+
+	// Content copied by the user after the output is left alone
+	if err := ios.OutputToMode("dictated", config.OutputModeClipboard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := clipboard.CopyToClipboard("user copy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ios.UndoLastOutput(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+	if got := clipboard.GetClipboardContent(); got != "user copy" {
+		t.Errorf("expected clipboard untouched, got %q", got)
+	}
+}
+
+func TestDeliverTranscript_ClipboardFallbackSnapshotsBeforeWriting(t *testing.T) {
+	ios, mocks := newFanOutIOService(t, []config.OutputTarget{
+		{Mode: config.OutputModeActiveWindow, Fallback: config.OutputModeClipboard},
+	})
+	mocks[config.OutputModeActiveWindow].SetTypeError(errors.New("no focused window"))
+	clipboard := mocks[config.OutputModeClipboard]
+	_ = clipboard.CopyToClipboard("at start")
+	ios.clipboardGuard.restoreAfter = time.Second
+	var restore func()
+	ios.clipboardGuard.schedule = func(_ time.Duration, fn func()) { restore = fn }
+
+	token := ios.BeginTranscription()
+	if ios.clipboardReader() != nil {
+		t.Error("a clipboard fallback must not be read at the start of a dictation")
+	}
+	_ = clipboard.CopyToClipboard("copied meanwhile")
+	if _, err := ios.OutputTranscript(token, outputInterfaces.Transcript{Text: "transcript"}, nil); err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	if clipboard.GetClipboardContent() != "transcript" {
+		t.Fatalf("fallback not delivered, clipboard = %q", clipboard.GetClipboardContent())
+	}
+	if restore == nil {
+		t.Fatal("expected a clipboard restore after the fallback write")
+	}
+	restore()
+	if got := clipboard.GetClipboardContent(); got != "copied meanwhile" {
+		t.Errorf("restored %q, want the content replaced by the fallback", got)
+	}
+}
@@ -24,7 +24,7 @@ type UIService struct {
 	logger        logger.Logger
 	trayManager   tray.Manager
 	notifyManager *notify.NotificationManager
-	config        *config.Config
+	config        *config.Live
 }
 
 // Create a new service instance
@@ -32,7 +32,7 @@ func NewUIService(
 	logger logger.Logger,
 	trayManager tray.Manager,
 	notifyManager *notify.NotificationManager,
-	config *config.Config,
+	config *config.Live,
 ) *UIService {
 	return &UIService{
 		logger:        logger,
@@ -67,6 +67,13 @@ func (us *UIService) UpdateSettings(cfg *config.Config) {
 	}
 }
 
+// Reload the tray "Recent" submenu from the transcript history
+func (us *UIService) RefreshHistory() {
+	if us.trayManager != nil {
+		us.trayManager.RefreshRecent()
+	}
+}
+
 // Update visual feedback during active recording
 func (us *UIService) UpdateRecordingUI(isRecording bool, level float64) {
 	us.SetRecordingState(isRecording)
@@ -153,7 +160,10 @@ func (us *UIService) createDefaultConfig(path string) error {
 	}
 
 	// Use current config if available, otherwise create default
-	cfg := us.config
+	var cfg *config.Config
+	if us.config != nil {
+		cfg = us.config.Get()
+	}
 	if cfg == nil {
 		cfg = &config.Config{}
 		config.SetDefaultConfig(cfg)
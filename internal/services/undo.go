@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
+	"github.com/AshBuk/dabri/output/outputters"
+)
+
+// Undo of the last output.
+//
+// Typed output (active_window, paste) remembers the outputter, the number of
+// characters it produced and the window focused afterwards; undo presses
+// BackSpace that many times while the same window still has focus. Without
+// window detection nothing is erased unless output.undo_without_focus_check
+// is set. Clipboard output remembers the previous clipboard content and puts it back.
+
+var (
+	// ErrNothingToUndo indicates that no output since the last undo can be reverted
+	ErrNothingToUndo = errors.New("nothing to undo")
+	// ErrFocusChanged indicates that another window gained focus after typing
+	ErrFocusChanged = errors.New("focus changed since the last output")
+	// ErrFocusUnverified indicates that the focused window cannot be checked before erasing
+	ErrFocusUnverified = errors.New("cannot verify the focused window (set output.undo_without_focus_check to erase anyway)")
+)
+
+// undoRecord describes how to revert the most recent output
+type undoRecord struct {
+	eraser   outputInterfaces.Eraser // Outputter that typed the text (nil if nothing was typed)
+	chars    int                     // Characters typed into the window
+	windowID string                  // Window focused after typing ("" if detection is unavailable)
+
+	clipboard clipboardAccess // Clipboard written by clipboard output (nil if none)
+	previous  string          // Clipboard content before the output
+	copied    string          // Text the output put on the clipboard
+}
+
+// resetUndo forgets the previous output when a new one begins
+func (ios *IOService) resetUndo() {
+	ios.mu.Lock()
+	ios.undo = nil
+	ios.mu.Unlock()
+}
+
+// recordTyped remembers typed text for undo if the outputter can erase it
+func (ios *IOService) recordTyped(out outputInterfaces.Outputter, text string) {
+	eraser, ok := out.(outputInterfaces.Eraser)
+	if !ok {
+		return
+	}
+	windowID := ""
+	if ios.windowDetect != nil {
+		if info, err := ios.windowDetect.Detect(); err == nil {
+			windowID = info.ID
+		}
+	}
+	ios.mu.Lock()
+	defer ios.mu.Unlock()
+	if ios.undo == nil {
+		ios.undo = &undoRecord{}
+	}
+	ios.undo.eraser, ios.undo.windowID = eraser, windowID
+	ios.undo.chars += outputters.TypedLength(text)
+}
+
+// recordClipboard remembers the clipboard content replaced by an output
+func (ios *IOService) recordClipboard(clipboard clipboardAccess, previous, copied string) {
+	ios.mu.Lock()
+	defer ios.mu.Unlock()
+	if ios.undo == nil {
+		ios.undo = &undoRecord{}
+	}
+	ios.undo.clipboard, ios.undo.previous, ios.undo.copied = clipboard, previous, copied
+}
+
+// Revert the last output: erase typed text if the same window still has
+// focus, and restore the previous clipboard if it still holds the transcript.
+// A refused undo keeps the record so it can be retried after refocusing
+func (ios *IOService) UndoLastOutput() (outputInterfaces.UndoResult, error) {
+	ios.mu.Lock()
+	rec := ios.undo
+	ios.mu.Unlock()
+	if rec == nil {
+		return outputInterfaces.UndoResult{}, ErrNothingToUndo
+	}
+
+	var result outputInterfaces.UndoResult
+	if rec.eraser != nil && rec.chars > 0 {
+		if err := ios.checkUndoFocus(rec.windowID); err != nil {
+			return result, err
+		}
+	}
+	ios.mu.Lock()
+	if ios.undo != rec {
+		// A new output started meanwhile
+		ios.mu.Unlock()
+		return result, ErrNothingToUndo
+	}
+	ios.undo = nil
+	ios.mu.Unlock()
+
+	if rec.eraser != nil && rec.chars > 0 {
+		if err := rec.eraser.EraseTyped(rec.chars); err != nil {
+			return result, fmt.Errorf("failed to erase typed text: %w", err)
+		}
+		result.Erased = rec.chars
+	}
+	if rec.clipboard != nil {
+		// Leave the clipboard alone if the user copied something else since
+		if current, err := rec.clipboard.ReadClipboard(); err == nil && current == rec.copied {
+			if err := rec.clipboard.CopyToClipboard(rec.previous); err != nil {
+				return result, fmt.Errorf("failed to restore clipboard: %w", err)
+			}
+			result.ClipboardRestored = true
+		}
+	}
+	if result.Erased == 0 && !result.ClipboardRestored {
+		return result, ErrNothingToUndo
+	}
+	ios.logger.Info("Undid last output: erased %d characters, clipboard restored: %t", result.Erased, result.ClipboardRestored)
+	return result, nil
+}
+
+// checkUndoFocus refuses to erase text when another window has focus or when
+// focus cannot be verified (e.g., GNOME Wayland), unless
+// output.undo_without_focus_check allows erasing blindly
+func (ios *IOService) checkUndoFocus(windowID string) error {
+	if windowID == "" || ios.windowDetect == nil {
+		if ios.config.Get().Output.UndoWithoutFocusCheck {
+			ios.logger.Warning("Cannot verify focus before undo; erasing in the focused window")
+			return nil
+		}
+		return ErrFocusUnverified
+	}
+	info, err := ios.windowDetect.Detect()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFocusUnverified, err)
+	}
+	if info.ID != windowID {
+		return ErrFocusChanged
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFDsStart is the first file descriptor passed by socket activation (SD_LISTEN_FDS_START)
+const listenFDsStart = 3
+
+// activationEnv are the variables describing sockets passed by systemd
+var activationEnv = []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"}
+
+// ListenFiles returns the sockets systemd passed to this process, in unit order
+// (empty when not socket activated). The variables are removed from the
+// environment so helper processes do not mistake the sockets for theirs, and
+// the descriptors are marked close-on-exec; InheritEnv hands them on to a
+// re-executed daemon
+func ListenFiles() []*os.File {
+	count := listenFDCount(os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"), os.Getpid())
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for _, key := range activationEnv {
+		_ = os.Unsetenv(key)
+	}
+
+	files := make([]*os.File, 0, count)
+	for i := range count {
+		fd := listenFDsStart + i
+		syscall.CloseOnExec(fd)
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		files = append(files, os.NewFile(uintptr(fd), name))
+	}
+	return files
+}
+
+// listenFDCount validates the LISTEN_PID and LISTEN_FDS values for process pid
+func listenFDCount(listenPID, listenFDs string, pid int) int {
+	if listenPID != strconv.Itoa(pid) {
+		return 0
+	}
+	count, err := strconv.Atoi(listenFDs)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
+
+// InheritEnv prepares files returned by ListenFiles to survive exec into a new
+// daemon image (same PID) and returns env extended with the matching LISTEN_*
+// variables. Without files it returns env with any stale LISTEN_* removed
+func InheritEnv(files []*os.File, env []string) ([]string, error) {
+	result := make([]string, 0, len(env)+len(activationEnv))
+	for _, entry := range env {
+		key, _, _ := strings.Cut(entry, "=")
+		if !slices.Contains(activationEnv, key) {
+			result = append(result, entry)
+		}
+	}
+	if len(files) == 0 {
+		return result, nil
+	}
+
+	names := make([]string, 0, len(files))
+	for i, file := range files {
+		fd := file.Fd()
+		if fd != uintptr(listenFDsStart+i) {
+			return nil, fmt.Errorf("activation socket %s moved to descriptor %d", file.Name(), fd)
+		}
+		if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, syscall.F_SETFD, 0); errno != 0 {
+			return nil, fmt.Errorf("failed to keep %s open across exec: %w", file.Name(), errno)
+		}
+		names = append(names, file.Name())
+	}
+	return append(result,
+		"LISTEN_PID="+strconv.Itoa(os.Getpid()),
+		"LISTEN_FDS="+strconv.Itoa(len(files)),
+		"LISTEN_FDNAMES="+strings.Join(names, ":"),
+	), nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListenFDCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		listenPID string
+		listenFDs string
+		expected  int
+	}{
+		{name: "not activated", listenPID: "", listenFDs: "", expected: 0},
+		{name: "activated", listenPID: "42", listenFDs: "1", expected: 1},
+		{name: "several sockets", listenPID: "42", listenFDs: "2", expected: 2},
+		{name: "meant for another process", listenPID: "7", listenFDs: "1", expected: 0},
+		{name: "invalid count", listenPID: "42", listenFDs: "one", expected: 0},
+		{name: "negative count", listenPID: "42", listenFDs: "-1", expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listenFDCount(tt.listenPID, tt.listenFDs, 42); got != tt.expected {
+				t.Errorf("listenFDCount() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestListenFiles_NotActivated(t *testing.T) {
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	if files := ListenFiles(); len(files) != 0 {
+		t.Errorf("ListenFiles() = %d files for another process", len(files))
+	}
+}
+
+func TestInheritEnv_WithoutFiles(t *testing.T) {
+	env := []string{"HOME=/home/user", "LISTEN_PID=1", "LISTEN_FDS=1", "LISTEN_FDNAMES=dabri.socket", "PATH=/usr/bin"}
+	got, err := InheritEnv(nil, env)
+	if err != nil {
+		t.Fatalf("InheritEnv: %v", err)
+	}
+	if want := []string{"HOME=/home/user", "PATH=/usr/bin"}; !slices.Equal(got, want) {
+		t.Errorf("InheritEnv() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+// Package systemd implements the parts of the systemd service protocol the
+// daemon uses: sd_notify readiness and watchdog messages, socket activation
+// and generated user unit files. Every function is a no-op outside systemd
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notification states understood by the service manager (see sd_notify(3))
+const (
+	Ready     = "READY=1"
+	Reloading = "RELOADING=1"
+	Stopping  = "STOPPING=1"
+	Watchdog  = "WATCHDOG=1"
+)
+
+// Status formats a free-form status line shown by "systemctl --user status"
+func Status(format string, args ...any) string {
+	return "STATUS=" + fmt.Sprintf(format, args...)
+}
+
+// Notify sends newline-separated states to $NOTIFY_SOCKET. It reports false
+// without error when the process was not started by systemd with Type=notify
+func Notify(states ...string) (bool, error) {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" || len(states) == 0 {
+		return false, nil
+	}
+	// A leading "@" names an abstract socket; Go translates it for the kernel
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to notify socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var message []byte
+	for i, state := range states {
+		if i > 0 {
+			message = append(message, '\n')
+		}
+		message = append(message, state...)
+	}
+	if _, err := conn.Write(message); err != nil {
+		return false, fmt.Errorf("failed to send notification: %w", err)
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the interval the service manager expects WATCHDOG=1
+// pings within, or false when the watchdog is disabled for this process
+func WatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	// WATCHDOG_PID is optional; when set it must name this process
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// listenNotify stands in for systemd: a unixgram socket exported as $NOTIFY_SOCKET
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func TestNotify(t *testing.T) {
+	conn := listenNotify(t)
+
+	sent, err := Notify(Ready, Status("Ready (model %s)", "base.en"))
+	if err != nil || !sent {
+		t.Fatalf("Notify = %v, %v", sent, err)
+	}
+	buf := make([]byte, 256)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got, want := string(buf[:n]), "READY=1\nSTATUS=Ready (model base.en)"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestNotify_WithoutSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Notify = %v, %v; want false, nil", sent, err)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	if sent, err := Notify(Ready); sent || err == nil {
+		t.Errorf("Notify to a missing socket = %v, %v; want an error", sent, err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	self := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		name     string
+		usec     string
+		pid      string
+		expected time.Duration
+		enabled  bool
+	}{
+		{name: "disabled", usec: "", pid: "", enabled: false},
+		{name: "enabled", usec: "30000000", pid: "", expected: 30 * time.Second, enabled: true},
+		{name: "enabled for this process", usec: "1000000", pid: self, expected: time.Second, enabled: true},
+		{name: "meant for another process", usec: "1000000", pid: "1", enabled: false},
+		{name: "invalid", usec: "soon", pid: "", enabled: false},
+		{name: "zero", usec: "0", pid: "", enabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tt.usec)
+			t.Setenv("WATCHDOG_PID", tt.pid)
+			interval, enabled := WatchdogInterval()
+			if interval != tt.expected || enabled != tt.enabled {
+				t.Errorf("WatchdogInterval() = %v, %v; want %v, %v", interval, enabled, tt.expected, tt.enabled)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Unit file names; matching names let systemd pair the socket with the service
+const (
+	ServiceUnitName = "dabri.service"
+	SocketUnitName  = "dabri.socket"
+)
+
+// DefaultWatchdog is the WatchdogSec of generated units
+const DefaultWatchdog = 30 * time.Second
+
+// UnitOptions describes the generated user units
+type UnitOptions struct {
+	Executable string        // Absolute path of the dabri binary (or AppImage)
+	Args       []string      // Daemon flags, e.g. --config <path>
+	Socket     bool          // Also generate dabri.socket for socket activation
+	SocketPath string        // ListenStream of the socket unit; %t is $XDG_RUNTIME_DIR
+	Watchdog   time.Duration // WatchdogSec; zero disables the watchdog
+}
+
+// ServiceUnit renders dabri.service. Type=notify: the daemon reports READY=1
+// once the model is loaded and the IPC socket is served
+func ServiceUnit(opts UnitOptions) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=Dabri offline speech-to-text daemon\n")
+	b.WriteString("Documentation=https://github.com/AshBuk/dabri\n")
+	b.WriteString("PartOf=graphical-session.target\n")
+	b.WriteString("After=graphical-session.target\n")
+	if opts.Socket {
+		b.WriteString("After=" + SocketUnitName + "\n")
+	}
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	b.WriteString("ExecStart=" + execLine(opts.Executable, opts.Args) + "\n")
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=2\n")
+	// Loading (or downloading) a large model can take minutes
+	b.WriteString("TimeoutStartSec=5min\n")
+	if opts.Watchdog > 0 {
+		b.WriteString(fmt.Sprintf("WatchdogSec=%d\n", int(opts.Watchdog.Seconds())))
+	}
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=graphical-session.target\n")
+	if opts.Socket {
+		b.WriteString("Also=" + SocketUnitName + "\n")
+	}
+	return b.String()
+}
+
+// SocketUnit renders dabri.socket, which starts the daemon on the first CLI command
+func SocketUnit(opts UnitOptions) string {
+	path := opts.SocketPath
+	if path == "" {
+		path = "%t/dabri.sock"
+	}
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=Dabri IPC socket\n")
+	b.WriteString("Documentation=https://github.com/AshBuk/dabri\n")
+	b.WriteString("\n[Socket]\n")
+	b.WriteString("ListenStream=" + path + "\n")
+	b.WriteString("SocketMode=0600\n")
+	b.WriteString("DirectoryMode=0700\n")
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=sockets.target\n")
+	return b.String()
+}
+
+// execLine quotes the command line for ExecStart; "%" and "$" are escaped so
+// systemd does not expand them
+func execLine(executable string, args []string) string {
+	words := make([]string, 0, len(args)+1)
+	for _, word := range append([]string{executable}, args...) {
+		word = strings.NewReplacer("%", "%%", "$", "$$").Replace(word)
+		if strings.ContainsAny(word, " \t\"'\\") {
+			word = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(word) + `"`
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
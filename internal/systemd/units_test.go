@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServiceUnit(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     UnitOptions
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "socket activated with watchdog",
+			opts: UnitOptions{Executable: "/usr/bin/dabri", Socket: true, Watchdog: DefaultWatchdog},
+			contains: []string{
+				"Type=notify\n", "ExecStart=/usr/bin/dabri\n", "ExecReload=/bin/kill -HUP $MAINPID\n",
+				"WatchdogSec=30\n", "After=dabri.socket\n", "Also=dabri.socket\n",
+			},
+		},
+		{
+			name:     "without socket or watchdog",
+			opts:     UnitOptions{Executable: "/usr/bin/dabri"},
+			contains: []string{"Type=notify\n"},
+			excludes: []string{"WatchdogSec", "dabri.socket"},
+		},
+		{
+			name:     "arguments are quoted and escaped",
+			opts:     UnitOptions{Executable: "/opt/My Apps/dabri", Args: []string{"--config", "/home/u/50%.yaml"}},
+			contains: []string{`ExecStart="/opt/My Apps/dabri" --config /home/u/50%%.yaml` + "\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit := ServiceUnit(tt.opts)
+			for _, line := range tt.contains {
+				if !strings.Contains(unit, line) {
+					t.Errorf("unit is missing %q:\n%s", line, unit)
+				}
+			}
+			for _, text := range tt.excludes {
+				if strings.Contains(unit, text) {
+					t.Errorf("unit should not contain %q:\n%s", text, unit)
+				}
+			}
+		})
+	}
+}
+
+func TestSocketUnit(t *testing.T) {
+	if unit := SocketUnit(UnitOptions{}); !strings.Contains(unit, "ListenStream=%t/dabri.sock\n") {
+		t.Errorf("default socket path missing:\n%s", unit)
+	}
+	if unit := SocketUnit(UnitOptions{SocketPath: "/run/user/1000/custom.sock"}); !strings.Contains(unit, "ListenStream=/run/user/1000/custom.sock\n") {
+		t.Errorf("custom socket path missing:\n%s", unit)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package testutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Selection names used by FakeClipboard
+const (
+	SelectionClipboard = "clipboard"
+	SelectionPrimary   = "primary"
+)
+
+// fakeClipboardTools are shell stand-ins for the clipboard and paste tools,
+// reading and writing one file per selection under $SEL_DIR
+var fakeClipboardTools = map[string]string{
+	// xsel --clipboard|--primary --input|--output
+	"xsel": "#!/bin/sh\nf=\"$SEL_DIR/${1#--}\"\nif [ \"$2\" = \"--output\" ]; then cat \"$f\"; else cat > \"$f\"; fi\n",
+	// wl-copy [--primary]
+	"wl-copy": "#!/bin/sh\nf=\"$SEL_DIR/clipboard\"\n[ \"$1\" = \"--primary\" ] && f=\"$SEL_DIR/primary\"\ncat > \"$f\"\n",
+	// wl-paste [--primary] --no-newline
+	"wl-paste": "#!/bin/sh\nf=\"$SEL_DIR/clipboard\"\n[ \"$1\" = \"--primary\" ] && f=\"$SEL_DIR/primary\"\ncat \"$f\"\n",
+	// xdotool key ... (paste keys are appended to $SEL_DIR/keys, one argument per line)
+	"xdotool": "#!/bin/sh\nprintf '%s\\n' \"$@\" >> \"$SEL_DIR/keys\"\n",
+}
+
+// FakeClipboard is a file-backed clipboard served by fake xsel, wl-copy,
+// wl-paste and xdotool installed on PATH for the duration of a test
+type FakeClipboard struct {
+	t   *testing.T
+	dir string
+}
+
+// NewFakeClipboard installs the fake tools and puts initial on the CLIPBOARD selection
+func NewFakeClipboard(t *testing.T, initial string) *FakeClipboard {
+	t.Helper()
+	dir := t.TempDir()
+	for name, script := range fakeClipboardTools {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0700); err != nil {
+			t.Fatalf("write fake %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", dir+":/bin:/usr/bin")
+	t.Setenv("SEL_DIR", dir)
+	c := &FakeClipboard{t: t, dir: dir}
+	c.Set(initial)
+	return c
+}
+
+// Dir returns the directory on PATH holding the fake tools; tests may add more there
+func (c *FakeClipboard) Dir() string { return c.dir }
+
+// KeysFile returns the file the fake xdotool appends its arguments to
+func (c *FakeClipboard) KeysFile() string { return filepath.Join(c.dir, "keys") }
+
+// Get returns the CLIPBOARD selection
+func (c *FakeClipboard) Get() string { return c.Selection(SelectionClipboard) }
+
+// Set replaces the CLIPBOARD selection, as if the user copied text
+func (c *FakeClipboard) Set(text string) { c.SetSelection(SelectionClipboard, text) }
+
+// Selection returns the content of a selection, or "" if it was never written
+func (c *FakeClipboard) Selection(name string) string {
+	c.t.Helper()
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		c.t.Fatalf("read %s selection: %v", name, err)
+	}
+	return string(data)
+}
+
+// SetSelection replaces the content of a selection
+func (c *FakeClipboard) SetSelection(name, text string) {
+	c.t.Helper()
+	if err := os.WriteFile(filepath.Join(c.dir, name), []byte(text), 0600); err != nil {
+		c.t.Fatalf("write %s selection: %v", name, err)
+	}
+}
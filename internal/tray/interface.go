@@ -9,6 +9,12 @@ import (
 	"github.com/AshBuk/dabri/config"
 )
 
+// RecentItem is a transcript shown in the tray "Recent" submenu
+type RecentItem struct {
+	ID    int64
+	Label string // Short preview of the transcript text
+}
+
 // Defines the interface for tray managers
 type Manager interface {
 	Start()
@@ -18,6 +24,8 @@ type Manager interface {
 	SetExitAction(onExit func())
 	// SetCoreActions sets core menu callbacks (toggle, show config, show about, reset to defaults)
 	SetCoreActions(onToggle func() error, onShowConfig func() error, onShowAbout func() error, onResetToDefaults func() error)
+	// SetCancelAction sets the callback discarding the current recording
+	SetCancelAction(onCancel func() error)
 	// SetAudioActions sets callbacks for audio-related actions
 	SetAudioActions(onSelectRecorder func(method string) error)
 	// SetModelAction sets callback for whisper model selection.
@@ -35,5 +43,9 @@ type Manager interface {
 	OutputToolsCallback(callback func() (clipboardTool, typeTool string))
 	// SetCaptureOnceSupport sets a callback indicating whether capture once is supported
 	SetCaptureOnceSupport(callback func() bool)
+	// SetHistoryActions sets callbacks listing recent transcripts and re-outputting one by ID
+	SetHistoryActions(onListRecent func() []RecentItem, onSelectRecent func(id int64) error)
+	// RefreshRecent reloads the "Recent" submenu after the history changed
+	RefreshRecent()
 	Stop()
 }
@@ -19,11 +19,14 @@ type MockTrayManager struct {
 	onShowConfig           func() error
 	onShowAbout            func() error
 	onResetToDefaults      func() error
+	onCancel               func() error
 	onSelectRecorder       func(method string) error
 	onSelectLang           func(language string) error
 	onToggleWorkflowNotify func() error
 	onGetOutputTools       func() (clipboardTool, typeTool string)
 	onSelectOutputMode     func(mode string) error
+	onListRecent           func() []RecentItem
+	onSelectRecent         func(id int64) error
 }
 
 // CreateMockTrayManager creates a mock tray manager that doesn't use systray.
@@ -67,6 +70,10 @@ func (tm *MockTrayManager) SetCoreActions(onToggle func() error, onShowConfig fu
 	tm.onResetToDefaults = onResetToDefaults
 }
 
+func (tm *MockTrayManager) SetCancelAction(onCancel func() error) {
+	tm.onCancel = onCancel
+}
+
 func (tm *MockTrayManager) SetAudioActions(onSelectRecorder func(method string) error) {
 	tm.onSelectRecorder = onSelectRecorder
 }
@@ -90,3 +97,10 @@ func (tm *MockTrayManager) SetCaptureOnceSupport(_ func() bool) {}
 func (tm *MockTrayManager) SetModelAction(_ func(ctx context.Context, modelID string) error) {}
 
 func (tm *MockTrayManager) SetHotkeyRebindAction(_ func(action string) error) {}
+
+func (tm *MockTrayManager) SetHistoryActions(onListRecent func() []RecentItem, onSelectRecent func(id int64) error) {
+	tm.onListRecent = onListRecent
+	tm.onSelectRecent = onSelectRecent
+}
+
+func (tm *MockTrayManager) RefreshRecent() {}
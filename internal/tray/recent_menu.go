@@ -0,0 +1,75 @@
+//go:build systray
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package tray
+
+import (
+	"fyne.io/systray"
+)
+
+// recentSlots is the number of transcripts shown in the "Recent" submenu
+const recentSlots = 10
+
+// createRecentSubmenu creates fixed slots for recent transcripts. systray
+// cannot reorder items, so slots are retitled and hidden instead
+func (tm *TrayManager) createRecentSubmenu() {
+	tm.recentItems = make([]*systray.MenuItem, recentSlots)
+	for i := range tm.recentItems {
+		item := tm.recentMenu.AddSubMenuItem("", "Copy to clipboard")
+		item.Hide()
+		tm.recentItems[i] = item
+		slot := i
+		tm.handleMenuItemClick(item, func() { tm.selectRecent(slot) })
+	}
+	tm.RefreshRecent()
+}
+
+// RefreshRecent reloads the "Recent" submenu after the history changed
+func (tm *TrayManager) RefreshRecent() {
+	// Guard against early calls before onReady creates menu items
+	if tm.recentMenu == nil || tm.onListRecent == nil {
+		return
+	}
+	items := tm.onListRecent()
+	if len(items) > recentSlots {
+		items = items[:recentSlots]
+	}
+
+	tm.recentMu.Lock()
+	defer tm.recentMu.Unlock()
+	tm.recentIDs = tm.recentIDs[:0]
+	for i, item := range tm.recentItems {
+		if i >= len(items) {
+			item.Hide()
+			continue
+		}
+		tm.recentIDs = append(tm.recentIDs, items[i].ID)
+		item.SetTitle(items[i].Label)
+		item.Show()
+	}
+	if len(items) == 0 {
+		tm.recentMenu.Disable()
+	} else {
+		tm.recentMenu.Enable()
+	}
+}
+
+// selectRecent re-outputs the transcript shown in the given slot
+func (tm *TrayManager) selectRecent(slot int) {
+	tm.recentMu.Lock()
+	if slot >= len(tm.recentIDs) {
+		tm.recentMu.Unlock()
+		return
+	}
+	id := tm.recentIDs[slot]
+	tm.recentMu.Unlock()
+
+	tm.logger.Info("Recent transcript %d clicked", id)
+	if tm.onSelectRecent != nil {
+		if err := tm.onSelectRecent(id); err != nil {
+			tm.logger.Error("Error re-outputting transcript %d: %v", id, err)
+		}
+	}
+}
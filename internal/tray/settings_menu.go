@@ -22,14 +22,14 @@ func (tm *TrayManager) createSettingsSubmenus() {
 	tm.outputMenu = tm.settingsItem.AddSubMenuItem("Output", "Output settings")
 
 	// Populate with initial values if config is available
-	if tm.config != nil {
+	if tm.currentConfig() != nil {
 		tm.populateSettingsMenus()
 	}
 }
 
 // populateSettingsMenus populates the settings submenus with current config values
 func (tm *TrayManager) populateSettingsMenus() {
-	if tm.config == nil {
+	if tm.currentConfig() == nil {
 		return
 	}
 
@@ -78,7 +78,7 @@ func (tm *TrayManager) setupAudioRecorderMenu() {
 	)
 
 	// Update initial UI state
-	tm.updateRecorderRadioUI(tm.config.Audio.RecordingMethod)
+	tm.updateRecorderRadioUI(tm.currentConfig().Audio.RecordingMethod)
 }
 
 // setupLanguageMenu creates and configures the language selection submenu.
@@ -86,8 +86,9 @@ func (tm *TrayManager) setupAudioRecorderMenu() {
 // (GNOME AppIndicator has 3-level menu depth limit).
 func (tm *TrayManager) setupLanguageMenu() {
 	// Create current language display at the top
-	currentLang := constants.LanguageByCode(tm.config.General.Language)
-	currentName := tm.config.General.Language
+	language := tm.currentConfig().General.Language
+	currentLang := constants.LanguageByCode(language)
+	currentName := language
 	if currentLang != nil {
 		currentName = currentLang.Name + " (" + currentLang.Code + ")"
 	}
@@ -108,7 +109,7 @@ func (tm *TrayManager) setupLanguageMenu() {
 	// Flat list of all languages (GNOME-compatible, 3 levels: Settings > Set Language > Language)
 	for _, lang := range constants.WhisperLanguages {
 		indicator := "○ "
-		if tm.config.General.Language == lang.Code {
+		if language == lang.Code {
 			indicator = "● "
 		}
 		itm := tm.languageMenu.AddSubMenuItem(indicator+lang.Name+" ("+lang.Code+")", lang.Code)
@@ -129,8 +130,9 @@ func (tm *TrayManager) setupLanguageMenu() {
 // Unlike other radio menus, model items use custom click handling that supports
 // cancel-on-reclick: clicking a downloading model cancels the download (✖ indicator).
 func (tm *TrayManager) setupModelMenu() {
-	currentModel := constants.ModelByID(tm.config.General.WhisperModel)
-	currentName := tm.config.General.WhisperModel
+	modelID := tm.currentConfig().General.WhisperModel
+	currentModel := constants.ModelByID(modelID)
+	currentName := modelID
 	if currentModel != nil {
 		currentName = currentModel.Name
 	}
@@ -142,7 +144,7 @@ func (tm *TrayManager) setupModelMenu() {
 
 	for _, m := range constants.WhisperModels {
 		indicator := "○ "
-		if tm.config.General.WhisperModel == m.ID {
+		if modelID == m.ID {
 			indicator = "● "
 		}
 		itm := tm.modelMenu.AddSubMenuItem(indicator+m.Name, m.ID)
@@ -168,8 +170,8 @@ func (tm *TrayManager) handleModelClick(modelID string) {
 		tm.modelMu.Unlock()
 		cancel()
 		tm.logger.Info("Model download cancelled by user: %s", modelID)
-		if tm.config != nil {
-			tm.updateModelRadioUI(tm.config.General.WhisperModel)
+		if cfg := tm.currentConfig(); cfg != nil {
+			tm.updateModelRadioUI(cfg.General.WhisperModel)
 		}
 		return
 	}
@@ -186,8 +188,8 @@ func (tm *TrayManager) handleModelClick(modelID string) {
 	tm.modelMu.Unlock()
 
 	// Restore UI for the previously downloading model
-	if prevModelID != "" && prevModelID != modelID && tm.config != nil {
-		tm.updateModelRadioUI(tm.config.General.WhisperModel)
+	if cfg := tm.currentConfig(); prevModelID != "" && prevModelID != modelID && cfg != nil {
+		tm.updateModelRadioUI(cfg.General.WhisperModel)
 	}
 
 	tm.setModelDownloadingUI(modelID)
@@ -214,8 +216,8 @@ func (tm *TrayManager) handleModelClick(modelID string) {
 
 		if err != nil {
 			tm.logger.Error("Model switch failed: %v", err)
-			if tm.config != nil {
-				tm.updateModelRadioUI(tm.config.General.WhisperModel)
+			if cfg := tm.currentConfig(); cfg != nil {
+				tm.updateModelRadioUI(cfg.General.WhisperModel)
 			}
 			return
 		}
@@ -261,13 +263,15 @@ func (tm *TrayManager) updateModelRadioUI(modelID string) {
 // setupOutputMenu creates and configures the output mode submenu
 func (tm *TrayManager) setupOutputMenu() {
 	modeDefs := []struct{ key, title string }{
-		{"clipboard", "Clipboard"}, {"active_window", "Active Window"},
+		{"clipboard", "Clipboard"}, {"active_window", "Active Window"}, {"paste", "Paste"}, {"file", "File (Journal)"},
+		{"webhook", "Webhook"},
 	}
 
+	output := tm.currentConfig().Output
 	// Create output mode items
 	for _, m := range modeDefs {
 		indicator := "○ "
-		if tm.config.Output.DefaultMode == m.key {
+		if output.DefaultMode == m.key {
 			indicator = "● "
 		}
 		itm := tm.outputMenu.AddSubMenuItem(indicator+m.title, "")
@@ -276,19 +280,19 @@ func (tm *TrayManager) setupOutputMenu() {
 
 	// Create display items (gray text)
 	tm.outputItems["mode"] = tm.outputMenu.AddSubMenuItem(
-		"Mode: "+humanizeOutputMode(tm.config.Output.DefaultMode),
+		"Mode: "+humanizeOutputMode(output.DefaultMode),
 		"Current output mode",
 	)
 	tm.outputItems["mode"].Disable()
 
 	tm.outputItems["clipboard_tool"] = tm.outputMenu.AddSubMenuItem(
-		"Clipboard Tool: "+tm.config.Output.ClipboardTool,
+		"Clipboard Tool: "+output.ClipboardTool,
 		"Current clipboard tool",
 	)
 	tm.outputItems["clipboard_tool"].Disable()
 
 	tm.outputItems["type_tool"] = tm.outputMenu.AddSubMenuItem(
-		"Type Tool: "+tm.config.Output.TypeTool,
+		"Type Tool: "+output.TypeTool,
 		"Current typing tool",
 	)
 	tm.outputItems["type_tool"].Disable()
@@ -301,7 +305,7 @@ func (tm *TrayManager) setupOutputMenu() {
 		}
 		return nil
 	}
-	for _, k := range []string{"clipboard", "active_window"} {
+	for _, k := range []string{"clipboard", "active_window", "paste", "file", "webhook"} {
 		if itm := tm.outputItems["mode_"+k]; itm != nil {
 			key := k
 			tm.handleRadioItemClick(
@@ -328,14 +332,14 @@ func (tm *TrayManager) setupWorkflowNotifications() {
 				tm.logger.Error("Error toggling workflow notifications: %v", err)
 			}
 			// Reflect new state in UI using updated config
-			if tm.config != nil {
-				tm.updateWorkflowNotificationUI(tm.config.Notifications.EnableWorkflowNotifications)
+			if cfg := tm.currentConfig(); cfg != nil {
+				tm.updateWorkflowNotificationUI(cfg.Notifications.EnableWorkflowNotifications)
 			}
 		}
 	})
 
 	// Update initial UI state
-	tm.updateWorkflowNotificationUI(tm.config.Notifications.EnableWorkflowNotifications)
+	tm.updateWorkflowNotificationUI(tm.currentConfig().Notifications.EnableWorkflowNotifications)
 }
 
 // hotkeyMenuConfig describes configuration for a single hotkey menu item
@@ -384,7 +388,8 @@ func (tm *TrayManager) createHotkeyMenuItem(cfg hotkeyMenuConfig, supportsCaptur
 
 // updateHotkeysMenuUI ensures hotkeys items exist and reflect current config
 func (tm *TrayManager) updateHotkeysMenuUI() {
-	if tm.hotkeysMenu == nil || tm.config == nil {
+	current := tm.currentConfig()
+	if tm.hotkeysMenu == nil || current == nil {
 		return
 	}
 
@@ -404,7 +409,7 @@ func (tm *TrayManager) updateHotkeysMenuUI() {
 			rebindTooltip:  "Change start/stop hotkey",
 			displayPrefix:  "Start/Stop Recording: ",
 			displayTooltip: "Current start/stop recording hotkey",
-			currentValue:   tm.config.Hotkeys.StartRecording,
+			currentValue:   current.Hotkeys.StartRecording,
 		},
 		{
 			actionName:     "show_config",
@@ -414,7 +419,7 @@ func (tm *TrayManager) updateHotkeysMenuUI() {
 			rebindTooltip:  "Change show config hotkey",
 			displayPrefix:  "Show Config: ",
 			displayTooltip: "Current show config hotkey",
-			currentValue:   tm.config.Hotkeys.ShowConfig,
+			currentValue:   current.Hotkeys.ShowConfig,
 		},
 		{
 			actionName:     "reset_to_defaults",
@@ -424,7 +429,7 @@ func (tm *TrayManager) updateHotkeysMenuUI() {
 			rebindTooltip:  "Change reset defaults hotkey",
 			displayPrefix:  "Reset to Defaults: ",
 			displayTooltip: "Current reset to defaults hotkey",
-			currentValue:   tm.config.Hotkeys.ResetToDefaults,
+			currentValue:   current.Hotkeys.ResetToDefaults,
 		},
 	}
 
@@ -496,7 +501,8 @@ func (tm *TrayManager) updateWorkflowNotificationUI(enabled bool) {
 // updateOutputModeRadioUI updates selection marks for output mode menu
 func (tm *TrayManager) updateOutputModeRadioUI(mode string) {
 	modeDefs := map[string]string{
-		"clipboard": "Clipboard", "active_window": "Active Window",
+		"clipboard": "Clipboard", "active_window": "Active Window", "paste": "Paste", "file": "File (Journal)",
+		"webhook": "Webhook",
 	}
 
 	for key, title := range modeDefs {
@@ -517,16 +523,17 @@ func (tm *TrayManager) updateOutputModeRadioUI(mode string) {
 
 // updateOutputUI updates the output settings display
 func (tm *TrayManager) updateOutputUI() {
-	if tm.config == nil {
+	cfg := tm.currentConfig()
+	if cfg == nil {
 		return
 	}
 
 	// Update mode radio buttons and display
-	tm.updateOutputModeRadioUI(tm.config.Output.DefaultMode)
+	tm.updateOutputModeRadioUI(cfg.Output.DefaultMode)
 
 	// Get actual tool names if callback is available
-	clipboardTool := tm.config.Output.ClipboardTool
-	typeTool := tm.config.Output.TypeTool
+	clipboardTool := cfg.Output.ClipboardTool
+	typeTool := cfg.Output.TypeTool
 	if tm.onGetOutputTools != nil {
 		clipboardTool, typeTool = tm.onGetOutputTools()
 	}
@@ -594,6 +601,12 @@ func humanizeOutputMode(mode string) string {
 		return "Clipboard"
 	case "active_window":
 		return "Active Window"
+	case "paste":
+		return "Paste"
+	case "file":
+		return "File (Journal)"
+	case "webhook":
+		return "Webhook"
 	default:
 		return mode
 	}
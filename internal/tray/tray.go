@@ -24,11 +24,14 @@ type TrayManager struct {
 	onShowConfig      func() error
 	onShowAbout       func() error
 	onResetToDefaults func() error
-	config            *config.Config
+	onCancel          func() error
+	config            *config.Config // Latest snapshot passed to UpdateSettings (protected by configMu)
+	configMu          sync.Mutex
 	logger            logger.Logger
 
 	// Menu items
 	toggleItem       *systray.MenuItem
+	cancelItem       *systray.MenuItem // Shown only while recording
 	settingsItem     *systray.MenuItem
 	showConfigItem   *systray.MenuItem
 	aboutItem        *systray.MenuItem
@@ -41,6 +44,7 @@ type TrayManager struct {
 	languageMenu      *systray.MenuItem
 	modelMenu         *systray.MenuItem
 	outputMenu        *systray.MenuItem
+	recentMenu        *systray.MenuItem
 
 	// Dynamic settings items
 	hotkeyItems   map[string]*systray.MenuItem
@@ -48,6 +52,9 @@ type TrayManager struct {
 	languageItems map[string]*systray.MenuItem
 	modelItems    map[string]*systray.MenuItem
 	outputItems   map[string]*systray.MenuItem
+	recentItems   []*systray.MenuItem // Fixed slots, hidden when unused
+	recentIDs     []int64             // History IDs shown in recentItems (protected by recentMu)
+	recentMu      sync.Mutex
 
 	// Audio action callbacks
 	onSelectRecorder func(method string) error
@@ -66,6 +73,9 @@ type TrayManager struct {
 
 	// Capability callbacks
 	getCaptureOnceSupport func() bool
+	// History callbacks
+	onListRecent   func() []RecentItem
+	onSelectRecent func(id int64) error
 
 	// Cancellation context for background menu handlers
 	ctx    context.Context
@@ -120,12 +130,18 @@ func (tm *TrayManager) onReady() {
 	systray.SetTitle("Dabri")
 	// Create main menu items
 	tm.toggleItem = systray.AddMenuItem("Start Recording", "Start/Stop recording")
+	tm.cancelItem = systray.AddMenuItem("Cancel Recording", "Discard the recording without transcribing")
+	tm.cancelItem.Hide()
 	// Workflow notifications toggle (radio indicator is set by updateWorkflowNotificationUI)
 	tm.audioItems["workflow_notifications"] = systray.AddMenuItem(
 		"○ Workflow Notifications",
 		"Toggle workflow notifications (recording, transcription)",
 	)
 
+	// Recent transcripts submenu
+	tm.recentMenu = systray.AddMenuItem("Recent", "Copy a recent transcript to the clipboard")
+	tm.createRecentSubmenu()
+
 	systray.AddSeparator()
 	// Settings submenu
 	tm.settingsItem = systray.AddMenuItem("Settings", "Application settings")
@@ -156,7 +172,9 @@ func (tm *TrayManager) onReady() {
 // UpdateSettings updates the settings display with new configuration
 func (tm *TrayManager) UpdateSettings(config *config.Config) {
 	// Store and delegate all UI updates to helpers in settings_menu.go
+	tm.configMu.Lock()
 	tm.config = config
+	tm.configMu.Unlock()
 	// Ensure hotkeys UI reflects latest config
 	tm.updateHotkeysMenuUI()
 	// The helpers gracefully no-op if items are not yet created
@@ -167,6 +185,13 @@ func (tm *TrayManager) UpdateSettings(config *config.Config) {
 	tm.updateOutputUI()
 }
 
+// currentConfig returns the configuration last passed to UpdateSettings (nil before the first call)
+func (tm *TrayManager) currentConfig() *config.Config {
+	tm.configMu.Lock()
+	defer tm.configMu.Unlock()
+	return tm.config
+}
+
 // handleMenuClicks handles all menu item clicks
 func (tm *TrayManager) handleMenuClicks() {
 	for {
@@ -180,6 +205,13 @@ func (tm *TrayManager) handleMenuClicks() {
 					tm.logger.Error("Error toggling recording: %v", err)
 				}
 			}
+		case <-tm.cancelItem.ClickedCh:
+			tm.logger.Info("Cancel recording clicked")
+			if tm.onCancel != nil {
+				if err := tm.onCancel(); err != nil {
+					tm.logger.Error("Error cancelling recording: %v", err)
+				}
+			}
 		case <-tm.showConfigItem.ClickedCh:
 			tm.logger.Info("Show config clicked")
 			if tm.onShowConfig != nil {
@@ -228,9 +260,11 @@ func (tm *TrayManager) SetRecordingState(isRecording bool) {
 	if isRecording {
 		systray.SetIcon(tm.iconMicOn)
 		tm.toggleItem.SetTitle("Stop Recording")
+		tm.cancelItem.Show()
 	} else {
 		systray.SetIcon(tm.iconMicOff)
 		tm.toggleItem.SetTitle("Start Recording")
+		tm.cancelItem.Hide()
 	}
 }
 
@@ -243,6 +277,11 @@ func (tm *TrayManager) Stop() {
 	tm.wg.Wait()
 }
 
+// SetCancelAction sets the callback discarding the current recording
+func (tm *TrayManager) SetCancelAction(onCancel func() error) {
+	tm.onCancel = onCancel
+}
+
 // SetAudioActions sets callbacks for audio-related actions (recorder selection)
 func (tm *TrayManager) SetAudioActions(onSelectRecorder func(method string) error) {
 	tm.onSelectRecorder = onSelectRecorder
@@ -283,3 +322,10 @@ func (tm *TrayManager) OutputToolsCallback(callback func() (clipboardTool, typeT
 func (tm *TrayManager) SetCaptureOnceSupport(callback func() bool) {
 	tm.getCaptureOnceSupport = callback
 }
+
+// SetHistoryActions sets callbacks listing recent transcripts and re-outputting one by ID
+func (tm *TrayManager) SetHistoryActions(onListRecent func() []RecentItem, onSelectRecent func(id int64) error) {
+	tm.onListRecent = onListRecent
+	tm.onSelectRecent = onSelectRecent
+	tm.RefreshRecent()
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Code dictation mode: converts spoken phrases into source code text.
+//
+//	"camel case user name equals get user open paren close paren"
+//	→ "userName = get user()"
+//
+// Casing commands apply to the words that follow until the next symbol,
+// casing command, or the end of the transcript. Sentence capitalization
+// and trailing punctuation added by whisper are dropped.
+
+// codeSymbol describes how a spoken symbol is emitted
+type codeSymbol struct {
+	text        string
+	attachLeft  bool // No space before the symbol
+	attachRight bool // No space after the symbol
+}
+
+// codeSymbols maps spoken phrases (lowercase, space-separated) to symbols
+var codeSymbols = map[string]codeSymbol{
+	"dot":           {".", true, true},
+	"arrow":         {"->", true, true},
+	"fat arrow":     {"=>", false, false},
+	"equals":        {"=", false, false},
+	"double equals": {"==", false, false},
+	"not equals":    {"!=", false, false},
+	"colon equals":  {":=", false, false},
+	"plus":          {"+", false, false},
+	"minus":         {"-", false, false},
+	"star":          {"*", false, false},
+	"slash":         {"/", true, true},
+	"underscore":    {"_", true, true},
+	"comma":         {",", true, false},
+	"colon":         {":", true, false},
+	"semicolon":     {";", true, false},
+	"open paren":    {"(", true, true},
+	"close paren":   {")", true, false},
+	"open bracket":  {"[", true, true},
+	"close bracket": {"]", true, false},
+	"open brace":    {"{", false, false},
+	"close brace":   {"}", false, false},
+	"new line":      {"\n", true, true},
+	"less than":     {"<", false, false},
+	"greater than":  {">", false, false},
+	"ampersand":     {"&", false, false},
+	"pipe":          {"|", false, false},
+	"bang":          {"!", false, true},
+	"question mark": {"?", true, false},
+	"hash":          {"#", false, true},
+	"at sign":       {"@", false, true},
+	"dollar sign":   {"$", false, true},
+	"percent":       {"%", false, false},
+	"double quote":  {"\"", false, false},
+	"single quote":  {"'", false, false},
+	"backtick":      {"`", false, false},
+	"open angle":    {"<", true, true},
+	"close angle":   {">", true, false},
+	"double colon":  {"::", true, true},
+	"triple equals": {"===", false, false},
+	"plus equals":   {"+=", false, false},
+	"minus equals":  {"-=", false, false},
+	"double amp":    {"&&", false, false},
+	"double pipe":   {"||", false, false},
+}
+
+// identifierCase joins lowercase words into a single identifier
+type identifierCase func(words []string) string
+
+// codeCasings maps spoken casing commands to their formatters
+var codeCasings = map[string]identifierCase{
+	"camel case":  camelCase,
+	"camelcase":   camelCase,
+	"pascal case": pascalCase,
+	"pascalcase":  pascalCase,
+	"snake case":  func(w []string) string { return strings.Join(w, "_") },
+	"snakecase":   func(w []string) string { return strings.Join(w, "_") },
+	"kebab case":  func(w []string) string { return strings.Join(w, "-") },
+	"kebabcase":   func(w []string) string { return strings.Join(w, "-") },
+}
+
+// maxPhraseWords is the longest spoken phrase in codeSymbols/codeCasings
+const maxPhraseWords = 2
+
+// codeToken is an emitted piece of output with its spacing rules
+type codeToken struct {
+	text        string
+	attachLeft  bool
+	attachRight bool
+}
+
+// FormatCodeTranscript converts a transcript into code using spoken symbol
+// words and casing commands. Whisper placeholders are stripped first
+func FormatCodeTranscript(input string) string {
+	words := codeWords(SanitizeTranscript(input))
+	var (
+		tokens  []codeToken
+		casing  identifierCase
+		pending []string
+	)
+	flush := func() {
+		if casing != nil && len(pending) > 0 {
+			tokens = append(tokens, codeToken{text: casing(pending)})
+		}
+		casing, pending = nil, nil
+	}
+	for i := 0; i < len(words); {
+		phrase, n := matchPhrase(words[i:])
+		if c, ok := codeCasings[phrase]; ok && n > 0 {
+			flush()
+			casing = c
+			i += n
+			continue
+		}
+		if sym, ok := codeSymbols[phrase]; ok && n > 0 {
+			flush()
+			tokens = append(tokens, codeToken(sym))
+			i += n
+			continue
+		}
+		if casing != nil {
+			pending = append(pending, words[i])
+		} else {
+			tokens = append(tokens, codeToken{text: words[i]})
+		}
+		i++
+	}
+	flush()
+	return joinCodeTokens(tokens)
+}
+
+// codeWords splits text into lowercase words without sentence punctuation
+func codeWords(text string) []string {
+	fields := strings.Fields(text)
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		w := strings.TrimRight(f, ".,!?;:")
+		if w == "" {
+			continue
+		}
+		words = append(words, strings.ToLower(w))
+	}
+	return words
+}
+
+// matchPhrase returns the longest known phrase at the start of words
+func matchPhrase(words []string) (string, int) {
+	for n := min(maxPhraseWords, len(words)); n > 0; n-- {
+		phrase := strings.Join(words[:n], " ")
+		if _, ok := codeSymbols[phrase]; ok {
+			return phrase, n
+		}
+		if _, ok := codeCasings[phrase]; ok {
+			return phrase, n
+		}
+	}
+	return "", 0
+}
+
+// joinCodeTokens concatenates tokens honoring their attach rules
+func joinCodeTokens(tokens []codeToken) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && !tokens[i-1].attachRight && !t.attachLeft {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+func camelCase(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0] + pascalCase(words[1:])
+}
+
+func pascalCase(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
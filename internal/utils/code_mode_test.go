@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import "testing"
+
+func TestFormatCodeTranscript(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "camel case", input: "Camel case user name.", expected: "userName"},
+		{name: "pascal case", input: "Pascal case http client", expected: "HttpClient"},
+		{name: "snake case", input: "snake case max retry count", expected: "max_retry_count"},
+		{name: "kebab case", input: "Kebab case dry run", expected: "dry-run"},
+		{name: "single word casing command", input: "camelcase is ready", expected: "isReady"},
+		{
+			name:     "symbols end casing scope",
+			input:    "Camel case user name equals get user open paren close paren.",
+			expected: "userName = get user()",
+		},
+		{name: "dot and arrow", input: "config dot output arrow mode", expected: "config.output->mode"},
+		{name: "two word symbols", input: "x colon equals y not equals z", expected: "x := y != z"},
+		{name: "comma spacing", input: "open paren a comma b close paren", expected: "(a, b)"},
+		{name: "whisper punctuation dropped", input: "Return, nil.", expected: "return nil"},
+		{name: "placeholders removed", input: "[MUSIC] snake case foo bar", expected: "foo_bar"},
+		{name: "casing without words", input: "camel case", expected: ""},
+		{name: "empty", input: "", expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatCodeTranscript(tt.input); got != tt.expected {
+				t.Errorf("FormatCodeTranscript(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostProcessTranscript_CodeMode(t *testing.T) {
+	if got := PostProcessTranscript("Snake case file name.", "code"); got != "file_name" {
+		t.Errorf("code mode: got %q", got)
+	}
+}
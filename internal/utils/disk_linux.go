@@ -13,21 +13,26 @@ import (
 
 // CheckDiskSpace ensures there's enough disk space available (Linux implementation)
 func CheckDiskSpace(path string) error {
-	dir := filepath.Dir(path)
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs(dir, &stat); err != nil {
+	available, err := FreeDiskSpace(filepath.Dir(path))
+	if err != nil {
 		return err
 	}
-
-	// Guard against invalid block size reported by the system
-	if stat.Bsize <= 0 {
-		return fmt.Errorf("invalid block size: %d", stat.Bsize)
-	}
-
-	available := stat.Bavail * uint64(stat.Bsize)
 	const requiredSpace uint64 = 100 * 1024 * 1024
 	if available < requiredSpace {
 		return fmt.Errorf("insufficient disk space: %d bytes available, %d required", available, requiredSpace)
 	}
 	return nil
 }
+
+// FreeDiskSpace returns the bytes available to unprivileged users on the filesystem holding dir
+func FreeDiskSpace(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	// Guard against invalid block size reported by the system
+	if stat.Bsize <= 0 {
+		return 0, fmt.Errorf("invalid block size: %d", stat.Bsize)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
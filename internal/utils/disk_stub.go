@@ -5,7 +5,14 @@
 
 package utils
 
+import "errors"
+
 // CheckDiskSpace is a no-op on non-Linux platforms
 func CheckDiskSpace(path string) error {
 	return nil
 }
+
+// FreeDiskSpace is not supported on non-Linux platforms
+func FreeDiskSpace(dir string) (uint64, error) {
+	return 0, errors.New("free disk space not supported on this platform")
+}
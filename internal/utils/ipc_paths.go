@@ -13,17 +13,31 @@ import (
 const (
 	// DefaultSocketFileName is the default IPC socket filename.
 	DefaultSocketFileName = "dabri.sock"
+	// DefaultMetricsSocketFileName is the default metrics socket filename.
+	DefaultMetricsSocketFileName = "dabri-metrics.sock"
 )
 
 // GetDefaultSocketPath returns the default IPC socket path.
 func GetDefaultSocketPath() string {
+	return runtimeSocketPath(DefaultSocketFileName)
+}
+
+// GetDefaultMetricsSocketPath returns the default metrics socket path,
+// used while metrics are enabled without the web server.
+func GetDefaultMetricsSocketPath() string {
+	return runtimeSocketPath(DefaultMetricsSocketFileName)
+}
+
+// runtimeSocketPath places a socket in $XDG_RUNTIME_DIR, falling back to the
+// config directory and then the temp directory.
+func runtimeSocketPath(name string) string {
 	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
-		return filepath.Join(runtimeDir, DefaultSocketFileName)
+		return filepath.Join(runtimeDir, name)
 	}
 
 	if configDir, err := config.EnsureConfigDir(); err == nil {
-		return filepath.Join(configDir, DefaultSocketFileName)
+		return filepath.Join(configDir, name)
 	}
 
-	return filepath.Join(os.TempDir(), DefaultSocketFileName)
+	return filepath.Join(os.TempDir(), name)
 }
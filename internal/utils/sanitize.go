@@ -6,6 +6,8 @@ package utils
 import (
 	"regexp"
 	"strings"
+
+	"github.com/AshBuk/dabri/config"
 )
 
 // Bracketed placeholders like [music], [BLANK_AUDIO], etc. (Unicode letters supported)
@@ -23,3 +25,18 @@ func SanitizeTranscript(input string) string {
 	cleaned = strings.TrimSpace(cleaned)
 	return cleaned
 }
+
+// postProcessors maps config post-processing modes to transcript transforms
+var postProcessors = map[string]func(string) string{
+	config.PostProcessingDefault: SanitizeTranscript,
+	config.PostProcessingCode:    FormatCodeTranscript,
+}
+
+// PostProcessTranscript applies the named post-processing mode to a transcript.
+// Empty or unknown modes fall back to the default sanitizer
+func PostProcessTranscript(input, mode string) string {
+	if process, ok := postProcessors[mode]; ok {
+		return process(input)
+	}
+	return SanitizeTranscript(input)
+}
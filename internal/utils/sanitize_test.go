@@ -197,3 +197,14 @@ func BenchmarkSanitizeTranscript_LargeInput(b *testing.B) {
 		SanitizeTranscript(input)
 	}
 }
+
+func TestPostProcessTranscript(t *testing.T) {
+	input := "  hello [MUSIC] world  "
+	if got := PostProcessTranscript(input, "default"); got != "hello world" {
+		t.Errorf("default mode: got %q", got)
+	}
+	// Unknown modes fall back to default sanitization
+	if got := PostProcessTranscript(input, "unknown"); got != "hello world" {
+		t.Errorf("unknown mode: got %q", got)
+	}
+}
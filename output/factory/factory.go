@@ -34,7 +34,7 @@ const (
 //	    │       │
 //	    │       └── uses → outputFactory.GetOutputterFromConfig() (this file)
 //	    │                     │
-//	    │                     └── creates → ClipboardOutputter or TypeOutputter
+//	    │                     └── creates → ClipboardOutputter, TypeOutputter, UinputOutputter, PasteOutputter, FileOutputter or WebhookOutputter
 //	    │
 //	    ├── Stage 2: FactoryAssembler
 //	    └── Stage 3: FactoryWirer
@@ -42,15 +42,19 @@ const (
 // Tool Selection Strategy:
 //   - Auto-detection: checks environment (X11/Wayland/GNOME) and tool availability
 //   - Priority chains: Wayland: wl-copy | X11: xsel (clipboard)
-//     GNOME+Wayland: ydotool>wtype | Other Wayland: wtype>ydotool | X11: xdotool (typing)
+//   - Typing tools: ranked by session support and capabilities (Unicode, key events,
+//     window targeting) from the outputters typing tool registry, e.g.
+//     X11: xdotool | GNOME+Wayland: ydotool>dotool | wlroots: wtype>wlrctl>ydotool
 //   - Config override: manual tool selection via config.Output.ClipboardTool/TypeTool
+//     (type_tool "uinput" selects the built-in /dev/uinput typer, see UinputOutputter)
 //   - Security: allowlist validation via config.IsCommandAllowed
 //
 // Usage:
 //
-//	GetOutputterFromConfig(config, EnvironmentWayland) // one-line creation
+//	GetOutputterFromConfig(config, EnvironmentWayland, webhookWorker) // one-line creation
 type Factory struct {
-	config *config.Config
+	config  *config.Config
+	webhook *outputters.WebhookWorker // Delivers webhook output (nil: webhook mode unavailable)
 }
 
 // NewFactory Constructor - initializes factory with config for tool selection
@@ -58,6 +62,12 @@ func NewFactory(config *config.Config) *Factory {
 	return &Factory{config: config}
 }
 
+// WithWebhookWorker sets the worker that delivers webhook output; the caller owns and closes it
+func (f *Factory) WithWebhookWorker(worker *outputters.WebhookWorker) *Factory {
+	f.webhook = worker
+	return f
+}
+
 // selectClipboardTool Tool selection - chooses clipboard tool based on environment
 // Config override: returns config.Output.ClipboardTool if not "auto"
 func (f *Factory) selectClipboardTool(env EnvironmentType) string {
@@ -76,89 +86,67 @@ func (f *Factory) selectClipboardTool(env EnvironmentType) string {
 
 // selectTypeTool Tool selection - chooses typing tool based on environment
 // Config override: returns config.Output.TypeTool if not "auto"
-func (f *Factory) selectTypeTool(env EnvironmentType) string {
+// Auto: best-ranked registry tool that is allowed and available; needKeyEvents
+// restricts the ranking to tools able to send key combinations (paste mode)
+func (f *Factory) selectTypeTool(env EnvironmentType, needKeyEvents bool) string {
 	if tool := f.config.Output.TypeTool; tool != "auto" {
 		return tool
 	}
-	switch env {
-	case EnvironmentWayland:
-		return f.selectWaylandTypeTool()
-	case EnvironmentX11:
-		return "xdotool"
-	default:
-		return f.selectFallbackTypeTool()
-	}
-}
-
-// selectWaylandTypeTool Wayland-specific selection - delegates to GNOME vs non-GNOME chains
-func (f *Factory) selectWaylandTypeTool() string {
-	if platform.IsGNOMEWithWayland() {
-		return f.selectGNOMEWaylandTypeTool()
-	}
-	return f.selectNonGNOMEWaylandTypeTool()
-}
-
-// selectGNOMEWaylandTypeTool GNOME+Wayland priority chain
-// Priority: ydotool (best compatibility) → wtype → xdotool (XWayland fallback)
-func (f *Factory) selectGNOMEWaylandTypeTool() string {
-	if f.isToolAvailable("ydotool") {
-		return "ydotool"
-	}
-	if f.isToolAvailable("wtype") {
-		return "wtype"
-	}
-	return "xdotool"
-}
-
-// selectNonGNOMEWaylandTypeTool Non-GNOME Wayland priority chain
-// Priority: wtype (native Wayland) → ydotool → xdotool (XWayland fallback)
-func (f *Factory) selectNonGNOMEWaylandTypeTool() string {
-	if f.isToolAvailable("wtype") {
-		return "wtype"
-	}
-	if f.isToolAvailable("ydotool") {
-		return "ydotool"
-	}
-	return "xdotool"
-}
-
-// selectFallbackTypeTool Unknown environment - auto-detect first available tool
-// Priority: xdotool (widest compatibility) → wtype → ydotool
-func (f *Factory) selectFallbackTypeTool() string {
-	for _, tool := range []string{"xdotool", "wtype", "ydotool"} {
-		if f.isToolAvailable(tool) {
-			return tool
+	ranked := outputters.RankTypingTools(outputters.DetectTypingSession(env), needKeyEvents)
+	for _, tool := range ranked {
+		if config.IsCommandAllowed(f.config, tool.Name) && f.isToolAvailable(tool.Name) && tool.Available() == nil {
+			return tool.Name
 		}
 	}
+	// Nothing usable: report the tool best suited to the session
+	if len(ranked) > 0 {
+		return ranked[0].Name
+	}
 	return "xdotool"
 }
 
 // GetOutputter Factory Method - creates outputter instance from environment + config
 // Process: tool selection → security validation → outputter creation
 // Security: validates tools via config.IsCommandAllowed before instantiation
-// Returns ClipboardOutputter or TypeOutputter based on config.Output.DefaultMode
+// Returns ClipboardOutputter, TypeOutputter, PasteOutputter, FileOutputter or WebhookOutputter based on config.Output.DefaultMode
 func (f *Factory) GetOutputter(env EnvironmentType) (interfaces.Outputter, error) {
+	// Journal and webhook output need no external tools
+	switch f.config.Output.DefaultMode {
+	case config.OutputModeFile:
+		return outputters.NewFileOutputter(f.config)
+	case config.OutputModeWebhook:
+		return outputters.NewWebhookOutputter(f.config, f.webhook)
+	}
 	clipboardTool := f.selectClipboardTool(env)
-	typeTool := f.selectTypeTool(env)
+	typeTool := f.selectTypeTool(env, f.config.Output.DefaultMode == config.OutputModePaste)
 	if clipboardTool != "" && !config.IsCommandAllowed(f.config, clipboardTool) {
 		return nil, fmt.Errorf("clipboard tool not allowed: %s", clipboardTool)
 	}
-	if typeTool != "" && !config.IsCommandAllowed(f.config, typeTool) {
+	// The uinput typer is built in and runs no external command
+	if typeTool != "" && typeTool != outputters.UinputTypeTool && !config.IsCommandAllowed(f.config, typeTool) {
 		return nil, fmt.Errorf("type tool not allowed: %s", typeTool)
 	}
 	switch f.config.Output.DefaultMode {
 	case config.OutputModeClipboard:
 		return outputters.NewClipboardOutputter(clipboardTool, f.config)
 	case config.OutputModeActiveWindow:
+		if typeTool == outputters.UinputTypeTool {
+			return outputters.NewUinputOutputter(clipboardTool, f.config)
+		}
 		return outputters.NewTypeOutputter(typeTool, f.config)
+	case config.OutputModePaste:
+		if typeTool == outputters.UinputTypeTool {
+			return nil, fmt.Errorf("paste mode requires an external typing tool, not %s", typeTool)
+		}
+		return outputters.NewPasteOutputter(clipboardTool, typeTool, f.config)
 	default:
 		return outputters.NewClipboardOutputter(clipboardTool, f.config)
 	}
 }
 
-// Create an outputter directly from a configuration
-func GetOutputterFromConfig(config *config.Config, env EnvironmentType) (interfaces.Outputter, error) {
-	factory := NewFactory(config)
+// Create an outputter directly from a configuration; webhook delivers webhook output
+func GetOutputterFromConfig(config *config.Config, env EnvironmentType, webhook *outputters.WebhookWorker) (interfaces.Outputter, error) {
+	factory := NewFactory(config).WithWebhookWorker(webhook)
 	return factory.GetOutputter(env)
 }
 
@@ -377,7 +377,7 @@ func TestGetOutputterFromConfig_ErrorHandling(t *testing.T) {
 				}
 			}()
 
-			outputter, err := GetOutputterFromConfig(tt.config, tt.env)
+			outputter, err := GetOutputterFromConfig(tt.config, tt.env, nil)
 
 			if tt.expectError {
 				if err == nil {
@@ -4,6 +4,7 @@
 package factory
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/AshBuk/dabri/config"
@@ -191,7 +192,7 @@ func TestGetOutputterFromConfig(t *testing.T) {
 			config.Output.ClipboardTool = "auto"
 			config.Output.TypeTool = "auto"
 
-			outputter, err := GetOutputterFromConfig(config, tt.env)
+			outputter, err := GetOutputterFromConfig(config, tt.env, nil)
 
 			if tt.expectError && err == nil {
 				t.Errorf("expected error but got none")
@@ -237,3 +238,20 @@ func TestEnvironmentType_Constants(t *testing.T) {
 		})
 	}
 }
+
+func TestFactory_GetOutputter_Uinput(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Output.TypeTool = "uinput"
+	cfg.Output.ClipboardTool = "xsel"
+	cfg.Security.AllowedCommands = []string{"xsel"} // uinput runs no command, so it needs no allowlist entry
+
+	cfg.Output.DefaultMode = config.OutputModeActiveWindow
+	if _, err := NewFactory(cfg).GetOutputter(EnvironmentWayland); err != nil && strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("uinput must not be checked against the command allowlist: %v", err)
+	}
+
+	cfg.Output.DefaultMode = config.OutputModePaste
+	if _, err := NewFactory(cfg).GetOutputter(EnvironmentWayland); err == nil {
+		t.Error("expected paste mode to reject the uinput typer")
+	}
+}
@@ -4,6 +4,8 @@
 package factory
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/AshBuk/dabri/config"
@@ -73,7 +75,7 @@ func TestSecurityAllowedCommands(t *testing.T) {
 	config.SetDefaultConfig(cfg)
 
 	// Test that new Wayland tools are in allowed commands
-	waylandTools := []string{"wtype", "ydotool"}
+	waylandTools := []string{"wtype", "ydotool", "dotool", "wlrctl"}
 
 	for _, tool := range waylandTools {
 		if !config.IsCommandAllowed(cfg, tool) {
@@ -90,3 +92,45 @@ func TestSecurityAllowedCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestSelectTypeTool_RanksAvailableTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		desktop   string
+		installed []string
+		allowed   []string
+		paste     bool
+		expected  string
+	}{
+		{"native wayland tool first", "KDE", []string{"xdotool", "ydotool", "wtype"}, nil, false, "wtype"},
+		{"skips missing tools", "KDE", []string{"xdotool", "ydotool"}, nil, false, "ydotool"},
+		{"skips disallowed tools", "KDE", []string{"wtype", "ydotool"}, []string{"ydotool"}, false, "ydotool"},
+		{"gnome has no virtual keyboard", "GNOME", []string{"wtype", "xdotool"}, nil, false, "xdotool"},
+		{"wlroots unicode typer", "sway", []string{"wlrctl", "ydotool"}, nil, false, "wlrctl"},
+		{"paste needs key events", "sway", []string{"wlrctl", "ydotool"}, nil, true, "ydotool"},
+		{"nothing installed", "GNOME", nil, nil, false, "ydotool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, tool := range tt.installed {
+				if err := os.WriteFile(filepath.Join(dir, tool), []byte("#!/bin/sh\n"), 0700); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", dir)
+			t.Setenv("XDG_CURRENT_DESKTOP", tt.desktop)
+			t.Setenv("SWAYSOCK", "")
+			t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+
+			cfg := &config.Config{}
+			config.SetDefaultConfig(cfg)
+			if tt.allowed != nil {
+				cfg.Security.AllowedCommands = tt.allowed
+			}
+			if got := NewFactory(cfg).selectTypeTool(EnvironmentWayland, tt.paste); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
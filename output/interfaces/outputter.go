@@ -3,12 +3,76 @@
 
 package interfaces
 
+import (
+	"context"
+	"time"
+)
+
 // Defines the contract for text output operations
 type Outputter interface {
 	// Copy text to the system clipboard
 	CopyToClipboard(text string) error
 	// Simulate typing text into the currently active window
 	TypeToActiveWindow(text string) error
+	// Read the current text content of the system clipboard
+	ReadClipboard() (string, error)
 	// Return the names of the underlying tools being used
 	GetToolNames() (clipboardTool, typeTool string)
 }
+
+// Delivery status of a single output target
+const (
+	TargetStatusOK       = "ok"       // Delivered by the target itself
+	TargetStatusFallback = "fallback" // Target failed; delivered by its fallback mode
+	TargetStatusFailed   = "failed"   // Target and its fallback (if any) failed
+	TargetStatusSkipped  = "skipped"  // Not attempted, or refused by the clipboard guard
+	TargetStatusCanceled = "canceled" // Typing was aborted by a new hotkey press
+)
+
+// TargetResult reports the outcome of delivering a transcript to one output target
+type TargetResult struct {
+	Target   string `json:"target"`
+	Status   string `json:"status"`
+	Fallback string `json:"fallback,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// TranscriptionResult is what a client waiting for a dictation receives
+type TranscriptionResult struct {
+	Text    string
+	Outputs []TargetResult // Per-target results of this dictation (nil when not delivered)
+}
+
+// Transcript is a dictation result with metadata about the session it came from
+type Transcript struct {
+	Text      string
+	SessionID string        // Recording session identifier (empty for ad-hoc output)
+	Language  string        // Recognition language
+	Duration  time.Duration // Recording duration
+	Timestamp time.Time
+	WindowID  string // Window focused at recording start; typing refocuses it (empty types into the focused window)
+}
+
+// TranscriptOutputter is implemented by outputters that record transcript
+// metadata (journal, webhook) in addition to the plain text
+type TranscriptOutputter interface {
+	OutputTranscript(t Transcript) error
+}
+
+// ContextTyper is implemented by outputters whose typing can be aborted
+// part-way; typing stops when ctx is canceled
+type ContextTyper interface {
+	TypeToActiveWindowContext(ctx context.Context, text string) error
+}
+
+// Eraser is implemented by outputters that can delete text they typed by
+// pressing BackSpace count times in the focused window
+type Eraser interface {
+	EraseTyped(count int) error
+}
+
+// UndoResult reports what undoing the last output reverted
+type UndoResult struct {
+	Erased            int  `json:"erased"`             // Typed characters deleted with BackSpace
+	ClipboardRestored bool `json:"clipboard_restored"` // Clipboard content from before the output was put back
+}
@@ -31,21 +31,31 @@ func NewClipboardOutputter(clipboardTool string, cfg *config.Config) (interfaces
 	}, nil
 }
 
-// Copy text to the system clipboard using the configured tool
+// Copy text to the configured selections using the configured tool
 func (o *ClipboardOutputter) CopyToClipboard(text string) error {
 	// Security: validate the command before execution
 	if !config.IsCommandAllowed(o.config, o.clipboardTool) {
 		return fmt.Errorf("clipboard tool not allowed: %s", o.clipboardTool)
 	}
+	for _, selection := range o.selections() {
+		if err := o.copyToSelection(selection, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	var cmd *exec.Cmd
+// copyToSelection writes text to a single selection ("clipboard" or "primary")
+func (o *ClipboardOutputter) copyToSelection(selection, text string) error {
 	var args []string
 
 	switch o.clipboardTool {
 	case "xsel":
-		args = []string{"--clipboard", "--input"}
+		args = []string{"--" + selection, "--input"}
 	case "wl-copy":
-		args = []string{} // wl-copy takes no additional args for this operation
+		if selection == config.ClipboardSelectionPrimary {
+			args = []string{"--primary"}
+		}
 	default:
 		return fmt.Errorf("unsupported clipboard tool: %s", o.clipboardTool)
 	}
@@ -53,17 +63,61 @@ func (o *ClipboardOutputter) CopyToClipboard(text string) error {
 	// Security: sanitize arguments
 	safeArgs := config.SanitizeCommandArgs(args)
 	// #nosec G204 -- Safe: tool is from an allowlist and arguments are sanitized
-	cmd = exec.Command(o.clipboardTool, safeArgs...)
+	cmd := exec.Command(o.clipboardTool, safeArgs...)
 	// Pipe the text to the command's standard input
 	cmd.Stdin = strings.NewReader(text)
-	// Run the command
-	err := cmd.Run()
-	if err != nil {
-		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to copy to %s selection: %w", selection, err)
 	}
 	return nil
 }
 
+// Read the current text of the main configured selection
+// (CLIPBOARD unless only PRIMARY is written)
+func (o *ClipboardOutputter) ReadClipboard() (string, error) {
+	return o.ReadSelection(o.selections()[0])
+}
+
+// ReadSelection reads a single selection using the reader paired with the configured tool
+func (o *ClipboardOutputter) ReadSelection(selection string) (string, error) {
+	var reader string
+	var args []string
+
+	switch o.clipboardTool {
+	case "xsel":
+		reader, args = "xsel", []string{"--" + selection, "--output"}
+	case "wl-copy":
+		reader, args = "wl-paste", []string{"--no-newline"}
+		if selection == config.ClipboardSelectionPrimary {
+			args = []string{"--primary", "--no-newline"}
+		}
+	default:
+		return "", fmt.Errorf("unsupported clipboard tool: %s", o.clipboardTool)
+	}
+	// Security: validate the command before execution
+	if !config.IsCommandAllowed(o.config, reader) {
+		return "", fmt.Errorf("clipboard tool not allowed: %s", reader)
+	}
+	// #nosec G204 -- Safe: tool is from an allowlist and arguments are constant
+	out, err := exec.Command(reader, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s selection: %w", selection, err)
+	}
+	return string(out), nil
+}
+
+// selections returns the selections written according to output.clipboard_selection
+func (o *ClipboardOutputter) selections() []string {
+	switch o.config.Output.ClipboardSelection {
+	case config.ClipboardSelectionPrimary:
+		return []string{config.ClipboardSelectionPrimary}
+	case config.ClipboardSelectionBoth:
+		return []string{config.ClipboardSelectionClipboard, config.ClipboardSelectionPrimary}
+	default:
+		return []string{config.ClipboardSelectionClipboard}
+	}
+}
+
 // Return an error as typing is not supported by this outputter
 func (o *ClipboardOutputter) TypeToActiveWindow(text string) error {
 	return fmt.Errorf("typing to active window not supported by clipboard outputter")
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/testutils"
 	"github.com/AshBuk/dabri/output/interfaces"
 )
 
@@ -152,6 +153,45 @@ func TestClipboardOutputter_CopyToClipboard_SupportedTools(t *testing.T) {
 	}
 }
 
+func TestClipboardOutputter_Selections(t *testing.T) {
+	tests := []struct {
+		selection     string
+		wantClipboard string
+		wantPrimary   string
+	}{
+		{config.ClipboardSelectionClipboard, "hello", ""},
+		{config.ClipboardSelectionPrimary, "", "hello"},
+		{config.ClipboardSelectionBoth, "hello", "hello"},
+	}
+	for _, tool := range []string{"xsel", "wl-copy"} {
+		for _, tt := range tests {
+			t.Run(tool+" "+tt.selection, func(t *testing.T) {
+				cb := testutils.NewFakeClipboard(t, "")
+				cfg := &config.Config{}
+				cfg.Security.AllowedCommands = []string{"xsel", "wl-copy", "wl-paste"}
+				cfg.Output.ClipboardSelection = tt.selection
+				out, err := NewClipboardOutputter(tool, cfg)
+				if err != nil {
+					t.Fatalf("NewClipboardOutputter: %v", err)
+				}
+
+				if err := out.CopyToClipboard("hello"); err != nil {
+					t.Fatalf("CopyToClipboard: %v", err)
+				}
+				if got := cb.Get(); got != tt.wantClipboard {
+					t.Errorf("expected CLIPBOARD %q, got %q", tt.wantClipboard, got)
+				}
+				if got := cb.Selection(testutils.SelectionPrimary); got != tt.wantPrimary {
+					t.Errorf("expected PRIMARY %q, got %q", tt.wantPrimary, got)
+				}
+				if got, err := out.ReadClipboard(); err != nil || got != "hello" {
+					t.Errorf("expected ReadClipboard to return the written text, got %q (%v)", got, err)
+				}
+			})
+		}
+	}
+}
+
 func TestClipboardOutputter_TypeToActiveWindow(t *testing.T) {
 	cfg := &config.Config{}
 	outputter := &ClipboardOutputter{
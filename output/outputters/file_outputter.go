@@ -0,0 +1,147 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+// journalEntry is a single JSONL journal record
+type journalEntry struct {
+	Timestamp string `json:"timestamp"`
+	Language  string `json:"language"`
+	Model     string `json:"model"`
+	Text      string `json:"text"`
+}
+
+// Implements the Outputter interface by appending transcripts to a journal
+// file (Markdown or JSONL) with optional daily rotation. Needs no window focus
+type FileOutputter struct {
+	config *config.Config
+	mu     sync.Mutex
+	now    func() time.Time
+}
+
+// Create a new journal file outputter
+func NewFileOutputter(cfg *config.Config) (interfaces.Outputter, error) {
+	if _, err := JournalDir(cfg); err != nil {
+		return nil, err
+	}
+	return &FileOutputter{config: cfg, now: time.Now}, nil
+}
+
+// Append text to the journal file. Delivers the transcript for "file" mode
+func (o *FileOutputter) TypeToActiveWindow(text string) error {
+	return o.Append(text, o.config.General.Language)
+}
+
+// Append the transcript to the journal with its recognition language
+func (o *FileOutputter) OutputTranscript(t interfaces.Transcript) error {
+	return o.Append(t.Text, t.Language)
+}
+
+// Append writes one journal entry with timestamp, recognition language and model
+func (o *FileOutputter) Append(text, language string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	dir, err := JournalDir(o.config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	now := o.now()
+	path := filepath.Join(dir, o.fileName(now))
+	entry, err := o.formatEntry(now, text, language)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal file: %w", err)
+	}
+	if _, err := f.WriteString(entry); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return f.Close()
+}
+
+// fileName returns the journal file name for the given time
+func (o *FileOutputter) fileName(now time.Time) string {
+	ext := ".md"
+	if o.config.Output.Journal.Format == config.JournalFormatJSONL {
+		ext = ".jsonl"
+	}
+	if o.config.Output.Journal.Rotation == config.JournalRotationNone {
+		return "journal" + ext
+	}
+	return now.Format("2006-01-02") + ext
+}
+
+// formatEntry renders a journal entry in the configured format
+func (o *FileOutputter) formatEntry(now time.Time, text, language string) (string, error) {
+	model := o.config.General.WhisperModel
+	if o.config.Output.Journal.Format == config.JournalFormatJSONL {
+		data, err := json.Marshal(journalEntry{
+			Timestamp: now.Format(time.RFC3339),
+			Language:  language,
+			Model:     model,
+			Text:      text,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to encode journal entry: %w", err)
+		}
+		return string(data) + "\n", nil
+	}
+	return fmt.Sprintf("## %s\n\n_language: %s, model: %s_\n\n%s\n\n",
+		now.Format("2006-01-02 15:04:05"), language, model, strings.TrimSpace(text)), nil
+}
+
+// Return an error as clipboard operations are not supported by this outputter
+func (o *FileOutputter) CopyToClipboard(text string) error {
+	return fmt.Errorf("copying to clipboard not supported by file outputter")
+}
+
+// Return an error as clipboard operations are not supported by this outputter
+func (o *FileOutputter) ReadClipboard() (string, error) {
+	return "", fmt.Errorf("reading clipboard not supported by file outputter")
+}
+
+// Return the tool names; the journal needs no external tools
+func (o *FileOutputter) GetToolNames() (clipboardTool, typeTool string) {
+	return "", "file"
+}
+
+// JournalDir resolves the journal directory, expanding "~" and falling
+// back to $XDG_DATA_HOME/dabri/journal when unset
+func JournalDir(cfg *config.Config) (string, error) {
+	dir := cfg.Output.Journal.Directory
+	if dir == "" {
+		dataDir, err := config.DataDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve journal directory: %w", err)
+		}
+		return filepath.Join(dataDir, "journal"), nil
+	}
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve journal directory: %w", err)
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	return dir, nil
+}
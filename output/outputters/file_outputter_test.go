@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+)
+
+func newTestFileOutputter(t *testing.T, format, rotation string, now time.Time) (*FileOutputter, string) {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	cfg.General.Language = "en"
+	cfg.General.WhisperModel = "small.en"
+	cfg.Output.Journal.Directory = dir
+	cfg.Output.Journal.Format = format
+	cfg.Output.Journal.Rotation = rotation
+	out, err := NewFileOutputter(cfg)
+	if err != nil {
+		t.Fatalf("NewFileOutputter: %v", err)
+	}
+	fo := out.(*FileOutputter)
+	fo.now = func() time.Time { return now }
+	return fo, dir
+}
+
+func TestFileOutputter_Markdown(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 26, 53, 0, time.Local)
+	out, dir := newTestFileOutputter(t, config.JournalFormatMarkdown, config.JournalRotationDaily, now)
+
+	if err := out.TypeToActiveWindow("hello world"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	if err := out.Append("bonjour", "fr"); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "2025-03-14.md"))
+	if err != nil {
+		t.Fatalf("journal file not written: %v", err)
+	}
+	expected := "## 2025-03-14 09:26:53\n\n_language: en, model: small.en_\n\nhello world\n\n" +
+		"## 2025-03-14 09:26:53\n\n_language: fr, model: small.en_\n\nbonjour\n\n"
+	if string(data) != expected {
+		t.Errorf("unexpected journal content:\n%q\nwant:\n%q", data, expected)
+	}
+}
+
+func TestFileOutputter_JSONL(t *testing.T) {
+	now := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+	out, dir := newTestFileOutputter(t, config.JournalFormatJSONL, config.JournalRotationDaily, now)
+
+	for _, text := range []string{"first", "second \"quoted\""} {
+		if err := out.TypeToActiveWindow(text); err != nil {
+			t.Fatalf("TypeToActiveWindow: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "2025-03-14.jsonl"))
+	if err != nil {
+		t.Fatalf("journal file not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), data)
+	}
+	var entry journalEntry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("invalid JSONL line: %v", err)
+	}
+	want := journalEntry{Timestamp: "2025-03-14T09:26:53Z", Language: "en", Model: "small.en", Text: "second \"quoted\""}
+	if entry != want {
+		t.Errorf("expected %+v, got %+v", want, entry)
+	}
+}
+
+func TestFileOutputter_Rotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		rotation string
+		expected []string
+	}{
+		{"daily markdown", config.JournalFormatMarkdown, config.JournalRotationDaily, []string{"2025-03-14.md", "2025-03-15.md"}},
+		{"daily jsonl", config.JournalFormatJSONL, config.JournalRotationDaily, []string{"2025-03-14.jsonl", "2025-03-15.jsonl"}},
+		{"none markdown", config.JournalFormatMarkdown, config.JournalRotationNone, []string{"journal.md"}},
+		{"none jsonl", config.JournalFormatJSONL, config.JournalRotationNone, []string{"journal.jsonl"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := time.Date(2025, 3, 14, 23, 59, 0, 0, time.Local)
+			out, dir := newTestFileOutputter(t, tt.format, tt.rotation, day)
+			if err := out.TypeToActiveWindow("before midnight"); err != nil {
+				t.Fatalf("TypeToActiveWindow: %v", err)
+			}
+			out.now = func() time.Time { return day.Add(2 * time.Minute) }
+			if err := out.TypeToActiveWindow("after midnight"); err != nil {
+				t.Fatalf("TypeToActiveWindow: %v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("ReadDir: %v", err)
+			}
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected files %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestFileOutputter_CreatesDirectory(t *testing.T) {
+	now := time.Date(2025, 3, 14, 12, 0, 0, 0, time.Local)
+	out, dir := newTestFileOutputter(t, config.JournalFormatMarkdown, config.JournalRotationDaily, now)
+	nested := filepath.Join(dir, "notes", "dictation")
+	out.config.Output.Journal.Directory = nested
+
+	if err := out.TypeToActiveWindow("text"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(nested, "2025-03-14.md"))
+	if err != nil {
+		t.Fatalf("journal file not created: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected file mode 0600, got %o", info.Mode().Perm())
+	}
+}
+
+func TestFileOutputter_ClipboardUnsupported(t *testing.T) {
+	out, _ := newTestFileOutputter(t, config.JournalFormatMarkdown, config.JournalRotationDaily, time.Now())
+	if err := out.CopyToClipboard("text"); err == nil {
+		t.Error("expected CopyToClipboard to fail")
+	}
+	if _, err := out.ReadClipboard(); err == nil {
+		t.Error("expected ReadClipboard to fail")
+	}
+	if _, typeTool := out.GetToolNames(); typeTool != "file" {
+		t.Errorf("expected type tool 'file', got %q", typeTool)
+	}
+}
+
+func TestJournalDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{"", filepath.Join(home, ".local", "share", "dabri", "journal")},
+		{"~", home},
+		{"~/notes", filepath.Join(home, "notes")},
+		{"/var/tmp/journal", "/var/tmp/journal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Output.Journal.Directory = tt.dir
+			got, err := JournalDir(cfg)
+			if err != nil {
+				t.Fatalf("JournalDir: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
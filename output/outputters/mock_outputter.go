@@ -54,6 +54,14 @@ func (m *MockOutputter) TypeToActiveWindow(text string) error {
 	return nil
 }
 
+// ReadClipboard returns the last text copied to the mock clipboard
+func (m *MockOutputter) ReadClipboard() (string, error) {
+	if m.clipboardError != nil {
+		return "", m.clipboardError
+	}
+	return m.clipboardContent, nil
+}
+
 // GetToolNames returns mock tool names
 func (m *MockOutputter) GetToolNames() (clipboardTool, typeTool string) {
 	return "mock-clipboard", "mock-type"
@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+// pasteSettleDelay gives the clipboard owner time to publish new content
+// before the paste keystroke is sent
+const pasteSettleDelay = 50 * time.Millisecond
+
+// pasteCombo describes a paste key combination as modifiers plus a key
+type pasteCombo struct {
+	modifiers []string // "ctrl", "shift"
+	key       string   // X11 keysym name
+}
+
+// pasteCombos maps config.Output.PasteKeys values to key combinations
+var pasteCombos = map[string]pasteCombo{
+	"ctrl+v":       {modifiers: []string{"ctrl"}, key: "v"},
+	"shift+insert": {modifiers: []string{"shift"}, key: "Insert"},
+	"ctrl+shift+v": {modifiers: []string{"ctrl", "shift"}, key: "v"},
+}
+
+// Implements the Outputter interface by pasting through the clipboard.
+// Saves the current clipboard, sets the transcript, sends the paste keys,
+// then restores the original clipboard after a delay
+type PasteOutputter struct {
+	clipboard *ClipboardOutputter
+	typeTool  string
+	config    *config.Config
+	// schedule runs the clipboard restore; replaced in tests to run synchronously
+	schedule func(delay time.Duration, fn func())
+}
+
+// Create a new paste outputter
+func NewPasteOutputter(clipboardTool, typeTool string, cfg *config.Config) (interfaces.Outputter, error) {
+	// Verify the required tools exist in the system's PATH
+	if _, err := exec.LookPath(clipboardTool); err != nil {
+		return nil, fmt.Errorf("clipboard tool not found: %s", clipboardTool)
+	}
+	tool, ok := LookupTypingTool(typeTool)
+	if !ok || !tool.Capabilities.KeyEvents {
+		return nil, fmt.Errorf("paste mode requires a typing tool that sends key combinations, not %s", typeTool)
+	}
+	if err := tool.Available(); err != nil {
+		return nil, err
+	}
+	return &PasteOutputter{
+		clipboard: &ClipboardOutputter{clipboardTool: clipboardTool, config: cfg},
+		typeTool:  typeTool,
+		config:    cfg,
+		schedule:  func(delay time.Duration, fn func()) { time.AfterFunc(delay, fn) },
+	}, nil
+}
+
+// Paste text into the currently active window via the clipboard
+func (o *PasteOutputter) TypeToActiveWindow(text string) error {
+	// Security: validate the command before execution
+	if !config.IsCommandAllowed(o.config, o.typeTool) {
+		return fmt.Errorf("typing tool not allowed: %s", o.typeTool)
+	}
+	tool, cmd, err := pasteKeyCommand(o.typeTool, o.config.Output.PasteKeys)
+	if err != nil {
+		return err
+	}
+	if !config.IsCommandAllowed(o.config, o.clipboard.clipboardTool) {
+		return fmt.Errorf("clipboard tool not allowed: %s", o.clipboard.clipboardTool)
+	}
+	// Save every selection that will be overwritten. An unreadable
+	// selection (e.g., empty on Wayland) simply isn't restored
+	selections := o.pasteSelections()
+	saved := make(map[string]string, len(selections))
+	for _, selection := range selections {
+		if content, err := o.clipboard.ReadSelection(selection); err == nil {
+			saved[selection] = content
+		}
+	}
+	for _, selection := range selections {
+		if err := o.clipboard.copyToSelection(selection, text); err != nil {
+			return err
+		}
+	}
+	time.Sleep(pasteSettleDelay)
+
+	if output, err := tool.command(context.Background(), cmd).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to send paste keys with %s: %w, output: %s", o.typeTool, err, string(output))
+	}
+	if len(saved) > 0 && o.config.Output.PasteRestoreDelayMs > 0 {
+		delay := time.Duration(o.config.Output.PasteRestoreDelayMs) * time.Millisecond
+		o.schedule(delay, func() { o.restoreClipboard(saved, text) })
+	}
+	return nil
+}
+
+// Delete the last count pasted characters by pressing BackSpace
+func (o *PasteOutputter) EraseTyped(count int) error {
+	return eraseWithTool(o.config, o.typeTool, count)
+}
+
+// restoreClipboard puts the saved content back into each selection
+// unless it was changed by someone else since the paste
+func (o *PasteOutputter) restoreClipboard(saved map[string]string, pasted string) {
+	for selection, content := range saved {
+		current, err := o.clipboard.ReadSelection(selection)
+		if err != nil || current != pasted {
+			continue
+		}
+		_ = o.clipboard.copyToSelection(selection, content)
+	}
+}
+
+// pasteSelections returns the configured selections plus CLIPBOARD,
+// which the paste keys read from in most applications
+func (o *PasteOutputter) pasteSelections() []string {
+	selections := o.clipboard.selections()
+	if !slices.Contains(selections, config.ClipboardSelectionClipboard) {
+		selections = append([]string{config.ClipboardSelectionClipboard}, selections...)
+	}
+	return selections
+}
+
+// Copy text to the system clipboard
+func (o *PasteOutputter) CopyToClipboard(text string) error {
+	return o.clipboard.CopyToClipboard(text)
+}
+
+// Read the current text content of the system clipboard
+func (o *PasteOutputter) ReadClipboard() (string, error) {
+	return o.clipboard.ReadClipboard()
+}
+
+// Return the names of the clipboard and typing tools being used
+func (o *PasteOutputter) GetToolNames() (clipboardTool, typeTool string) {
+	return o.clipboard.clipboardTool, o.typeTool
+}
+
+// pasteKeyCommand builds the typing tool command that sends the paste combination
+func pasteKeyCommand(typeTool, keys string) (*TypingTool, typingCommand, error) {
+	if keys == "" {
+		keys = "ctrl+v"
+	}
+	combo, ok := pasteCombos[keys]
+	if !ok {
+		return nil, typingCommand{}, fmt.Errorf("unsupported paste keys: %s", keys)
+	}
+	tool, ok := LookupTypingTool(typeTool)
+	if !ok {
+		return nil, typingCommand{}, fmt.Errorf("unsupported typing tool: %s", typeTool)
+	}
+	cmd, err := tool.comboCommand(combo)
+	if err != nil {
+		return nil, typingCommand{}, err
+	}
+	return tool, cmd, nil
+}
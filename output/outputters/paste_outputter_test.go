@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/testutils"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+func TestPasteKeyCommand(t *testing.T) {
+	tests := []struct {
+		tool     string
+		keys     string
+		expected []string
+		stdin    string
+	}{
+		{"xdotool", "ctrl+v", []string{"key", "--clearmodifiers", "ctrl+v"}, ""},
+		{"xdotool", "shift+insert", []string{"key", "--clearmodifiers", "shift+Insert"}, ""},
+		{"xdotool", "", []string{"key", "--clearmodifiers", "ctrl+v"}, ""},
+		{"wtype", "ctrl+v", []string{"-M", "ctrl", "-k", "v", "-m", "ctrl"}, ""},
+		{"wtype", "ctrl+shift+v", []string{"-M", "ctrl", "-M", "shift", "-k", "v", "-m", "shift", "-m", "ctrl"}, ""},
+		{"ydotool", "ctrl+v", []string{"key", "29:1", "47:1", "47:0", "29:0"}, ""},
+		{"ydotool", "shift+insert", []string{"key", "42:1", "110:1", "110:0", "42:0"}, ""},
+		{"dotool", "ctrl+shift+v", nil, "key ctrl+shift+v\n"},
+		{"dotool", "shift+insert", nil, "key shift+insert\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool+" "+tt.keys, func(t *testing.T) {
+			_, cmd, err := pasteKeyCommand(tt.tool, tt.keys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(cmd.args, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected %q, got %q", tt.expected, cmd.args)
+			}
+			if cmd.stdin != tt.stdin {
+				t.Errorf("expected stdin %q, got %q", tt.stdin, cmd.stdin)
+			}
+		})
+	}
+
+	if _, _, err := pasteKeyCommand("xdotool", "alt+v"); err == nil {
+		t.Error("expected error for unsupported paste keys")
+	}
+	if _, _, err := pasteKeyCommand("nonexistent", "ctrl+v"); err == nil {
+		t.Error("expected error for unsupported typing tool")
+	}
+	if _, _, err := pasteKeyCommand("wlrctl", "ctrl+v"); err == nil {
+		t.Error("expected error for a tool without key combinations")
+	}
+}
+
+func newTestPasteOutputter(t *testing.T, restoreDelayMs int) *PasteOutputter {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xsel", "xdotool"}
+	cfg.Output.PasteKeys = "ctrl+v"
+	cfg.Output.PasteRestoreDelayMs = restoreDelayMs
+	out, err := NewPasteOutputter("xsel", "xdotool", cfg)
+	if err != nil {
+		t.Fatalf("NewPasteOutputter: %v", err)
+	}
+	return out.(*PasteOutputter)
+}
+
+func TestPasteOutputter_PastesAndRestores(t *testing.T) {
+	cb := testutils.NewFakeClipboard(t, "previous")
+	outputter := newTestPasteOutputter(t, 500)
+
+	var scheduled time.Duration
+	var restore func()
+	outputter.schedule = func(delay time.Duration, fn func()) { scheduled, restore = delay, fn }
+
+	if err := outputter.TypeToActiveWindow("transcript"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	if got := cb.Get(); got != "transcript" {
+		t.Errorf("expected clipboard to hold transcript before restore, got %q", got)
+	}
+	if keys := readCapturedArgs(t, cb.KeysFile()); strings.Join(keys, " ") != "key --clearmodifiers ctrl+v" {
+		t.Errorf("unexpected paste keys: %q", keys)
+	}
+	if restore == nil || scheduled != 500*time.Millisecond {
+		t.Fatalf("expected restore scheduled after 500ms, got %v", scheduled)
+	}
+	restore()
+	if got := cb.Get(); got != "previous" {
+		t.Errorf("expected clipboard restored to %q, got %q", "previous", got)
+	}
+}
+
+func TestPasteOutputter_SkipsRestoreWhenClipboardChanged(t *testing.T) {
+	cb := testutils.NewFakeClipboard(t, "previous")
+	outputter := newTestPasteOutputter(t, 500)
+
+	var restore func()
+	outputter.schedule = func(_ time.Duration, fn func()) { restore = fn }
+	if err := outputter.TypeToActiveWindow("transcript"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	// The user copies something else before the restore fires
+	cb.Set("user copy")
+	restore()
+	if got := cb.Get(); got != "user copy" {
+		t.Errorf("restore must not clobber a newer clipboard, got %q", got)
+	}
+}
+
+func TestPasteOutputter_RestoreDisabled(t *testing.T) {
+	testutils.NewFakeClipboard(t, "previous")
+	outputter := newTestPasteOutputter(t, 0)
+	outputter.schedule = func(time.Duration, func()) { t.Error("restore should not be scheduled") }
+	if err := outputter.TypeToActiveWindow("transcript"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+}
+
+func TestPasteOutputter_RestoresBothSelections(t *testing.T) {
+	cb := testutils.NewFakeClipboard(t, "old clipboard")
+	cb.SetSelection(testutils.SelectionPrimary, "old primary")
+	if err := os.WriteFile(filepath.Join(cb.Dir(), "wtype"), []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"wl-copy", "wl-paste", "wtype"}
+	cfg.Output.ClipboardSelection = config.ClipboardSelectionBoth
+	cfg.Output.PasteRestoreDelayMs = 500
+	out, err := NewPasteOutputter("wl-copy", "wtype", cfg)
+	if err != nil {
+		t.Fatalf("NewPasteOutputter: %v", err)
+	}
+	outputter := out.(*PasteOutputter)
+	var restore func()
+	outputter.schedule = func(_ time.Duration, fn func()) { restore = fn }
+
+	if err := outputter.TypeToActiveWindow("transcript"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	for _, selection := range []string{testutils.SelectionClipboard, testutils.SelectionPrimary} {
+		if got := cb.Selection(selection); got != "transcript" {
+			t.Errorf("expected %s to hold transcript, got %q", selection, got)
+		}
+	}
+	// The user selects new text before the restore fires; PRIMARY is left alone
+	cb.SetSelection(testutils.SelectionPrimary, "new selection")
+	restore()
+	if got := cb.Get(); got != "old clipboard" {
+		t.Errorf("expected CLIPBOARD restored, got %q", got)
+	}
+	if got := cb.Selection(testutils.SelectionPrimary); got != "new selection" {
+		t.Errorf("restore must not clobber a newer PRIMARY, got %q", got)
+	}
+}
+
+func TestPasteOutputter_PrimaryModeStillSetsClipboard(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Output.ClipboardSelection = config.ClipboardSelectionPrimary
+	outputter := &PasteOutputter{clipboard: &ClipboardOutputter{clipboardTool: "xsel", config: cfg}, config: cfg}
+	if got := strings.Join(outputter.pasteSelections(), ","); got != "clipboard,primary" {
+		t.Errorf("expected paste to write clipboard and primary, got %s", got)
+	}
+}
+
+func TestPasteOutputter_Interface(t *testing.T) {
+	var _ interfaces.Outputter = (*PasteOutputter)(nil)
+	var _ interfaces.Eraser = (*PasteOutputter)(nil)
+}
@@ -4,11 +4,14 @@
 package outputters
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/AshBuk/dabri/config"
-	"github.com/AshBuk/dabri/internal/platform"
+	"github.com/AshBuk/dabri/internal/metrics"
 	"github.com/AshBuk/dabri/output/interfaces"
 )
 
@@ -18,11 +21,15 @@ type TypeOutputter struct {
 	config   *config.Config
 }
 
-// Create a new type outputter
+// Create a new type outputter for a tool from the typing tool registry
 func NewTypeOutputter(typeTool string, cfg *config.Config) (interfaces.Outputter, error) {
-	// Verify the required tool exists in the system's PATH
-	if _, err := exec.LookPath(typeTool); err != nil {
-		return nil, fmt.Errorf("type tool not found: %s", typeTool)
+	tool, err := typingToolFor(typeTool)
+	if err != nil {
+		return nil, err
+	}
+	// Verify the tool is installed and usable
+	if err := tool.Available(); err != nil {
+		return nil, err
 	}
 	return &TypeOutputter{
 		typeTool: typeTool,
@@ -30,60 +37,171 @@ func NewTypeOutputter(typeTool string, cfg *config.Config) (interfaces.Outputter
 	}, nil
 }
 
+// typeChunkPause separates consecutive typing commands so slow targets
+// (VMs, remote desktops) can keep up
+const typeChunkPause = 25 * time.Millisecond
+
+// typingChunk is one typing command: a run of literal text or a single key
+type typingChunk struct {
+	text string
+	key  string // "Return" or "Tab"; empty for text
+}
+
+// controlKeys maps control characters to the keys typed for them
+var controlKeys = map[rune]string{'\n': "Return", '\t': "Tab"}
+
 // Simulate typing text into the currently active window
 func (o *TypeOutputter) TypeToActiveWindow(text string) error {
+	return o.TypeToActiveWindowContext(context.Background(), text)
+}
+
+// Type text in chunks, pressing Return/Tab for newlines and tabs.
+// Stops between chunks (and kills the running tool) when ctx is canceled
+func (o *TypeOutputter) TypeToActiveWindowContext(ctx context.Context, text string) error {
 	// Security: validate the command before execution
 	if !config.IsCommandAllowed(o.config, o.typeTool) {
 		return fmt.Errorf("typing tool not allowed: %s", o.typeTool)
 	}
-	// Proactive fallback: ydotool on Wayland does not support non-ASCII characters
-	if platform.DetectEnvironment() == platform.EnvironmentWayland && o.typeTool == "ydotool" && isNonASCII(text) {
-		return fmt.Errorf("ydotool on Wayland doesn't support non-ASCII characters, use clipboard fallback")
-	}
-	var cmd *exec.Cmd
-	var args []string
-
-	// Use "--" to terminate option parsing where supported, so a transcript
-	// that happens to start with "-"/"--" is not interpreted as a flag.
-	switch o.typeTool {
-	case "xdotool":
-		args = []string{"type", "--clearmodifiers", "--", text}
-	case "wtype":
-		args = []string{"--", text}
-	case "ydotool":
-		// ydotool's option parser does not document "--", pass text directly.
-		args = []string{"type", text}
-	default:
-		return fmt.Errorf("unsupported typing tool: %s", o.typeTool)
-	}
-
-	// #nosec G204 -- Tool is allowlisted; text is passed as argv, not shell input.
-	cmd = exec.Command(o.typeTool, args...)
-	// Run the command
-	output, err := cmd.CombinedOutput()
+	tool, err := typingToolFor(o.typeTool)
 	if err != nil {
-		// Runtime fallback: if wtype fails, try ydotool if it is allowed and available
-		if o.typeTool == "wtype" && config.IsCommandAllowed(o.config, "ydotool") {
-			if _, lookErr := exec.LookPath("ydotool"); lookErr == nil {
-				// #nosec G204 -- Tool is allowlisted; text is passed as argv, not shell input.
-				fallbackCmd := exec.Command("ydotool", "type", text)
-				if fbOut, fbErr := fallbackCmd.CombinedOutput(); fbErr == nil {
-					return nil
-				} else {
-					return fmt.Errorf("wtype failed: %w (out: %s); ydotool fallback failed: %v (out: %s)", err, string(output), fbErr, string(fbOut))
-				}
+		return err
+	}
+	// Proactive fallback: keycode-based tools (ydotool, dotool) cannot type non-ASCII characters
+	if !tool.Capabilities.Unicode && isNonASCII(text) {
+		return fmt.Errorf("%s doesn't support non-ASCII characters, use clipboard fallback", o.typeTool)
+	}
+
+	chunks := splitTypingChunks(text, o.config.Output.TypeChunkSize)
+	for i, chunk := range chunks {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(typeChunkPause):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("typing canceled after %d of %d chunks: %w", i, len(chunks), err)
+		}
+		if err := o.typeChunk(ctx, tool, chunk); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return fmt.Errorf("typing canceled after %d of %d chunks: %w", i, len(chunks), ctxErr)
 			}
+			return err
 		}
-		return fmt.Errorf("failed to type text with %s: %w, output: %s", o.typeTool, err, string(output))
 	}
 	return nil
 }
 
+// typeChunk runs the typing tool for one chunk
+func (o *TypeOutputter) typeChunk(ctx context.Context, tool *TypingTool, chunk typingChunk) error {
+	delay := o.config.Output.TypeDelayMs
+	cmd, err := tool.chunkCommand(chunk, delay)
+	if err != nil {
+		return err
+	}
+	output, err := tool.command(ctx, cmd).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	// Runtime fallback: e.g. if wtype fails, try ydotool if it is allowed and available
+	if fb, ok := LookupTypingTool(tool.fallback); ok && ctx.Err() == nil && config.IsCommandAllowed(o.config, fb.Name) && fb.Available() == nil {
+		fbCmd, fbErr := fb.chunkCommand(chunk, delay)
+		if fbErr == nil {
+			var fbOut []byte
+			if fbOut, fbErr = fb.command(ctx, fbCmd).CombinedOutput(); fbErr == nil {
+				metrics.FallbackSwitches.Inc("typing_tool", tool.Name, fb.Name)
+				return nil
+			}
+			return fmt.Errorf("%s failed: %w (out: %s); %s fallback failed: %v (out: %s)", tool.Name, err, string(output), fb.Name, fbErr, string(fbOut))
+		}
+	}
+	return fmt.Errorf("failed to type text with %s: %w, output: %s", tool.Name, err, string(output))
+}
+
+// Delete the last count typed characters by pressing BackSpace
+func (o *TypeOutputter) EraseTyped(count int) error {
+	return eraseWithTool(o.config, o.typeTool, count)
+}
+
+// eraseWithTool presses BackSpace count times with an external typing tool
+func eraseWithTool(cfg *config.Config, typeTool string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	if !config.IsCommandAllowed(cfg, typeTool) {
+		return fmt.Errorf("typing tool not allowed: %s", typeTool)
+	}
+	tool, ok := LookupTypingTool(typeTool)
+	if !ok {
+		return fmt.Errorf("unsupported typing tool: %s", typeTool)
+	}
+	cmd, err := tool.keyCommand("BackSpace", count)
+	if err != nil {
+		return err
+	}
+	if output, err := tool.command(context.Background(), cmd).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to erase text with %s: %w, output: %s", typeTool, err, string(output))
+	}
+	return nil
+}
+
+// TypedLength returns the number of characters typing text produces in the
+// target window: carriage returns are dropped, newlines and tabs are one key
+func TypedLength(text string) int {
+	return utf8.RuneCountInString(strings.ReplaceAll(text, "\r", ""))
+}
+
+// typingToolFor returns the registry adapter for a tool that can type text
+func typingToolFor(name string) (*TypingTool, error) {
+	tool, ok := LookupTypingTool(name)
+	if !ok {
+		return nil, fmt.Errorf("unsupported typing tool: %s", name)
+	}
+	if !tool.Capabilities.Typing {
+		return nil, fmt.Errorf("%s cannot type text, it only targets windows", name)
+	}
+	return tool, nil
+}
+
+// splitTypingChunks splits text into runs of at most chunkSize characters
+// (0 = unlimited) and separate Return/Tab keys. Carriage returns are dropped
+func splitTypingChunks(text string, chunkSize int) []typingChunk {
+	var chunks []typingChunk
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, typingChunk{text: string(current)})
+			current = current[:0]
+		}
+	}
+	for _, r := range text {
+		if r == '\r' {
+			continue
+		}
+		if key, ok := controlKeys[r]; ok {
+			flush()
+			chunks = append(chunks, typingChunk{key: key})
+			continue
+		}
+		current = append(current, r)
+		if chunkSize > 0 && len(current) >= chunkSize {
+			flush()
+		}
+	}
+	flush()
+	return chunks
+}
+
 // Return an error as clipboard operations are not supported by this outputter
 func (o *TypeOutputter) CopyToClipboard(text string) error {
 	return fmt.Errorf("copying to clipboard not supported by type outputter")
 }
 
+// Return an error as clipboard operations are not supported by this outputter
+func (o *TypeOutputter) ReadClipboard() (string, error) {
+	return "", fmt.Errorf("reading clipboard not supported by type outputter")
+}
+
 // Return the name of the typing tool being used
 func (o *TypeOutputter) GetToolNames() (clipboardTool, typeTool string) {
 	return "", o.typeTool
@@ -4,8 +4,9 @@
 package outputters
 
 import (
+	"context"
+	"errors"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -25,6 +26,16 @@ func TestNewTypeOutputter(t *testing.T) {
 			typeTool:    "nonexistent-type-tool",
 			expectError: true,
 		},
+		{
+			name:        "tool outside the registry",
+			typeTool:    "cat",
+			expectError: true,
+		},
+		{
+			name:        "window targeting only",
+			typeTool:    "kdotool",
+			expectError: true,
+		},
 		{
 			name:        "empty tool name",
 			typeTool:    "",
@@ -50,18 +61,9 @@ func TestNewTypeOutputter(t *testing.T) {
 }
 
 func TestNewTypeOutputter_WithExistingTool(t *testing.T) {
-	// Find an existing command for testing
-	var existingTool string
-	for _, tool := range []string{"cat", "echo", "true"} {
-		if _, err := exec.LookPath(tool); err == nil {
-			existingTool = tool
-			break
-		}
-	}
-
-	if existingTool == "" {
-		t.Skip("No basic tools available for testing")
-	}
+	// Registry tools only; a fake xdotool stands in for the real one
+	installFakeTool(t, "xdotool")
+	existingTool := "xdotool"
 
 	cfg := &config.Config{}
 	outputter, err := NewTypeOutputter(existingTool, cfg)
@@ -196,6 +198,82 @@ func readCapturedArgs(t *testing.T, path string) []string {
 	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
 }
 
+func TestSplitTypingChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		chunkSize int
+		expected  []typingChunk
+	}{
+		{"unlimited", "hello world", 0, []typingChunk{{text: "hello world"}}},
+		{"chunked by characters", "héllo", 2, []typingChunk{{text: "hé"}, {text: "ll"}, {text: "o"}}},
+		{"newline and tab keys", "a\nb\tc", 0, []typingChunk{{text: "a"}, {key: "Return"}, {text: "b"}, {key: "Tab"}, {text: "c"}}},
+		{"crlf is one return", "a\r\n\nb", 0, []typingChunk{{text: "a"}, {key: "Return"}, {key: "Return"}, {text: "b"}}},
+		{"empty", "", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitTypingChunks(tt.text, tt.chunkSize)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("chunk %d: expected %+v, got %+v", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTypeOutputter_TypesChunksAndKeys(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "calls.txt")
+	// Each invocation appends its argv as one line
+	script := "#!/bin/sh\necho \"$*\" >> \"$CALLS_FILE\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "xdotool"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+":/bin:/usr/bin")
+	t.Setenv("CALLS_FILE", logFile)
+
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xdotool"}
+	cfg.Output.TypeChunkSize = 4
+	cfg.Output.TypeDelayMs = 15
+	outputter := &TypeOutputter{typeTool: "xdotool", config: cfg}
+
+	if err := outputter.TypeToActiveWindow("hello\nok"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	expected := []string{
+		"type --clearmodifiers --delay 15 -- hell",
+		"type --clearmodifiers --delay 15 -- o",
+		"key --clearmodifiers Return",
+		"type --clearmodifiers --delay 15 -- ok",
+	}
+	if got := readCapturedArgs(t, logFile); strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected calls %q, got %q", expected, got)
+	}
+}
+
+func TestTypeOutputter_CanceledContext(t *testing.T) {
+	captureFile := installFakeTool(t, "xdotool")
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xdotool"}
+	outputter := &TypeOutputter{typeTool: "xdotool", config: cfg}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := outputter.TypeToActiveWindowContext(ctx, "never typed")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, statErr := os.Stat(captureFile); !os.IsNotExist(statErr) {
+		t.Error("typing tool must not run after cancellation")
+	}
+}
+
 func TestTypeOutputter_CopyToClipboard(t *testing.T) {
 	cfg := &config.Config{}
 	outputter := &TypeOutputter{
@@ -216,4 +294,26 @@ func TestTypeOutputter_CopyToClipboard(t *testing.T) {
 func TestTypeOutputter_Interface(t *testing.T) {
 	// Verify it implements the interfaces.Outputter interface
 	var _ interfaces.Outputter = (*TypeOutputter)(nil)
+	var _ interfaces.ContextTyper = (*TypeOutputter)(nil)
+	var _ interfaces.Eraser = (*TypeOutputter)(nil)
+}
+
+func TestTypeOutputter_EraseTyped(t *testing.T) {
+	captureFile := installFakeTool(t, "xdotool")
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xdotool"}
+	outputter := &TypeOutputter{typeTool: "xdotool", config: cfg}
+
+	if err := outputter.EraseTyped(TypedLength("héllo\r\nok")); err != nil {
+		t.Fatalf("EraseTyped: %v", err)
+	}
+	expected := []string{"key", "--clearmodifiers", "--repeat", "8", "BackSpace"}
+	if got := readCapturedArgs(t, captureFile); strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected args %q, got %q", expected, got)
+	}
+
+	cfg.Security.AllowedCommands = nil
+	if err := outputter.EraseTyped(1); err == nil {
+		t.Error("expected error for disallowed typing tool")
+	}
 }
@@ -0,0 +1,389 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AshBuk/dabri/internal/platform"
+)
+
+// uinputDevicePath is the kernel virtual input device used by uinput-based typers
+const uinputDevicePath = "/dev/uinput"
+
+// TypingCapabilities describes what an external typing tool can do
+type TypingCapabilities struct {
+	Typing          bool // Types text into the focused window
+	Unicode         bool // Types characters outside ASCII
+	KeyEvents       bool // Sends key combinations (needed for paste mode)
+	WindowTargeting bool // Activates a window by ID before typing
+}
+
+// TypingSession describes the desktop session a typing tool runs in
+type TypingSession struct {
+	Env     platform.EnvironmentType
+	Desktop string // XDG_CURRENT_DESKTOP or DESKTOP_SESSION
+	Wlroots bool   // Compositor implements the wlroots virtual keyboard/toplevel protocols
+}
+
+// DetectTypingSession describes the current session for typing tool ranking
+func DetectTypingSession(env platform.EnvironmentType) TypingSession {
+	desktop := platform.DetectDesktopEnvironment()
+	wlroots := os.Getenv("SWAYSOCK") != "" || os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != ""
+	for _, name := range []string{"sway", "hyprland", "river", "wayfire", "labwc"} {
+		if strings.Contains(strings.ToLower(desktop), name) {
+			wlroots = true
+		}
+	}
+	return TypingSession{Env: env, Desktop: desktop, Wlroots: wlroots}
+}
+
+func (s TypingSession) isGNOME() bool {
+	return strings.Contains(strings.ToUpper(s.Desktop), "GNOME")
+}
+
+func (s TypingSession) isKDE() bool {
+	return strings.Contains(strings.ToUpper(s.Desktop), "KDE")
+}
+
+// Session support levels returned by TypingTool.session
+const (
+	sessionUnsupported = 0
+	sessionFallback    = 1 // Works partially, e.g. xdotool through XWayland
+	sessionNative      = 2
+)
+
+// typingCommand is one invocation of a typing tool
+type typingCommand struct {
+	args  []string
+	stdin string // Command script written to stdin (dotool); empty for argv-only tools
+}
+
+// TypingTool adapts one external typing tool: how to invoke it, what it
+// can do and where it works
+type TypingTool struct {
+	Name         string
+	Capabilities TypingCapabilities
+	// session rates support for a session (sessionUnsupported..sessionNative)
+	session func(s TypingSession) int
+	// probe checks runtime requirements beyond the binary being on PATH; nil if none
+	probe func() error
+	// typeText, pressKey, pressCombo and activate build commands; nil when unsupported.
+	// delayMs > 0 sets the per-key delay; pressKey taps "Return", "Tab" or "BackSpace" count times
+	typeText   func(text string, delayMs int) typingCommand
+	pressKey   func(key string, count int) typingCommand
+	pressCombo func(combo pasteCombo) typingCommand
+	activate   func(windowID string) typingCommand
+	// fallback is retried when the tool fails at runtime (e.g. wtype on an unsupported compositor)
+	fallback string
+}
+
+// typingTools is the adapter registry; the order breaks ranking ties
+var typingTools = []*TypingTool{
+	{
+		Name:         "xdotool",
+		Capabilities: TypingCapabilities{Typing: true, Unicode: true, KeyEvents: true, WindowTargeting: true},
+		session: func(s TypingSession) int {
+			if s.Env == platform.EnvironmentX11 {
+				return sessionNative
+			}
+			// XWayland windows only
+			return sessionFallback
+		},
+		// "--" terminates option parsing, so a transcript that happens to
+		// start with "-"/"--" is not interpreted as a flag
+		typeText: func(text string, delayMs int) typingCommand {
+			args := []string{"type", "--clearmodifiers"}
+			if delayMs > 0 {
+				args = append(args, "--delay", strconv.Itoa(delayMs))
+			}
+			return typingCommand{args: append(args, "--", text)}
+		},
+		pressKey: func(key string, count int) typingCommand {
+			args := []string{"key", "--clearmodifiers"}
+			if count > 1 {
+				args = append(args, "--repeat", strconv.Itoa(count))
+			}
+			return typingCommand{args: append(args, key)}
+		},
+		pressCombo: func(combo pasteCombo) typingCommand {
+			keysym := strings.Join(append(slices.Clone(combo.modifiers), combo.key), "+")
+			return typingCommand{args: []string{"key", "--clearmodifiers", keysym}}
+		},
+		activate: func(windowID string) typingCommand {
+			return typingCommand{args: []string{"windowactivate", "--sync", windowID}}
+		},
+	},
+	{
+		Name:         "wtype",
+		Capabilities: TypingCapabilities{Typing: true, Unicode: true, KeyEvents: true},
+		session: func(s TypingSession) int {
+			switch {
+			case s.Env == platform.EnvironmentWayland && !s.isGNOME():
+				return sessionNative
+			case s.Env == platform.EnvironmentUnknown:
+				return sessionFallback
+			}
+			// GNOME has no virtual keyboard protocol
+			return sessionUnsupported
+		},
+		typeText: func(text string, delayMs int) typingCommand {
+			var args []string
+			if delayMs > 0 {
+				args = append(args, "-d", strconv.Itoa(delayMs))
+			}
+			return typingCommand{args: append(args, "--", text)}
+		},
+		pressKey: func(key string, count int) typingCommand {
+			var args []string
+			for range count {
+				args = append(args, "-k", key)
+			}
+			return typingCommand{args: args}
+		},
+		pressCombo: func(combo pasteCombo) typingCommand {
+			var args []string
+			for _, mod := range combo.modifiers {
+				args = append(args, "-M", mod)
+			}
+			args = append(args, "-k", combo.key)
+			for i := len(combo.modifiers) - 1; i >= 0; i-- {
+				args = append(args, "-m", combo.modifiers[i])
+			}
+			return typingCommand{args: args}
+		},
+		fallback: "ydotool",
+	},
+	{
+		Name: "wlrctl",
+		// Return/Tab/BackSpace are typed as characters; no modifier combinations
+		Capabilities: TypingCapabilities{Typing: true, Unicode: true},
+		session: func(s TypingSession) int {
+			if s.Env == platform.EnvironmentWayland && s.Wlroots {
+				return sessionNative
+			}
+			return sessionUnsupported
+		},
+		typeText: func(text string, _ int) typingCommand {
+			return typingCommand{args: []string{"keyboard", "type", text}}
+		},
+		// xkbcommon maps CR, TAB and BS to the Return, Tab and BackSpace keysyms
+		pressKey: func(key string, count int) typingCommand {
+			return typingCommand{args: []string{"keyboard", "type", strings.Repeat(wlrctlKeyChars[key], count)}}
+		},
+	},
+	{
+		Name:         "ydotool",
+		Capabilities: TypingCapabilities{Typing: true, KeyEvents: true},
+		session:      uinputSession,
+		// ydotool's option parser does not document "--", pass text directly
+		typeText: func(text string, delayMs int) typingCommand {
+			args := []string{"type"}
+			if delayMs > 0 {
+				args = append(args, "--key-delay", strconv.Itoa(delayMs))
+			}
+			return typingCommand{args: append(args, text)}
+		},
+		pressKey: func(key string, count int) typingCommand {
+			code := ydotoolKeycodes[key]
+			args := []string{"key"}
+			for range count {
+				args = append(args, code+":1", code+":0")
+			}
+			return typingCommand{args: args}
+		},
+		// Press modifiers, tap the key, release modifiers in reverse order
+		pressCombo: func(combo pasteCombo) typingCommand {
+			args := []string{"key"}
+			for _, mod := range combo.modifiers {
+				args = append(args, ydotoolKeycodes[mod]+":1")
+			}
+			code := ydotoolKeycodes[combo.key]
+			args = append(args, code+":1", code+":0")
+			for i := len(combo.modifiers) - 1; i >= 0; i-- {
+				args = append(args, ydotoolKeycodes[combo.modifiers[i]]+":0")
+			}
+			return typingCommand{args: args}
+		},
+	},
+	{
+		Name:         "dotool",
+		Capabilities: TypingCapabilities{Typing: true, KeyEvents: true},
+		session:      uinputSession,
+		probe:        probeUinput,
+		// dotool reads one command per line from stdin
+		typeText: func(text string, delayMs int) typingCommand {
+			var script strings.Builder
+			if delayMs > 0 {
+				fmt.Fprintf(&script, "typedelay %d\n", delayMs)
+			}
+			fmt.Fprintf(&script, "type %s\n", text)
+			return typingCommand{stdin: script.String()}
+		},
+		pressKey: func(key string, count int) typingCommand {
+			return typingCommand{stdin: strings.Repeat("key "+dotoolKeyNames[key]+"\n", count)}
+		},
+		pressCombo: func(combo pasteCombo) typingCommand {
+			keys := append(slices.Clone(combo.modifiers), strings.ToLower(combo.key))
+			return typingCommand{stdin: "key " + strings.Join(keys, "+") + "\n"}
+		},
+	},
+	{
+		Name: "kdotool",
+		// KWin scripting only: targets windows but cannot type
+		Capabilities: TypingCapabilities{WindowTargeting: true},
+		session: func(s TypingSession) int {
+			if s.isKDE() {
+				return sessionNative
+			}
+			return sessionUnsupported
+		},
+		activate: func(windowID string) typingCommand {
+			return typingCommand{args: []string{"windowactivate", windowID}}
+		},
+	},
+}
+
+// ydotoolKeycodes maps key names to Linux input event codes used by ydotool
+var ydotoolKeycodes = map[string]string{
+	"Return":    "28",  // KEY_ENTER
+	"Tab":       "15",  // KEY_TAB
+	"BackSpace": "14",  // KEY_BACKSPACE
+	"ctrl":      "29",  // KEY_LEFTCTRL
+	"shift":     "42",  // KEY_LEFTSHIFT
+	"v":         "47",  // KEY_V
+	"Insert":    "110", // KEY_INSERT
+}
+
+// dotoolKeyNames maps typed keys to dotool (Linux input) key names
+var dotoolKeyNames = map[string]string{"Return": "enter", "Tab": "tab", "BackSpace": "backspace"}
+
+// wlrctlKeyChars maps keys to the control characters wlrctl types for them
+var wlrctlKeyChars = map[string]string{"Return": "\r", "Tab": "\t", "BackSpace": "\b"}
+
+// uinputSession rates tools that type through /dev/uinput: they work below
+// the display server, but only session-less when nothing else is known
+func uinputSession(s TypingSession) int {
+	if s.Env == platform.EnvironmentUnknown {
+		return sessionFallback
+	}
+	return sessionNative
+}
+
+// probeUinput checks write access to /dev/uinput
+func probeUinput() error {
+	file, err := os.OpenFile(uinputDevicePath, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("cannot open %s (requires write access, e.g. via the input group or a udev rule): %w", uinputDevicePath, err)
+	}
+	return file.Close()
+}
+
+// LookupTypingTool returns the adapter registered for a tool name
+func LookupTypingTool(name string) (*TypingTool, bool) {
+	for _, tool := range typingTools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return nil, false
+}
+
+// RankTypingTools returns the tools that can type in the session, best first.
+// Session support dominates, then Unicode, key events and window targeting.
+// With requireKeyEvents only tools able to send key combinations are returned
+func RankTypingTools(session TypingSession, requireKeyEvents bool) []*TypingTool {
+	type ranked struct {
+		tool  *TypingTool
+		score int
+	}
+	var candidates []ranked
+	for _, tool := range typingTools {
+		caps := tool.Capabilities
+		support := tool.session(session)
+		if !caps.Typing || support == sessionUnsupported || (requireKeyEvents && !caps.KeyEvents) {
+			continue
+		}
+		score := support * 100
+		if caps.Unicode {
+			score += 20
+		}
+		if caps.KeyEvents {
+			score += 10
+		}
+		if caps.WindowTargeting {
+			score++
+		}
+		candidates = append(candidates, ranked{tool, score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	tools := make([]*TypingTool, len(candidates))
+	for i, c := range candidates {
+		tools[i] = c.tool
+	}
+	return tools
+}
+
+// Available reports whether the tool is installed and its runtime requirements are met
+func (t *TypingTool) Available() error {
+	if _, err := exec.LookPath(t.Name); err != nil {
+		return fmt.Errorf("type tool not found: %s", t.Name)
+	}
+	if t.probe != nil {
+		return t.probe()
+	}
+	return nil
+}
+
+// chunkCommand builds the command typing one chunk
+func (t *TypingTool) chunkCommand(chunk typingChunk, delayMs int) (typingCommand, error) {
+	if chunk.key != "" {
+		return t.keyCommand(chunk.key, 1)
+	}
+	if t.typeText == nil {
+		return typingCommand{}, fmt.Errorf("%s cannot type text", t.Name)
+	}
+	return t.typeText(chunk.text, delayMs), nil
+}
+
+// keyCommand builds the command tapping a key count times
+func (t *TypingTool) keyCommand(key string, count int) (typingCommand, error) {
+	if t.pressKey == nil {
+		return typingCommand{}, fmt.Errorf("%s cannot press %s", t.Name, key)
+	}
+	return t.pressKey(key, count), nil
+}
+
+// comboCommand builds the command sending a key combination
+func (t *TypingTool) comboCommand(combo pasteCombo) (typingCommand, error) {
+	if t.pressCombo == nil {
+		return typingCommand{}, fmt.Errorf("%s cannot send key combinations", t.Name)
+	}
+	return t.pressCombo(combo), nil
+}
+
+// ActivateArgs returns the arguments focusing a window by its ID
+func (t *TypingTool) ActivateArgs(windowID string) ([]string, error) {
+	if t.activate == nil {
+		return nil, fmt.Errorf("%s cannot target windows", t.Name)
+	}
+	return t.activate(windowID).args, nil
+}
+
+// command prepares a command built by the adapter, feeding its stdin script
+func (t *TypingTool) command(ctx context.Context, cmd typingCommand) *exec.Cmd {
+	// #nosec G204 -- Tool comes from the fixed registry and is allowlisted; text is passed as argv or stdin, not shell input.
+	c := exec.CommandContext(ctx, t.Name, cmd.args...)
+	if cmd.stdin != "" {
+		c.Stdin = strings.NewReader(cmd.stdin)
+	}
+	return c
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/config/models"
+	"github.com/AshBuk/dabri/internal/platform"
+)
+
+func TestTypingTool_ChunkCommand(t *testing.T) {
+	tests := []struct {
+		tool     string
+		chunk    typingChunk
+		delayMs  int
+		expected string
+		stdin    string
+	}{
+		{"xdotool", typingChunk{text: "hi"}, 20, "type --clearmodifiers --delay 20 -- hi", ""},
+		{"xdotool", typingChunk{key: "Return"}, 20, "key --clearmodifiers Return", ""},
+		{"wtype", typingChunk{text: "hi"}, 20, "-d 20 -- hi", ""},
+		{"wtype", typingChunk{key: "Tab"}, 0, "-k Tab", ""},
+		{"ydotool", typingChunk{text: "hi"}, 20, "type --key-delay 20 hi", ""},
+		{"ydotool", typingChunk{key: "Return"}, 0, "key 28:1 28:0", ""},
+		{"ydotool", typingChunk{key: "Tab"}, 0, "key 15:1 15:0", ""},
+		{"dotool", typingChunk{text: "-hi there"}, 20, "", "typedelay 20\ntype -hi there\n"},
+		{"dotool", typingChunk{text: "hi"}, 0, "", "type hi\n"},
+		{"dotool", typingChunk{key: "Return"}, 0, "", "key enter\n"},
+		{"wlrctl", typingChunk{text: "hi"}, 20, "keyboard type hi", ""},
+		{"wlrctl", typingChunk{key: "Return"}, 0, "keyboard type \r", ""},
+	}
+	for _, tt := range tests {
+		tool, ok := LookupTypingTool(tt.tool)
+		if !ok {
+			t.Fatalf("%s is not registered", tt.tool)
+		}
+		cmd, err := tool.chunkCommand(tt.chunk, tt.delayMs)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.tool, err)
+		}
+		if got := strings.Join(cmd.args, " "); got != tt.expected {
+			t.Errorf("%s %+v: expected %q, got %q", tt.tool, tt.chunk, tt.expected, got)
+		}
+		if cmd.stdin != tt.stdin {
+			t.Errorf("%s %+v: expected stdin %q, got %q", tt.tool, tt.chunk, tt.stdin, cmd.stdin)
+		}
+	}
+
+	kdotool, _ := LookupTypingTool("kdotool")
+	if _, err := kdotool.chunkCommand(typingChunk{text: "hi"}, 0); err == nil {
+		t.Error("expected kdotool to be unable to type")
+	}
+}
+
+func TestTypingTool_KeyCommandRepeats(t *testing.T) {
+	tests := []struct {
+		tool     string
+		expected string
+		stdin    string
+	}{
+		{"xdotool", "key --clearmodifiers --repeat 3 BackSpace", ""},
+		{"wtype", "-k BackSpace -k BackSpace -k BackSpace", ""},
+		{"ydotool", "key 14:1 14:0 14:1 14:0 14:1 14:0", ""},
+		{"dotool", "", "key backspace\nkey backspace\nkey backspace\n"},
+		{"wlrctl", "keyboard type \b\b\b", ""},
+	}
+	for _, tt := range tests {
+		tool, _ := LookupTypingTool(tt.tool)
+		cmd, err := tool.keyCommand("BackSpace", 3)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.tool, err)
+		}
+		if got := strings.Join(cmd.args, " "); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.tool, tt.expected, got)
+		}
+		if cmd.stdin != tt.stdin {
+			t.Errorf("%s: expected stdin %q, got %q", tt.tool, tt.stdin, cmd.stdin)
+		}
+	}
+
+	kdotool, _ := LookupTypingTool("kdotool")
+	if _, err := kdotool.keyCommand("BackSpace", 1); err == nil {
+		t.Error("expected kdotool to be unable to press keys")
+	}
+}
+
+func TestTypingTool_ActivateArgs(t *testing.T) {
+	xdotool, _ := LookupTypingTool("xdotool")
+	if args, err := xdotool.ActivateArgs("0x2a"); err != nil || strings.Join(args, " ") != "windowactivate --sync 0x2a" {
+		t.Errorf("unexpected xdotool activation: %q, %v", args, err)
+	}
+	kdotool, _ := LookupTypingTool("kdotool")
+	if args, err := kdotool.ActivateArgs("{abc}"); err != nil || strings.Join(args, " ") != "windowactivate {abc}" {
+		t.Errorf("unexpected kdotool activation: %q, %v", args, err)
+	}
+	wtype, _ := LookupTypingTool("wtype")
+	if _, err := wtype.ActivateArgs("1"); err == nil {
+		t.Error("expected wtype to be unable to target windows")
+	}
+}
+
+// The config validator accepts type_tool names from models.TypingTools
+func TestTypingTools_MatchConfigNames(t *testing.T) {
+	names := []string{UinputTypeTool}
+	for _, tool := range typingTools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	want := slices.Sorted(slices.Values(models.TypingTools))
+	if !slices.Equal(names, want) {
+		t.Errorf("registry tools %v, config accepts %v", names, want)
+	}
+}
+
+func TestRankTypingTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		session   TypingSession
+		keyEvents bool
+		expected  string
+	}{
+		{"x11", TypingSession{Env: platform.EnvironmentX11, Desktop: "XFCE"}, false, "xdotool ydotool dotool"},
+		{"gnome wayland", TypingSession{Env: platform.EnvironmentWayland, Desktop: "ubuntu:GNOME"}, false, "ydotool dotool xdotool"},
+		{"kde wayland", TypingSession{Env: platform.EnvironmentWayland, Desktop: "KDE"}, false, "wtype ydotool dotool xdotool"},
+		{"sway", TypingSession{Env: platform.EnvironmentWayland, Desktop: "sway", Wlroots: true}, false, "wtype wlrctl ydotool dotool xdotool"},
+		{"sway paste keys", TypingSession{Env: platform.EnvironmentWayland, Desktop: "sway", Wlroots: true}, true, "wtype ydotool dotool xdotool"},
+		{"unknown", TypingSession{Env: platform.EnvironmentUnknown}, false, "xdotool wtype ydotool dotool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, tool := range RankTypingTools(tt.session, tt.keyEvents) {
+				names = append(names, tool.Name)
+			}
+			if got := strings.Join(names, " "); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDetectTypingSession(t *testing.T) {
+	t.Setenv("SWAYSOCK", "")
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	t.Setenv("XDG_CURRENT_DESKTOP", "river")
+	if s := DetectTypingSession(platform.EnvironmentWayland); !s.Wlroots || s.Desktop != "river" {
+		t.Errorf("expected wlroots session, got %+v", s)
+	}
+	t.Setenv("XDG_CURRENT_DESKTOP", "KDE")
+	if s := DetectTypingSession(platform.EnvironmentWayland); s.Wlroots {
+		t.Errorf("KDE is not wlroots, got %+v", s)
+	}
+	t.Setenv("SWAYSOCK", "/run/user/1000/sway-ipc.sock")
+	if s := DetectTypingSession(platform.EnvironmentWayland); !s.Wlroots {
+		t.Errorf("expected SWAYSOCK to mark wlroots, got %+v", s)
+	}
+}
+
+func TestTypeOutputter_DotoolReadsStdin(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "stdin.txt")
+	script := "#!/bin/sh\ncat >> \"$STDIN_FILE\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "dotool"), []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+":/bin:/usr/bin")
+	t.Setenv("STDIN_FILE", logFile)
+
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"dotool"}
+	outputter := &TypeOutputter{typeTool: "dotool", config: cfg}
+	if err := outputter.TypeToActiveWindow("hi\nthere"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "type hi\nkey enter\ntype there\n"; string(data) != want {
+		t.Errorf("expected dotool script %q, got %q", want, string(data))
+	}
+	if err := outputter.TypeToActiveWindow("naïve"); err == nil || !strings.Contains(err.Error(), "non-ASCII") {
+		t.Errorf("expected non-ASCII error for dotool, got %v", err)
+	}
+}
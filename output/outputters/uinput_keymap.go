@@ -0,0 +1,389 @@
+//go:build linux
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	evdev "github.com/holoplot/go-evdev"
+)
+
+// noSymbol marks a key position without a typeable symbol on that level
+// (dead keys and symbols not worth mapping)
+const noSymbol = '•'
+
+// keyStroke is a single key press with the modifiers needed to produce a rune
+type keyStroke struct {
+	code  evdev.EvCode
+	shift bool
+	altGr bool
+}
+
+// Physical key rows of a 105-key keyboard, left to right, as evdev codes.
+// Layout levels below are strings with one rune per key of the row
+var (
+	keyRowE = []evdev.EvCode{
+		evdev.KEY_GRAVE, evdev.KEY_1, evdev.KEY_2, evdev.KEY_3, evdev.KEY_4, evdev.KEY_5, evdev.KEY_6,
+		evdev.KEY_7, evdev.KEY_8, evdev.KEY_9, evdev.KEY_0, evdev.KEY_MINUS, evdev.KEY_EQUAL,
+	}
+	keyRowD = []evdev.EvCode{
+		evdev.KEY_Q, evdev.KEY_W, evdev.KEY_E, evdev.KEY_R, evdev.KEY_T, evdev.KEY_Y, evdev.KEY_U,
+		evdev.KEY_I, evdev.KEY_O, evdev.KEY_P, evdev.KEY_LEFTBRACE, evdev.KEY_RIGHTBRACE, evdev.KEY_BACKSLASH,
+	}
+	keyRowC = []evdev.EvCode{
+		evdev.KEY_A, evdev.KEY_S, evdev.KEY_D, evdev.KEY_F, evdev.KEY_G, evdev.KEY_H,
+		evdev.KEY_J, evdev.KEY_K, evdev.KEY_L, evdev.KEY_SEMICOLON, evdev.KEY_APOSTROPHE,
+	}
+	keyRowB = []evdev.EvCode{
+		evdev.KEY_102ND, evdev.KEY_Z, evdev.KEY_X, evdev.KEY_C, evdev.KEY_V, evdev.KEY_B,
+		evdev.KEY_N, evdev.KEY_M, evdev.KEY_COMMA, evdev.KEY_DOT, evdev.KEY_SLASH,
+	}
+	keyRows = [][]evdev.EvCode{keyRowE, keyRowD, keyRowC, keyRowB}
+)
+
+// xkbLayout lists the symbols of each key row (E, D, C, B) per level.
+// Level 3 (AltGr) may be empty when the layout has no useful AltGr symbols
+type xkbLayout struct {
+	base  [4]string
+	shift [4]string
+	altGr [4]string
+}
+
+// xkbLayouts holds the supported XKB layouts (default variants).
+// Dead keys are mapped to noSymbol; such characters are pasted instead
+var xkbLayouts = map[string]xkbLayout{
+	"us": {
+		base:  [4]string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "•zxcvbnm,./"},
+		shift: [4]string{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "•ZXCVBNM<>?"},
+	},
+	"gb": {
+		base:  [4]string{"`1234567890-=", "qwertyuiop[]#", "asdfghjkl;'", "\\zxcvbnm,./"},
+		shift: [4]string{"¬!\"£$%^&*()_+", "QWERTYUIOP{}~", "ASDFGHJKL:@", "|ZXCVBNM<>?"},
+		altGr: [4]string{"¦•••€••••••••", "", "", ""},
+	},
+	"de": {
+		base:  [4]string{"•1234567890ß•", "qwertzuiopü+#", "asdfghjklöä", "<yxcvbnm,.-"},
+		shift: [4]string{"°!\"§$%&/()=?•", "QWERTZUIOPÜ*'", "ASDFGHJKLÖÄ", ">YXCVBNM;:_"},
+		altGr: [4]string{"•¹²³¼½¬{[]}\\•", "@•€••••••••~•", "", "|••••••µ•••"},
+	},
+	"fr": {
+		base:  [4]string{"²&é\"'(-è_çà)=", "azertyuiop•$*", "qsdfghjklmù", "<wxcvbn,;:!"},
+		shift: [4]string{"•1234567890°+", "AZERTYUIOP•£µ", "QSDFGHJKLM%", ">WXCVBN?./§"},
+		altGr: [4]string{"•••#{[|•\\^@]}", "••€••••••••¤•", "", ""},
+	},
+	"es": {
+		base:  [4]string{"º1234567890'¡", "qwertyuiop•+ç", "asdfghjklñ•", "<zxcvbnm,.-"},
+		shift: [4]string{"ª!\"·$%&/()=?¿", "QWERTYUIOP•*Ç", "ASDFGHJKLÑ•", ">ZXCVBNM;:_"},
+		altGr: [4]string{"\\|@#~€¬••••••", "••€•••••••[]}", "••••••••••{", ""},
+	},
+	"it": {
+		base:  [4]string{"\\1234567890'ì", "qwertyuiopè+ù", "asdfghjklòà", "<zxcvbnm,.-"},
+		shift: [4]string{"|!\"£$%&/()=?^", "QWERTYUIOPé*§", "ASDFGHJKLç°", ">ZXCVBNM;:_"},
+		altGr: [4]string{"", "••€•••••••[]•", "•••••••••@#", ""},
+	},
+}
+
+// Keymap maps runes to key strokes for one XKB layout
+type Keymap struct {
+	layout string
+	keys   map[rune]keyStroke
+}
+
+// NewKeymap builds the keymap for an XKB layout name such as "de" or "de(nodeadkeys)".
+// Variants are ignored; the default variant of the layout is used
+func NewKeymap(layout string) (*Keymap, error) {
+	name := keymapName(layout)
+	def, ok := xkbLayouts[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported keyboard layout for uinput typing: %s", layout)
+	}
+	km := &Keymap{layout: name, keys: make(map[rune]keyStroke)}
+	// Lower levels win when a rune appears on several keys or levels
+	km.addLevel(def.base, false, false)
+	km.addLevel(def.shift, true, false)
+	km.addLevel(def.altGr, false, true)
+	for r, code := range map[rune]evdev.EvCode{' ': evdev.KEY_SPACE, '\n': evdev.KEY_ENTER, '\t': evdev.KEY_TAB} {
+		km.keys[r] = keyStroke{code: code}
+	}
+	return km, nil
+}
+
+// keymapName strips the variant from an XKB layout, e.g. "de(nodeadkeys)"
+func keymapName(layout string) string {
+	name := strings.TrimSpace(layout)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// addLevel registers the runes of one level; a row string may be empty
+func (k *Keymap) addLevel(rows [4]string, shift, altGr bool) {
+	for i, row := range rows {
+		codes := keyRows[i]
+		for j, r := range []rune(row) {
+			if j >= len(codes) || r == noSymbol {
+				continue
+			}
+			if _, exists := k.keys[r]; !exists {
+				k.keys[r] = keyStroke{code: codes[j], shift: shift, altGr: altGr}
+			}
+		}
+	}
+}
+
+// Layout returns the XKB layout name of the keymap
+func (k *Keymap) Layout() string {
+	return k.layout
+}
+
+// Lookup returns the key stroke producing r, if the layout can type it
+func (k *Keymap) Lookup(r rune) (keyStroke, bool) {
+	stroke, ok := k.keys[r]
+	return stroke, ok
+}
+
+// textSegment is a run of text that is either typed or pasted as a whole
+type textSegment struct {
+	text  string
+	paste bool // True when the layout cannot type the run
+}
+
+// Segment splits text into typeable runs and runs that need the paste fallback
+func (k *Keymap) Segment(text string) []textSegment {
+	var segments []textSegment
+	var current strings.Builder
+	currentPaste := false
+	for _, r := range text {
+		_, ok := k.keys[r]
+		if current.Len() > 0 && ok == currentPaste {
+			segments = append(segments, textSegment{text: current.String(), paste: currentPaste})
+			current.Reset()
+		}
+		currentPaste = !ok
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		segments = append(segments, textSegment{text: current.String(), paste: currentPaste})
+	}
+	return segments
+}
+
+// xkbLayoutSources are checked in order for the system XKB layout:
+// systemd-localed (vconsole.conf, X11 config) and Debian's keyboard file
+var xkbLayoutSources = []string{
+	"/etc/vconsole.conf",
+	"/etc/X11/xorg.conf.d/00-keyboard.conf",
+	"/etc/default/keyboard",
+}
+
+// DetectXKBLayout returns the active XKB layout: the layout currently
+// selected in the desktop session (GNOME, KDE), then XKB_DEFAULT_LAYOUT and
+// the system keyboard configuration. Without a session to ask, the first of
+// several configured layouts (e.g. "us,de") is used. Defaults to "us"
+func DetectXKBLayout() string {
+	if layout := sessionXKBLayout(); layout != "" {
+		return layout
+	}
+	if layout := firstLayout(os.Getenv("XKB_DEFAULT_LAYOUT")); layout != "" {
+		return layout
+	}
+	for _, path := range xkbLayoutSources {
+		if layout := readXKBLayoutFile(path); layout != "" {
+			return layout
+		}
+	}
+	return "us"
+}
+
+// sessionCommandTimeout bounds gsettings/gdbus queries made before typing
+const sessionCommandTimeout = 500 * time.Millisecond
+
+// sessionCommand runs a desktop query tool; replaced in tests
+var sessionCommand = func(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionCommandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// sessionXKBLayout returns the layout selected in the desktop session, or ""
+func sessionXKBLayout() string {
+	desktop := strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP"))
+	switch {
+	case strings.Contains(desktop, "GNOME"):
+		return gnomeXKBLayout()
+	case strings.Contains(desktop, "KDE"):
+		return kdeXKBLayout()
+	}
+	return ""
+}
+
+// gnomeInputSource matches one ('xkb', 'de+nodeadkeys') tuple of a gsettings list
+var gnomeInputSource = regexp.MustCompile(`\('([^']*)',\s*'([^']*)'\)`)
+
+// gnomeXKBLayout reads the current input source: the head of mru-sources,
+// or sources[current] on GNOME versions without it
+func gnomeXKBLayout() string {
+	const schema = "org.gnome.desktop.input-sources"
+	if mru, err := sessionCommand("gsettings", "get", schema, "mru-sources"); err == nil {
+		if layouts := parseGnomeSources(mru); len(layouts) > 0 {
+			return layouts[0]
+		}
+	}
+	sources, err := sessionCommand("gsettings", "get", schema, "sources")
+	if err != nil {
+		return ""
+	}
+	layouts := parseGnomeSources(sources)
+	current, err := sessionCommand("gsettings", "get", schema, "current")
+	if err != nil {
+		current = "0"
+	}
+	return layoutAt(layouts, parseGroupIndex(current))
+}
+
+// parseGnomeSources returns the XKB layouts of a gsettings source list in
+// order, without variants; input methods (ibus) are skipped
+func parseGnomeSources(value string) []string {
+	var layouts []string
+	for _, m := range gnomeInputSource.FindAllStringSubmatch(value, -1) {
+		if m[1] != "xkb" {
+			continue
+		}
+		layout, _, _ := strings.Cut(m[2], "+")
+		layouts = append(layouts, layout)
+	}
+	return layouts
+}
+
+// kdeXKBLayout reads LayoutList from kxkbrc and the current group index
+// from the keyboard daemon
+func kdeXKBLayout() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	layouts := readKxkbLayouts(filepath.Join(configHome, "kxkbrc"))
+	if len(layouts) == 0 {
+		return ""
+	}
+	index := 0
+	if out, err := sessionCommand("gdbus", "call", "--session", "--dest", "org.kde.keyboard",
+		"--object-path", "/Layouts", "--method", "org.kde.KeyboardLayouts.getLayout"); err == nil {
+		index = parseGroupIndex(out)
+	}
+	return layoutAt(layouts, index)
+}
+
+// readKxkbLayouts returns the layouts of the [Layout] group of kxkbrc, or nil
+// when KDE does not manage the layouts (Use=false)
+func readKxkbLayouts(path string) []string {
+	data, err := os.ReadFile(path) // #nosec G304 -- Fixed per-user configuration path
+	if err != nil {
+		return nil
+	}
+	var layouts []string
+	use, inLayout := false, false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inLayout = line == "[Layout]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inLayout || !ok {
+			continue
+		}
+		switch key {
+		case "LayoutList":
+			for _, layout := range strings.Split(value, ",") {
+				if layout = strings.TrimSpace(layout); layout != "" {
+					layouts = append(layouts, layout)
+				}
+			}
+		case "Use":
+			use = value == "true"
+		}
+	}
+	if !use {
+		return nil
+	}
+	return layouts
+}
+
+// parseGroupIndex reads the index of "uint32 1" (gsettings) or "(uint32 1,)" (gdbus)
+func parseGroupIndex(value string) int {
+	value = strings.TrimPrefix(strings.Trim(value, "(), "), "uint32")
+	index, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return index
+}
+
+// layoutAt returns layouts[index], falling back to the first layout
+func layoutAt(layouts []string, index int) string {
+	if len(layouts) == 0 {
+		return ""
+	}
+	if index < 0 || index >= len(layouts) {
+		index = 0
+	}
+	return layouts[index]
+}
+
+// readXKBLayoutFile extracts the layout from KEY=value files (XKBLAYOUT=de)
+// or xorg.conf sections (Option "XkbLayout" "de")
+func readXKBLayoutFile(path string) string {
+	file, err := os.Open(path) // #nosec G304 -- Fixed system configuration paths
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if layout := parseXKBLayoutLine(scanner.Text()); layout != "" {
+			return layout
+		}
+	}
+	return ""
+}
+
+// parseXKBLayoutLine returns the first layout named on a config line, if any
+func parseXKBLayoutLine(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	if value, ok := strings.CutPrefix(line, "XKBLAYOUT="); ok {
+		return firstLayout(strings.Trim(value, "\"'"))
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 3 && fields[0] == "Option" && strings.EqualFold(strings.Trim(fields[1], "\""), "XkbLayout") {
+		return firstLayout(strings.Trim(fields[2], "\""))
+	}
+	return ""
+}
+
+// firstLayout returns the first entry of a comma-separated layout list
+func firstLayout(layouts string) string {
+	first, _, _ := strings.Cut(layouts, ",")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,240 @@
+//go:build linux
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	evdev "github.com/holoplot/go-evdev"
+)
+
+func TestXKBLayouts_RowLengths(t *testing.T) {
+	for name, layout := range xkbLayouts {
+		for level, rows := range map[string][4]string{"base": layout.base, "shift": layout.shift, "altgr": layout.altGr} {
+			for i, row := range rows {
+				if n := len([]rune(row)); n != 0 && n != len(keyRows[i]) {
+					t.Errorf("%s %s row %d has %d symbols, want %d", name, level, i, n, len(keyRows[i]))
+				}
+			}
+		}
+		if len([]rune(layout.base[0])) == 0 || len([]rune(layout.shift[0])) == 0 {
+			t.Errorf("%s must define base and shift levels", name)
+		}
+	}
+}
+
+func TestKeymap_Lookup(t *testing.T) {
+	tests := []struct {
+		layout string
+		r      rune
+		want   keyStroke
+	}{
+		{"us", 'a', keyStroke{code: evdev.KEY_A}},
+		{"us", 'A', keyStroke{code: evdev.KEY_A, shift: true}},
+		{"us", '@', keyStroke{code: evdev.KEY_2, shift: true}},
+		{"us", '"', keyStroke{code: evdev.KEY_APOSTROPHE, shift: true}},
+		{"us", ' ', keyStroke{code: evdev.KEY_SPACE}},
+		{"us", '\n', keyStroke{code: evdev.KEY_ENTER}},
+		{"de", 'z', keyStroke{code: evdev.KEY_Y}},
+		{"de", 'y', keyStroke{code: evdev.KEY_Z}},
+		{"de", 'ü', keyStroke{code: evdev.KEY_LEFTBRACE}},
+		{"de", '@', keyStroke{code: evdev.KEY_Q, altGr: true}},
+		{"de", '€', keyStroke{code: evdev.KEY_E, altGr: true}},
+		{"de(nodeadkeys)", '|', keyStroke{code: evdev.KEY_102ND, altGr: true}},
+		{"fr", 'a', keyStroke{code: evdev.KEY_Q}},
+		{"fr", '1', keyStroke{code: evdev.KEY_1, shift: true}},
+		{"fr", 'é', keyStroke{code: evdev.KEY_2}},
+		{"gb", '£', keyStroke{code: evdev.KEY_3, shift: true}},
+		{"gb", '#', keyStroke{code: evdev.KEY_BACKSLASH}},
+		{"es", 'ñ', keyStroke{code: evdev.KEY_SEMICOLON}},
+		{"it", 'è', keyStroke{code: evdev.KEY_LEFTBRACE}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout+" "+string(tt.r), func(t *testing.T) {
+			km, err := NewKeymap(tt.layout)
+			if err != nil {
+				t.Fatalf("NewKeymap: %v", err)
+			}
+			got, ok := km.Lookup(tt.r)
+			if !ok {
+				t.Fatalf("expected %q to be typeable", tt.r)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestKeymap_Unmapped(t *testing.T) {
+	us, _ := NewKeymap("us")
+	de, _ := NewKeymap("de")
+	for _, r := range []rune{'é', 'ß', '€', '•'} {
+		if _, ok := us.Lookup(r); ok {
+			t.Errorf("us should not type %q", r)
+		}
+	}
+	// Dead keys are never mapped
+	if _, ok := de.Lookup('^'); ok {
+		t.Error("de dead circumflex should not be mapped")
+	}
+	if _, err := NewKeymap("ru"); err == nil {
+		t.Error("expected error for unsupported layout")
+	}
+}
+
+func TestKeymap_Segment(t *testing.T) {
+	km, _ := NewKeymap("us")
+	got := km.Segment("café au lait — olé")
+	want := []textSegment{
+		{text: "caf"},
+		{text: "é", paste: true},
+		{text: " au lait "},
+		{text: "—", paste: true},
+		{text: " ol"},
+		{text: "é", paste: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d segments, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("segment %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if segments := km.Segment(""); len(segments) != 0 {
+		t.Errorf("expected no segments for empty text, got %+v", segments)
+	}
+}
+
+func TestParseXKBLayoutLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`XKBLAYOUT=de`, "de"},
+		{`XKBLAYOUT="us,de"`, "us"},
+		{`  Option "XkbLayout" "fr"`, "fr"},
+		{`Option "XkbModel" "pc105"`, ""},
+		{`# XKBLAYOUT=de`, ""},
+		{`KEYMAP=de-latin1`, ""},
+	}
+	for _, tt := range tests {
+		if got := parseXKBLayoutLine(tt.line); got != tt.want {
+			t.Errorf("parseXKBLayoutLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDetectXKBLayout(t *testing.T) {
+	dir := t.TempDir()
+	vconsole := filepath.Join(dir, "vconsole.conf")
+	if err := os.WriteFile(vconsole, []byte("KEYMAP=de-latin1\nXKBLAYOUT=de\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	orig := xkbLayoutSources
+	t.Cleanup(func() { xkbLayoutSources = orig })
+	xkbLayoutSources = []string{filepath.Join(dir, "missing"), vconsole}
+
+	t.Setenv("XDG_CURRENT_DESKTOP", "")
+	t.Setenv("XKB_DEFAULT_LAYOUT", "")
+	if got := DetectXKBLayout(); got != "de" {
+		t.Errorf("expected layout from vconsole.conf, got %q", got)
+	}
+	t.Setenv("XKB_DEFAULT_LAYOUT", "fr,us")
+	if got := DetectXKBLayout(); got != "fr" {
+		t.Errorf("expected XKB_DEFAULT_LAYOUT to win, got %q", got)
+	}
+	t.Setenv("XKB_DEFAULT_LAYOUT", "")
+	xkbLayoutSources = nil
+	if got := DetectXKBLayout(); got != "us" {
+		t.Errorf("expected us default, got %q", got)
+	}
+}
+
+func TestSessionXKBLayout(t *testing.T) {
+	const schema = "org.gnome.desktop.input-sources"
+	tests := []struct {
+		name    string
+		desktop string
+		kxkbrc  string
+		replies map[string]string // Command line -> output; missing commands fail
+		want    string
+	}{
+		{
+			name:    "gnome most recently used source",
+			desktop: "ubuntu:GNOME",
+			replies: map[string]string{
+				"gsettings get " + schema + " mru-sources": "[('xkb', 'de+nodeadkeys'), ('xkb', 'us')]",
+			},
+			want: "de",
+		},
+		{
+			name:    "gnome current index without mru",
+			desktop: "GNOME",
+			replies: map[string]string{
+				"gsettings get " + schema + " mru-sources": "@a(ss) []",
+				"gsettings get " + schema + " sources":     "[('xkb', 'us'), ('ibus', 'anthy'), ('xkb', 'fr')]",
+				"gsettings get " + schema + " current":     "uint32 1",
+			},
+			want: "fr",
+		},
+		{
+			name:    "kde current group",
+			desktop: "KDE",
+			kxkbrc:  "[Layout]\nLayoutList=us,ru\nUse=true\n",
+			replies: map[string]string{
+				"gdbus call --session --dest org.kde.keyboard --object-path /Layouts --method org.kde.KeyboardLayouts.getLayout": "(uint32 1,)",
+			},
+			want: "ru",
+		},
+		{
+			name:    "kde first layout when the daemon is unreachable",
+			desktop: "KDE",
+			kxkbrc:  "[Layout]\nLayoutList=de,us\nUse=true\n",
+			want:    "de",
+		},
+		{
+			name:    "kde layouts not managed",
+			desktop: "KDE",
+			kxkbrc:  "[Layout]\nLayoutList=de,us\nUse=false\n",
+			want:    "",
+		},
+		{
+			name:    "other desktops are not queried",
+			desktop: "sway",
+			replies: map[string]string{"gsettings get " + schema + " mru-sources": "[('xkb', 'de')]"},
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.kxkbrc != "" {
+				if err := os.WriteFile(filepath.Join(dir, "kxkbrc"), []byte(tt.kxkbrc), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("XDG_CURRENT_DESKTOP", tt.desktop)
+			orig := sessionCommand
+			t.Cleanup(func() { sessionCommand = orig })
+			sessionCommand = func(name string, args ...string) (string, error) {
+				if out, ok := tt.replies[strings.Join(append([]string{name}, args...), " ")]; ok {
+					return out, nil
+				}
+				return "", errors.New("not available")
+			}
+			if got := sessionXKBLayout(); got != tt.want {
+				t.Errorf("sessionXKBLayout() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
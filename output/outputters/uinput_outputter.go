@@ -0,0 +1,329 @@
+//go:build linux
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+	evdev "github.com/holoplot/go-evdev"
+)
+
+// UinputTypeTool is the type_tool value selecting the built-in uinput typer
+const UinputTypeTool = "uinput"
+
+const (
+	uinputDeviceName = "dabri virtual keyboard"
+	// uinputSettleDelay gives the compositor time to pick up a new device
+	uinputSettleDelay = 300 * time.Millisecond
+	// uinputKeyDelay separates key strokes so applications do not drop them;
+	// output.type_delay_ms overrides it
+	uinputKeyDelay = 8 * time.Millisecond
+)
+
+// keyEventWriter is the subset of evdev.InputDevice used for typing
+type keyEventWriter interface {
+	WriteOne(event *evdev.InputEvent) error
+}
+
+// The virtual keyboard is created once and shared by every uinput outputter
+// in the process; writes are serialized so strokes never interleave
+var (
+	uinputMu       sync.Mutex
+	uinputKeyboard *evdev.InputDevice
+)
+
+// openUinputKeyboard returns the shared virtual keyboard, creating it on first use
+func openUinputKeyboard() (keyEventWriter, error) {
+	if uinputKeyboard != nil {
+		return uinputKeyboard, nil
+	}
+	device, err := evdev.CreateDevice(uinputDeviceName, evdev.InputID{
+		BusType: evdev.BUS_VIRTUAL,
+		Vendor:  0x1,
+		Product: 0x1,
+		Version: 1,
+	}, map[evdev.EvType][]evdev.EvCode{evdev.EV_KEY: uinputKeyCodes()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create uinput keyboard: %w", err)
+	}
+	uinputKeyboard = device
+	time.Sleep(uinputSettleDelay)
+	return uinputKeyboard, nil
+}
+
+// uinputKeyCodes lists every key the virtual keyboard may press
+func uinputKeyCodes() []evdev.EvCode {
+	codes := []evdev.EvCode{
+		evdev.KEY_SPACE, evdev.KEY_ENTER, evdev.KEY_TAB, evdev.KEY_INSERT, evdev.KEY_BACKSPACE,
+		evdev.KEY_LEFTCTRL, evdev.KEY_LEFTSHIFT, evdev.KEY_RIGHTALT,
+	}
+	for _, row := range keyRows {
+		codes = append(codes, row...)
+	}
+	return codes
+}
+
+// Implements the Outputter interface by typing through a /dev/uinput virtual
+// keyboard. Text is mapped to key codes for the active XKB layout; characters
+// the layout cannot type are pasted through the clipboard instead
+type UinputOutputter struct {
+	keymap       *Keymap             // Rebuilt under uinputMu when the layout changes
+	detectLayout func() string       // Current session layout (nil keeps the keymap)
+	clipboard    *ClipboardOutputter // Nil disables the paste fallback
+	config       *config.Config
+	// device, sleep and schedule are replaced in tests to record key events
+	device   func() (keyEventWriter, error)
+	sleep    func(time.Duration)
+	schedule func(delay time.Duration, fn func())
+}
+
+// Create a new uinput outputter for the detected XKB layout.
+// clipboardTool is used for the paste fallback and may be empty
+func NewUinputOutputter(clipboardTool string, cfg *config.Config) (interfaces.Outputter, error) {
+	keymap, err := NewKeymap(DetectXKBLayout())
+	if err != nil {
+		return nil, err
+	}
+	// Verify access up front so the caller can fall back to another tool
+	if err := probeUinput(); err != nil {
+		return nil, err
+	}
+
+	var clipboard *ClipboardOutputter
+	if clipboardTool != "" {
+		if _, err := exec.LookPath(clipboardTool); err == nil {
+			clipboard = &ClipboardOutputter{clipboardTool: clipboardTool, config: cfg}
+		}
+	}
+	return &UinputOutputter{
+		keymap:       keymap,
+		detectLayout: DetectXKBLayout,
+		clipboard:    clipboard,
+		config:       cfg,
+		device:       openUinputKeyboard,
+		sleep:        time.Sleep,
+		schedule:     func(delay time.Duration, fn func()) { time.AfterFunc(delay, fn) },
+	}, nil
+}
+
+// Type text into the currently active window via the virtual keyboard
+func (o *UinputOutputter) TypeToActiveWindow(text string) error {
+	return o.TypeToActiveWindowContext(context.Background(), text)
+}
+
+// Type text, pausing after every output.type_chunk_size characters.
+// Stops before the next key stroke when ctx is canceled
+func (o *UinputOutputter) TypeToActiveWindowContext(ctx context.Context, text string) error {
+	uinputMu.Lock()
+	defer uinputMu.Unlock()
+	if err := o.refreshKeymap(); err != nil {
+		return err
+	}
+	segments := o.keymap.Segment(text)
+	for _, segment := range segments {
+		if segment.paste && o.clipboard == nil {
+			return fmt.Errorf("layout %s cannot type %q and no clipboard tool is available for pasting", o.keymap.Layout(), segment.text)
+		}
+	}
+
+	device, err := o.device()
+	if err != nil {
+		return err
+	}
+
+	var saved, pasted string
+	savedOK := false
+	// Restore the clipboard even when typing stops part-way
+	defer func() {
+		if pasted != "" && savedOK && o.config.Output.PasteRestoreDelayMs > 0 {
+			delay := time.Duration(o.config.Output.PasteRestoreDelayMs) * time.Millisecond
+			o.schedule(delay, func() { o.restoreClipboard(saved, pasted) })
+		}
+	}()
+	typed := 0
+	for i, segment := range segments {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("typing canceled after %d characters: %w", typed, err)
+		}
+		if !segment.paste {
+			for _, r := range segment.text {
+				if err := ctx.Err(); err != nil {
+					return fmt.Errorf("typing canceled after %d characters: %w", typed, err)
+				}
+				stroke, _ := o.keymap.Lookup(r)
+				if err := o.tap(device, stroke, nil); err != nil {
+					return err
+				}
+				typed++
+				if size := o.config.Output.TypeChunkSize; size > 0 && typed%size == 0 {
+					o.sleep(typeChunkPause)
+				}
+			}
+			continue
+		}
+		if pasted == "" {
+			// An unreadable clipboard (e.g., empty on Wayland) simply isn't restored
+			content, readErr := o.clipboard.ReadClipboard()
+			saved, savedOK = content, readErr == nil
+		}
+		if err := o.pasteSegment(device, segment.text); err != nil {
+			return fmt.Errorf("failed to paste %q (segment %d): %w", segment.text, i+1, err)
+		}
+		pasted = segment.text
+		typed += len([]rune(segment.text))
+	}
+	return nil
+}
+
+// refreshKeymap follows layout switches made since the keymap was built.
+// Called with uinputMu held
+func (o *UinputOutputter) refreshKeymap() error {
+	if o.detectLayout == nil {
+		return nil
+	}
+	layout := o.detectLayout()
+	if keymapName(layout) == o.keymap.Layout() {
+		return nil
+	}
+	keymap, err := NewKeymap(layout)
+	if err != nil {
+		return err
+	}
+	o.keymap = keymap
+	return nil
+}
+
+// Delete the last count typed characters by pressing BackSpace
+func (o *UinputOutputter) EraseTyped(count int) error {
+	uinputMu.Lock()
+	defer uinputMu.Unlock()
+	device, err := o.device()
+	if err != nil {
+		return err
+	}
+	for range count {
+		if err := o.tap(device, keyStroke{code: evdev.KEY_BACKSPACE}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pasteSegment puts text on the clipboard and sends the configured paste keys
+func (o *UinputOutputter) pasteSegment(device keyEventWriter, text string) error {
+	combo, ok := pasteCombos[o.config.Output.PasteKeys]
+	if !ok {
+		combo = pasteCombos["ctrl+v"]
+	}
+	var stroke keyStroke
+	if combo.key == "Insert" {
+		stroke = keyStroke{code: evdev.KEY_INSERT}
+	} else if stroke, ok = o.keymap.Lookup('v'); !ok {
+		return fmt.Errorf("layout %s has no 'v' key for pasting", o.keymap.Layout())
+	}
+	// Paste key combinations are defined by keysym, so only the key itself
+	// comes from the layout; shift/AltGr needed to type 'v' would change it
+	stroke.shift, stroke.altGr = false, false
+	modifiers := make([]evdev.EvCode, 0, len(combo.modifiers))
+	for _, mod := range combo.modifiers {
+		switch mod {
+		case "ctrl":
+			modifiers = append(modifiers, evdev.KEY_LEFTCTRL)
+		case "shift":
+			modifiers = append(modifiers, evdev.KEY_LEFTSHIFT)
+		}
+	}
+
+	if err := o.clipboard.CopyToClipboard(text); err != nil {
+		return err
+	}
+	o.sleep(pasteSettleDelay)
+	if err := o.tap(device, stroke, modifiers); err != nil {
+		return err
+	}
+	// Let the application read the clipboard before it changes again
+	o.sleep(pasteSettleDelay)
+	return nil
+}
+
+// restoreClipboard puts the saved content back unless the clipboard
+// was changed by someone else since the paste
+func (o *UinputOutputter) restoreClipboard(saved, pasted string) {
+	current, err := o.clipboard.ReadClipboard()
+	if err != nil || current != pasted {
+		return
+	}
+	_ = o.clipboard.CopyToClipboard(saved)
+}
+
+// tap presses and releases one key with its modifiers plus any extra modifiers
+func (o *UinputOutputter) tap(device keyEventWriter, stroke keyStroke, extra []evdev.EvCode) error {
+	modifiers := append([]evdev.EvCode(nil), extra...)
+	if stroke.shift {
+		modifiers = append(modifiers, evdev.KEY_LEFTSHIFT)
+	}
+	if stroke.altGr {
+		modifiers = append(modifiers, evdev.KEY_RIGHTALT)
+	}
+	events := make([]evdev.InputEvent, 0, 2*len(modifiers)+4)
+	for _, mod := range modifiers {
+		events = append(events, keyEvent(mod, 1))
+	}
+	events = append(events, keyEvent(stroke.code, 1), synEvent(), keyEvent(stroke.code, 0))
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		events = append(events, keyEvent(modifiers[i], 0))
+	}
+	events = append(events, synEvent())
+	for i := range events {
+		if err := device.WriteOne(&events[i]); err != nil {
+			return fmt.Errorf("failed to write uinput event: %w", err)
+		}
+	}
+	delay := uinputKeyDelay
+	if ms := o.config.Output.TypeDelayMs; ms > 0 {
+		delay = time.Duration(ms) * time.Millisecond
+	}
+	o.sleep(delay)
+	return nil
+}
+
+func keyEvent(code evdev.EvCode, value int32) evdev.InputEvent {
+	return evdev.InputEvent{Type: evdev.EV_KEY, Code: code, Value: value}
+}
+
+func synEvent() evdev.InputEvent {
+	return evdev.InputEvent{Type: evdev.EV_SYN, Code: evdev.SYN_REPORT}
+}
+
+// Copy text to the clipboard used by the paste fallback
+func (o *UinputOutputter) CopyToClipboard(text string) error {
+	if o.clipboard == nil {
+		return fmt.Errorf("copying to clipboard not supported by uinput outputter")
+	}
+	return o.clipboard.CopyToClipboard(text)
+}
+
+// Read the clipboard used by the paste fallback
+func (o *UinputOutputter) ReadClipboard() (string, error) {
+	if o.clipboard == nil {
+		return "", fmt.Errorf("reading clipboard not supported by uinput outputter")
+	}
+	return o.clipboard.ReadClipboard()
+}
+
+// Return the names of the clipboard tool (paste fallback) and the typer
+func (o *UinputOutputter) GetToolNames() (clipboardTool, typeTool string) {
+	if o.clipboard != nil {
+		clipboardTool = o.clipboard.clipboardTool
+	}
+	return clipboardTool, UinputTypeTool
+}
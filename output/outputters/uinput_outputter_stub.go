@@ -0,0 +1,21 @@
+//go:build !linux
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"fmt"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+// UinputTypeTool is the type_tool value selecting the built-in uinput typer
+const UinputTypeTool = "uinput"
+
+// NewUinputOutputter is unavailable outside Linux
+func NewUinputOutputter(clipboardTool string, cfg *config.Config) (interfaces.Outputter, error) {
+	return nil, fmt.Errorf("uinput typing is only supported on Linux")
+}
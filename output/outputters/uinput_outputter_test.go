@@ -0,0 +1,183 @@
+//go:build linux
+
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/internal/testutils"
+	"github.com/AshBuk/dabri/output/interfaces"
+	evdev "github.com/holoplot/go-evdev"
+)
+
+// recordingKeyboard captures key events instead of writing to /dev/uinput
+type recordingKeyboard struct {
+	events []evdev.InputEvent
+}
+
+func (k *recordingKeyboard) WriteOne(event *evdev.InputEvent) error {
+	k.events = append(k.events, *event)
+	return nil
+}
+
+// keys renders key events as "+NAME"/"-NAME", dropping SYN reports
+func (k *recordingKeyboard) keys() string {
+	var out []string
+	for _, e := range k.events {
+		if e.Type != evdev.EV_KEY {
+			continue
+		}
+		sign := "-"
+		if e.Value == 1 {
+			sign = "+"
+		}
+		out = append(out, sign+strings.TrimPrefix(evdev.CodeName(evdev.EV_KEY, e.Code), "KEY_"))
+	}
+	return strings.Join(out, " ")
+}
+
+func newTestUinputOutputter(t *testing.T, layout string, clipboard *ClipboardOutputter, cfg *config.Config) (*UinputOutputter, *recordingKeyboard) {
+	t.Helper()
+	km, err := NewKeymap(layout)
+	if err != nil {
+		t.Fatalf("NewKeymap: %v", err)
+	}
+	keyboard := &recordingKeyboard{}
+	return &UinputOutputter{
+		keymap:    km,
+		clipboard: clipboard,
+		config:    cfg,
+		device:    func() (keyEventWriter, error) { return keyboard, nil },
+		sleep:     func(time.Duration) {},
+		schedule:  func(time.Duration, func()) {},
+	}, keyboard
+}
+
+func TestUinputOutputter_TypesWithModifiers(t *testing.T) {
+	out, keyboard := newTestUinputOutputter(t, "de", nil, &config.Config{})
+	if err := out.TypeToActiveWindow("Hz@\n"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	want := "+LEFTSHIFT +H -H -LEFTSHIFT +Y -Y +RIGHTALT +Q -Q -RIGHTALT +ENTER -ENTER"
+	if got := keyboard.keys(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	// Every stroke ends with a SYN report
+	if last := keyboard.events[len(keyboard.events)-1]; last.Type != evdev.EV_SYN {
+		t.Errorf("expected trailing SYN report, got %+v", last)
+	}
+}
+
+func TestUinputOutputter_FollowsLayoutSwitch(t *testing.T) {
+	out, keyboard := newTestUinputOutputter(t, "us", nil, &config.Config{})
+	out.detectLayout = func() string { return "de(nodeadkeys)" }
+	if err := out.TypeToActiveWindow("z"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	// German layout: z is on the Y key
+	if want := "+Y -Y"; keyboard.keys() != want {
+		t.Errorf("expected %q, got %q", want, keyboard.keys())
+	}
+
+	out.detectLayout = func() string { return "klingon" }
+	if err := out.TypeToActiveWindow("z"); err == nil {
+		t.Error("expected an error for an unsupported session layout")
+	}
+}
+
+func TestUinputOutputter_PastesUnmappedText(t *testing.T) {
+	cb := testutils.NewFakeClipboard(t, "")
+	cfg := &config.Config{}
+	cfg.Security.AllowedCommands = []string{"xsel"}
+	cfg.Output.PasteKeys = "ctrl+v"
+	cfg.Output.PasteRestoreDelayMs = 500
+	out, keyboard := newTestUinputOutputter(t, "us", &ClipboardOutputter{clipboardTool: "xsel", config: cfg}, cfg)
+	if err := out.clipboard.CopyToClipboard("previous"); err != nil {
+		t.Fatal(err)
+	}
+	var restore func()
+	out.schedule = func(_ time.Duration, fn func()) { restore = fn }
+
+	if err := out.TypeToActiveWindow("a€"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	if want := "+A -A +LEFTCTRL +V -V -LEFTCTRL"; keyboard.keys() != want {
+		t.Errorf("expected %q, got %q", want, keyboard.keys())
+	}
+	if got := cb.Get(); got != "€" {
+		t.Errorf("expected pasted text on clipboard, got %q", got)
+	}
+	if restore == nil {
+		t.Fatal("expected clipboard restore to be scheduled")
+	}
+	restore()
+	if got := cb.Get(); got != "previous" {
+		t.Errorf("expected clipboard restored, got %q", got)
+	}
+}
+
+func TestUinputOutputter_UnmappedWithoutClipboard(t *testing.T) {
+	out, keyboard := newTestUinputOutputter(t, "us", nil, &config.Config{})
+	if err := out.TypeToActiveWindow("naïve"); err == nil {
+		t.Fatal("expected error without a paste fallback")
+	}
+	if len(keyboard.events) != 0 {
+		t.Errorf("nothing must be typed when the text cannot be delivered, got %s", keyboard.keys())
+	}
+}
+
+func TestUinputOutputter_DeviceError(t *testing.T) {
+	out, _ := newTestUinputOutputter(t, "us", nil, &config.Config{})
+	out.device = func() (keyEventWriter, error) { return nil, fmt.Errorf("permission denied") }
+	if err := out.TypeToActiveWindow("hello"); err == nil {
+		t.Fatal("expected device error")
+	}
+}
+
+func TestUinputOutputter_DelayAndCancel(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Output.TypeDelayMs = 30
+	out, keyboard := newTestUinputOutputter(t, "us", nil, cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	var sleeps []time.Duration
+	out.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		cancel() // Abort after the first key stroke
+	}
+
+	err := out.TypeToActiveWindowContext(ctx, "abc")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if got := keyboard.keys(); got != "+A -A" {
+		t.Errorf("expected typing to stop after one key, got %q", got)
+	}
+	if len(sleeps) != 1 || sleeps[0] != 30*time.Millisecond {
+		t.Errorf("expected configured key delay, got %v", sleeps)
+	}
+}
+
+func TestUinputOutputter_Interface(t *testing.T) {
+	var _ interfaces.Outputter = (*UinputOutputter)(nil)
+	var _ interfaces.ContextTyper = (*UinputOutputter)(nil)
+	var _ interfaces.Eraser = (*UinputOutputter)(nil)
+}
+
+func TestUinputOutputter_EraseTyped(t *testing.T) {
+	out, keyboard := newTestUinputOutputter(t, "us", nil, &config.Config{})
+	if err := out.EraseTyped(2); err != nil {
+		t.Fatalf("EraseTyped: %v", err)
+	}
+	if want, got := "+BACKSPACE -BACKSPACE +BACKSPACE -BACKSPACE", keyboard.keys(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,507 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+// WebhookSignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>"
+// when a webhook secret is configured
+const WebhookSignatureHeader = "X-Dabri-Signature"
+
+// Retry backoff doubles from webhookBackoffBase up to webhookBackoffMax
+const (
+	webhookBackoffBase    = 500 * time.Millisecond
+	webhookBackoffMax     = 8 * time.Second
+	webhookQueueFile      = "webhook-queue.jsonl"
+	webhookFlushInterval  = 30 * time.Second // Retry of queued payloads without new transcripts
+	webhookPendingPayload = 64               // Transcripts waiting for the worker
+)
+
+// webhookQueueMu serializes access to the on-disk retry queue, which is
+// shared by every webhook outputter in the process
+var webhookQueueMu sync.Mutex
+
+// WebhookPayload is the JSON body posted for each transcript
+type WebhookPayload struct {
+	Text      string  `json:"text"`
+	SessionID string  `json:"session_id,omitempty"`
+	Language  string  `json:"language"`
+	Duration  float64 `json:"duration"`  // Recording duration in seconds
+	Timestamp string  `json:"timestamp"` // RFC3339
+}
+
+// webhookStatusError reports a non-2xx response from the endpoint
+type webhookStatusError struct {
+	code int
+}
+
+func (e *webhookStatusError) Error() string {
+	return fmt.Sprintf("webhook returned HTTP %d", e.code)
+}
+
+// permanent reports whether retrying the request cannot succeed
+func (e *webhookStatusError) permanent() bool {
+	return e.code >= 400 && e.code < 500 && e.code != http.StatusRequestTimeout && e.code != http.StatusTooManyRequests
+}
+
+// Implements the Outputter interface by POSTing transcripts as JSON to a
+// local service. Each transcript is written to a bounded on-disk queue and
+// handed to a WebhookWorker, so a slow or unreachable endpoint never holds up
+// the dictation: failed deliveries are retried with backoff and stay queued
+// until the next transcript or the periodic flush delivers them
+type WebhookOutputter struct {
+	config    *config.Config
+	client    *http.Client
+	queuePath string
+	worker    *WebhookWorker
+	// sleep waits between retries; replaced in tests to skip the backoff
+	sleep func(time.Duration)
+}
+
+// webhookJob is a transcript handed to the worker with the settings of the
+// outputter that produced it
+type webhookJob struct {
+	out    *WebhookOutputter
+	body   []byte
+	queued bool // Already in the on-disk queue (false when queue_size is 0)
+}
+
+// WebhookWorker delivers webhook transcripts in the background and retries
+// the on-disk queue every webhookFlushInterval. It is owned by the output
+// service, which closes it on shutdown
+type WebhookWorker struct {
+	jobs    chan webhookJob
+	stop    chan struct{}
+	done    chan struct{}
+	onError func(error)    // Receives delivery failures (nil to ignore them)
+	pending sync.WaitGroup // Jobs accepted but not yet handled
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	last    *WebhookOutputter // Settings used by the periodic flush
+}
+
+// NewWebhookWorker Constructor - onError is called from the worker goroutine
+// for failed deliveries; the goroutine starts with the first transcript
+func NewWebhookWorker(onError func(error)) *WebhookWorker {
+	return &WebhookWorker{
+		jobs:    make(chan webhookJob, webhookPendingPayload),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		onError: onError,
+	}
+}
+
+// submit hands a payload to the worker without waiting for delivery.
+// A queued payload survives a full backlog or a closed worker on disk
+func (w *WebhookWorker) submit(job webhookJob) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		if job.queued {
+			return nil
+		}
+		return fmt.Errorf("webhook delivery stopped")
+	}
+	w.last = job.out
+	if !w.started {
+		w.started = true
+		go w.run()
+	}
+	w.pending.Add(1)
+	select {
+	case w.jobs <- job:
+		return nil
+	default:
+		w.pending.Done()
+		if job.queued {
+			return nil
+		}
+		return fmt.Errorf("webhook delivery backlog full (%d transcripts)", webhookPendingPayload)
+	}
+}
+
+// run delivers jobs in order and retries the queue while the daemon is idle
+func (w *WebhookWorker) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(webhookFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			w.drainPending()
+			return
+		case job := <-w.jobs:
+			w.handle(job)
+			w.pending.Done()
+		case <-ticker.C:
+			w.mu.Lock()
+			last := w.last
+			w.mu.Unlock()
+			if last != nil {
+				_ = last.drainQueue(false)
+			}
+		}
+	}
+}
+
+// handle delivers one job: queued jobs drain the queue in order, others are
+// posted after whatever an earlier configuration left in the queue
+func (w *WebhookWorker) handle(job webhookJob) {
+	var err error
+	if job.queued {
+		err = job.out.drainQueue(true)
+	} else {
+		_ = job.out.drainQueue(false)
+		err = job.out.postWithRetry(job.body)
+	}
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
+
+// drainPending runs at shutdown: queued jobs are already on disk and wait for
+// the next start; jobs without a queue get a single delivery attempt
+func (w *WebhookWorker) drainPending() {
+	for {
+		select {
+		case job := <-w.jobs:
+			if !job.queued {
+				if err := job.out.post(job.body); err != nil && w.onError != nil {
+					w.onError(err)
+				}
+			}
+			w.pending.Done()
+		default:
+			return
+		}
+	}
+}
+
+// stopping reports whether Close was called
+func (w *WebhookWorker) stopping() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleep waits between retries, returning early when the worker is closed
+func (w *WebhookWorker) sleep(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-w.stop:
+	}
+}
+
+// Close stops accepting transcripts and waits for the worker to finish the
+// delivery in flight. Undelivered transcripts stay in webhook-queue.jsonl and
+// are sent after the next start; returns ctx.Err() if ctx ends first
+func (w *WebhookWorker) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	started := w.started
+	w.mu.Unlock()
+	close(w.stop)
+	if !started {
+		return nil
+	}
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("webhook delivery did not finish: %w", ctx.Err())
+	}
+}
+
+// wait blocks until every submitted job was handled; used by tests
+func (w *WebhookWorker) wait() {
+	w.pending.Wait()
+}
+
+// Create a new webhook outputter delivering through worker
+func NewWebhookOutputter(cfg *config.Config, worker *WebhookWorker) (interfaces.Outputter, error) {
+	if cfg.Output.Webhook.URL == "" {
+		return nil, fmt.Errorf("webhook url not configured")
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("webhook delivery worker not available")
+	}
+	dataDir, err := config.DataDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve webhook queue directory: %w", err)
+	}
+	timeout := time.Duration(cfg.Output.Webhook.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &WebhookOutputter{
+		config:    cfg,
+		client:    &http.Client{Timeout: timeout},
+		queuePath: filepath.Join(dataDir, webhookQueueFile),
+		worker:    worker,
+		sleep:     worker.sleep,
+	}, nil
+}
+
+// Post text without session metadata. Delivers the transcript for "webhook" mode
+func (o *WebhookOutputter) TypeToActiveWindow(text string) error {
+	return o.OutputTranscript(interfaces.Transcript{Text: text, Language: o.config.General.Language})
+}
+
+// Queue the transcript on disk and hand it to the background worker, which
+// posts it after previously queued ones. Returns once the transcript is
+// accepted; delivery failures go to the worker's error callback
+func (o *WebhookOutputter) OutputTranscript(t interfaces.Transcript) error {
+	if t.Timestamp.IsZero() {
+		t.Timestamp = time.Now()
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Text:      t.Text,
+		SessionID: t.SessionID,
+		Language:  t.Language,
+		Duration:  t.Duration.Seconds(),
+		Timestamp: t.Timestamp.Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	// Snapshot the settings: the worker may run after the config changed
+	cfg := *o.config
+	out := *o
+	out.config = &cfg
+	job := webhookJob{out: &out, body: body}
+	if cfg.Output.Webhook.QueueSize > 0 {
+		// Persist before acknowledging, so a daemon stop cannot lose the transcript
+		if err := o.enqueue(body); err != nil {
+			return err
+		}
+		job.queued = true
+	}
+	return o.worker.submit(job)
+}
+
+// postWithRetry posts the body, retrying transient failures with backoff
+func (o *WebhookOutputter) postWithRetry(body []byte) error {
+	for attempt := 0; ; attempt++ {
+		err := o.post(body)
+		if err == nil {
+			return nil
+		}
+		var statusErr *webhookStatusError
+		if (errors.As(err, &statusErr) && statusErr.permanent()) || attempt >= o.config.Output.Webhook.MaxRetries || o.worker.stopping() {
+			return err
+		}
+		o.sleep(webhookBackoff(attempt))
+	}
+}
+
+// post sends a single request with the optional HMAC signature
+func (o *WebhookOutputter) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, o.config.Output.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", config.AppName)
+	if secret := o.config.Output.Webhook.Secret; secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookBody(secret, body))
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain a bounded amount so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &webhookStatusError{code: resp.StatusCode}
+	}
+	return nil
+}
+
+// SignWebhookBody returns the hex HMAC-SHA256 of body keyed with secret
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the delay before retry number attempt+1
+func webhookBackoff(attempt int) time.Duration {
+	delay := webhookBackoffBase << attempt
+	if delay <= 0 || delay > webhookBackoffMax {
+		return webhookBackoffMax
+	}
+	return delay
+}
+
+// enqueue appends a payload to the on-disk queue, dropping the oldest
+// entries beyond the configured size
+func (o *WebhookOutputter) enqueue(body []byte) error {
+	webhookQueueMu.Lock()
+	defer webhookQueueMu.Unlock()
+	entries, err := o.loadQueue()
+	if err != nil {
+		return err
+	}
+	entries = append(entries, body)
+	if limit := o.config.Output.Webhook.QueueSize; len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return o.saveQueue(entries)
+}
+
+// drainQueue delivers queued payloads in order, removing each one once it
+// was delivered or rejected, and stops at the first transient failure or when
+// the worker is closed. retry applies the backoff retries of new transcripts.
+// The queue is not locked while posting, so outputs can queue meanwhile
+func (o *WebhookOutputter) drainQueue(retry bool) error {
+	var rejected error
+	for !o.worker.stopping() {
+		head, err := o.queueHead()
+		if err != nil {
+			return err
+		}
+		if head == nil {
+			break
+		}
+		if retry {
+			err = o.postWithRetry(head)
+		} else {
+			err = o.post(head)
+		}
+		var statusErr *webhookStatusError
+		if err != nil && (!errors.As(err, &statusErr) || !statusErr.permanent()) {
+			return fmt.Errorf("webhook delivery failed, queued for retry: %w", err)
+		}
+		if err != nil && rejected == nil {
+			rejected = err
+		}
+		if err := o.dropQueueHead(head); err != nil {
+			return err
+		}
+	}
+	return rejected
+}
+
+// queueHead returns the oldest queued payload, or nil when the queue is empty
+func (o *WebhookOutputter) queueHead() ([]byte, error) {
+	webhookQueueMu.Lock()
+	defer webhookQueueMu.Unlock()
+	entries, err := o.loadQueue()
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// dropQueueHead removes head from the queue unless the size bound dropped it meanwhile
+func (o *WebhookOutputter) dropQueueHead(head []byte) error {
+	webhookQueueMu.Lock()
+	defer webhookQueueMu.Unlock()
+	entries, err := o.loadQueue()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 || !bytes.Equal(entries[0], head) {
+		return nil
+	}
+	return o.saveQueue(entries[1:])
+}
+
+// QueueLength reports the number of transcripts waiting for a retry
+func (o *WebhookOutputter) QueueLength() int {
+	webhookQueueMu.Lock()
+	defer webhookQueueMu.Unlock()
+	entries, _ := o.loadQueue()
+	return len(entries)
+}
+
+// loadQueue reads queued payloads, one JSON document per line
+func (o *WebhookOutputter) loadQueue() ([][]byte, error) {
+	data, err := os.ReadFile(o.queuePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook queue: %w", err)
+	}
+	var entries [][]byte
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && json.Valid([]byte(line)) {
+			entries = append(entries, []byte(line))
+		}
+	}
+	return entries, nil
+}
+
+// saveQueue atomically rewrites the queue file, removing it when empty
+func (o *WebhookOutputter) saveQueue(entries [][]byte) error {
+	if len(entries) == 0 {
+		if err := os.Remove(o.queuePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear webhook queue: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(o.queuePath), 0o700); err != nil {
+		return fmt.Errorf("failed to create webhook queue directory: %w", err)
+	}
+	var buf bytes.Buffer
+	for _, body := range entries {
+		buf.Write(body)
+		buf.WriteByte('\n')
+	}
+	tmp := o.queuePath + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write webhook queue: %w", err)
+	}
+	if err := os.Rename(tmp, o.queuePath); err != nil {
+		return fmt.Errorf("failed to write webhook queue: %w", err)
+	}
+	return nil
+}
+
+// Return an error as clipboard operations are not supported by this outputter
+func (o *WebhookOutputter) CopyToClipboard(text string) error {
+	return fmt.Errorf("copying to clipboard not supported by webhook outputter")
+}
+
+// Return an error as clipboard operations are not supported by this outputter
+func (o *WebhookOutputter) ReadClipboard() (string, error) {
+	return "", fmt.Errorf("reading clipboard not supported by webhook outputter")
+}
+
+// Return the tool names; the webhook needs no external tools
+func (o *WebhookOutputter) GetToolNames() (clipboardTool, typeTool string) {
+	return "", "webhook"
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2025 Asher Buk
+// SPDX-License-Identifier: MIT
+
+package outputters
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AshBuk/dabri/config"
+	"github.com/AshBuk/dabri/output/interfaces"
+)
+
+// webhookRecorder is an httptest endpoint answering with scripted status codes
+type webhookRecorder struct {
+	mu       sync.Mutex
+	statuses []int // Status per request; the last one repeats
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := http.StatusOK
+	if n := len(r.bodies); n < len(r.statuses) {
+		status = r.statuses[n]
+	} else if len(r.statuses) > 0 {
+		status = r.statuses[len(r.statuses)-1]
+	}
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+	w.WriteHeader(status)
+}
+
+func (r *webhookRecorder) payloads(t *testing.T) []WebhookPayload {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []WebhookPayload
+	for _, body := range r.bodies {
+		var p WebhookPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Fatalf("invalid webhook body %q: %v", body, err)
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// webhookErrors collects failures reported by the delivery worker
+type webhookErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (e *webhookErrors) add(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errs = append(e.errs, err)
+}
+
+func (e *webhookErrors) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.errs)
+}
+
+// captureWebhookErrors routes failures of the outputter's worker to the returned
+// collector; call before the first output, which starts the worker
+func captureWebhookErrors(out *WebhookOutputter) *webhookErrors {
+	errs := &webhookErrors{}
+	out.worker.onError = errs.add
+	return errs
+}
+
+// sendWebhook outputs text and waits for the worker to handle it
+func sendWebhook(t *testing.T, out *WebhookOutputter, text string) {
+	t.Helper()
+	if err := out.TypeToActiveWindow(text); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	out.worker.wait()
+}
+
+func newTestWebhookOutputter(t *testing.T, rec *webhookRecorder) (*WebhookOutputter, *config.Config, *[]time.Duration) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	cfg.General.Language = "en"
+	cfg.Output.Webhook.URL = server.URL
+	worker := NewWebhookWorker(nil)
+	t.Cleanup(func() { _ = worker.Close(context.Background()) })
+	out, err := NewWebhookOutputter(cfg, worker)
+	if err != nil {
+		t.Fatalf("NewWebhookOutputter: %v", err)
+	}
+	wo := out.(*WebhookOutputter)
+	var sleeps []time.Duration
+	wo.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return wo, cfg, &sleeps
+}
+
+func TestWebhookOutputter_PostsPayload(t *testing.T) {
+	rec := &webhookRecorder{}
+	out, _, _ := newTestWebhookOutputter(t, rec)
+
+	err := out.OutputTranscript(interfaces.Transcript{
+		Text:      "turn on the lights",
+		SessionID: "abc123",
+		Language:  "de",
+		Duration:  2500 * time.Millisecond,
+		Timestamp: time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("OutputTranscript: %v", err)
+	}
+	out.worker.wait()
+	payloads := rec.payloads(t)
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(payloads))
+	}
+	want := WebhookPayload{Text: "turn on the lights", SessionID: "abc123", Language: "de", Duration: 2.5, Timestamp: "2025-03-14T09:26:53Z"}
+	if payloads[0] != want {
+		t.Errorf("expected %+v, got %+v", want, payloads[0])
+	}
+	if ct := rec.headers[0].Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	if sig := rec.headers[0].Get(WebhookSignatureHeader); sig != "" {
+		t.Errorf("expected no signature without a secret, got %q", sig)
+	}
+}
+
+func TestWebhookOutputter_SignsBody(t *testing.T) {
+	rec := &webhookRecorder{}
+	out, cfg, _ := newTestWebhookOutputter(t, rec)
+	cfg.Output.Webhook.Secret = "s3cret"
+
+	sendWebhook(t, out, "hello")
+	want := "sha256=" + SignWebhookBody("s3cret", rec.bodies[0])
+	if got := rec.headers[0].Get(WebhookSignatureHeader); got != want {
+		t.Errorf("expected signature %q, got %q", want, got)
+	}
+	// Known HMAC-SHA256 vector guards the signing scheme itself
+	if got := SignWebhookBody("key", []byte("The quick brown fox jumps over the lazy dog")); got != "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8" {
+		t.Errorf("unexpected HMAC: %s", got)
+	}
+}
+
+func TestWebhookOutputter_RetriesWithBackoff(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}}
+	out, _, sleeps := newTestWebhookOutputter(t, rec)
+	errs := captureWebhookErrors(out)
+
+	sendWebhook(t, out, "hello")
+	if errs.count() != 0 {
+		t.Fatalf("expected delivery after retries, got %v", errs.errs)
+	}
+	if len(rec.bodies) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(rec.bodies))
+	}
+	if len(*sleeps) != 2 || (*sleeps)[0] != 500*time.Millisecond || (*sleeps)[1] != time.Second {
+		t.Errorf("unexpected backoff delays: %v", *sleeps)
+	}
+	if out.QueueLength() != 0 {
+		t.Errorf("expected empty queue, got %d", out.QueueLength())
+	}
+}
+
+func TestWebhookOutputter_RejectedIsNotRetriedOrQueued(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusBadRequest}}
+	out, _, _ := newTestWebhookOutputter(t, rec)
+	errs := captureWebhookErrors(out)
+
+	sendWebhook(t, out, "hello")
+	if errs.count() != 1 {
+		t.Fatalf("expected one reported error for the rejected request, got %d", errs.count())
+	}
+	if len(rec.bodies) != 1 {
+		t.Errorf("expected a single attempt, got %d", len(rec.bodies))
+	}
+	if out.QueueLength() != 0 {
+		t.Errorf("rejected payloads must not be queued, got %d", out.QueueLength())
+	}
+}
+
+func TestWebhookOutputter_QueuesAndFlushesInOrder(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusBadGateway}}
+	out, cfg, _ := newTestWebhookOutputter(t, rec)
+	cfg.Output.Webhook.MaxRetries = 1
+	errs := captureWebhookErrors(out)
+
+	for _, text := range []string{"first", "second"} {
+		sendWebhook(t, out, text)
+	}
+	if errs.count() != 2 {
+		t.Fatalf("expected failures while endpoint is down, got %d", errs.count())
+	}
+	if out.QueueLength() != 2 {
+		t.Fatalf("expected 2 queued transcripts, got %d", out.QueueLength())
+	}
+
+	// Endpoint recovers: queued transcripts go out first, in order
+	rec.mu.Lock()
+	rec.statuses, rec.bodies, rec.headers = []int{http.StatusOK}, nil, nil
+	rec.mu.Unlock()
+	sendWebhook(t, out, "third")
+	var texts []string
+	for _, p := range rec.payloads(t) {
+		texts = append(texts, p.Text)
+	}
+	if len(texts) != 3 || texts[0] != "first" || texts[1] != "second" || texts[2] != "third" {
+		t.Errorf("unexpected delivery order: %v", texts)
+	}
+	if out.QueueLength() != 0 {
+		t.Errorf("expected queue drained, got %d", out.QueueLength())
+	}
+}
+
+func TestWebhookOutputter_QueueIsBounded(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusServiceUnavailable}}
+	out, cfg, _ := newTestWebhookOutputter(t, rec)
+	cfg.Output.Webhook.MaxRetries = 0
+	cfg.Output.Webhook.QueueSize = 2
+
+	for _, text := range []string{"one", "two", "three"} {
+		sendWebhook(t, out, text)
+	}
+	entries, err := out.loadQueue()
+	if err != nil {
+		t.Fatalf("loadQueue: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected queue bounded to 2, got %d", len(entries))
+	}
+	var oldest WebhookPayload
+	if err := json.Unmarshal(entries[0], &oldest); err != nil || oldest.Text != "two" {
+		t.Errorf("expected oldest entry dropped, first queued is %q", oldest.Text)
+	}
+}
+
+func TestWebhookOutputter_DoesNotWaitForEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	rec := &webhookRecorder{}
+	out, cfg, _ := newTestWebhookOutputter(t, rec)
+	cfg.Output.Webhook.URL = server.URL
+	cfg.Output.Webhook.MaxRetries = 0
+	cfg.Output.Webhook.QueueSize = 0 // Nothing written to the temp dir after the test
+
+	start := time.Now()
+	if err := out.TypeToActiveWindow("hello"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("output waited %v for the endpoint", elapsed)
+	}
+	release <- struct{}{}
+	out.worker.wait()
+}
+
+func TestWebhookOutputter_QueuesBeforeAcknowledging(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	out, cfg, _ := newTestWebhookOutputter(t, &webhookRecorder{})
+	cfg.Output.Webhook.URL = server.URL
+
+	if err := out.TypeToActiveWindow("hello"); err != nil {
+		t.Fatalf("TypeToActiveWindow: %v", err)
+	}
+	if n := out.QueueLength(); n != 1 {
+		t.Errorf("accepted transcript must be on disk before delivery, queue has %d", n)
+	}
+	close(release)
+	out.worker.wait()
+	if n := out.QueueLength(); n != 0 {
+		t.Errorf("delivered transcript must leave the queue, queue has %d", n)
+	}
+}
+
+func TestWebhookWorker_CloseKeepsUndeliveredTranscripts(t *testing.T) {
+	received, release := make(chan struct{}, 2), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	out, cfg, _ := newTestWebhookOutputter(t, &webhookRecorder{})
+	cfg.Output.Webhook.URL = server.URL
+
+	for _, text := range []string{"first", "second"} {
+		if err := out.TypeToActiveWindow(text); err != nil {
+			t.Fatalf("TypeToActiveWindow: %v", err)
+		}
+	}
+	<-received // "first" is in flight, "second" waits in the worker
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := out.worker.Close(ctx); err == nil {
+		t.Error("Close should report the delivery still in flight")
+	}
+	close(release)
+	<-out.worker.done
+
+	entries, err := out.loadQueue()
+	if err != nil {
+		t.Fatalf("loadQueue: %v", err)
+	}
+	var pending WebhookPayload
+	if len(entries) != 1 || json.Unmarshal(entries[0], &pending) != nil || pending.Text != "second" {
+		t.Errorf("expected the undelivered transcript kept on disk, got %q", entries)
+	}
+	if err := out.TypeToActiveWindow("after close"); err != nil {
+		t.Errorf("a queued transcript must be accepted after Close: %v", err)
+	}
+	if n := out.QueueLength(); n != 2 {
+		t.Errorf("expected transcript queued for the next start, queue has %d", n)
+	}
+}
+
+func TestWebhookOutputter_RequiresURL(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetDefaultConfig(cfg)
+	if _, err := NewWebhookOutputter(cfg, NewWebhookWorker(nil)); err == nil {
+		t.Fatal("expected error without a webhook url")
+	}
+	cfg.Output.Webhook.URL = "http://127.0.0.1:9/hook"
+	if _, err := NewWebhookOutputter(cfg, nil); err == nil {
+		t.Fatal("expected error without a delivery worker")
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{0, 500 * time.Millisecond},
+		{1, time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{10, 8 * time.Second},
+		{80, 8 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempt); got != tt.expected {
+			t.Errorf("webhookBackoff(%d) = %v, want %v", tt.attempt, got, tt.expected)
+		}
+	}
+}
@@ -166,7 +166,7 @@ func TestApplicationInitializationFlow(t *testing.T) {
 
 		// Test whisper system (if bundled model available)
 		t.Log("Testing whisper system...")
-		modelManager := whisper.NewModelManager(cfg)
+		modelManager := whisper.NewModelManager(config.NewLive(cfg))
 		if modelPath, err := modelManager.GetModelPath(context.Background()); err == nil {
 			t.Logf("Bundled whisper model found at %s but skipping engine test (requires CGO)", modelPath)
 		} else {
@@ -154,7 +154,7 @@ func TestModelManagement(t *testing.T) {
 		config.SetDefaultConfig(cfg)
 
 		// Test ModelManager's bundled model resolution
-		modelManager := whisper.NewModelManager(cfg)
+		modelManager := whisper.NewModelManager(config.NewLive(cfg))
 
 		// This should always return a path (even if file doesn't exist in dev)
 		modelPath, err := modelManager.GetModelPath(context.Background())
@@ -15,10 +15,10 @@ import (
 )
 
 // Helper function to create test config
-func createTestConfig() *config.Config {
+func createTestConfig() *config.Live {
 	cfg := &config.Config{}
 	config.SetDefaultConfig(cfg)
-	return cfg
+	return config.NewLive(cfg)
 }
 
 // Helper function to check if a command exists
@@ -171,7 +171,7 @@ func TestModelAvailability(t *testing.T) {
 		config.SetDefaultConfig(cfg)
 
 		// Test ModelManager's bundled model path resolution
-		modelManager := whisper.NewModelManager(cfg)
+		modelManager := whisper.NewModelManager(config.NewLive(cfg))
 		modelPath, err := modelManager.GetModelPath(context.Background())
 		if err != nil {
 			t.Logf("Bundled model not found (expected for development): %v", err)
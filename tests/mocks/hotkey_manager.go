@@ -16,9 +16,14 @@ type HotkeyManager interface {
 	Stop()
 	RegisterCallbacks(startRecording, stopRecording func() error)
 	RegisterHotkeyAction(action string, callback manager.HotkeyAction)
+	RegisterOnDemandAction(action string, callback manager.HotkeyAction)
+	SetActionEnabled(action string, enabled bool) error
 	ReloadConfig(newConfig adapters.HotkeyConfig) error
 	CaptureOnce(timeout time.Duration) (string, error)
 	SupportsCaptureOnce() bool
+	ResetRecordingState()
+	SetRecordingState(recording bool)
+	WaitModifiersReleased(timeout time.Duration) bool
 }
 
 // MockHotkeyManager implements HotkeyManager for testing
@@ -65,12 +70,24 @@ func (m *MockHotkeyManager) RegisterHotkeyAction(action string, callback manager
 	}
 }
 
+func (m *MockHotkeyManager) RegisterOnDemandAction(action string, callback manager.HotkeyAction) {
+	m.RegisterHotkeyAction(action, callback)
+}
+
+func (m *MockHotkeyManager) SetActionEnabled(_ string, _ bool) error { return nil }
+
 func (m *MockHotkeyManager) ReloadConfig(_ adapters.HotkeyConfig) error { return nil }
 
 func (m *MockHotkeyManager) CaptureOnce(_ time.Duration) (string, error) { return "alt+r", nil }
 
 func (m *MockHotkeyManager) SupportsCaptureOnce() bool { return true }
 
+func (m *MockHotkeyManager) ResetRecordingState() {}
+
+func (m *MockHotkeyManager) SetRecordingState(_ bool) {}
+
+func (m *MockHotkeyManager) WaitModifiersReleased(_ time.Duration) bool { return true }
+
 // Test helper methods
 func (m *MockHotkeyManager) WasStartCalled() bool { return m.startCalled }
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/hotkeys/adapters"
+	outputInterfaces "github.com/AshBuk/dabri/output/interfaces"
 )
 
 // MockAudioService implements AudioServiceInterface for testing
@@ -22,13 +23,18 @@ func (m *MockAudioService) Shutdown() error {
 	return m.shutdownError
 }
 
-func (m *MockAudioService) HandleStartRecording() error                   { return nil }
-func (m *MockAudioService) HandleStopRecording() error                    { return nil }
-func (m *MockAudioService) IsRecording() bool                             { return false }
-func (m *MockAudioService) ClearSession()                                 {}
-func (m *MockAudioService) SwitchModel(_ context.Context, _ string) error { return nil }
-func (m *MockAudioService) DeleteModel(_ string) error                    { return nil }
-func (m *MockAudioService) GetLastTranscript() string                     { return "" }
+func (m *MockAudioService) HandleStartRecording() error                                   { return nil }
+func (m *MockAudioService) HandleStartRecordingWithOptions(_ config.SessionOptions) error { return nil }
+func (m *MockAudioService) HandleStopRecording() error                                    { return nil }
+func (m *MockAudioService) HandleStopRecordingWithOptions(_ config.SessionOptions) error  { return nil }
+func (m *MockAudioService) CancelRecording() error                                        { return nil }
+func (m *MockAudioService) IsRecording() bool                                             { return false }
+func (m *MockAudioService) SetActivityCallback(_ func(active bool))                       {}
+func (m *MockAudioService) ClearSession()                                                 {}
+func (m *MockAudioService) SwitchModel(_ context.Context, _ string) error                 { return nil }
+func (m *MockAudioService) DeleteModel(_ string) error                                    { return nil }
+func (m *MockAudioService) ReloadRecorder()                                               {}
+func (m *MockAudioService) GetLastTranscript() string                                     { return "" }
 
 // Test helper methods
 func (m *MockAudioService) WasShutdownCalled() bool { return m.shutdownCalled }
@@ -54,6 +60,7 @@ func (m *MockUIService) SetSuccess(message string)                         {}
 func (m *MockUIService) ShowConfigFile() error                             { return nil }
 func (m *MockUIService) ShowAboutPage() error                              { return nil }
 func (m *MockUIService) UpdateSettings(cfg *config.Config)                 {}
+func (m *MockUIService) RefreshHistory()                                   {}
 
 // Test helper methods
 func (m *MockUIService) WasShutdownCalled() bool { return m.shutdownCalled }
@@ -71,14 +78,26 @@ func (m *MockIOService) Shutdown() error {
 	return m.shutdownError
 }
 
-func (m *MockIOService) OutputText(text string) error                               { return nil }
-func (m *MockIOService) SetOutputMethod(method string) error                        { return nil }
-func (m *MockIOService) BeginTranscription()                                        {}
-func (m *MockIOService) CompleteTranscription(result string)                        {}
-func (m *MockIOService) WaitForTranscription(timeout time.Duration) (string, error) { return "", nil }
-func (m *MockIOService) GetOutputToolNames() (string, string)                       { return "mock-clipboard", "mock-typing" }
-func (m *MockIOService) StartWebSocketServer() error                                { return nil }
-func (m *MockIOService) StopWebSocketServer() error                                 { return nil }
+func (m *MockIOService) OutputText(text string) error { return nil }
+func (m *MockIOService) OutputTranscript(_ uint64, _ outputInterfaces.Transcript, _ *config.Profile) ([]outputInterfaces.TargetResult, error) {
+	return nil, nil
+}
+func (m *MockIOService) OutputToMode(text, mode string) error { return nil }
+func (m *MockIOService) SetOutputMethod(method string) error  { return nil }
+func (m *MockIOService) ReloadOutput() error                  { return nil }
+func (m *MockIOService) CancelTyping() bool                   { return false }
+func (m *MockIOService) UndoLastOutput() (outputInterfaces.UndoResult, error) {
+	return outputInterfaces.UndoResult{}, nil
+}
+func (m *MockIOService) BeginTranscription() uint64                                        { return 0 }
+func (m *MockIOService) CompleteTranscription(result outputInterfaces.TranscriptionResult) {}
+func (m *MockIOService) WaitForTranscription(timeout time.Duration) (outputInterfaces.TranscriptionResult, error) {
+	return outputInterfaces.TranscriptionResult{}, nil
+}
+func (m *MockIOService) GetOutputToolNames() (string, string)  { return "mock-clipboard", "mock-typing" }
+func (m *MockIOService) StartWebSocketServer() error           { return nil }
+func (m *MockIOService) StopWebSocketServer() error            { return nil }
+func (m *MockIOService) SetRemoteCancelHandler(_ func() error) {}
 
 // Test helper methods
 func (m *MockIOService) WasShutdownCalled() bool { return m.shutdownCalled }
@@ -103,10 +122,15 @@ func (m *MockConfigService) GetConfig() *config.Config          { return &config
 
 func (m *MockConfigService) UpdateLanguage(language string) error      { return nil }
 func (m *MockConfigService) UpdateWhisperModel(modelID string) error   { return nil }
+func (m *MockConfigService) SetLoadedModel(modelID string)             {}
 func (m *MockConfigService) ToggleWorkflowNotifications() error        { return nil }
 func (m *MockConfigService) UpdateRecordingMethod(method string) error { return nil }
 func (m *MockConfigService) UpdateOutputMode(mode string) error        { return nil }
+func (m *MockConfigService) UpdatePostProcessing(mode string) error    { return nil }
 func (m *MockConfigService) UpdateHotkey(action, combo string) error   { return nil }
+func (m *MockConfigService) SetValue(path, value string) error         { return nil }
+func (m *MockConfigService) UnsetValue(path string) error              { return nil }
+func (m *MockConfigService) Reload() ([]string, error)                 { return nil, nil }
 
 // Test helper methods
 func (m *MockConfigService) WasShutdownCalled() bool { return m.shutdownCalled }
@@ -117,6 +141,7 @@ func (m *MockConfigService) SetShutdownError(err error) { m.shutdownError = err
 type MockHotkeyService struct {
 	shutdownCalled bool
 	shutdownError  error
+	captureError   error
 }
 
 func (m *MockHotkeyService) Shutdown() error {
@@ -132,19 +157,42 @@ func (m *MockHotkeyService) SetupHotkeyCallbacks(
 ) error {
 	return nil
 }
-func (m *MockHotkeyService) RegisterHotkeys() error   { return nil }
-func (m *MockHotkeyService) UnregisterHotkeys() error { return nil }
+func (m *MockHotkeyService) RegisterAction(_ string, _ func() error) error { return nil }
+func (m *MockHotkeyService) RegisterHotkeys() error                        { return nil }
+func (m *MockHotkeyService) UnregisterHotkeys() error                      { return nil }
 func (m *MockHotkeyService) ReloadFromConfig(startRecording, stopRecording func() error, _ func() adapters.HotkeyConfig) error {
 	return nil
 }
 
+// RegisterOnDemandAction mock implementation
+func (m *MockHotkeyService) RegisterOnDemandAction(action string, callback func() error) error {
+	return nil
+}
+
+// SetActionEnabled mock implementation
+func (m *MockHotkeyService) SetActionEnabled(action string, enabled bool) {}
+
 // CaptureOnce mock implementation
-func (m *MockHotkeyService) CaptureOnce(timeoutMs int) (string, error) { return "alt+r", nil }
+func (m *MockHotkeyService) CaptureOnce(timeoutMs int) (string, error) {
+	if m.captureError != nil {
+		return "", m.captureError
+	}
+	return "alt+r", nil
+}
 
 // SupportsCaptureOnce mock implementation
 func (m *MockHotkeyService) SupportsCaptureOnce() bool { return true }
 
+func (m *MockHotkeyService) ResetRecordingState() {}
+
+func (m *MockHotkeyService) SetRecordingState(recording bool) {}
+
+// WaitModifiersReleased mock implementation
+func (m *MockHotkeyService) WaitModifiersReleased(timeout time.Duration) bool { return true }
+
 // Test helper methods
 func (m *MockHotkeyService) WasShutdownCalled() bool { return m.shutdownCalled }
 
+func (m *MockHotkeyService) SetCaptureError(err error) { m.captureError = err }
+
 func (m *MockHotkeyService) SetShutdownError(err error) { m.shutdownError = err }
@@ -11,8 +11,9 @@ import (
 
 // Verify client credentials via token or open access policy
 func (s *WebSocketServer) authenticate(r *http.Request) bool {
+	authToken := s.config.Get().WebServer.AuthToken
 	// If auth token is not set, all connections are allowed
-	if s.config.WebServer.AuthToken == "" {
+	if authToken == "" {
 		return true
 	}
 	// Check for token in query params or headers
@@ -24,22 +25,23 @@ func (s *WebSocketServer) authenticate(r *http.Request) bool {
 		headerToken = headerToken[7:]
 	}
 	// Check if either token matches using constant-time comparison to prevent timing attacks
-	queryMatch := subtle.ConstantTimeCompare([]byte(queryToken), []byte(s.config.WebServer.AuthToken)) == 1
-	headerMatch := subtle.ConstantTimeCompare([]byte(headerToken), []byte(s.config.WebServer.AuthToken)) == 1
+	queryMatch := subtle.ConstantTimeCompare([]byte(queryToken), []byte(authToken)) == 1
+	headerMatch := subtle.ConstantTimeCompare([]byte(headerToken), []byte(authToken)) == 1
 
 	return queryMatch || headerMatch
 }
 
 // Confirm token matches configured authentication secret
 func (s *WebSocketServer) validateToken(token string) bool { // nolint:unused // used in tests
+	authToken := s.config.Get().WebServer.AuthToken
 	// If auth token is not set, all tokens are invalid
-	if s.config.WebServer.AuthToken == "" {
+	if authToken == "" {
 		return false
 	}
 	// Trim whitespace
 	token = strings.TrimSpace(token)
 	// Compare with configured token using constant-time comparison
-	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.WebServer.AuthToken)) == 1
+	return subtle.ConstantTimeCompare([]byte(token), []byte(authToken)) == 1
 }
 
 // Extract real client IP considering proxy headers
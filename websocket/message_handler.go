@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/AshBuk/dabri/config"
 	"github.com/gorilla/websocket"
 )
 
@@ -23,7 +24,7 @@ func (s *WebSocketServer) processMessages(conn *websocket.Conn) {
 			break
 		}
 		// Log request if enabled
-		if s.config.WebServer.LogRequests {
+		if s.config.Get().WebServer.LogRequests {
 			s.logger.Debug("Received WebSocket message: %s", string(rawMessage))
 		}
 
@@ -37,10 +38,19 @@ func (s *WebSocketServer) processMessages(conn *websocket.Conn) {
 		// Process message based on type
 		// Synchronous handling per-connection provides natural backpressure
 		switch msg.Type {
-		case "start-recording":
-			s.handleStartRecording(conn, msg.RequestID)
-		case "stop-recording":
-			s.handleStopRecording(conn, msg.RequestID)
+		case "start-recording", "stop-recording":
+			req, err := parseSessionRequest(msg.Payload)
+			if err != nil {
+				s.sendError(conn, "invalid_options", err.Error(), msg.RequestID)
+				continue
+			}
+			if msg.Type == "start-recording" {
+				s.handleStartRecording(conn, msg.RequestID, req)
+			} else {
+				s.handleStopRecording(conn, msg.RequestID, req)
+			}
+		case "cancel-recording":
+			s.handleCancelRecording(conn, msg.RequestID)
 		case "ping":
 			s.sendMessage(conn, "pong", nil)
 		default:
@@ -50,14 +60,16 @@ func (s *WebSocketServer) processMessages(conn *websocket.Conn) {
 	}
 }
 
-// Start recording through AudioService.
-func (s *WebSocketServer) handleStartRecording(conn *websocket.Conn, requestID string) {
+// Start recording through AudioService with the per-dictation options of the payload.
+func (s *WebSocketServer) handleStartRecording(conn *websocket.Conn, requestID string, req sessionRequest) {
 	audio := s.audioController()
 	if audio == nil {
 		s.sendError(conn, "recording_error", "audio controller not wired", requestID)
 		return
 	}
-	err := s.executeWithRetry(audio.HandleStartRecording, conn)
+	err := s.executeWithRetry(func() error {
+		return audio.HandleStartRecordingWithOptions(req.options)
+	}, conn)
 	if err != nil {
 		s.logger.Error("Error starting recording: %v", err)
 		s.sendError(conn, "recording_error", fmt.Sprintf("Error starting recording: %v", err), requestID)
@@ -66,8 +78,56 @@ func (s *WebSocketServer) handleStartRecording(conn *websocket.Conn, requestID s
 	s.sendMessage(conn, "recording-started", nil, requestID)
 }
 
+// sessionRequest holds the per-dictation fields of a recording payload.
+type sessionRequest struct {
+	options config.SessionOptions
+	deliver bool // Deliver to the configured output targets ({"output": true}, stop-recording only)
+}
+
+// parseSessionRequest reads the per-dictation fields of a start/stop-recording payload:
+// {"language":"de","output":"none","post_processing":"code","task":"translate"}.
+// "output" also accepts true, which delivers to the configured output targets.
+func parseSessionRequest(payload interface{}) (sessionRequest, error) {
+	var req sessionRequest
+	params, ok := payload.(map[string]interface{})
+	if !ok {
+		return req, nil
+	}
+	fields := map[string]*string{
+		"language":        &req.options.Language,
+		"post_processing": &req.options.PostProcessing,
+		"task":            &req.options.Task,
+	}
+	for key, dst := range fields {
+		if value, exists := params[key]; exists {
+			str, isString := value.(string)
+			if !isString {
+				return req, fmt.Errorf("%s must be a string", key)
+			}
+			*dst = str
+		}
+	}
+	switch output := params["output"].(type) {
+	case nil:
+	case bool:
+		req.deliver = output
+	case string:
+		req.options.Output = output
+	default:
+		return req, fmt.Errorf("output must be a string or a boolean")
+	}
+	if err := config.ValidateSessionOptions(req.options); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
 // Stop recording through AudioService and return the transcript.
-func (s *WebSocketServer) handleStopRecording(conn *websocket.Conn, requestID string) {
+// When the stop payload asks for output, or the session options resolved
+// from the start and stop payloads select "clipboard" or "type", the
+// transcript also goes to the output targets and the per-target results
+// are included in the transcription payload.
+func (s *WebSocketServer) handleStopRecording(conn *websocket.Conn, requestID string, req sessionRequest) {
 	audio := s.audioController()
 	if audio == nil {
 		s.sendError(conn, "recording_error", "audio controller not wired", requestID)
@@ -76,7 +136,7 @@ func (s *WebSocketServer) handleStopRecording(conn *websocket.Conn, requestID st
 
 	ctx, cancel := context.WithTimeout(context.Background(), transcriptionCtxTimeout)
 	defer cancel()
-	text, err := audio.HandleStopRecordingSync(ctx)
+	text, options, err := audio.HandleStopRecordingSync(ctx, req.options)
 	if err != nil {
 		if ctx.Err() != nil {
 			s.logger.Error("Timeout transcribing audio")
@@ -89,9 +149,34 @@ func (s *WebSocketServer) handleStopRecording(conn *websocket.Conn, requestID st
 	}
 	// Confirm stop after the operation actually succeeded, then deliver
 	s.sendMessage(conn, "recording-stopped", nil, requestID)
-	s.sendMessage(conn, "transcription", map[string]string{
-		"text": text,
-	}, requestID)
+	payload := map[string]interface{}{"text": text}
+	deliver := req.deliver || options.OutputMode() != ""
+	if router := s.outputRouter(); deliver && router != nil && text != "" {
+		results, err := router.DeliverTranscript(text, options.OutputMode())
+		payload["outputs"] = results
+		if err != nil {
+			s.logger.Error("Error delivering transcript: %v", err)
+			payload["output_error"] = err.Error()
+		}
+	}
+	s.sendMessage(conn, "transcription", payload, requestID)
+}
+
+// Discard the recording or pending transcription through the cancel handler.
+// Messages on one connection are handled in order, so cancelling another
+// client's stop-recording requires a separate connection.
+func (s *WebSocketServer) handleCancelRecording(conn *websocket.Conn, requestID string) {
+	cancel := s.cancelHandler()
+	if cancel == nil {
+		s.sendError(conn, "recording_error", "audio controller not wired", requestID)
+		return
+	}
+	if err := cancel(); err != nil {
+		s.logger.Warning("Error cancelling recording: %v", err)
+		s.sendError(conn, "cancel_error", fmt.Sprintf("Error cancelling recording: %v", err), requestID)
+		return
+	}
+	s.sendMessage(conn, "recording-cancelled", nil, requestID)
 }
 
 // Deliver structured error response for client debugging
@@ -100,7 +185,7 @@ func (s *WebSocketServer) sendError(conn *websocket.Conn, errorType string, erro
 		Type:       "error",
 		Error:      errorType,
 		Payload:    errorMsg,
-		APIVersion: s.config.WebServer.APIVersion,
+		APIVersion: s.config.Get().WebServer.APIVersion,
 		RequestID:  requestID,
 		Timestamp:  time.Now().Unix(),
 	}
@@ -14,13 +14,22 @@ import (
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/logger"
+	"github.com/AshBuk/dabri/internal/metrics"
+	"github.com/AshBuk/dabri/output/interfaces"
 	"github.com/gorilla/websocket"
 )
 
 // AudioController exposes recording actions to WebSocket handlers.
 type AudioController interface {
-	HandleStartRecording() error
-	HandleStopRecordingSync(ctx context.Context) (string, error)
+	HandleStartRecordingWithOptions(opts config.SessionOptions) error
+	HandleStopRecordingSync(ctx context.Context, opts config.SessionOptions) (string, config.SessionOptions, error)
+	CancelRecording() error
+}
+
+// OutputRouter delivers API transcripts to the configured output targets,
+// or to mode alone when it is non-empty.
+type OutputRouter interface {
+	DeliverTranscript(text, mode string) ([]interfaces.TargetResult, error)
 }
 
 // WebSocket server configuration constants
@@ -45,11 +54,13 @@ const (
 
 // Enables real-time speech-to-text API for external clients
 type WebSocketServer struct {
-	config      *config.Config
+	config      *config.Live
 	clients     map[*websocket.Conn]bool
 	clientsLock sync.Mutex
 	upgrader    websocket.Upgrader
 	audio       AudioController
+	output      OutputRouter
+	cancel      func() error
 	audioMu     sync.RWMutex
 	server      *http.Server
 	started     atomic.Bool
@@ -69,10 +80,11 @@ type Message struct {
 }
 
 // checkOriginFunc creates a CORS origin validation function
-func checkOriginFunc(cfg *config.Config) func(*http.Request) bool {
+func checkOriginFunc(cfg *config.Live) func(*http.Request) bool {
 	return func(r *http.Request) bool {
+		allowed := cfg.Get().WebServer.CORSOrigins
 		// Allow all origins if configured with "*"
-		if cfg.WebServer.CORSOrigins == "*" {
+		if allowed == "*" {
 			return true
 		}
 
@@ -85,12 +97,12 @@ func checkOriginFunc(cfg *config.Config) func(*http.Request) bool {
 
 		// Check if origin matches configured CORS origins
 		// For simplicity, exact match. Could be extended to support wildcards
-		return origin == cfg.WebServer.CORSOrigins
+		return origin == allowed
 	}
 }
 
 // Initialize server with security and resource constraints.
-func NewWebSocketServer(config *config.Config, logger logger.Logger) *WebSocketServer {
+func NewWebSocketServer(config *config.Live, logger logger.Logger) *WebSocketServer {
 	return &WebSocketServer{
 		config:  config,
 		clients: make(map[*websocket.Conn]bool),
@@ -111,6 +123,41 @@ func (s *WebSocketServer) SetAudioController(audio AudioController) {
 	s.audioMu.Unlock()
 }
 
+// SetOutputRouter wires delivery of API transcripts to output targets.
+func (s *WebSocketServer) SetOutputRouter(output OutputRouter) {
+	s.audioMu.Lock()
+	s.output = output
+	s.audioMu.Unlock()
+}
+
+// SetCancelHandler replaces AudioController.CancelRecording for cancel-recording
+// messages, so the application can resync its own recording state.
+func (s *WebSocketServer) SetCancelHandler(cancel func() error) {
+	s.audioMu.Lock()
+	s.cancel = cancel
+	s.audioMu.Unlock()
+}
+
+// cancelHandler returns the cancel handler, falling back to AudioController (thread-safe).
+func (s *WebSocketServer) cancelHandler() func() error {
+	s.audioMu.RLock()
+	defer s.audioMu.RUnlock()
+	if s.cancel != nil {
+		return s.cancel
+	}
+	if s.audio != nil {
+		return s.audio.CancelRecording
+	}
+	return nil
+}
+
+// outputRouter returns the current OutputRouter (thread-safe).
+func (s *WebSocketServer) outputRouter() OutputRouter {
+	s.audioMu.RLock()
+	defer s.audioMu.RUnlock()
+	return s.output
+}
+
 // audioController returns the current AudioController (thread-safe).
 func (s *WebSocketServer) audioController() AudioController {
 	s.audioMu.RLock()
@@ -120,7 +167,8 @@ func (s *WebSocketServer) audioController() AudioController {
 
 // Begin accepting client connections with health monitoring
 func (s *WebSocketServer) Start() error {
-	if !s.config.WebServer.Enabled {
+	cfg := s.config.Get()
+	if !cfg.WebServer.Enabled {
 		return nil
 	}
 	// Handler for WebSocket connection setup
@@ -128,7 +176,7 @@ func (s *WebSocketServer) Start() error {
 	mux.HandleFunc("/ws", s.handleWebSocket)
 
 	// API versioning - support multiple API versions
-	apiVersion := s.config.WebServer.APIVersion
+	apiVersion := cfg.WebServer.APIVersion
 	if apiVersion != "" {
 		mux.HandleFunc(fmt.Sprintf("/api/%s/ws", apiVersion), s.handleWebSocket)
 	}
@@ -140,8 +188,11 @@ func (s *WebSocketServer) Start() error {
 			s.logger.Debug("health write error: %v", err)
 		}
 	})
+	if cfg.Metrics.Enabled {
+		mux.HandleFunc("/metrics", s.handleMetrics)
+	}
 	// Create HTTP server with timeouts
-	addr := fmt.Sprintf("%s:%d", s.config.WebServer.Host, s.config.WebServer.Port)
+	addr := fmt.Sprintf("%s:%d", cfg.WebServer.Host, cfg.WebServer.Port)
 	s.server = &http.Server{
 		Addr:         addr,
 		Handler:      mux,
@@ -163,6 +214,15 @@ func (s *WebSocketServer) Start() error {
 	return nil
 }
 
+// handleMetrics serves Prometheus metrics; auth_token applies as for /ws.
+func (s *WebSocketServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticate(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	metrics.Default.Handler().ServeHTTP(w, r)
+}
+
 // Ensure clean client disconnection before termination
 func (s *WebSocketServer) Stop() {
 	if s.server != nil && s.started.Load() {
@@ -202,7 +262,7 @@ func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request
 	clientCount := len(s.clients)
 	s.clientsLock.Unlock()
 
-	if s.config.WebServer.MaxClients > 0 && clientCount >= s.config.WebServer.MaxClients {
+	if maxClients := s.config.Get().WebServer.MaxClients; maxClients > 0 && clientCount >= maxClients {
 		s.logger.Warning("Max clients limit reached, rejecting connection from %s", r.RemoteAddr)
 		http.Error(w, "Too many connections", http.StatusServiceUnavailable)
 		return
@@ -240,7 +300,7 @@ func (s *WebSocketServer) handleWebSocket(w http.ResponseWriter, r *http.Request
 	// Send welcome message with version info
 	s.sendMessage(conn, "connected", map[string]string{
 		"server":      "Dabri",
-		"api_version": s.config.WebServer.APIVersion,
+		"api_version": s.config.Get().WebServer.APIVersion,
 	})
 	// Start ping/pong goroutine.
 	// Fire-and-forget is safe here: Stop() closes all connections, causing WriteControl
@@ -270,7 +330,7 @@ func (s *WebSocketServer) sendMessage(conn *websocket.Conn, messageType string,
 	msg := Message{
 		Type:       messageType,
 		Payload:    payload,
-		APIVersion: s.config.WebServer.APIVersion,
+		APIVersion: s.config.Get().WebServer.APIVersion,
 		Timestamp:  time.Now().Unix(),
 	}
 	// Set request ID if provided
@@ -284,7 +344,7 @@ func (s *WebSocketServer) sendMessage(conn *websocket.Conn, messageType string,
 		return
 	}
 	// Log if enabled
-	if s.config.WebServer.LogRequests {
+	if s.config.Get().WebServer.LogRequests {
 		s.logger.Debug("Sending WebSocket message: %s", string(data))
 	}
 	// Send message
@@ -9,32 +9,45 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/AshBuk/dabri/config"
 	"github.com/AshBuk/dabri/internal/testutils"
+	"github.com/AshBuk/dabri/output/interfaces"
 	"github.com/gorilla/websocket"
 )
 
 // Mock implementations for testing
 type mockAudioController struct {
-	startErr   error
-	stopText   string
-	stopErr    error
-	startCalls int
-	stopCalls  int
+	startErr     error
+	stopText     string
+	stopErr      error
+	cancelErr    error
+	startCalls   int
+	stopCalls    int
+	cancelCalls  int
+	options      config.SessionOptions
+	startOptions config.SessionOptions // Options the recording was started with
 }
 
-func (m *mockAudioController) HandleStartRecording() error {
+func (m *mockAudioController) HandleStartRecordingWithOptions(opts config.SessionOptions) error {
 	m.startCalls++
+	m.options = opts
 	return m.startErr
 }
 
-func (m *mockAudioController) HandleStopRecordingSync(_ context.Context) (string, error) {
+func (m *mockAudioController) HandleStopRecordingSync(_ context.Context, opts config.SessionOptions) (string, config.SessionOptions, error) {
 	m.stopCalls++
-	return m.stopText, m.stopErr
+	m.options = opts
+	return m.stopText, m.startOptions.Merge(opts), m.stopErr
+}
+
+func (m *mockAudioController) CancelRecording() error {
+	m.cancelCalls++
+	return m.cancelErr
 }
 
 func createTestConfig() *config.Config {
@@ -55,12 +68,12 @@ func TestNewWebSocketServer(t *testing.T) {
 	cfg := createTestConfig()
 	logger := testutils.NewMockLogger()
 
-	server := NewWebSocketServer(cfg, logger)
+	server := NewWebSocketServer(config.NewLive(cfg), logger)
 	if server == nil {
 		t.Fatal("NewWebSocketServer returned nil")
 	}
 
-	if server.config != cfg {
+	if server.config.Get() != cfg {
 		t.Error("Config not set correctly")
 	}
 
@@ -81,7 +94,7 @@ func TestWebSocketServer_Start_Disabled(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.Enabled = false
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	err := server.Start()
 
@@ -98,7 +111,7 @@ func TestWebSocketServer_Start_Enabled(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.Port = 0 // Use random port for testing
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 	err := server.Start()
 	if err != nil {
 		t.Errorf("Expected no error when starting server, got %v", err)
@@ -113,7 +126,7 @@ func TestWebSocketServer_Stop(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.Port = 0 // Use random port for testing
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 	// Start server
 	err := server.Start()
 	if err != nil {
@@ -134,7 +147,7 @@ func TestWebSocketServer_Authentication_NoToken(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.AuthToken = ""
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 	// Create test request
 	req := httptest.NewRequest("GET", "/ws", nil)
 	result := server.authenticate(req)
@@ -147,7 +160,7 @@ func TestWebSocketServer_Authentication_WithToken(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.AuthToken = "test-token"
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	tests := []struct {
 		name       string
@@ -202,11 +215,42 @@ func TestWebSocketServer_Authentication_WithToken(t *testing.T) {
 	}
 }
 
+func TestWebSocketServer_HandleMetrics(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.WebServer.AuthToken = "test-token"
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{"authorized", "Bearer test-token", http.StatusOK},
+		{"unauthorized", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			server.handleMetrics(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), "# TYPE dabri_transcription_queue_depth gauge") {
+				t.Errorf("body does not contain daemon metrics:\n%s", rec.Body.String())
+			}
+		})
+	}
+}
+
 func TestWebSocketServer_ValidateToken(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.AuthToken = "test-token"
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	tests := []struct {
 		name     string
@@ -249,7 +293,7 @@ func TestWebSocketServer_ValidateToken_NoAuthToken(t *testing.T) {
 	cfg := createTestConfig()
 	cfg.WebServer.AuthToken = ""
 
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	result := server.validateToken("any-token")
 
@@ -260,7 +304,7 @@ func TestWebSocketServer_ValidateToken_NoAuthToken(t *testing.T) {
 
 func TestWebSocketServer_SendMessage(t *testing.T) {
 	cfg := createTestConfig()
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	// Create test WebSocket connection
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -313,12 +357,12 @@ func TestWebSocketServer_SendMessage(t *testing.T) {
 }
 
 func TestWebSocketServer_HandleStartRecordingSendsStarted(t *testing.T) {
-	server := NewWebSocketServer(createTestConfig(), testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
 	audio := &mockAudioController{}
 	server.SetAudioController(audio)
 
 	messages := collectHandlerMessages(t, server, 1, func(conn *websocket.Conn) {
-		server.handleStartRecording(conn, "req-start")
+		server.handleStartRecording(conn, "req-start", sessionRequest{options: config.SessionOptions{Language: "de"}})
 	})
 
 	if audio.startCalls != 1 {
@@ -327,15 +371,18 @@ func TestWebSocketServer_HandleStartRecordingSendsStarted(t *testing.T) {
 	if messages[0].Type != "recording-started" || messages[0].RequestID != "req-start" {
 		t.Fatalf("unexpected start message: %+v", messages[0])
 	}
+	if audio.options.Language != "de" {
+		t.Errorf("expected start options to reach the audio controller, got %+v", audio.options)
+	}
 }
 
 func TestWebSocketServer_HandleStopRecordingSendsStoppedThenTranscription(t *testing.T) {
-	server := NewWebSocketServer(createTestConfig(), testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
 	audio := &mockAudioController{stopText: "hello"}
 	server.SetAudioController(audio)
 
 	messages := collectHandlerMessages(t, server, 2, func(conn *websocket.Conn) {
-		server.handleStopRecording(conn, "req-stop")
+		server.handleStopRecording(conn, "req-stop", sessionRequest{})
 	})
 
 	if audio.stopCalls != 1 {
@@ -351,11 +398,11 @@ func TestWebSocketServer_HandleStopRecordingSendsStoppedThenTranscription(t *tes
 }
 
 func TestWebSocketServer_HandleStopRecordingErrorDoesNotSendStopped(t *testing.T) {
-	server := NewWebSocketServer(createTestConfig(), testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
 	server.SetAudioController(&mockAudioController{stopErr: fmt.Errorf("stop failed")})
 
 	messages := collectHandlerMessages(t, server, 1, func(conn *websocket.Conn) {
-		server.handleStopRecording(conn, "req-stop")
+		server.handleStopRecording(conn, "req-stop", sessionRequest{})
 	})
 
 	if messages[0].Type != "error" || messages[0].Error != "transcription_error" {
@@ -363,6 +410,214 @@ func TestWebSocketServer_HandleStopRecordingErrorDoesNotSendStopped(t *testing.T
 	}
 }
 
+func TestWebSocketServer_HandleCancelRecording(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelErr error
+		wantType  string
+		wantError string
+	}{
+		{"cancelled", nil, "recording-cancelled", ""},
+		{"nothing to cancel", fmt.Errorf("nothing to cancel"), "error", "cancel_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+			audio := &mockAudioController{cancelErr: tt.cancelErr}
+			server.SetAudioController(audio)
+
+			messages := collectHandlerMessages(t, server, 1, func(conn *websocket.Conn) {
+				server.handleCancelRecording(conn, "req-cancel")
+			})
+
+			if audio.cancelCalls != 1 {
+				t.Fatalf("expected one cancel call, got %d", audio.cancelCalls)
+			}
+			if messages[0].Type != tt.wantType || messages[0].Error != tt.wantError || messages[0].RequestID != "req-cancel" {
+				t.Fatalf("unexpected cancel message: %+v", messages[0])
+			}
+		})
+	}
+}
+
+func TestWebSocketServer_HandleCancelRecordingUsesCancelHandler(t *testing.T) {
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+	audio := &mockAudioController{}
+	server.SetAudioController(audio)
+	handled := 0
+	server.SetCancelHandler(func() error { handled++; return nil })
+
+	messages := collectHandlerMessages(t, server, 1, func(conn *websocket.Conn) {
+		server.handleCancelRecording(conn, "req-cancel")
+	})
+
+	if handled != 1 || audio.cancelCalls != 0 {
+		t.Fatalf("handler calls = %d, controller calls = %d; want the handler only", handled, audio.cancelCalls)
+	}
+	if messages[0].Type != "recording-cancelled" {
+		t.Fatalf("unexpected cancel message: %+v", messages[0])
+	}
+}
+
+type mockOutputRouter struct {
+	results []interfaces.TargetResult
+	err     error
+	texts   []string
+	modes   []string
+}
+
+func (m *mockOutputRouter) DeliverTranscript(text, mode string) ([]interfaces.TargetResult, error) {
+	m.texts = append(m.texts, text)
+	m.modes = append(m.modes, mode)
+	return m.results, m.err
+}
+
+func TestWebSocketServer_HandleStopRecordingDeliversToOutputTargets(t *testing.T) {
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+	server.SetAudioController(&mockAudioController{stopText: "hello"})
+	router := &mockOutputRouter{
+		results: []interfaces.TargetResult{
+			{Target: "active_window", Status: interfaces.TargetStatusFallback, Fallback: "clipboard", Error: "no focus"},
+			{Target: "file", Status: interfaces.TargetStatusOK},
+		},
+	}
+	server.SetOutputRouter(router)
+
+	messages := collectHandlerMessages(t, server, 2, func(conn *websocket.Conn) {
+		server.handleStopRecording(conn, "req-stop", sessionRequest{deliver: true})
+	})
+
+	if len(router.texts) != 1 || router.texts[0] != "hello" || router.modes[0] != "" {
+		t.Fatalf("expected transcript delivered once to the configured targets, got %v %q", router.texts, router.modes)
+	}
+	payload, ok := messages[1].Payload.(map[string]interface{})
+	if !ok || messages[1].Type != "transcription" {
+		t.Fatalf("unexpected transcription message: %+v", messages[1])
+	}
+	outputs, ok := payload["outputs"].([]interface{})
+	if !ok || len(outputs) != 2 {
+		t.Fatalf("expected 2 output results, got %+v", payload["outputs"])
+	}
+	first, _ := outputs[0].(map[string]interface{})
+	if first["target"] != "active_window" || first["status"] != "fallback" || first["fallback"] != "clipboard" {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if _, exists := payload["output_error"]; exists {
+		t.Errorf("unexpected output_error: %v", payload["output_error"])
+	}
+}
+
+func TestWebSocketServer_HandleStopRecordingWithoutOutputSkipsDelivery(t *testing.T) {
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+	server.SetAudioController(&mockAudioController{stopText: "hello"})
+	router := &mockOutputRouter{}
+	server.SetOutputRouter(router)
+
+	messages := collectHandlerMessages(t, server, 2, func(conn *websocket.Conn) {
+		server.handleStopRecording(conn, "req-stop", sessionRequest{})
+	})
+
+	if len(router.texts) != 0 {
+		t.Fatalf("expected no delivery, got %v", router.texts)
+	}
+	payload, _ := messages[1].Payload.(map[string]interface{})
+	if _, exists := payload["outputs"]; exists {
+		t.Errorf("expected no outputs in payload, got %+v", payload)
+	}
+}
+
+func TestWebSocketServer_HandleStopRecordingDeliversToSessionOutput(t *testing.T) {
+	server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+	audio := &mockAudioController{stopText: "hello"}
+	server.SetAudioController(audio)
+	router := &mockOutputRouter{}
+	server.SetOutputRouter(router)
+
+	req, err := parseSessionRequest(map[string]interface{}{"output": "type", "task": "translate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	collectHandlerMessages(t, server, 2, func(conn *websocket.Conn) {
+		server.handleStopRecording(conn, "req-stop", req)
+	})
+
+	if len(router.modes) != 1 || router.modes[0] != config.OutputModeActiveWindow {
+		t.Fatalf("expected delivery by typing only, got %q", router.modes)
+	}
+	if audio.options.Task != config.TaskTranslate {
+		t.Errorf("expected stop options to reach the audio controller, got %+v", audio.options)
+	}
+}
+
+func TestWebSocketServer_HandleStopRecordingUsesStartOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     config.SessionOptions
+		stop      sessionRequest
+		wantModes []string
+	}{
+		{"clipboard from start", config.SessionOptions{Output: config.SessionOutputClipboard}, sessionRequest{}, []string{config.OutputModeClipboard}},
+		{"stop overrides start", config.SessionOptions{Output: config.SessionOutputClipboard},
+			sessionRequest{options: config.SessionOptions{Output: config.SessionOutputNone}}, nil},
+		{"configured targets on request", config.SessionOptions{}, sessionRequest{deliver: true}, []string{""}},
+		{"no output requested", config.SessionOptions{}, sessionRequest{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewWebSocketServer(config.NewLive(createTestConfig()), testutils.NewMockLogger())
+			server.SetAudioController(&mockAudioController{stopText: "hello", startOptions: tt.start})
+			router := &mockOutputRouter{}
+			server.SetOutputRouter(router)
+
+			collectHandlerMessages(t, server, 2, func(conn *websocket.Conn) {
+				server.handleStopRecording(conn, "req-stop", tt.stop)
+			})
+
+			if !slices.Equal(router.modes, tt.wantModes) {
+				t.Errorf("delivered modes = %q, want %q", router.modes, tt.wantModes)
+			}
+		})
+	}
+}
+
+func TestParseSessionRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     interface{}
+		wantOptions config.SessionOptions
+		wantDeliver bool
+		wantErr     bool
+	}{
+		{"no payload", nil, config.SessionOptions{}, false, false},
+		{"non-object payload", "output", config.SessionOptions{}, false, false},
+		{"deliver to configured targets", map[string]interface{}{"output": true}, config.SessionOptions{}, true, false},
+		{"no delivery", map[string]interface{}{"output": false}, config.SessionOptions{}, false, false},
+		{"session output none", map[string]interface{}{"output": "none", "language": "de"},
+			config.SessionOptions{Output: "none", Language: "de"}, false, false},
+		{"session output clipboard", map[string]interface{}{"output": "clipboard"},
+			config.SessionOptions{Output: "clipboard"}, false, false},
+		{"post-processing and task", map[string]interface{}{"post_processing": "code", "task": "translate"},
+			config.SessionOptions{PostProcessing: "code", Task: "translate"}, false, false},
+		{"string true is not a flag", map[string]interface{}{"output": "true"}, config.SessionOptions{}, false, true},
+		{"unknown language", map[string]interface{}{"language": "klingon"}, config.SessionOptions{}, false, true},
+		{"non-string language", map[string]interface{}{"language": 7.0}, config.SessionOptions{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := parseSessionRequest(tt.payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSessionRequest(%v) error = %v, wantErr %v", tt.payload, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if req.options != tt.wantOptions || req.deliver != tt.wantDeliver {
+				t.Errorf("parseSessionRequest(%v) = %+v, want options %+v deliver %t", tt.payload, req, tt.wantOptions, tt.wantDeliver)
+			}
+		})
+	}
+}
+
 func collectHandlerMessages(t *testing.T, server *WebSocketServer, count int, action func(*websocket.Conn)) []Message {
 	t.Helper()
 
@@ -403,7 +658,7 @@ func collectHandlerMessages(t *testing.T, server *WebSocketServer, count int, ac
 
 func TestWebSocketServer_ExecuteWithRetry_Success(t *testing.T) {
 	cfg := createTestConfig()
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	// Create mock connection
 	conn := &websocket.Conn{}
@@ -435,7 +690,7 @@ func TestWebSocketServer_ExecuteWithRetry_Success(t *testing.T) {
 
 func TestWebSocketServer_ExecuteWithRetry_MaxRetries(t *testing.T) {
 	cfg := createTestConfig()
-	server := NewWebSocketServer(cfg, testutils.NewMockLogger())
+	server := NewWebSocketServer(config.NewLive(cfg), testutils.NewMockLogger())
 
 	// Create mock connection
 	conn := &websocket.Conn{}
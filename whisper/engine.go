@@ -78,6 +78,12 @@ func (w *WhisperEngine) Close() error {
 // Perform speech-to-text conversion on the given audio file.
 // Handle file validation, audio loading, context management, and processing
 func (w *WhisperEngine) Transcribe(audioFile string) (string, error) {
+	return w.transcribe(audioFile, w.config.General.Language, false)
+}
+
+// transcribe runs whisper on the audio file using the given recognition language,
+// translating the speech to English when translate is set
+func (w *WhisperEngine) transcribe(audioFile, language string, translate bool) (string, error) {
 	if !utils.IsValidFile(audioFile) {
 		return "", fmt.Errorf("audio file not found or invalid: %s", audioFile)
 	}
@@ -102,12 +108,13 @@ func (w *WhisperEngine) Transcribe(audioFile string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to create whisper context: %w", err)
 	}
-	// Set the target language for transcription if specified in the config
-	if lang := w.config.General.Language; lang != "" {
+	// Set the target language for transcription if specified
+	if lang := language; lang != "" {
 		if err := context.SetLanguage(lang); err != nil {
 			return "", fmt.Errorf("failed to set language: %w", err)
 		}
 	}
+	context.SetTranslate(translate)
 
 	if err := context.Process(audioData, nil, nil, nil); err != nil {
 		return "", fmt.Errorf("failed to process audio: %w", err)
@@ -131,13 +138,30 @@ func (w *WhisperEngine) Transcribe(audioFile string) (string, error) {
 // whisper.cpp processing cannot be interrupted safely, so context cancellation is
 // checked before and after the synchronous call instead of spawning hidden work.
 func (w *WhisperEngine) TranscribeWithContext(ctx context.Context, audioFile string) (string, error) {
+	return w.TranscribeWithLanguage(ctx, audioFile, w.config.General.Language)
+}
+
+// TranscribeWithLanguage behaves like TranscribeWithContext but overrides the
+// configured recognition language for this call only (e.g., per-application profiles)
+func (w *WhisperEngine) TranscribeWithLanguage(ctx context.Context, audioFile, language string) (string, error) {
+	return w.transcribeWithContext(ctx, audioFile, language, false)
+}
+
+// TranslateWithLanguage behaves like TranscribeWithLanguage but translates the
+// speech to English (whisper "translate" task). English-only models ignore it
+func (w *WhisperEngine) TranslateWithLanguage(ctx context.Context, audioFile, language string) (string, error) {
+	return w.transcribeWithContext(ctx, audioFile, language, true)
+}
+
+// transcribeWithContext checks for cancellation around the synchronous whisper call
+func (w *WhisperEngine) transcribeWithContext(ctx context.Context, audioFile, language string, translate bool) (string, error) {
 	select {
 	case <-ctx.Done():
 		return "", fmt.Errorf("transcription cancelled: %w", ctx.Err())
 	default:
 	}
 
-	txt, err := w.Transcribe(audioFile)
+	txt, err := w.transcribe(audioFile, language, translate)
 	if err != nil {
 		return "", err
 	}
@@ -35,6 +35,16 @@ func (w *WhisperEngine) TranscribeWithContext(_ context.Context, _ string) (stri
 	return "", errors.New("transcription unavailable: built without cgo")
 }
 
+// TranscribeWithLanguage returns an error in the stub implementation
+func (w *WhisperEngine) TranscribeWithLanguage(_ context.Context, _, _ string) (string, error) {
+	return "", errors.New("transcription unavailable: built without cgo")
+}
+
+// TranslateWithLanguage returns an error in the stub implementation
+func (w *WhisperEngine) TranslateWithLanguage(_ context.Context, _, _ string) (string, error) {
+	return "", errors.New("transcription unavailable: built without cgo")
+}
+
 // GetModel returns nil in the stub implementation
 func (w *WhisperEngine) GetModel() interfaces.WhisperModel {
 	return nil
@@ -32,6 +32,8 @@ type WhisperEngine interface {
 	Transcribe(audioFile string) (string, error)
 	// Add cancellation support for long running operations
 	TranscribeWithContext(ctx context.Context, audioFile string) (string, error)
+	// Transcribe with a per-call language override
+	TranscribeWithLanguage(ctx context.Context, audioFile, language string) (string, error)
 	// Close the engine and release any associated resources
 	Close() error
 	// Return the underlying model object
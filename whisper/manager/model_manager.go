@@ -16,11 +16,11 @@ import (
 
 // Manages Whisper model lifecycle: resolution, download, and validation
 type ModelManager struct {
-	config *config.Config
+	config *config.Live
 }
 
 // Create a new manager responsible for the Whisper model
-func NewModelManager(config *config.Config) *ModelManager {
+func NewModelManager(config *config.Live) *ModelManager {
 	return &ModelManager{config: config}
 }
 
@@ -63,7 +63,7 @@ func (m *ModelManager) resolveModel(ctx context.Context, modelID string) (string
 		return "", fmt.Errorf("unknown model ID: %s", modelID)
 	}
 
-	resolver := providers.NewModelPathResolver(m.config, def.FileName)
+	resolver := providers.NewModelPathResolver(m.config.Get(), def.FileName)
 
 	// Check bundled / user data / dev paths
 	modelPath := resolver.GetBundledModelPath()
@@ -96,7 +96,7 @@ func (m *ModelManager) DeleteModel(modelID string) error {
 	if modelID == m.configuredModelID() {
 		return fmt.Errorf("cannot delete active model: %s", modelID)
 	}
-	resolver := providers.NewModelPathResolver(m.config, def.FileName)
+	resolver := providers.NewModelPathResolver(m.config.Get(), def.FileName)
 	path := resolver.GetUserDataModelPath()
 	if err := os.Remove(path); err != nil {
 		if os.IsNotExist(err) {
@@ -109,7 +109,7 @@ func (m *ModelManager) DeleteModel(modelID string) error {
 
 // configuredModelID returns the model ID from config, falling back to default
 func (m *ModelManager) configuredModelID() string {
-	if id := m.config.General.WhisperModel; id != "" {
+	if id := m.config.Get().General.WhisperModel; id != "" {
 		return id
 	}
 	return constants.DefaultModelID
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+
+	"github.com/AshBuk/dabri/internal/metrics"
 )
 
 // ModelDownloader handles downloading the whisper model from Hugging Face
@@ -38,7 +40,7 @@ func (d *ModelDownloader) Download(ctx context.Context, destPath string) error {
 	tmpPath := destPath + ".tmp"
 
 	// Download to temporary file
-	if err := d.downloadToFile(ctx, tmpPath); err != nil {
+	if err := d.downloadToFile(ctx, tmpPath, filepath.Base(destPath)); err != nil {
 		_ = os.Remove(tmpPath) // Clean up on error
 		return err
 	}
@@ -62,8 +64,9 @@ func (d *ModelDownloader) Download(ctx context.Context, destPath string) error {
 	return nil
 }
 
-// downloadToFile downloads the model URL to the specified file
-func (d *ModelDownloader) downloadToFile(ctx context.Context, path string) error {
+// downloadToFile downloads the model URL to the specified file; received bytes
+// are counted in the model download metric as they arrive
+func (d *ModelDownloader) downloadToFile(ctx context.Context, path, model string) error {
 	// Create HTTP request with context for cancellation support
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil) // #nosec G107 -- URL comes from hardcoded model definitions, not user input
 	if err != nil {
@@ -88,13 +91,21 @@ func (d *ModelDownloader) downloadToFile(ctx context.Context, path string) error
 	defer func() { _ = out.Close() }()
 
 	// Copy response body to file
-	_, err = io.Copy(out, resp.Body)
+	_, err = io.Copy(io.MultiWriter(out, downloadCounter(model)), resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to write model file: %w", err)
 	}
 	return nil
 }
 
+// downloadCounter adds everything written to it to the model download metric
+type downloadCounter string
+
+func (c downloadCounter) Write(p []byte) (int, error) {
+	metrics.ModelDownloadBytes.Add(float64(len(p)), string(c))
+	return len(p), nil
+}
+
 // GetModelURL returns the download URL
 func (d *ModelDownloader) GetModelURL() string {
 	return d.url
@@ -11,6 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/AshBuk/dabri/internal/metrics"
 )
 
 const testMinSize = 1024 // 1 KB for tests
@@ -42,6 +44,26 @@ func TestModelDownloader_Download_Success(t *testing.T) {
 	}
 }
 
+func TestModelDownloader_Download_CountsBytes(t *testing.T) {
+	modelData := strings.Repeat("x", testMinSize)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(modelData))
+	}))
+	defer server.Close()
+
+	downloader := NewModelDownloaderForURL(server.URL, testMinSize)
+	if err := downloader.Download(context.Background(), filepath.Join(t.TempDir(), "counted_model.bin")); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	var out strings.Builder
+	if err := metrics.Default.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	if want := `dabri_model_download_bytes_total{model="counted_model.bin"} 1024`; !strings.Contains(out.String(), want) {
+		t.Errorf("metrics do not contain %q", want)
+	}
+}
+
 func TestModelDownloader_Download_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
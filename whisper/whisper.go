@@ -23,6 +23,6 @@ type (
 )
 
 // Create a new manager for the bundled Whisper model
-func NewModelManager(config *config.Config) ModelManager {
+func NewModelManager(config *config.Live) ModelManager {
 	return manager.NewModelManager(config)
 }